@@ -1,35 +1,47 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
+	skrstorageclass "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/storageclass"
 	skrsvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/svc"
 
 	skrpvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/pvc"
 
 	skrnode "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/node"
 
+	skrpool "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/pool"
+
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/keb"
 
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/gorilla/mux"
 
+	kmccache "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/cache"
 	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/service"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/shard"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/sink"
 
 	gardenersecret "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/gardener/secret"
 	gardenershoot "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/gardener/shoot"
 	kmcprocess "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/process"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/reload"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/env"
@@ -40,7 +52,10 @@ import (
 const (
 	metricsPath        = "/metrics"
 	healthzPath        = "/healthz"
+	specsPath          = "/specs"
 	edpCredentialsFile = "/edp-credentials/token"
+
+	specsWatchBatchDelay = 2 * time.Second
 )
 
 func main() {
@@ -55,11 +70,11 @@ func main() {
 	}
 
 	// Load public cloud specs
-	publicCloudSpecs, err := kmcprocess.LoadPublicCloudSpecs(cfg)
+	publicCloudSpecs, err := newPublicCloudSpecs(cfg, logger)
 	if err != nil {
 		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load public cloud spec")
 	}
-	logger.Debugf("public cloud spec: %v", publicCloudSpecs)
+	logger.Infof("loaded public cloud specs: %+v", publicCloudSpecs.Status())
 
 	secretClient, err := gardenersecret.NewClient(opts)
 	if err != nil {
@@ -82,6 +97,16 @@ func main() {
 	// Creating cache with no expiration and the data will never be cleaned up
 	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
 
+	// Creating store to restore the last metrics after a restart
+	storeConfig := new(kmccache.StoreConfig)
+	if err := envconfig.Process("", storeConfig); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load record store config")
+	}
+	recordStore, err := newRecordStore(*storeConfig)
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Create record store")
+	}
+
 	// Creating EDP client
 	edpConfig := new(edp.Config)
 	if err := envconfig.Process("", edpConfig); err != nil {
@@ -97,22 +122,97 @@ func main() {
 
 	edpClient := edp.NewClient(edpConfig, logger)
 
+	edpOutboxConfig := new(edp.OutboxConfig)
+	if err := envconfig.Process("", edpOutboxConfig); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load EDP outbox config")
+	}
+	var edpOutbox *edp.Outbox
+	if edpOutboxConfig.Dir != "" {
+		edpOutbox, err = edp.NewOutbox(*edpOutboxConfig, edpClient, logger)
+		if err != nil {
+			logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Create EDP outbox")
+		}
+		go edpOutbox.Run(wait.NeverStop)
+	}
+
+	// Creating sinks the metrics are sent to
+	sinkConfig := new(sink.Config)
+	if err := envconfig.Process("", sinkConfig); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load sink config")
+	}
+	sinks, err := sink.NewSinks(*sinkConfig, edpClient, edpOutbox, logger)
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Create sinks")
+	}
+	logger.Infof("sending metrics to sinks: %v", sinks.Names())
+
+	// Creating aggregator of the time-weighted usage between the events
+	var aggregator *kmcprocess.Aggregator
+	var skrPoolObserver skrpool.Observer
+	if cfg.UsageAggregationEnabled {
+		aggregator = kmcprocess.NewAggregator()
+		skrPoolObserver = aggregator
+	}
+
+	// Creating pool of informers to watch the SKRs instead of listing the resources on every scrape
+	skrPoolConfig := new(skrpool.Config)
+	if err := envconfig.Process("", skrPoolConfig); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load SKR pool config")
+	}
+
+	var skrPool *skrpool.Pool
+	if skrPoolConfig.Enabled {
+		skrPool = skrpool.NewPool(*skrPoolConfig, skrPoolObserver, logger)
+	}
+
+	// Creating sharder to split the subaccounts across the replicas
+	shardConfig := new(shard.Config)
+	if err := envconfig.Process("", shardConfig); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load sharding config")
+	}
+	var sharder *shard.Sharder
+	if shardConfig.Enabled {
+		if storeConfig.Type == kmccache.StoreTypeConfigMap {
+			logger.With(log.KeyResult, log.ValueFail).Fatal("Sharding requires the memory or file record store")
+		}
+		// The outbox delivers the stored events regardless of the owner, so two replicas could send them
+		if edpOutbox != nil {
+			logger.With(log.KeyResult, log.ValueFail).Fatal("Sharding cannot be used with the EDP outbox")
+		}
+		// A send must finish before another replica can take over the subaccount
+		if maxSend := edpConfig.MaxSendDuration(); maxSend >= shardConfig.LeaseDuration {
+			logger.With(log.KeyResult, log.ValueFail).Fatalf("Sharding requires sending to EDP to take less than the lease duration %v, "+
+				"but it can take up to %v, decrease EDP_TIMEOUT or EDP_RETRY", shardConfig.LeaseDuration, maxSend)
+		}
+		sharder, err = newSharder(*shardConfig, logger)
+		if err != nil {
+			logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Create sharder")
+		}
+	}
+
 	queue := workqueue.NewDelayingQueue()
 
 	kmcProcess := kmcprocess.Process{
-		KEBClient:       kebClient,
-		ShootClient:     shootClient,
-		SecretClient:    secretClient,
-		EDPClient:       edpClient,
-		Logger:          logger,
-		Providers:       publicCloudSpecs,
-		Cache:           cache,
-		ScrapeInterval:  opts.ScrapeInterval,
-		Queue:           queue,
-		WorkersPoolSize: opts.WorkerPoolSize,
-		NodeConfig:      skrnode.Config{},
-		PVCConfig:       skrpvc.Config{},
-		SvcConfig:       skrsvc.Config{},
+		KEBClient:          kebClient,
+		ShootClient:        shootClient,
+		SecretClient:       secretClient,
+		Sink:               sinks,
+		Logger:             logger,
+		PublicCloudSpecs:   publicCloudSpecs,
+		Cache:              cache,
+		RecordStore:        recordStore,
+		RecordSaveInterval: storeConfig.SaveInterval,
+		ScrapeInterval:     opts.ScrapeInterval,
+		Queue:              queue,
+		WorkersPoolSize:    opts.WorkerPoolSize,
+		NodeConfig:         skrnode.Config{},
+		PVCConfig:          skrpvc.Config{},
+		SvcConfig:          skrsvc.Config{},
+		StorageClassConfig: skrstorageclass.Config{},
+		SKRPool:            skrPool,
+		Aggregator:         aggregator,
+		Sharder:            sharder,
+		Statuses:           kmcprocess.NewRuntimeStatuses(),
 	}
 
 	// Start execution
@@ -127,6 +227,11 @@ func main() {
 		writer.WriteHeader(http.StatusOK)
 	})
 	router.Path(metricsPath).Handler(promhttp.Handler())
+	router.Path(specsPath).Handler(publicCloudSpecs)
+	// The debug API shows the computed metrics per runtime and is only served with a token
+	if cfg.DebugAPIToken != "" {
+		router.PathPrefix(kmcprocess.DebugPathPrefix).Handler(kmcProcess.DebugHandler(cfg.DebugAPIToken))
+	}
 
 	kmcSvr := service.Server{
 		Addr:   fmt.Sprintf(":%d", opts.ListenAddr),
@@ -136,6 +241,11 @@ func main() {
 
 	// Start a server to cater to the metrics and healthz endpoints
 	kmcSvr.Start()
+
+	// Let the other replicas take over the subaccounts without waiting for the lease to expire
+	if sharder != nil {
+		sharder.Release()
+	}
 }
 
 func enableDebugging(debugPort int, log *zap.SugaredLogger) {
@@ -161,6 +271,80 @@ func enableDebugging(debugPort int, log *zap.SugaredLogger) {
 	}()
 }
 
+// newPublicCloudSpecs loads the public cloud specs and reloads them when the file or the ConfigMap changes
+func newPublicCloudSpecs(cfg *env.Config, logger *zap.SugaredLogger) (*kmcprocess.PublicCloudSpecs, error) {
+	var configMaps dynamic.ResourceInterface
+	if cfg.PublicCloudSpecsFile == "" && cfg.PublicCloudSpecsConfigMapName != "" {
+		restConfig, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		configMaps = dynamicClient.Resource(kmccache.ConfigMapGroupVersionResource()).Namespace(cfg.PublicCloudSpecsConfigMapNamespace)
+	}
+
+	source, err := kmcprocess.NewSpecsSource(cfg, configMaps)
+	if err != nil {
+		return nil, err
+	}
+	publicCloudSpecs, err := kmcprocess.NewPublicCloudSpecs(source, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	switch source := source.(type) {
+	case *kmcprocess.FileSpecsSource:
+		watcher := reload.NewWatcher("public-cloud-specs", []string{source.Path()}, specsWatchBatchDelay, publicCloudSpecs.Reload, logger)
+		go watcher.Run(context.Background())
+	case *kmcprocess.ConfigMapSpecsSource:
+		go publicCloudSpecs.Run(cfg.PublicCloudSpecsReloadInterval, wait.NeverStop)
+	}
+	return publicCloudSpecs, nil
+}
+
+// newRecordStore creates the record store, the ConfigMap store uses the KCP cluster where KMC runs
+func newRecordStore(config kmccache.StoreConfig) (kmccache.RecordStore, error) {
+	if config.Type != kmccache.StoreTypeConfigMap {
+		return kmccache.NewStore(config, nil)
+	}
+
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	configMaps := dynamicClient.Resource(kmccache.ConfigMapGroupVersionResource()).Namespace(config.ConfigMapNamespace)
+	return kmccache.NewStore(config, configMaps)
+}
+
+// newSharder creates the sharder with the Leases of the KCP cluster where KMC runs
+func newSharder(config shard.Config, logger *zap.SugaredLogger) (*shard.Sharder, error) {
+	if config.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		config.Identity = hostname
+	}
+
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	leases := dynamicClient.Resource(shard.LeaseGroupVersionResource()).Namespace(config.Namespace)
+	return shard.NewSharder(config, leases, logger)
+}
+
 // getEDPToken read the EDP token from the mounted secret file
 func getEDPToken() (string, error) {
 	token, err := os.ReadFile(edpCredentialsFile)
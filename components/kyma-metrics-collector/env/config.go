@@ -1,6 +1,15 @@
 package env
 
+import "time"
+
 // Config contains the configurations which are controlled by the ENV vars
 type Config struct {
-	PublicCloudSpecs string `envconfig:"PUBLIC_CLOUD_SPECS" required:"true"`
+	PublicCloudSpecs                   string        `envconfig:"PUBLIC_CLOUD_SPECS"`
+	PublicCloudSpecsFile               string        `envconfig:"PUBLIC_CLOUD_SPECS_FILE"`
+	PublicCloudSpecsConfigMapName      string        `envconfig:"PUBLIC_CLOUD_SPECS_CONFIGMAP_NAME"`
+	PublicCloudSpecsConfigMapNamespace string        `envconfig:"PUBLIC_CLOUD_SPECS_CONFIGMAP_NAMESPACE" default:"kcp-system"`
+	PublicCloudSpecsConfigMapKey       string        `envconfig:"PUBLIC_CLOUD_SPECS_CONFIGMAP_KEY" default:"providers"`
+	PublicCloudSpecsReloadInterval     time.Duration `envconfig:"PUBLIC_CLOUD_SPECS_RELOAD_INTERVAL" default:"1m"`
+	UsageAggregationEnabled            bool          `envconfig:"USAGE_AGGREGATION_ENABLED" default:"false"`
+	DebugAPIToken                      string        `envconfig:"DEBUG_API_TOKEN"`
 }
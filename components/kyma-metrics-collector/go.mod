@@ -3,6 +3,7 @@ module github.com/kyma-project/control-plane/components/kyma-metrics-collector
 go 1.20
 
 require (
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/gardener/gardener v1.75.0
 	github.com/gardener/gardener-extension-provider-aws v1.41.1
 	github.com/gardener/gardener-extension-provider-azure v1.33.0
@@ -34,6 +35,7 @@ require (
 	github.com/google/gnostic v0.6.9 // indirect
 	github.com/google/go-cmp v0.5.9 // indirect
 	github.com/google/gofuzz v1.2.0 // indirect
+	github.com/googleapis/gnostic v0.5.5 // indirect
 	github.com/imdario/mergo v0.3.13 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/kyma-incubator/compass/components/director v0.0.0-20220706110254-3d5dce79e48d // indirect
@@ -3,9 +3,9 @@ package cache
 import "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
 
 type Record struct {
-	SubAccountID string
-	RuntimeID    string
-	ShootName    string
-	KubeConfig   string
-	Metric       *edp.ConsumptionMetrics
+	SubAccountID string                  `json:"subAccountID"`
+	RuntimeID    string                  `json:"runtimeID"`
+	ShootName    string                  `json:"shootName"`
+	KubeConfig   string                  `json:"-"`
+	Metric       *edp.ConsumptionMetrics `json:"metric,omitempty"`
 }
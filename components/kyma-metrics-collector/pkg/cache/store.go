@@ -0,0 +1,256 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	StoreTypeMemory    = "memory"
+	StoreTypeFile      = "file"
+	StoreTypeConfigMap = "configmap"
+
+	configMapRecordsKey = "records.json.gz"
+	storeTimeout        = 30 * time.Second
+)
+
+type StoreConfig struct {
+	Type               string        `envconfig:"RECORD_STORE" default:"memory"`
+	FilePath           string        `envconfig:"RECORD_STORE_FILE_PATH" default:"/tmp/kmc-records.json"`
+	ConfigMapName      string        `envconfig:"RECORD_STORE_CONFIGMAP_NAME" default:"kcp-kyma-metrics-collector-records"`
+	ConfigMapNamespace string        `envconfig:"RECORD_STORE_CONFIGMAP_NAMESPACE" default:"kcp-system"`
+	SaveInterval       time.Duration `envconfig:"RECORD_STORE_SAVE_INTERVAL" default:"1m"`
+}
+
+// RecordStore keeps the records with the last metrics, so that they can be restored after KMC restarts.
+// The kubeconfigs are never stored.
+type RecordStore interface {
+	Save(records []Record) error
+	Load() ([]Record, error)
+}
+
+// NewStore creates the store of the configured type, the ConfigMaps client is used only by the ConfigMap store
+func NewStore(config StoreConfig, configMaps dynamic.ResourceInterface) (RecordStore, error) {
+	switch config.Type {
+	case StoreTypeMemory:
+		return NewMemoryStore(), nil
+	case StoreTypeFile:
+		return NewFileStore(config.FilePath), nil
+	case StoreTypeConfigMap:
+		if configMaps == nil {
+			return nil, fmt.Errorf("ConfigMaps client is required for the %s record store", StoreTypeConfigMap)
+		}
+		return NewConfigMapStore(configMaps, config.ConfigMapName), nil
+	default:
+		return nil, fmt.Errorf("unknown record store type %q", config.Type)
+	}
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Save(records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = make([]Record, 0, len(records))
+	for _, record := range records {
+		record.KubeConfig = ""
+		s.records = append(s.records, record)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Load() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Record{}, s.records...), nil
+}
+
+// FileStore keeps the snapshot of the records in a JSON file
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Save(records []Record) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal records")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return errors.Wrapf(err, "failed to create directory for records snapshot")
+	}
+	tempPath := s.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write records snapshot")
+	}
+	return errors.Wrapf(os.Rename(tempPath, s.path), "failed to replace records snapshot")
+}
+
+func (s *FileStore) Load() ([]Record, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read records snapshot")
+	}
+
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal records snapshot")
+	}
+	return records, nil
+}
+
+// ConfigMapStore keeps the compressed snapshot of the records in a ConfigMap in the KCP cluster
+type ConfigMapStore struct {
+	configMaps dynamic.ResourceInterface
+	name       string
+}
+
+func NewConfigMapStore(configMaps dynamic.ResourceInterface, name string) *ConfigMapStore {
+	return &ConfigMapStore{configMaps: configMaps, name: name}
+}
+
+func (s *ConfigMapStore) Save(records []Record) error {
+	data, err := compressRecords(records)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	configMap, err := s.get(ctx)
+	if k8serrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			TypeMeta:   metaV1.TypeMeta{Kind: "ConfigMap", APIVersion: corev1.SchemeGroupVersion.String()},
+			ObjectMeta: metaV1.ObjectMeta{Name: s.name},
+			BinaryData: map[string][]byte{configMapRecordsKey: data},
+		}
+		unstructuredConfigMap, err := toUnstructured(configMap)
+		if err != nil {
+			return err
+		}
+		_, err = s.configMaps.Create(ctx, unstructuredConfigMap, metaV1.CreateOptions{})
+		return errors.Wrapf(err, "failed to create records ConfigMap")
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get records ConfigMap")
+	}
+
+	configMap.BinaryData = map[string][]byte{configMapRecordsKey: data}
+	unstructuredConfigMap, err := toUnstructured(configMap)
+	if err != nil {
+		return err
+	}
+	_, err = s.configMaps.Update(ctx, unstructuredConfigMap, metaV1.UpdateOptions{})
+	return errors.Wrapf(err, "failed to update records ConfigMap")
+}
+
+func (s *ConfigMapStore) Load() ([]Record, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	configMap, err := s.get(ctx)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get records ConfigMap")
+	}
+
+	data, found := configMap.BinaryData[configMapRecordsKey]
+	if !found {
+		return nil, nil
+	}
+	return decompressRecords(data)
+}
+
+func (s *ConfigMapStore) get(ctx context.Context) (*corev1.ConfigMap, error) {
+	unstructuredConfigMap, err := s.configMaps.Get(ctx, s.name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	configMap := new(corev1.ConfigMap)
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(unstructuredConfigMap.Object, configMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert records ConfigMap")
+	}
+	return configMap, nil
+}
+
+func toUnstructured(configMap *corev1.ConfigMap) (*unstructured.Unstructured, error) {
+	object, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(configMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert records ConfigMap")
+	}
+	return &unstructured.Unstructured{Object: object}, nil
+}
+
+func ConfigMapGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Version:  corev1.SchemeGroupVersion.Version,
+		Group:    corev1.SchemeGroupVersion.Group,
+		Resource: "configmaps",
+	}
+}
+
+func compressRecords(records []Record) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if err := json.NewEncoder(writer).Encode(records); err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal records")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.Wrapf(err, "failed to compress records")
+	}
+	return buffer.Bytes(), nil
+}
+
+func decompressRecords(data []byte) ([]Record, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decompress records")
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decompress records")
+	}
+
+	var records []Record
+	if err := json.Unmarshal(decompressed, &records); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal records")
+	}
+	return records, nil
+}
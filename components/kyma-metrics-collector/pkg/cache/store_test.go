@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+func TestStores(t *testing.T) {
+	records := []Record{
+		{
+			SubAccountID: "subaccount-1",
+			RuntimeID:    "runtime-1",
+			ShootName:    "shoot-1",
+			KubeConfig:   "kubeconfig",
+			Metric: &edp.ConsumptionMetrics{
+				RuntimeId: "runtime-1",
+				Compute:   edp.Compute{ProvisionedCpus: 8, VMTypes: []edp.VMType{{Name: "m5.xlarge", Count: 2}}},
+			},
+		},
+		{SubAccountID: "subaccount-2", RuntimeID: "runtime-2", ShootName: "shoot-2"},
+	}
+	expectedRecords := []Record{records[0], records[1]}
+	expectedRecords[0].KubeConfig = ""
+
+	for name, newStore := range map[string]func(t *testing.T) RecordStore{
+		StoreTypeMemory: func(t *testing.T) RecordStore {
+			return NewMemoryStore()
+		},
+		StoreTypeFile: func(t *testing.T) RecordStore {
+			return NewFileStore(filepath.Join(t.TempDir(), "records", "snapshot.json"))
+		},
+		StoreTypeConfigMap: func(t *testing.T) RecordStore {
+			configMaps := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()).Resource(ConfigMapGroupVersionResource()).Namespace("kcp-system")
+			return NewConfigMapStore(configMaps, "kmc-records")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			store := newStore(t)
+
+			// Nothing stored yet
+			loaded, err := store.Load()
+			g.Expect(err).Should(gomega.BeNil())
+			g.Expect(loaded).Should(gomega.BeEmpty())
+
+			// Kubeconfigs are not stored
+			g.Expect(store.Save(records)).Should(gomega.Succeed())
+			loaded, err = store.Load()
+			g.Expect(err).Should(gomega.BeNil())
+			g.Expect(loaded).Should(gomega.Equal(expectedRecords))
+
+			// The snapshot is replaced
+			g.Expect(store.Save(records[1:])).Should(gomega.Succeed())
+			loaded, err = store.Load()
+			g.Expect(err).Should(gomega.BeNil())
+			g.Expect(loaded).Should(gomega.Equal(expectedRecords[1:]))
+		})
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	_, err := NewStore(StoreConfig{Type: StoreTypeConfigMap}, nil)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+
+	_, err = NewStore(StoreConfig{Type: "redis"}, nil)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+
+	store, err := NewStore(StoreConfig{Type: StoreTypeFile, FilePath: filepath.Join(t.TempDir(), "snapshot.json")}, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(store).Should(gomega.BeAssignableToTypeOf(&FileStore{}))
+}
@@ -73,8 +73,8 @@ func (eClient Client) Send(req *http.Request, payload []byte) (*http.Response, e
 	customBackoff := wait.Backoff{
 		Steps:    eClient.Config.EventRetry,
 		Duration: eClient.Config.Timeout,
-		Factor:   5.0,
-		Jitter:   0.1,
+		Factor:   sendBackoffFactor,
+		Jitter:   sendBackoffJitter,
 	}
 	err = retry.OnError(customBackoff, func(err error) bool {
 		if err != nil {
@@ -120,6 +120,36 @@ func (eClient Client) Send(req *http.Request, payload []byte) (*http.Response, e
 	return resp, nil
 }
 
+// Deliver sends the event stream to EDP once and leaves retries to the caller
+func (eClient Client) Deliver(dataTenant string, payload []byte) error {
+	req, err := eClient.NewRequest(dataTenant)
+	if err != nil {
+		return err
+	}
+
+	metricTimer := prometheus.NewTimer(sentRequestDuration)
+	req.Body = ioutil.NopCloser(bytes.NewReader(payload))
+	resp, err := eClient.HttpClient.Do(req)
+	metricTimer.ObserveDuration()
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST event to EDP")
+	}
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			eClient.namedLogger().Warn(err)
+		}
+	}()
+
+	totalRequest.WithLabelValues(fmt.Sprintf("%d", resp.StatusCode)).Inc()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to send event stream as EDP returned HTTP: %d", resp.StatusCode)
+	}
+
+	eClient.namedLogger().Debugf("sent an event to '%s' with eventstream: '%s'", req.URL.String(), string(payload))
+	return nil
+}
+
 func (c *Client) namedLogger() *zap.SugaredLogger {
 	return c.Logger.Named(clientName).With("component", "EDP")
 }
@@ -120,6 +120,37 @@ func TestClientRetry(t *testing.T) {
 	g.Expect(testutil.ToFloat64(status500Counter)).Should(gomega.Equal(float64(1)))
 }
 
+func TestClientDeliver(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Resetting any old state
+	totalRequest.Reset()
+	expectedPath := fmt.Sprintf("/namespaces/%s/dataStreams/%s/%s/dataTenants/%s/%s/events", testNamespace, testDataStreamName, testDataStreamVersion, testTenant, testEnv)
+
+	countCalls := 0
+	status := http.StatusServiceUnavailable
+	edpTestHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		g.Expect(req.URL.Path).To(gomega.Equal(expectedPath))
+		countCalls += 1
+		rw.WriteHeader(status)
+	})
+	srv := kmctesting.StartTestServer(expectedPath, edpTestHandler, g)
+	// Close the server when test finishes
+	defer srv.Close()
+
+	edpClient := NewClient(NewTestConfig(srv.URL), logger.NewLogger(zapcore.InfoLevel))
+
+	// Ensure the request is not retried
+	err := edpClient.Deliver(testTenant, []byte("foodata"))
+	g.Expect(err).ShouldNot(gomega.BeNil())
+	g.Expect(err.Error()).Should(gomega.Equal("failed to send event stream as EDP returned HTTP: 503"))
+	g.Expect(countCalls).Should(gomega.Equal(1))
+
+	status = http.StatusCreated
+	err = edpClient.Deliver(testTenant, []byte("foodata"))
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(countCalls).Should(gomega.Equal(2))
+}
+
 func NewTestConfig(url string) *Config {
 	return &Config{
 		URL:               url,
@@ -132,3 +163,14 @@ func NewTestConfig(url string) *Config {
 		EventRetry:        2,
 	}
 }
+
+func TestConfigMaxSendDuration(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// 3 attempts of 1s with the waits of 1s and 5s with 10% jitter in between
+	config := Config{Timeout: time.Second, EventRetry: 3}
+	g.Expect(config.MaxSendDuration()).Should(gomega.Equal(9600 * time.Millisecond))
+
+	config = Config{Timeout: time.Second, EventRetry: 1}
+	g.Expect(config.MaxSendDuration()).Should(gomega.Equal(time.Second))
+}
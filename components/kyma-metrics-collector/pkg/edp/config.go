@@ -2,6 +2,11 @@ package edp
 
 import "time"
 
+const (
+	sendBackoffFactor = 5.0
+	sendBackoffJitter = 0.1
+)
+
 type Config struct {
 	URL               string        `envconfig:"EDP_URL" default:"https://input.yevents.io" required:"true"`
 	Namespace         string        `envconfig:"EDP_NAMESPACE" default:"kyma-dev" required:"true"`
@@ -12,3 +17,29 @@ type Config struct {
 	EventRetry        int           `envconfig:"EDP_RETRY" default:"3"`
 	Token             string
 }
+
+// MaxSendDuration returns the longest time Client.Send can take, when every attempt times out and every wait
+// between the attempts gets the maximum jitter
+func (c Config) MaxSendDuration() time.Duration {
+	var total time.Duration
+	wait := c.Timeout
+	for attempt := 1; attempt <= c.EventRetry; attempt++ {
+		total += c.Timeout
+		if attempt < c.EventRetry {
+			total += time.Duration(float64(wait) * (1 + sendBackoffJitter))
+			wait = time.Duration(float64(wait) * sendBackoffFactor)
+		}
+	}
+	return total
+}
+
+type OutboxConfig struct {
+	// Dir enables the outbox, events are stored in it until they are sent to EDP
+	Dir              string        `envconfig:"EDP_OUTBOX_DIR"`
+	MaxEvents        int           `envconfig:"EDP_OUTBOX_MAX_EVENTS" default:"10000"`
+	MaxAge           time.Duration `envconfig:"EDP_OUTBOX_MAX_AGE" default:"168h"`
+	OverflowPolicy   string        `envconfig:"EDP_OUTBOX_OVERFLOW_POLICY" default:"drop-oldest"`
+	DeliveryInterval time.Duration `envconfig:"EDP_OUTBOX_DELIVERY_INTERVAL" default:"10s"`
+	InitialBackoff   time.Duration `envconfig:"EDP_OUTBOX_INITIAL_BACKOFF" default:"30s"`
+	MaxBackoff       time.Duration `envconfig:"EDP_OUTBOX_MAX_BACKOFF" default:"30m"`
+}
@@ -1,12 +1,36 @@
 package edp
 
+const (
+	// SchemaVersionUsage is the version of the events with the time-weighted usage,
+	// the events without the schema version contain only the snapshot
+	SchemaVersionUsage = "v2"
+)
+
 type ConsumptionMetrics struct {
-	RuntimeId    string     `json:"runtime_id" validate:"required"`
-	SubAccountId string     `json:"sub_account_id" validate:"required"`
-	ShootName    string     `json:"shoot_name" validate:"required"`
-	Timestamp    string     `json:"timestamp" validate:"required"`
-	Compute      Compute    `json:"compute" validate:"required"`
-	Networking   Networking `json:"networking" validate:"required"`
+	SchemaVersion string     `json:"schema_version,omitempty"`
+	RuntimeId     string     `json:"runtime_id" validate:"required"`
+	SubAccountId  string     `json:"sub_account_id" validate:"required"`
+	ShootName     string     `json:"shoot_name" validate:"required"`
+	Timestamp     string     `json:"timestamp" validate:"required"`
+	Compute       Compute    `json:"compute" validate:"required"`
+	Networking    Networking `json:"networking" validate:"required"`
+	Usage         *Usage     `json:"usage,omitempty"`
+}
+
+// Usage is the time-weighted consumption between the previous and the current event of the runtime
+type Usage struct {
+	StartTimestamp string        `json:"start_timestamp" validate:"required"`
+	EndTimestamp   string        `json:"end_timestamp" validate:"required"`
+	CPUHours       float64       `json:"cpu_hours" validate:"numeric"`
+	RAMGbHours     float64       `json:"ram_gb_hours" validate:"numeric"`
+	VolumeGbHours  float64       `json:"volume_gb_hours" validate:"numeric"`
+	IPHours        float64       `json:"ip_hours" validate:"numeric"`
+	VMTypes        []VMTypeUsage `json:"vm_types"`
+}
+
+type VMTypeUsage struct {
+	Name      string  `json:"name" validate:"required"`
+	NodeHours float64 `json:"node_hours" validate:"numeric"`
 }
 type Networking struct {
 	ProvisionedVnets int `json:"provisioned_vnets" validate:"numeric"`
@@ -16,6 +40,9 @@ type Networking struct {
 type VMType struct {
 	Name  string `json:"name" validate:"required"`
 	Count int    `json:"count" validate:"numeric"`
+	// FromNodeCapacity is true when the VM type is missing in the public cloud specs,
+	// and its CPU and memory were taken from the node capacity
+	FromNodeCapacity bool `json:"from_node_capacity,omitempty"`
 }
 
 type Compute struct {
@@ -29,4 +56,18 @@ type ProvisionedVolumes struct {
 	SizeGbTotal   int64 `json:"size_gb_total" validate:"numeric"`
 	Count         int   `json:"count" validate:"numeric"`
 	SizeGbRounded int64 `json:"size_gb_rounded" validate:"numeric"`
+	// StorageClasses breaks down the PVCs by their storage class
+	StorageClasses []VolumeBreakdown `json:"storage_classes,omitempty"`
+	// DiskTypes breaks down the PVCs and the boot volumes of the nodes by the disk type of the provider
+	DiskTypes []VolumeBreakdown `json:"disk_types,omitempty"`
+	// BootVolumes breaks down the boot volumes of the nodes by their worker pool,
+	// they are not part of the totals above
+	BootVolumes []VolumeBreakdown `json:"boot_volumes,omitempty"`
+}
+
+type VolumeBreakdown struct {
+	Name          string `json:"name" validate:"required"`
+	Count         int    `json:"count" validate:"numeric"`
+	SizeGbTotal   int64  `json:"size_gb_total" validate:"numeric"`
+	SizeGbRounded int64  `json:"size_gb_rounded" validate:"numeric"`
 }
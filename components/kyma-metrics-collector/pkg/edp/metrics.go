@@ -30,4 +30,32 @@ var (
 			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10},
 		},
 	)
+
+	outboxEvents = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "outbox_events",
+			Help:      "Number of events in the outbox waiting to be sent to EDP.",
+		},
+	)
+
+	outboxOldestEventAge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "outbox_oldest_event_age_seconds",
+			Help:      "Age of the oldest event in the outbox in seconds.",
+		},
+	)
+
+	outboxDroppedEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "outbox_dropped_events_total",
+			Help:      "Total number of events dropped from the outbox without being sent to EDP.",
+		},
+		[]string{"reason"},
+	)
 )
@@ -0,0 +1,362 @@
+package edp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+const (
+	// OverflowDropOldest drops the oldest event in the outbox to make room for the new one
+	OverflowDropOldest = "drop-oldest"
+	// OverflowReject rejects the new event when the outbox is full
+	OverflowReject = "reject"
+
+	outboxName          = "edp-outbox"
+	outboxEventFileExt  = ".json"
+	outboxTempFileExt   = ".tmp"
+	droppedOverflow     = "overflow"
+	droppedRejected     = "rejected"
+	droppedExpired      = "expired"
+	droppedUnreadable   = "unreadable"
+	outboxSequenceWidth = 20
+)
+
+var ErrOutboxFull = errors.New("EDP outbox is full")
+
+// Sender sends a single event stream to EDP
+type Sender interface {
+	Deliver(dataTenant string, payload []byte) error
+}
+
+// Outbox stores events on disk until they are sent to EDP, so that they are not lost when EDP is unavailable.
+// Events of the same tenant are sent in the order they were added.
+type Outbox struct {
+	config OutboxConfig
+	sender Sender
+	logger *zap.SugaredLogger
+
+	mu       sync.Mutex
+	entries  []outboxEntry
+	nextSeq  uint64
+	backoffs map[string]tenantBackoff
+
+	timeNow func() time.Time
+}
+
+type outboxEntry struct {
+	sequence  uint64
+	tenant    string
+	createdAt time.Time
+}
+
+type outboxEvent struct {
+	Tenant    string    `json:"tenant"`
+	CreatedAt time.Time `json:"createdAt"`
+	Payload   []byte    `json:"payload"`
+}
+
+type tenantBackoff struct {
+	failures    int
+	nextAttempt time.Time
+}
+
+func NewOutbox(config OutboxConfig, sender Sender, logger *zap.SugaredLogger) (*Outbox, error) {
+	if config.OverflowPolicy != OverflowDropOldest && config.OverflowPolicy != OverflowReject {
+		return nil, fmt.Errorf("unknown outbox overflow policy %q", config.OverflowPolicy)
+	}
+	if err := os.MkdirAll(config.Dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create outbox directory")
+	}
+
+	outbox := &Outbox{
+		config:   config,
+		sender:   sender,
+		logger:   logger,
+		nextSeq:  1,
+		backoffs: map[string]tenantBackoff{},
+		timeNow:  time.Now,
+	}
+	if err := outbox.load(); err != nil {
+		return nil, errors.Wrapf(err, "failed to load events from outbox")
+	}
+	outbox.updateMetrics()
+
+	return outbox, nil
+}
+
+// Enqueue persists the event in the outbox, it is sent to EDP by Run
+func (o *Outbox) Enqueue(dataTenant string, payload []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.config.MaxEvents > 0 && len(o.entries) >= o.config.MaxEvents {
+		if o.config.OverflowPolicy == OverflowReject {
+			outboxDroppedEvents.WithLabelValues(droppedRejected).Inc()
+			return ErrOutboxFull
+		}
+		oldest := o.entries[0]
+		o.removeLocked(oldest.sequence)
+		outboxDroppedEvents.WithLabelValues(droppedOverflow).Inc()
+		o.namedLogger().With(log.KeySubAccountID, oldest.tenant).Warn("dropped the oldest event as the outbox is full")
+	}
+
+	entry := outboxEntry{sequence: o.nextSeq, tenant: dataTenant, createdAt: o.timeNow()}
+	data, err := json.Marshal(outboxEvent{Tenant: dataTenant, CreatedAt: entry.createdAt, Payload: payload})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal outbox event")
+	}
+	if err := o.writeFile(entry.sequence, data); err != nil {
+		return errors.Wrapf(err, "failed to store event in outbox")
+	}
+
+	o.nextSeq++
+	o.entries = append(o.entries, entry)
+	o.updateMetricsLocked()
+
+	return nil
+}
+
+// Run delivers the stored events until the stop channel is closed
+func (o *Outbox) Run(stop <-chan struct{}) {
+	wait.Until(o.DeliverEvents, o.config.DeliveryInterval, stop)
+}
+
+// DeliverEvents sends the stored events to EDP. When sending fails, the remaining events of the tenant
+// are not sent until its backoff passes.
+func (o *Outbox) DeliverEvents() {
+	o.mu.Lock()
+	o.expireLocked()
+	entries := make([]outboxEntry, len(o.entries))
+	copy(entries, o.entries)
+	o.mu.Unlock()
+
+	blocked := map[string]bool{}
+	for _, entry := range entries {
+		if blocked[entry.tenant] {
+			continue
+		}
+		if o.inBackoff(entry.tenant) {
+			blocked[entry.tenant] = true
+			continue
+		}
+
+		event, err := o.readFile(entry.sequence)
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				// the event was dropped in the meantime
+				continue
+			}
+			o.namedLogger().With(log.KeyError, err.Error()).With(log.KeySubAccountID, entry.tenant).Error("dropping unreadable event from outbox")
+			o.remove(entry.sequence)
+			outboxDroppedEvents.WithLabelValues(droppedUnreadable).Inc()
+			continue
+		}
+
+		if err := o.sender.Deliver(entry.tenant, event.Payload); err != nil {
+			backoff := o.backOff(entry.tenant)
+			blocked[entry.tenant] = true
+			o.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+				With(log.KeySubAccountID, entry.tenant).With(log.KeyRetry, log.ValueTrue).
+				Warnf("send event from outbox, retrying after %v", backoff)
+			continue
+		}
+
+		o.remove(entry.sequence)
+		o.resetBackoff(entry.tenant)
+		o.namedLogger().With(log.KeyResult, log.ValueSuccess).With(log.KeySubAccountID, entry.tenant).Debug("sent event from outbox")
+	}
+
+	o.updateMetrics()
+}
+
+// Len returns the number of events waiting in the outbox
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+func (o *Outbox) load() error {
+	files, err := os.ReadDir(o.config.Dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasSuffix(name, outboxTempFileExt) {
+			// leftover of an interrupted write, the event was never added to the outbox
+			_ = os.Remove(filepath.Join(o.config.Dir, name))
+			continue
+		}
+		if file.IsDir() || !strings.HasSuffix(name, outboxEventFileExt) {
+			continue
+		}
+
+		sequence, err := strconv.ParseUint(strings.TrimSuffix(name, outboxEventFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		event, err := o.readFile(sequence)
+		if err != nil {
+			o.namedLogger().With(log.KeyError, err.Error()).Errorf("dropping unreadable outbox file %s", name)
+			_ = os.Remove(o.eventPath(sequence))
+			outboxDroppedEvents.WithLabelValues(droppedUnreadable).Inc()
+			continue
+		}
+
+		o.entries = append(o.entries, outboxEntry{sequence: sequence, tenant: event.Tenant, createdAt: event.CreatedAt})
+		if sequence >= o.nextSeq {
+			o.nextSeq = sequence + 1
+		}
+	}
+
+	sort.Slice(o.entries, func(i, j int) bool {
+		return o.entries[i].sequence < o.entries[j].sequence
+	})
+	if len(o.entries) > 0 {
+		o.namedLogger().Infof("loaded %d events from outbox", len(o.entries))
+	}
+
+	return nil
+}
+
+func (o *Outbox) expireLocked() {
+	if o.config.MaxAge <= 0 {
+		return
+	}
+
+	now := o.timeNow()
+	for len(o.entries) > 0 && now.Sub(o.entries[0].createdAt) > o.config.MaxAge {
+		expired := o.entries[0]
+		o.removeLocked(expired.sequence)
+		outboxDroppedEvents.WithLabelValues(droppedExpired).Inc()
+		o.namedLogger().With(log.KeySubAccountID, expired.tenant).Warnf("dropped event older than %v from outbox", o.config.MaxAge)
+	}
+}
+
+func (o *Outbox) remove(sequence uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.removeLocked(sequence)
+}
+
+func (o *Outbox) removeLocked(sequence uint64) {
+	for i, entry := range o.entries {
+		if entry.sequence == sequence {
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			break
+		}
+	}
+
+	if err := os.Remove(o.eventPath(sequence)); err != nil && !os.IsNotExist(err) {
+		o.namedLogger().With(log.KeyError, err.Error()).Error("remove event from outbox")
+	}
+}
+
+func (o *Outbox) inBackoff(tenant string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	backoff, found := o.backoffs[tenant]
+	return found && o.timeNow().Before(backoff.nextAttempt)
+}
+
+func (o *Outbox) backOff(tenant string) time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	backoff := o.backoffs[tenant]
+	delay := o.config.InitialBackoff
+	for i := 0; i < backoff.failures && delay < o.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if o.config.MaxBackoff > 0 && delay > o.config.MaxBackoff {
+		delay = o.config.MaxBackoff
+	}
+
+	backoff.failures++
+	backoff.nextAttempt = o.timeNow().Add(delay)
+	o.backoffs[tenant] = backoff
+
+	return delay
+}
+
+func (o *Outbox) resetBackoff(tenant string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.backoffs, tenant)
+}
+
+func (o *Outbox) writeFile(sequence uint64, data []byte) error {
+	path := o.eventPath(sequence)
+	tempPath := path + outboxTempFileExt
+
+	file, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+func (o *Outbox) readFile(sequence uint64) (outboxEvent, error) {
+	var event outboxEvent
+
+	data, err := os.ReadFile(o.eventPath(sequence))
+	if err != nil {
+		return event, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return event, errors.Wrapf(err, "failed to unmarshal outbox event")
+	}
+
+	return event, nil
+}
+
+func (o *Outbox) eventPath(sequence uint64) string {
+	return filepath.Join(o.config.Dir, fmt.Sprintf("%0*d%s", outboxSequenceWidth, sequence, outboxEventFileExt))
+}
+
+func (o *Outbox) updateMetrics() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.updateMetricsLocked()
+}
+
+func (o *Outbox) updateMetricsLocked() {
+	outboxEvents.Set(float64(len(o.entries)))
+	if len(o.entries) == 0 {
+		outboxOldestEventAge.Set(0)
+		return
+	}
+	outboxOldestEventAge.Set(o.timeNow().Sub(o.entries[0].createdAt).Seconds())
+}
+
+func (o *Outbox) namedLogger() *zap.SugaredLogger {
+	return o.logger.Named(outboxName).With("component", "EDP")
+}
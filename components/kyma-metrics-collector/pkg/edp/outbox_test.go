@@ -0,0 +1,187 @@
+package edp
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+type fakeSender struct {
+	mu        sync.Mutex
+	delivered map[string][]string
+	failing   map[string]bool
+}
+
+func newFakeSender() *fakeSender {
+	return &fakeSender{delivered: map[string][]string{}, failing: map[string]bool{}}
+}
+
+func (s *fakeSender) Deliver(dataTenant string, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failing[dataTenant] {
+		return fmt.Errorf("failed to send event stream as EDP returned HTTP: 503")
+	}
+	s.delivered[dataTenant] = append(s.delivered[dataTenant], string(payload))
+	return nil
+}
+
+func (s *fakeSender) setFailing(dataTenant string, failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing[dataTenant] = failing
+}
+
+func newTestOutboxConfig(dir string) OutboxConfig {
+	return OutboxConfig{
+		Dir:              dir,
+		MaxEvents:        10,
+		MaxAge:           time.Hour,
+		OverflowPolicy:   OverflowDropOldest,
+		DeliveryInterval: time.Second,
+		InitialBackoff:   time.Minute,
+		MaxBackoff:       4 * time.Minute,
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	log := logger.NewLogger(zapcore.InfoLevel)
+
+	t.Run("delivers events of a tenant in order and keeps them until EDP is available", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := time.Now()
+		sender := newFakeSender()
+		sender.setFailing("tenant-a", true)
+
+		outbox, err := NewOutbox(newTestOutboxConfig(t.TempDir()), sender, log)
+		g.Expect(err).Should(gomega.BeNil())
+		outbox.timeNow = func() time.Time { return now }
+
+		g.Expect(outbox.Enqueue("tenant-a", []byte("a1"))).Should(gomega.Succeed())
+		g.Expect(outbox.Enqueue("tenant-b", []byte("b1"))).Should(gomega.Succeed())
+		g.Expect(outbox.Enqueue("tenant-a", []byte("a2"))).Should(gomega.Succeed())
+
+		// EDP fails for tenant-a, other tenants are not blocked
+		outbox.DeliverEvents()
+		g.Expect(sender.delivered["tenant-a"]).Should(gomega.BeEmpty())
+		g.Expect(sender.delivered["tenant-b"]).Should(gomega.Equal([]string{"b1"}))
+		g.Expect(outbox.Len()).Should(gomega.Equal(2))
+		g.Expect(testutil.ToFloat64(outboxEvents)).Should(gomega.Equal(float64(2)))
+
+		// tenant-a is not retried before its backoff passes
+		sender.setFailing("tenant-a", false)
+		outbox.DeliverEvents()
+		g.Expect(sender.delivered["tenant-a"]).Should(gomega.BeEmpty())
+
+		now = now.Add(time.Minute)
+		outbox.DeliverEvents()
+		g.Expect(sender.delivered["tenant-a"]).Should(gomega.Equal([]string{"a1", "a2"}))
+		g.Expect(outbox.Len()).Should(gomega.Equal(0))
+		g.Expect(testutil.ToFloat64(outboxEvents)).Should(gomega.Equal(float64(0)))
+	})
+
+	t.Run("backs off exponentially up to the max backoff", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		sender := newFakeSender()
+
+		outbox, err := NewOutbox(newTestOutboxConfig(t.TempDir()), sender, log)
+		g.Expect(err).Should(gomega.BeNil())
+
+		g.Expect(outbox.backOff("tenant")).Should(gomega.Equal(time.Minute))
+		g.Expect(outbox.backOff("tenant")).Should(gomega.Equal(2 * time.Minute))
+		g.Expect(outbox.backOff("tenant")).Should(gomega.Equal(4 * time.Minute))
+		g.Expect(outbox.backOff("tenant")).Should(gomega.Equal(4 * time.Minute))
+	})
+
+	t.Run("loads stored events after restart", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		dir := t.TempDir()
+		sender := newFakeSender()
+		sender.setFailing("tenant", true)
+
+		outbox, err := NewOutbox(newTestOutboxConfig(dir), sender, log)
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(outbox.Enqueue("tenant", []byte("first"))).Should(gomega.Succeed())
+		g.Expect(outbox.Enqueue("tenant", []byte("second"))).Should(gomega.Succeed())
+		outbox.DeliverEvents()
+
+		sender.setFailing("tenant", false)
+		restarted, err := NewOutbox(newTestOutboxConfig(dir), sender, log)
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(restarted.Len()).Should(gomega.Equal(2))
+		g.Expect(restarted.Enqueue("tenant", []byte("third"))).Should(gomega.Succeed())
+
+		restarted.DeliverEvents()
+		g.Expect(sender.delivered["tenant"]).Should(gomega.Equal([]string{"first", "second", "third"}))
+	})
+
+	t.Run("drops the oldest event when the outbox is full", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		outboxDroppedEvents.Reset()
+		sender := newFakeSender()
+		config := newTestOutboxConfig(t.TempDir())
+		config.MaxEvents = 2
+
+		outbox, err := NewOutbox(config, sender, log)
+		g.Expect(err).Should(gomega.BeNil())
+		for _, payload := range []string{"first", "second", "third"} {
+			g.Expect(outbox.Enqueue("tenant", []byte(payload))).Should(gomega.Succeed())
+		}
+
+		outbox.DeliverEvents()
+		g.Expect(sender.delivered["tenant"]).Should(gomega.Equal([]string{"second", "third"}))
+		g.Expect(testutil.ToFloat64(outboxDroppedEvents.WithLabelValues(droppedOverflow))).Should(gomega.Equal(float64(1)))
+	})
+
+	t.Run("rejects new events when the outbox is full", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		outboxDroppedEvents.Reset()
+		sender := newFakeSender()
+		config := newTestOutboxConfig(t.TempDir())
+		config.MaxEvents = 1
+		config.OverflowPolicy = OverflowReject
+
+		outbox, err := NewOutbox(config, sender, log)
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(outbox.Enqueue("tenant", []byte("first"))).Should(gomega.Succeed())
+		g.Expect(outbox.Enqueue("tenant", []byte("second"))).Should(gomega.MatchError(ErrOutboxFull))
+
+		outbox.DeliverEvents()
+		g.Expect(sender.delivered["tenant"]).Should(gomega.Equal([]string{"first"}))
+		g.Expect(testutil.ToFloat64(outboxDroppedEvents.WithLabelValues(droppedRejected))).Should(gomega.Equal(float64(1)))
+	})
+
+	t.Run("drops events older than the max age", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := time.Now()
+		sender := newFakeSender()
+
+		outbox, err := NewOutbox(newTestOutboxConfig(t.TempDir()), sender, log)
+		g.Expect(err).Should(gomega.BeNil())
+		outbox.timeNow = func() time.Time { return now }
+		g.Expect(outbox.Enqueue("tenant", []byte("old"))).Should(gomega.Succeed())
+
+		now = now.Add(2 * time.Hour)
+		g.Expect(outbox.Enqueue("tenant", []byte("new"))).Should(gomega.Succeed())
+		outbox.DeliverEvents()
+
+		g.Expect(sender.delivered["tenant"]).Should(gomega.Equal([]string{"new"}))
+	})
+
+	t.Run("fails for unknown overflow policy", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		config := newTestOutboxConfig(t.TempDir())
+		config.OverflowPolicy = "block"
+
+		_, err := NewOutbox(config, newFakeSender(), log)
+		g.Expect(err).ShouldNot(gomega.BeNil())
+	})
+}
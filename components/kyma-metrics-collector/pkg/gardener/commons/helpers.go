@@ -2,6 +2,7 @@ package commons
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -18,5 +19,8 @@ func SetupSchemeOrDie() (*runtime.Scheme, error) {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		return nil, err
 	}
+	if err := storagev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
 	return scheme, nil
 }
@@ -0,0 +1,351 @@
+package process
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+const (
+	lifetimeNode   = "node"
+	lifetimeVolume = "volume"
+	lifetimeIP     = "ip"
+
+	usagePrecision = 1e4
+)
+
+// Aggregator tracks the lifetimes of the nodes, volumes and IPs of every runtime, so that the usage between two
+// events is weighted by time instead of being sampled at the time of the event. The lifetimes are observed
+// in the snapshots taken for every event and, when the SKR pool is enabled, in the watch events.
+type Aggregator struct {
+	mu       sync.Mutex
+	runtimes map[string]*runtimeUsage
+
+	timeNow func() time.Time
+}
+
+type runtimeUsage struct {
+	windowStart time.Time
+	// windowEnd is the end of the last aggregated window, the next window starts there once it is committed
+	windowEnd time.Time
+	// aggregated is false until the first aggregation of the runtime
+	aggregated bool
+	alive      map[string]*lifetime
+	ended      []lifetime
+	// inactive are the existing resources which are not counted, e.g. Services which are not of type LoadBalancer
+	inactive map[string]bool
+}
+
+// lifetime is the period in which a resource existed with the same attributes
+type lifetime struct {
+	kind  string
+	start time.Time
+	end   time.Time
+
+	// vmType and capacity are set for nodes
+	vmType   string
+	capacity *Feature
+	// sizeGB is set for volumes
+	sizeGB int64
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		runtimes: map[string]*runtimeUsage{},
+		timeNow:  time.Now,
+	}
+}
+
+// Observe records the changes of the SKR resources, it implements the Observer of the SKR pool
+func (a *Aggregator) Observe(subAccountID string, object k8sruntime.Object, deleted bool) {
+	key, observed, present := lifetimeOf(object)
+	if key == "" {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.timeNow()
+	at := now
+	if deleted {
+		present = false
+		if deletedAt := deletionTimeOf(object); !deletedAt.IsZero() && deletedAt.Before(now) {
+			at = deletedAt
+		}
+	}
+	a.runtimeLocked(subAccountID, now).observe(key, observed, present, !deleted, at)
+}
+
+// Aggregate records the snapshot of the input and returns the time-weighted usage since the last committed
+// window of the runtime. The first window of a runtime starts at since when it is set, e.g. at the end of the last
+// sent usage before a restart, otherwise at the first snapshot.
+func (a *Aggregator) Aggregate(subAccountID string, input Input, providers *Providers, since time.Time) *edp.Usage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.timeNow()
+	windowStart := now
+	if !since.IsZero() && since.Before(now) {
+		windowStart = since
+	}
+	usage := a.runtimeLocked(subAccountID, windowStart)
+	// The runtime could be created by the watch events before its first aggregation
+	if !usage.aggregated && windowStart.Before(usage.windowStart) {
+		usage.windowStart = windowStart
+	}
+	usage.aggregated = true
+	usage.observeSnapshot(input, now)
+
+	providerType := ""
+	if input.shoot != nil {
+		providerType = input.shoot.Spec.Provider.Type
+	}
+	return usage.aggregate(providerType, providers, now)
+}
+
+// Commit starts the next window of the runtime at the end of the last aggregated window. It is called once the usage
+// was sent, so that the usage of a window which failed to be sent is included in the next one.
+func (a *Aggregator) Commit(subAccountID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	usage, found := a.runtimes[subAccountID]
+	if !found || usage.windowEnd.IsZero() {
+		return
+	}
+
+	usage.windowStart = usage.windowEnd
+	usage.windowEnd = time.Time{}
+	ended := usage.ended[:0]
+	for _, l := range usage.ended {
+		if l.end.After(usage.windowStart) {
+			ended = append(ended, l)
+		}
+	}
+	usage.ended = ended
+}
+
+// Forget drops the lifetimes of the runtime which is not tracked anymore
+func (a *Aggregator) Forget(subAccountID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.runtimes, subAccountID)
+}
+
+func (a *Aggregator) runtimeLocked(subAccountID string, windowStart time.Time) *runtimeUsage {
+	usage, found := a.runtimes[subAccountID]
+	if !found {
+		usage = &runtimeUsage{windowStart: windowStart, alive: map[string]*lifetime{}, inactive: map[string]bool{}}
+		a.runtimes[subAccountID] = usage
+	}
+	return usage
+}
+
+// observe starts, ends or splits the lifetime of the resource. A lifetime is split when the attributes
+// of the resource change, e.g. when a volume is resized. The lifetime of a new resource starts at its creation,
+// otherwise at the time of the change.
+func (u *runtimeUsage) observe(key string, observed lifetime, present, exists bool, at time.Time) {
+	current, alive := u.alive[key]
+	if alive && present && current.sameAttributes(observed) {
+		return
+	}
+	if alive {
+		ended := *current
+		ended.end = at
+		if ended.end.Before(ended.start) {
+			ended.end = ended.start
+		}
+		u.ended = append(u.ended, ended)
+		delete(u.alive, key)
+	}
+	if alive || u.inactive[key] {
+		observed.start = at
+	}
+
+	delete(u.inactive, key)
+	switch {
+	case present:
+		u.alive[key] = &observed
+	case exists:
+		u.inactive[key] = true
+	}
+}
+
+// observeSnapshot starts the lifetimes of the resources in the input, and ends the lifetimes of the resources
+// missing in the input
+func (u *runtimeUsage) observeSnapshot(input Input, now time.Time) {
+	existing := map[string]bool{}
+	observe := func(object k8sruntime.Object) {
+		key, observed, present := lifetimeOf(object)
+		if key == "" {
+			return
+		}
+		existing[key] = true
+		u.observe(key, observed, present, true, now)
+	}
+
+	if input.nodeList != nil {
+		for i := range input.nodeList.Items {
+			observe(&input.nodeList.Items[i])
+		}
+	}
+	if input.pvcList != nil {
+		for i := range input.pvcList.Items {
+			observe(&input.pvcList.Items[i])
+		}
+	}
+	if input.svcList != nil {
+		for i := range input.svcList.Items {
+			observe(&input.svcList.Items[i])
+		}
+	}
+
+	for key, current := range u.alive {
+		if !existing[key] {
+			u.observe(key, *current, false, false, now)
+		}
+	}
+	for key := range u.inactive {
+		if !existing[key] {
+			delete(u.inactive, key)
+		}
+	}
+}
+
+// aggregate returns the usage of the lifetimes overlapping the window since the last committed aggregation
+func (u *runtimeUsage) aggregate(providerType string, providers *Providers, now time.Time) *edp.Usage {
+	result := &edp.Usage{
+		StartTimestamp: u.windowStart.Format(time.RFC3339),
+		EndTimestamp:   now.Format(time.RFC3339),
+		VMTypes:        []edp.VMTypeUsage{},
+	}
+	nodeHours := map[string]float64{}
+
+	add := func(l lifetime, end time.Time) {
+		hours := overlap(l.start, end, u.windowStart, now).Hours()
+		if hours <= 0 {
+			return
+		}
+		switch l.kind {
+		case lifetimeNode:
+			nodeHours[l.vmType] += hours
+			feature := l.capacity
+			if providers != nil {
+				if specs := providers.GetFeature(providerType, l.vmType); specs != nil {
+					feature = specs
+				}
+			}
+			if feature != nil {
+				result.CPUHours += float64(feature.CpuCores) * hours
+				result.RAMGbHours += feature.Memory * hours
+			}
+		case lifetimeVolume:
+			result.VolumeGbHours += float64(l.sizeGB) * hours
+		case lifetimeIP:
+			result.IPHours += hours
+		}
+	}
+	for _, l := range u.ended {
+		add(l, l.end)
+	}
+	for _, l := range u.alive {
+		add(*l, now)
+	}
+
+	for vmType, hours := range nodeHours {
+		result.VMTypes = append(result.VMTypes, edp.VMTypeUsage{Name: vmType, NodeHours: round(hours)})
+	}
+	sort.Slice(result.VMTypes, func(i, j int) bool {
+		return result.VMTypes[i].Name < result.VMTypes[j].Name
+	})
+	result.CPUHours = round(result.CPUHours)
+	result.RAMGbHours = round(result.RAMGbHours)
+	result.VolumeGbHours = round(result.VolumeGbHours)
+	result.IPHours = round(result.IPHours)
+
+	u.windowEnd = now
+	return result
+}
+
+func (l lifetime) sameAttributes(other lifetime) bool {
+	if l.kind != other.kind || l.vmType != other.vmType || l.sizeGB != other.sizeGB {
+		return false
+	}
+	if l.capacity == nil || other.capacity == nil {
+		return l.capacity == other.capacity
+	}
+	return *l.capacity == *other.capacity
+}
+
+// lifetimeOf returns the key and the attributes of the resource, and whether it is counted for the usage.
+// Only bound PVCs and Services of type LoadBalancer are counted, as in the snapshot.
+func lifetimeOf(object k8sruntime.Object) (string, lifetime, bool) {
+	switch object := object.(type) {
+	case *corev1.Node:
+		return resourceKey(lifetimeNode, object.Namespace, object.Name, string(object.UID)), lifetime{
+			kind:     lifetimeNode,
+			start:    object.CreationTimestamp.Time,
+			vmType:   strings.ToLower(object.Labels[nodeInstanceTypeLabel]),
+			capacity: getFeatureFromCapacity(*object),
+		}, true
+	case *corev1.PersistentVolumeClaim:
+		observed := lifetime{kind: lifetimeVolume, start: object.CreationTimestamp.Time}
+		if object.Status.Phase == corev1.ClaimBound {
+			observed.sizeGB = getSizeInGB(object.Status.Capacity.Storage())
+		}
+		return resourceKey(lifetimeVolume, object.Namespace, object.Name, string(object.UID)), observed,
+			object.Status.Phase == corev1.ClaimBound
+	case *corev1.Service:
+		return resourceKey(lifetimeIP, object.Namespace, object.Name, string(object.UID)),
+			lifetime{kind: lifetimeIP, start: object.CreationTimestamp.Time},
+			object.Spec.Type == corev1.ServiceTypeLoadBalancer
+	default:
+		return "", lifetime{}, false
+	}
+}
+
+func deletionTimeOf(object k8sruntime.Object) time.Time {
+	var deletionTimestamp *metaV1.Time
+	switch object := object.(type) {
+	case *corev1.Node:
+		deletionTimestamp = object.DeletionTimestamp
+	case *corev1.PersistentVolumeClaim:
+		deletionTimestamp = object.DeletionTimestamp
+	case *corev1.Service:
+		deletionTimestamp = object.DeletionTimestamp
+	}
+	if deletionTimestamp == nil {
+		return time.Time{}
+	}
+	return deletionTimestamp.Time
+}
+
+func resourceKey(kind, namespace, name, uid string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", kind, namespace, name, uid)
+}
+
+// overlap returns the duration in which the lifetime from start to end overlaps the window
+func overlap(start, end, windowStart, windowEnd time.Time) time.Duration {
+	if start.Before(windowStart) {
+		start = windowStart
+	}
+	if end.After(windowEnd) {
+		end = windowEnd
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*usagePrecision) / usagePrecision
+}
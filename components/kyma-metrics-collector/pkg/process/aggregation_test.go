@@ -0,0 +1,211 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/env"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+	kmctesting "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/testing"
+)
+
+func TestAggregator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	providersData, err := kmctesting.LoadFixtureFromFile(providersFile)
+	g.Expect(err).Should(gomega.BeNil())
+	providers, err := LoadPublicCloudSpecs(&env.Config{PublicCloudSpecs: string(providersData)})
+	g.Expect(err).Should(gomega.BeNil())
+
+	start := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	newAggregator := func(now *time.Time) *Aggregator {
+		aggregator := NewAggregator()
+		aggregator.timeNow = func() time.Time { return *now }
+		return aggregator
+	}
+	// aggregate sends the usage successfully
+	aggregate := func(aggregator *Aggregator, input Input) *edp.Usage {
+		usage := aggregator.Aggregate("subaccount", input, providers, time.Time{})
+		aggregator.Commit("subaccount")
+		return usage
+	}
+	newInput := func(nodes *corev1.NodeList, pvcs *corev1.PersistentVolumeClaimList, svcs *corev1.ServiceList) Input {
+		return Input{
+			shoot:    kmctesting.GetShoot("testShoot", kmctesting.WithAzureProviderAndStandardD8V3VMs),
+			nodeList: nodes,
+			pvcList:  pvcs,
+			svcList:  svcs,
+		}
+	}
+
+	t.Run("weights the snapshots by time", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := start
+		aggregator := newAggregator(&now)
+		input := newInput(
+			kmctesting.Get2Nodes(),
+			&corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{*kmctesting.GetPV("pvc1", "default", "20Gi")}},
+			kmctesting.Get2SvcsOfDiffTypes(),
+		)
+
+		usage := aggregate(aggregator, input)
+		g.Expect(*usage).Should(gomega.Equal(edp.Usage{
+			StartTimestamp: "2022-01-01T10:00:00Z",
+			EndTimestamp:   "2022-01-01T10:00:00Z",
+			VMTypes:        []edp.VMTypeUsage{},
+		}))
+
+		now = start.Add(time.Hour)
+		usage = aggregate(aggregator, input)
+		g.Expect(*usage).Should(gomega.Equal(edp.Usage{
+			StartTimestamp: "2022-01-01T10:00:00Z",
+			EndTimestamp:   "2022-01-01T11:00:00Z",
+			CPUHours:       16,
+			RAMGbHours:     64,
+			VolumeGbHours:  20,
+			IPHours:        1,
+			VMTypes:        []edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 2}},
+		}))
+
+		// The node missing in the next snapshot is counted until the snapshot
+		now = start.Add(90 * time.Minute)
+		oneNode := &corev1.NodeList{Items: []corev1.Node{kmctesting.Get2Nodes().Items[0]}}
+		usage = aggregate(aggregator, newInput(oneNode, nil, nil))
+		g.Expect(usage.StartTimestamp).Should(gomega.Equal("2022-01-01T11:00:00Z"))
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 1}}))
+		g.Expect(usage.VolumeGbHours).Should(gomega.Equal(10.0))
+		g.Expect(usage.IPHours).Should(gomega.Equal(0.5))
+
+		now = start.Add(2 * time.Hour)
+		usage = aggregate(aggregator, newInput(oneNode, nil, nil))
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 0.5}}))
+		g.Expect(usage.VolumeGbHours).Should(gomega.BeZero())
+		g.Expect(usage.IPHours).Should(gomega.BeZero())
+	})
+
+	t.Run("splits the lifetimes on the observed changes", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := start
+		aggregator := newAggregator(&now)
+		node := kmctesting.GetNode("node1", "Standard_D8_v3")
+		node.UID = "node1"
+		pvc := kmctesting.GetPV("pvc1", "default", "10Gi")
+		pvc.UID = "pvc1"
+		svc := kmctesting.GetSvc("svc1", "default", kmctesting.WithLoadBalancer)
+		svc.UID = "svc1"
+		input := newInput(&corev1.NodeList{Items: []corev1.Node{node}}, &corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{*pvc}},
+			&corev1.ServiceList{Items: []corev1.Service{*svc}})
+		aggregate(aggregator, input)
+
+		// The LoadBalancer is switched to ClusterIP after 15 minutes
+		now = start.Add(15 * time.Minute)
+		clusterIP := svc.DeepCopy()
+		kmctesting.WithClusterIP(clusterIP)
+		aggregator.Observe("subaccount", clusterIP, false)
+
+		// A second node is created after 20 minutes and deleted after 40 minutes,
+		// the deletion is observed after 45 minutes
+		now = start.Add(20 * time.Minute)
+		newNode := kmctesting.GetNode("node2", "Standard_D8_v3")
+		newNode.UID = "node2"
+		newNode.CreationTimestamp = metaV1.NewTime(now)
+		aggregator.Observe("subaccount", &newNode, false)
+
+		// The volume is resized after 30 minutes
+		now = start.Add(30 * time.Minute)
+		resized := kmctesting.GetPV("pvc1", "default", "20Gi")
+		resized.UID = "pvc1"
+		aggregator.Observe("subaccount", resized, false)
+
+		now = start.Add(45 * time.Minute)
+		deletedNode := newNode.DeepCopy()
+		deletionTimestamp := metaV1.NewTime(start.Add(40 * time.Minute))
+		deletedNode.DeletionTimestamp = &deletionTimestamp
+		aggregator.Observe("subaccount", deletedNode, true)
+
+		now = start.Add(time.Hour)
+		input = newInput(&corev1.NodeList{Items: []corev1.Node{node}}, &corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{*resized}},
+			&corev1.ServiceList{Items: []corev1.Service{*clusterIP}})
+		usage := aggregate(aggregator, input)
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 1.3333}}))
+		g.Expect(usage.CPUHours).Should(gomega.Equal(10.6667))
+		g.Expect(usage.RAMGbHours).Should(gomega.Equal(42.6667))
+		g.Expect(usage.VolumeGbHours).Should(gomega.Equal(15.0))
+		g.Expect(usage.IPHours).Should(gomega.Equal(0.25))
+
+		// The LoadBalancer is counted again from the time it is switched back
+		now = start.Add(90 * time.Minute)
+		aggregator.Observe("subaccount", svc, false)
+		now = start.Add(2 * time.Hour)
+		usage = aggregate(aggregator, newInput(&corev1.NodeList{Items: []corev1.Node{node}}, nil,
+			&corev1.ServiceList{Items: []corev1.Service{*svc}}))
+		g.Expect(usage.IPHours).Should(gomega.Equal(0.5))
+	})
+
+	t.Run("falls back to the node capacity for unknown machine types", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := start
+		aggregator := newAggregator(&now)
+		input := newInput(&corev1.NodeList{Items: []corev1.Node{kmctesting.GetNodeWithCapacity("node1", "foo", "4", "16Gi")}}, nil, nil)
+		aggregate(aggregator, input)
+
+		now = start.Add(30 * time.Minute)
+		usage := aggregate(aggregator, input)
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "foo", NodeHours: 0.5}}))
+		g.Expect(usage.CPUHours).Should(gomega.Equal(2.0))
+		g.Expect(usage.RAMGbHours).Should(gomega.Equal(8.0))
+	})
+
+	t.Run("starts a new window for forgotten runtimes", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := start
+		aggregator := newAggregator(&now)
+		input := newInput(kmctesting.Get2Nodes(), nil, nil)
+		aggregate(aggregator, input)
+
+		aggregator.Forget("subaccount")
+		now = start.Add(time.Hour)
+		usage := aggregate(aggregator, input)
+		g.Expect(usage.StartTimestamp).Should(gomega.Equal("2022-01-01T11:00:00Z"))
+		g.Expect(usage.VMTypes).Should(gomega.BeEmpty())
+		g.Expect(usage.CPUHours).Should(gomega.BeZero())
+	})
+	t.Run("includes the usage of the window which failed to be sent", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := start
+		aggregator := newAggregator(&now)
+		input := newInput(kmctesting.Get2Nodes(), nil, nil)
+		aggregate(aggregator, input)
+
+		// The usage is not committed as the send failed
+		now = start.Add(30 * time.Minute)
+		usage := aggregator.Aggregate("subaccount", input, providers, time.Time{})
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 1}}))
+
+		now = start.Add(time.Hour)
+		usage = aggregate(aggregator, input)
+		g.Expect(usage.StartTimestamp).Should(gomega.Equal("2022-01-01T10:00:00Z"))
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 2}}))
+
+		now = start.Add(90 * time.Minute)
+		usage = aggregate(aggregator, input)
+		g.Expect(usage.StartTimestamp).Should(gomega.Equal("2022-01-01T11:00:00Z"))
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 1}}))
+	})
+
+	t.Run("starts the first window at the end of the last sent usage", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		now := start.Add(time.Hour)
+		aggregator := newAggregator(&now)
+		// The nodes are observed by the watch before the first aggregation
+		nodes := kmctesting.Get2Nodes()
+		aggregator.Observe("subaccount", &nodes.Items[0], false)
+
+		usage := aggregator.Aggregate("subaccount", newInput(nodes, nil, nil), providers, start)
+		g.Expect(usage.StartTimestamp).Should(gomega.Equal("2022-01-01T10:00:00Z"))
+		g.Expect(usage.VMTypes).Should(gomega.Equal([]edp.VMTypeUsage{{Name: "standard_d8_v3", NodeHours: 2}}))
+	})
+}
@@ -0,0 +1,232 @@
+package process
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gorilla/mux"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	kmccache "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/cache"
+	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+const (
+	DebugPathPrefix = "/debug"
+
+	subAccountIDVar = "subAccountID"
+	// maxDryRunBodyBytes limits the size of the uploaded fixtures
+	maxDryRunBodyBytes = 10 << 20
+)
+
+// RuntimeStatuses keeps the time of the last successful scrape and the last error per subAccountID
+type RuntimeStatuses struct {
+	mu       sync.Mutex
+	statuses map[string]runtimeStatus
+	timeNow  func() time.Time
+}
+
+type runtimeStatus struct {
+	lastSuccess time.Time
+	lastError   string
+	lastErrorAt time.Time
+}
+
+func NewRuntimeStatuses() *RuntimeStatuses {
+	return &RuntimeStatuses{
+		statuses: map[string]runtimeStatus{},
+		timeNow:  time.Now,
+	}
+}
+
+// succeeded records that a new metric of the subAccountID was sent
+func (s *RuntimeStatuses) succeeded(subAccountID string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.statuses[subAccountID]
+	status.lastSuccess = s.timeNow()
+	s.statuses[subAccountID] = status
+}
+
+// failed records the error of the last failed scrape or send of the subAccountID
+func (s *RuntimeStatuses) failed(subAccountID string, err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.statuses[subAccountID]
+	status.lastError = err.Error()
+	status.lastErrorAt = s.timeNow()
+	s.statuses[subAccountID] = status
+}
+
+// forget removes the status of the subAccountID which is not tracked anymore
+func (s *RuntimeStatuses) forget(subAccountID string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.statuses, subAccountID)
+}
+
+func (s *RuntimeStatuses) get(subAccountID string) runtimeStatus {
+	if s == nil {
+		return runtimeStatus{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statuses[subAccountID]
+}
+
+// SubAccountStatus is a tracked subAccount shown by the debug API
+type SubAccountStatus struct {
+	SubAccountID string `json:"subAccountID"`
+	RuntimeID    string `json:"runtimeID"`
+	ShootName    string `json:"shootName"`
+	// Owned is false when the subAccount is processed by another replica
+	Owned       bool       `json:"owned"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+// DryRunRequest contains the resources of a runtime to generate a metric from, without sending it
+type DryRunRequest struct {
+	Shoot          *gardencorev1beta1.Shoot          `json:"shoot"`
+	Nodes          *corev1.NodeList                  `json:"nodes"`
+	PVCs           *corev1.PersistentVolumeClaimList `json:"pvcs,omitempty"`
+	Services       *corev1.ServiceList               `json:"services,omitempty"`
+	StorageClasses *storagev1.StorageClassList       `json:"storageClasses,omitempty"`
+}
+
+// DebugHandler serves the debug API, which shows what KMC computed for the runtimes. Every request must carry
+// the token as a bearer token.
+func (p Process) DebugHandler(token string) http.Handler {
+	router := mux.NewRouter()
+	debugRouter := router.PathPrefix(DebugPathPrefix).Subrouter()
+	debugRouter.Path("/subaccounts").Methods(http.MethodGet).HandlerFunc(p.listSubAccounts)
+	debugRouter.Path(fmt.Sprintf("/subaccounts/{%s}/metric", subAccountIDVar)).Methods(http.MethodGet).HandlerFunc(p.getLastMetric)
+	debugRouter.Path(fmt.Sprintf("/subaccounts/{%s}/scrape", subAccountIDVar)).Methods(http.MethodPost).HandlerFunc(p.scrapeSubAccount)
+	debugRouter.Path("/parse").Methods(http.MethodPost).HandlerFunc(p.dryRunParse)
+	return bearerTokenAuth(token, router)
+}
+
+// bearerTokenAuth rejects the requests without the token
+func bearerTokenAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		bearer := strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			http.Error(writer, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// listSubAccounts shows the tracked subAccounts sorted by their IDs
+func (p Process) listSubAccounts(writer http.ResponseWriter, _ *http.Request) {
+	items := p.Cache.Items()
+	subAccounts := make([]SubAccountStatus, 0, len(items))
+	for subAccountID, item := range items {
+		record, ok := item.Object.(kmccache.Record)
+		if !ok {
+			continue
+		}
+		subAccount := SubAccountStatus{
+			SubAccountID: subAccountID,
+			RuntimeID:    record.RuntimeID,
+			ShootName:    record.ShootName,
+			Owned:        p.ownsSubAccount(subAccountID),
+		}
+		status := p.Statuses.get(subAccountID)
+		if !status.lastSuccess.IsZero() {
+			subAccount.LastSuccess = &status.lastSuccess
+		}
+		if status.lastError != "" {
+			subAccount.LastError = status.lastError
+			subAccount.LastErrorAt = &status.lastErrorAt
+		}
+		subAccounts = append(subAccounts, subAccount)
+	}
+	sort.Slice(subAccounts, func(i, j int) bool {
+		return subAccounts[i].SubAccountID < subAccounts[j].SubAccountID
+	})
+	p.writeJSON(writer, http.StatusOK, subAccounts)
+}
+
+// getLastMetric shows the last metric sent for the subAccount
+func (p Process) getLastMetric(writer http.ResponseWriter, request *http.Request) {
+	subAccountID := mux.Vars(request)[subAccountIDVar]
+	obj, found := p.Cache.Get(subAccountID)
+	if !found {
+		http.Error(writer, fmt.Sprintf("subAccountID: %s is not tracked", subAccountID), http.StatusNotFound)
+		return
+	}
+	record, ok := obj.(kmccache.Record)
+	if !ok || record.Metric == nil {
+		http.Error(writer, fmt.Sprintf("no metric sent for subAccountID: %s", subAccountID), http.StatusNotFound)
+		return
+	}
+	p.writeJSON(writer, http.StatusOK, record.Metric)
+}
+
+// scrapeSubAccount queues the subAccount to be scraped immediately instead of after the scrape interval
+func (p Process) scrapeSubAccount(writer http.ResponseWriter, request *http.Request) {
+	subAccountID := mux.Vars(request)[subAccountIDVar]
+	if _, found := p.Cache.Get(subAccountID); !found {
+		http.Error(writer, fmt.Sprintf("subAccountID: %s is not tracked", subAccountID), http.StatusNotFound)
+		return
+	}
+	if !p.ownsSubAccount(subAccountID) {
+		http.Error(writer, fmt.Sprintf("subAccountID: %s is owned by another replica", subAccountID), http.StatusConflict)
+		return
+	}
+	p.Queue.Add(subAccountID)
+	p.namedLogger().With(log.KeySubAccountID, subAccountID).Info("queued subAccountID for scraping from the debug API")
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// dryRunParse generates the metric from the uploaded resources with the active public cloud specs. The metric is
+// neither sent nor stored.
+func (p Process) dryRunParse(writer http.ResponseWriter, request *http.Request) {
+	var dryRun DryRunRequest
+	if err := json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxDryRunBodyBytes)).Decode(&dryRun); err != nil {
+		http.Error(writer, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	input := Input{
+		shoot:            dryRun.Shoot,
+		nodeList:         dryRun.Nodes,
+		pvcList:          dryRun.PVCs,
+		svcList:          dryRun.Services,
+		storageClassList: dryRun.StorageClasses,
+	}
+	metric, err := input.Parse(p.providers())
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("failed to parse: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	p.writeJSON(writer, http.StatusOK, metric)
+}
+
+func (p Process) writeJSON(writer http.ResponseWriter, statusCode int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	if err := json.NewEncoder(writer).Encode(body); err != nil {
+		p.namedLogger().With(log.KeyError, err.Error()).Error("write debug API response")
+	}
+}
@@ -0,0 +1,144 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/env"
+	kmccache "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/cache"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+	kmctesting "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/testing"
+)
+
+const debugToken = "debug-token"
+
+func TestDebugHandler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	providersData, err := kmctesting.LoadFixtureFromFile(providersFile)
+	g.Expect(err).Should(gomega.BeNil())
+	providers, err := LoadPublicCloudSpecs(&env.Config{PublicCloudSpecs: string(providersData)})
+	g.Expect(err).Should(gomega.BeNil())
+
+	now := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	statuses := NewRuntimeStatuses()
+	statuses.timeNow = func() time.Time { return now }
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	g.Expect(cache.Add("subaccount-b", kmccache.Record{SubAccountID: "subaccount-b", RuntimeID: "runtime-b", ShootName: "shoot-b",
+		KubeConfig: "kubeconfig", Metric: NewMetric()}, gocache.NoExpiration)).Should(gomega.Succeed())
+	g.Expect(cache.Add("subaccount-a", kmccache.Record{SubAccountID: "subaccount-a", RuntimeID: "runtime-a", ShootName: "shoot-a"},
+		gocache.NoExpiration)).Should(gomega.Succeed())
+	statuses.succeeded("subaccount-b")
+	statuses.failed("subaccount-a", fmt.Errorf("no nodes to process"))
+
+	queue := workqueue.NewDelayingQueue()
+	defer queue.ShutDown()
+	process := Process{
+		Cache:     cache,
+		Queue:     queue,
+		Providers: providers,
+		Statuses:  statuses,
+		Logger:    logger.NewLogger(zapcore.InfoLevel),
+	}
+	handler := process.DebugHandler(debugToken)
+
+	serve := func(method, path string, body []byte, token string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewReader(body))
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("rejects requests without the token", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		g.Expect(serve(http.MethodGet, "/debug/subaccounts", nil, "").Code).Should(gomega.Equal(http.StatusUnauthorized))
+		g.Expect(serve(http.MethodGet, "/debug/subaccounts", nil, "wrong").Code).Should(gomega.Equal(http.StatusUnauthorized))
+		recorder := httptest.NewRecorder()
+		Process{}.DebugHandler("").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/subaccounts", nil))
+		g.Expect(recorder.Code).Should(gomega.Equal(http.StatusUnauthorized))
+	})
+
+	t.Run("lists the tracked subaccounts", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		recorder := serve(http.MethodGet, "/debug/subaccounts", nil, debugToken)
+		g.Expect(recorder.Code).Should(gomega.Equal(http.StatusOK))
+
+		var subAccounts []SubAccountStatus
+		g.Expect(json.Unmarshal(recorder.Body.Bytes(), &subAccounts)).Should(gomega.Succeed())
+		g.Expect(subAccounts).Should(gomega.Equal([]SubAccountStatus{
+			{SubAccountID: "subaccount-a", RuntimeID: "runtime-a", ShootName: "shoot-a", Owned: true, LastError: "no nodes to process", LastErrorAt: &now},
+			{SubAccountID: "subaccount-b", RuntimeID: "runtime-b", ShootName: "shoot-b", Owned: true, LastSuccess: &now},
+		}))
+	})
+
+	t.Run("shows the last sent metric", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		recorder := serve(http.MethodGet, "/debug/subaccounts/subaccount-b/metric", nil, debugToken)
+		g.Expect(recorder.Code).Should(gomega.Equal(http.StatusOK))
+		var metric edp.ConsumptionMetrics
+		g.Expect(json.Unmarshal(recorder.Body.Bytes(), &metric)).Should(gomega.Succeed())
+		g.Expect(metric).Should(gomega.Equal(*NewMetric()))
+
+		g.Expect(serve(http.MethodGet, "/debug/subaccounts/subaccount-a/metric", nil, debugToken).Code).Should(gomega.Equal(http.StatusNotFound))
+		g.Expect(serve(http.MethodGet, "/debug/subaccounts/unknown/metric", nil, debugToken).Code).Should(gomega.Equal(http.StatusNotFound))
+	})
+
+	t.Run("queues the subaccount for scraping", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		g.Expect(serve(http.MethodPost, "/debug/subaccounts/subaccount-a/scrape", nil, debugToken).Code).Should(gomega.Equal(http.StatusAccepted))
+		g.Expect(queue.Len()).Should(gomega.Equal(1))
+		item, _ := queue.Get()
+		g.Expect(item).Should(gomega.Equal("subaccount-a"))
+		queue.Done(item)
+
+		g.Expect(serve(http.MethodPost, "/debug/subaccounts/unknown/scrape", nil, debugToken).Code).Should(gomega.Equal(http.StatusNotFound))
+		g.Expect(serve(http.MethodGet, "/debug/subaccounts/subaccount-a/scrape", nil, debugToken).Code).Should(gomega.Equal(http.StatusMethodNotAllowed))
+	})
+
+	t.Run("parses the uploaded resources without sending", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		body, err := json.Marshal(DryRunRequest{
+			Shoot:    kmctesting.GetShoot("testShoot", kmctesting.WithAzureProviderAndStandardD8V3VMs),
+			Nodes:    kmctesting.Get2Nodes(),
+			PVCs:     &corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{*kmctesting.GetPV("pvc1", "default", "20Gi")}},
+			Services: kmctesting.Get2SvcsOfDiffTypes(),
+		})
+		g.Expect(err).Should(gomega.BeNil())
+
+		recorder := serve(http.MethodPost, "/debug/parse", body, debugToken)
+		g.Expect(recorder.Code).Should(gomega.Equal(http.StatusOK))
+		var metric edp.ConsumptionMetrics
+		g.Expect(json.Unmarshal(recorder.Body.Bytes(), &metric)).Should(gomega.Succeed())
+		g.Expect(metric.Compute.VMTypes).Should(gomega.Equal([]edp.VMType{{Name: "standard_d8_v3", Count: 2}}))
+		g.Expect(metric.Compute.ProvisionedCpus).Should(gomega.Equal(16))
+		g.Expect(metric.Compute.ProvisionedVolumes.SizeGbTotal).Should(gomega.Equal(int64(20)))
+		g.Expect(metric.Networking.ProvisionedIPs).Should(gomega.Equal(1))
+
+		// Nothing is sent or stored
+		g.Expect(queue.Len()).Should(gomega.Equal(0))
+		g.Expect(cache.ItemCount()).Should(gomega.Equal(2))
+	})
+
+	t.Run("rejects invalid resources", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		g.Expect(serve(http.MethodPost, "/debug/parse", []byte("{"), debugToken).Code).Should(gomega.Equal(http.StatusBadRequest))
+
+		body, err := json.Marshal(DryRunRequest{Shoot: kmctesting.GetShoot("testShoot", kmctesting.WithAzureProviderAndStandardD8V3VMs)})
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(serve(http.MethodPost, "/debug/parse", body, debugToken).Code).Should(gomega.Equal(http.StatusUnprocessableEntity))
+	})
+}
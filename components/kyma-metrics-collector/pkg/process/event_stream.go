@@ -1,6 +1,7 @@
 package process
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"strings"
@@ -19,6 +20,7 @@ import (
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 )
 
 const (
@@ -26,9 +28,10 @@ const (
 	// storageRoundingFactor rounds of storage to 32. E.g. 17 -> 32, 33 -> 64
 	storageRoundingFactor = 32
 
-	Azure = "azure"
-	AWS   = "aws"
-	GCP   = "gcp"
+	Azure     = "azure"
+	AWS       = "aws"
+	GCP       = "gcp"
+	OpenStack = "openstack"
 )
 
 type EventStream struct {
@@ -41,6 +44,8 @@ type Input struct {
 	nodeList *corev1.NodeList
 	pvcList  *corev1.PersistentVolumeClaimList
 	svcList  *corev1.ServiceList
+	// storageClassList is optional, the disk types of the PVCs are unknown without it
+	storageClassList *storagev1.StorageClassList
 }
 
 type NodeInfo struct {
@@ -48,6 +53,16 @@ type NodeInfo struct {
 	memory int
 }
 
+// openStackInfrastructureConfig is the part of the OpenStack InfrastructureConfig used for the metrics
+type openStackInfrastructureConfig struct {
+	Networks struct {
+		// ID of the existing network used instead of creating a new one
+		ID      *string `json:"id,omitempty"`
+		Worker  string  `json:"worker,omitempty"`
+		Workers string  `json:"workers,omitempty"`
+	} `json:"networks"`
+}
+
 func (inp Input) Parse(providers *Providers) (*edp.ConsumptionMetrics, error) {
 
 	if inp.nodeList == nil {
@@ -62,11 +77,17 @@ func (inp Input) Parse(providers *Providers) (*edp.ConsumptionMetrics, error) {
 	provisionedMemory := 0.0
 	providerType := inp.shoot.Spec.Provider.Type
 	vmTypes := make(map[string]int)
+	vmTypesFromCapacity := make(map[string]bool)
 
 	pvcStorage := int64(0)
 	pvcStorageRounded := int64(0)
 	volumeCount := 0
 	vnets := 0
+	classes := newStorageClasses(inp.storageClassList)
+	volumesByClass := volumeBreakdowns{}
+	volumesByDiskType := volumeBreakdowns{}
+	bootVolumes := volumeBreakdowns{}
+	nodesPerWorker := make(map[string]int)
 
 	for _, node := range inp.nodeList.Items {
 		nodeType := node.Labels[nodeInstanceTypeLabel]
@@ -75,11 +96,22 @@ func (inp Input) Parse(providers *Providers) (*edp.ConsumptionMetrics, error) {
 		// Calculate CPU and Memory
 		vmFeature := providers.GetFeature(providerType, nodeType)
 		if vmFeature == nil {
-			return nil, fmt.Errorf("providerType: %s and nodeType: %s does not exist in the map", providerType, nodeType)
+			// The machine type is not in the public cloud specs yet, so the node capacity is used
+			vmFeature = getFeatureFromCapacity(node)
+			if vmFeature == nil {
+				return nil, fmt.Errorf("providerType: %s and nodeType: %s does not exist in the map and node has no capacity", providerType, nodeType)
+			}
+			vmTypesFromCapacity[nodeType] = true
 		}
 		provisionedCPUs += vmFeature.CpuCores
 		provisionedMemory += vmFeature.Memory
 		vmTypes[nodeType] += 1
+		if workerPool := node.Labels[workerPoolLabel]; workerPool != "" {
+			nodesPerWorker[workerPool] += 1
+		}
+	}
+	for nodeType := range vmTypesFromCapacity {
+		unknownMachineTypes.WithLabelValues(providerType, nodeType).Set(1)
 	}
 
 	if inp.pvcList != nil {
@@ -90,10 +122,19 @@ func (inp Input) Parse(providers *Providers) (*edp.ConsumptionMetrics, error) {
 				pvcStorage += currPVC
 				pvcStorageRounded += getVolumeRoundedToFactor(currPVC)
 				volumeCount += 1
+
+				className := classes.classOf(pvc)
+				volumesByClass.add(className, 1, currPVC)
+				volumesByDiskType.add(classes.diskTypeOf(className), 1, currPVC)
 			}
 		}
 	}
 
+	// Calculate storage from the boot volumes of the nodes
+	if err := addBootVolumes(inp.shoot.Spec.Provider.Workers, nodesPerWorker, bootVolumes, volumesByDiskType); err != nil {
+		return nil, err
+	}
+
 	provisionedIPs := 0
 	if inp.svcList != nil {
 		// Calculate network related information
@@ -139,6 +180,15 @@ func (inp Input) Parse(providers *Providers) (*edp.ConsumptionMetrics, error) {
 			if infraConfig.Networks.VPC != nil && infraConfig.Networks.VPC.CloudRouter != nil {
 				vnets += 1
 			}
+		case OpenStack:
+			// The OpenStack provider extension API is not a dependency, so only the used fields are decoded
+			infraConfig := &openStackInfrastructureConfig{}
+			if err := json.Unmarshal(rawExtension.Raw, infraConfig); err != nil {
+				return nil, err
+			}
+			if infraConfig.Networks.ID != nil || infraConfig.Networks.Workers != "" || infraConfig.Networks.Worker != "" {
+				vnets += 1
+			}
 		default:
 			return nil, fmt.Errorf("provider: %s does not match in the system", inp.shoot.Spec.Provider.Type)
 		}
@@ -150,20 +200,38 @@ func (inp Input) Parse(providers *Providers) (*edp.ConsumptionMetrics, error) {
 	metric.Compute.ProvisionedVolumes.SizeGbTotal = pvcStorage
 	metric.Compute.ProvisionedVolumes.SizeGbRounded = pvcStorageRounded
 	metric.Compute.ProvisionedVolumes.Count = volumeCount
+	metric.Compute.ProvisionedVolumes.StorageClasses = volumesByClass.sorted()
+	metric.Compute.ProvisionedVolumes.DiskTypes = volumesByDiskType.sorted()
+	metric.Compute.ProvisionedVolumes.BootVolumes = bootVolumes.sorted()
 
 	metric.Networking.ProvisionedIPs = provisionedIPs
 	metric.Networking.ProvisionedVnets = vnets
 
 	for vmType, count := range vmTypes {
 		metric.Compute.VMTypes = append(metric.Compute.VMTypes, edp.VMType{
-			Name:  vmType,
-			Count: count,
+			Name:             vmType,
+			Count:            count,
+			FromNodeCapacity: vmTypesFromCapacity[vmType],
 		})
 	}
 
 	return metric, nil
 }
 
+// getFeatureFromCapacity derives the CPU and memory of the node from its capacity, CPUs are rounded up
+func getFeatureFromCapacity(node corev1.Node) *Feature {
+	cpu, hasCPU := node.Status.Capacity[corev1.ResourceCPU]
+	memory, hasMemory := node.Status.Capacity[corev1.ResourceMemory]
+	if !hasCPU || !hasMemory || cpu.IsZero() || memory.IsZero() {
+		return nil
+	}
+
+	return &Feature{
+		CpuCores: int(math.Ceil(float64(cpu.MilliValue()) / 1000)),
+		Memory:   float64(memory.Value()) / math.Pow(2, 30),
+	}
+}
+
 // getTimestampNow returns the time now in the format of RFC3339
 func getTimestampNow() string {
 	return time.Now().Format(time.RFC3339)
@@ -3,6 +3,8 @@ package process
 import (
 	"testing"
 
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/env"
@@ -49,6 +51,9 @@ func TestParse(t *testing.T) {
 						SizeGbTotal:   35,
 						Count:         3,
 						SizeGbRounded: 96,
+						// The PVCs have no storage class and there is no default one
+						StorageClasses: []edp.VolumeBreakdown{{Name: "unknown", Count: 3, SizeGbTotal: 35, SizeGbRounded: 96}},
+						DiskTypes:      []edp.VolumeBreakdown{{Name: "unknown", Count: 3, SizeGbTotal: 35, SizeGbRounded: 96}},
 					},
 				},
 				Networking: edp.Networking{
@@ -112,6 +117,171 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with OpenStack with 2 vms and 3 pvcs(5,10 and 20Gi)",
+			input: Input{
+				shoot: kmctesting.GetShoot("testShoot", kmctesting.WithOpenStackProviderAndGC4M16VMs),
+				nodeList: &corev1.NodeList{Items: []corev1.Node{
+					kmctesting.GetNode("node1", "g_c4_m16"),
+					kmctesting.GetNode("node2", "g_c4_m16"),
+				}},
+				pvcList: kmctesting.Get3PVCs(),
+			},
+			providers: *providers,
+			expectedMetrics: edp.ConsumptionMetrics{
+				Compute: edp.Compute{
+					VMTypes: []edp.VMType{{
+						Name:  "g_c4_m16",
+						Count: 2,
+					}},
+					ProvisionedCpus:  8,
+					ProvisionedRAMGb: 32,
+					ProvisionedVolumes: edp.ProvisionedVolumes{
+						SizeGbTotal:   35,
+						Count:         3,
+						SizeGbRounded: 96,
+						// The PVCs have no storage class and there is no default one
+						StorageClasses: []edp.VolumeBreakdown{{Name: "unknown", Count: 3, SizeGbTotal: 35, SizeGbRounded: 96}},
+						DiskTypes:      []edp.VolumeBreakdown{{Name: "unknown", Count: 3, SizeGbTotal: 35, SizeGbRounded: 96}},
+					},
+				},
+				Networking: edp.Networking{
+					ProvisionedVnets: 1,
+					ProvisionedIPs:   0,
+				},
+			},
+		},
+		{
+			name: "with Azure, 3 pvcs of different storage classes and boot volumes",
+			input: Input{
+				shoot: kmctesting.GetShoot("testShoot", kmctesting.WithAzureProviderAndStandardD8V3VMs, kmctesting.WithWorkerVolumes),
+				nodeList: &corev1.NodeList{Items: []corev1.Node{
+					kmctesting.GetNodeInWorkerPool("node1", "Standard_D8_v3", "cpu-worker-0"),
+					kmctesting.GetNodeInWorkerPool("node2", "Standard_D8_v3", "cpu-worker-0"),
+				}},
+				pvcList: &corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{
+					*kmctesting.GetPV("pvc-default", "foo", "10Gi"),
+					*kmctesting.GetPVWithStorageClass("pvc-premium", "foo", "40Gi", "managed-premium"),
+					*kmctesting.GetPVWithStorageClass("pvc-missing", "bar", "5Gi", "missing"),
+				}},
+				storageClassList: kmctesting.GetAzureStorageClasses(),
+			},
+			providers: *providers,
+			expectedMetrics: edp.ConsumptionMetrics{
+				Compute: edp.Compute{
+					VMTypes: []edp.VMType{{
+						Name:  "standard_d8_v3",
+						Count: 2,
+					}},
+					ProvisionedCpus:  16,
+					ProvisionedRAMGb: 64,
+					ProvisionedVolumes: edp.ProvisionedVolumes{
+						SizeGbTotal:   55,
+						Count:         3,
+						SizeGbRounded: 128,
+						StorageClasses: []edp.VolumeBreakdown{
+							{Name: "default", Count: 1, SizeGbTotal: 10, SizeGbRounded: 32},
+							{Name: "managed-premium", Count: 1, SizeGbTotal: 40, SizeGbRounded: 64},
+							{Name: "missing", Count: 1, SizeGbTotal: 5, SizeGbRounded: 32},
+						},
+						DiskTypes: []edp.VolumeBreakdown{
+							{Name: "premium_lrs", Count: 1, SizeGbTotal: 40, SizeGbRounded: 64},
+							{Name: "standardssd_lrs", Count: 3, SizeGbTotal: 110, SizeGbRounded: 160},
+							{Name: "unknown", Count: 1, SizeGbTotal: 5, SizeGbRounded: 32},
+						},
+						BootVolumes: []edp.VolumeBreakdown{
+							{Name: "cpu-worker-0", Count: 2, SizeGbTotal: 100, SizeGbRounded: 128},
+						},
+					},
+				},
+				Networking: edp.Networking{
+					ProvisionedVnets: 1,
+					ProvisionedIPs:   0,
+				},
+			},
+		},
+		{
+			name: "with Azure, pvcs with the beta storage class annotation and without storage class",
+			input: Input{
+				shoot: kmctesting.GetShoot("testShoot", kmctesting.WithAzureProviderAndStandardD8V3VMs),
+				nodeList: &corev1.NodeList{Items: []corev1.Node{
+					kmctesting.GetNodeInWorkerPool("node1", "Standard_D8_v3", "cpu-worker-0"),
+					kmctesting.GetNodeInWorkerPool("node2", "Standard_D8_v3", "cpu-worker-0"),
+				}},
+				pvcList: &corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{
+					*withAnnotations(kmctesting.GetPV("pvc-legacy", "foo", "40Gi"), map[string]string{corev1.BetaStorageClassAnnotation: "managed-premium"}),
+					*kmctesting.GetPVWithStorageClass("pvc-static", "foo", "10Gi", ""),
+				}},
+				storageClassList: kmctesting.GetAzureStorageClasses(),
+			},
+			providers: *providers,
+			expectedMetrics: edp.ConsumptionMetrics{
+				Compute: edp.Compute{
+					VMTypes: []edp.VMType{{
+						Name:  "standard_d8_v3",
+						Count: 2,
+					}},
+					ProvisionedCpus:  16,
+					ProvisionedRAMGb: 64,
+					ProvisionedVolumes: edp.ProvisionedVolumes{
+						SizeGbTotal:   50,
+						Count:         2,
+						SizeGbRounded: 96,
+						StorageClasses: []edp.VolumeBreakdown{
+							{Name: "managed-premium", Count: 1, SizeGbTotal: 40, SizeGbRounded: 64},
+							{Name: "unknown", Count: 1, SizeGbTotal: 10, SizeGbRounded: 32},
+						},
+						DiskTypes: []edp.VolumeBreakdown{
+							{Name: "premium_lrs", Count: 1, SizeGbTotal: 40, SizeGbRounded: 64},
+							{Name: "unknown", Count: 1, SizeGbTotal: 10, SizeGbRounded: 32},
+						},
+					},
+				},
+				Networking: edp.Networking{
+					ProvisionedVnets: 1,
+					ProvisionedIPs:   0,
+				},
+			},
+		},
+		{
+			name: "with Azure and invalid boot volume size",
+			input: Input{
+				shoot: kmctesting.GetShoot("testShoot", kmctesting.WithAzureProviderAndStandardD8V3VMs, func(shoot *gardencorev1beta1.Shoot) {
+					shoot.Spec.Provider.Workers[0].Volume = &gardencorev1beta1.Volume{VolumeSize: "foo"}
+				}),
+				nodeList: &corev1.NodeList{Items: []corev1.Node{
+					kmctesting.GetNodeInWorkerPool("node1", "Standard_D8_v3", "cpu-worker-0"),
+				}},
+			},
+			providers:   *providers,
+			expectedErr: true,
+		},
+		{
+			name: "with Azure and vm type missing from the list of vmtypes but with node capacity",
+			input: Input{
+				shoot: kmctesting.GetShoot("testShoot", kmctesting.WithAzureProviderAndStandardD8V3VMs),
+				nodeList: &corev1.NodeList{Items: []corev1.Node{
+					kmctesting.GetNodeWithCapacity("node1", "Standard_Foo", "3500m", "12Gi"),
+					kmctesting.GetNodeWithCapacity("node2", "Standard_Foo", "3500m", "12Gi"),
+				}},
+			},
+			providers: *providers,
+			expectedMetrics: edp.ConsumptionMetrics{
+				Compute: edp.Compute{
+					VMTypes: []edp.VMType{{
+						Name:             "standard_foo",
+						Count:            2,
+						FromNodeCapacity: true,
+					}},
+					ProvisionedCpus:  8,
+					ProvisionedRAMGb: 24,
+				},
+				Networking: edp.Networking{
+					ProvisionedVnets: 1,
+					ProvisionedIPs:   0,
+				},
+			},
+		},
 		{
 			name: "with Azure and vm type missing from the list of vmtypes",
 			input: Input{
@@ -214,3 +384,8 @@ func TestGetVolumeRoundedToFactor(t *testing.T) {
 		g.Expect(tc.expected).To(gomega.Equal(got))
 	}
 }
+
+func withAnnotations(pvc *corev1.PersistentVolumeClaim, annotations map[string]string) *corev1.PersistentVolumeClaim {
+	pvc.Annotations = annotations
+	return pvc
+}
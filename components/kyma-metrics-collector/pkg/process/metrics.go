@@ -15,4 +15,24 @@ var (
 		},
 		[]string{"requestURI"},
 	)
+
+	unknownMachineTypes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kmc",
+			Subsystem: "process",
+			Name:      "unknown_machine_types",
+			Help:      "Machine types of nodes missing in the public cloud specs since last reload.",
+		},
+		[]string{"provider", "machine_type"},
+	)
+
+	specsReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "process",
+			Name:      "public_cloud_specs_reloads_total",
+			Help:      "Total number of reloads of the public cloud specs.",
+		},
+		[]string{"status"},
+	)
 )
@@ -2,9 +2,7 @@ package process
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -15,38 +13,55 @@ import (
 
 	gardenerv1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	kmccache "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/cache"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
 	gardenersecret "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/gardener/secret"
 	gardenershoot "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/gardener/shoot"
 	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/shard"
 	skrnode "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/node"
+	skrpool "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/pool"
 	skrpvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/pvc"
+	skrstorageclass "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/storageclass"
 	skrsvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/svc"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/pkg/errors"
 
 	kebruntime "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
-	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/sink"
 	"github.com/patrickmn/go-cache"
 )
 
 type Process struct {
-	KEBClient       *keb.Client
-	EDPClient       *edp.Client
-	Queue           workqueue.DelayingInterface
-	ShootClient     *gardenershoot.Client
-	SecretClient    *gardenersecret.Client
-	Cache           *cache.Cache
-	Providers       *Providers
-	ScrapeInterval  time.Duration
-	WorkersPoolSize int
-	NodeConfig      skrnode.ConfigInf
-	PVCConfig       skrpvc.ConfigInf
-	SvcConfig       skrsvc.ConfigInf
-	Logger          *zap.SugaredLogger
+	KEBClient          *keb.Client
+	Sink               sink.Sink
+	Queue              workqueue.DelayingInterface
+	ShootClient        *gardenershoot.Client
+	SecretClient       *gardenersecret.Client
+	Cache              *cache.Cache
+	RecordStore        kmccache.RecordStore
+	RecordSaveInterval time.Duration
+	Providers          *Providers
+	PublicCloudSpecs   *PublicCloudSpecs
+	ScrapeInterval     time.Duration
+	WorkersPoolSize    int
+	NodeConfig         skrnode.ConfigInf
+	PVCConfig          skrpvc.ConfigInf
+	SvcConfig          skrsvc.ConfigInf
+	StorageClassConfig skrstorageclass.ConfigInf
+	SKRPool            *skrpool.Pool
+	Aggregator         *Aggregator
+	Sharder            *shard.Sharder
+	Statuses           *RuntimeStatuses
+	Logger             *zap.SugaredLogger
+
+	// saveRequests triggers saving the records before the next RecordSaveInterval
+	saveRequests chan struct{}
 }
 
 const (
@@ -99,21 +114,90 @@ func (p Process) generateRecordWithNewMetrics(identifier int, subAccountID strin
 		return
 	}
 
-	// Get nodes dynamic client
-	nodesClient, err := p.NodeConfig.NewClient(record.KubeConfig)
+	nodes, pvcList, svcList, storageClassList, err := p.listSKRResources(ctx, record)
 	if err != nil {
 		return
 	}
 
-	// Get nodes
-	var nodes *corev1.NodeList
-	nodes, err = nodesClient.List(ctx)
+	if len(nodes.Items) == 0 {
+		err = fmt.Errorf("no nodes to process")
+		return
+	}
+
+	// Create input
+	input := Input{
+		shoot:    shoot,
+		nodeList: nodes,
+		pvcList:  pvcList,
+		svcList:  svcList,
+		// StorageClasses are used for the disk types of the PVCs
+		storageClassList: storageClassList,
+	}
+	metric, err := input.Parse(p.providers())
 	if err != nil {
 		return
 	}
+	metric.RuntimeId = record.RuntimeID
+	metric.SubAccountId = record.SubAccountID
+	metric.ShootName = record.ShootName
+	if p.Aggregator != nil {
+		metric.SchemaVersion = edp.SchemaVersionUsage
+		metric.Usage = p.Aggregator.Aggregate(record.SubAccountID, input, p.providers(), lastUsageEnd(record.Metric))
+	}
+	record.Metric = metric
+	return
+}
 
-	if len(nodes.Items) == 0 {
-		err = fmt.Errorf("no nodes to process")
+// lastUsageEnd returns the end of the usage in the last sent metric of the runtime, so that the usage since then is
+// not lost when the window of the runtime starts again after a restart
+func lastUsageEnd(metric *edp.ConsumptionMetrics) time.Time {
+	if metric == nil || metric.Usage == nil {
+		return time.Time{}
+	}
+	end, err := time.Parse(time.RFC3339, metric.Usage.EndTimestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return end
+}
+
+// listSKRResources gets the nodes, PVCs, Services and StorageClasses of the runtime from the SKR pool when it is set
+// and has room for the runtime, otherwise they are listed from the SKR
+func (p Process) listSKRResources(ctx context.Context, record kmccache.Record) (nodes *corev1.NodeList, pvcList *corev1.PersistentVolumeClaimList, svcList *corev1.ServiceList, storageClassList *storagev1.StorageClassList, err error) {
+	var runtimeCache *skrpool.RuntimeCache
+	if p.SKRPool != nil {
+		runtimeCache, err = p.SKRPool.Get(record.SubAccountID, record.KubeConfig)
+		if errors.Is(err, skrpool.ErrPoolFull) {
+			err = nil
+		} else if err != nil {
+			return
+		}
+	}
+	if runtimeCache != nil {
+		if nodes, err = runtimeCache.Nodes(); err != nil {
+			return
+		}
+		if pvcList, err = runtimeCache.PVCs(); err != nil {
+			return
+		}
+		if svcList, err = runtimeCache.Services(); err != nil {
+			return
+		}
+		storageClassList, err = runtimeCache.StorageClasses()
+		if err != nil {
+			p.logStorageClassesFailure(record, err)
+			storageClassList, err = nil, nil
+		}
+		return
+	}
+
+	// Get nodes
+	nodesClient, err := p.NodeConfig.NewClient(record.KubeConfig)
+	if err != nil {
+		return
+	}
+	nodes, err = nodesClient.List(ctx)
+	if err != nil {
 		return
 	}
 
@@ -122,39 +206,53 @@ func (p Process) generateRecordWithNewMetrics(identifier int, subAccountID strin
 	if err != nil {
 		return
 	}
-	var pvcList *corev1.PersistentVolumeClaimList
 	pvcList, err = pvcClient.List(ctx)
 	if err != nil {
 		return
 	}
 
 	// Get Svcs
-	var svcList *corev1.ServiceList
 	svcClient, err := p.SvcConfig.NewClient(record.KubeConfig)
 	if err != nil {
 		return
 	}
 	svcList, err = svcClient.List(ctx)
-	if err != nil {
+	if err != nil || p.StorageClassConfig == nil {
 		return
 	}
 
-	// Create input
-	input := Input{
-		shoot:    shoot,
-		nodeList: nodes,
-		pvcList:  pvcList,
-		svcList:  svcList,
+	// Get StorageClasses
+	storageClassList = p.listStorageClasses(ctx, record)
+	return
+}
+
+// listStorageClasses lists the StorageClasses of the runtime. They are used only for the disk types, so when they cannot
+// be listed, the failure is logged and nil is returned, and the disk types of the PVCs are unknown instead of failing the scrape.
+func (p Process) listStorageClasses(ctx context.Context, record kmccache.Record) *storagev1.StorageClassList {
+	storageClassClient, err := p.StorageClassConfig.NewClient(record.KubeConfig)
+	if err != nil {
+		p.logStorageClassesFailure(record, err)
+		return nil
 	}
-	metric, err := input.Parse(p.Providers)
+	storageClassList, err := storageClassClient.List(ctx)
 	if err != nil {
-		return
+		p.logStorageClassesFailure(record, err)
+		return nil
 	}
-	metric.RuntimeId = record.RuntimeID
-	metric.SubAccountId = record.SubAccountID
-	metric.ShootName = record.ShootName
-	record.Metric = metric
-	return
+	return storageClassList
+}
+
+func (p Process) logStorageClassesFailure(record kmccache.Record, err error) {
+	p.namedLogger().With(log.KeySubAccountID, record.SubAccountID).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+		Warn("get StorageClasses, continuing with unknown disk types")
+}
+
+// providers returns the reloadable public cloud specs when they are set, otherwise the static ones
+func (p Process) providers() *Providers {
+	if p.PublicCloudSpecs != nil {
+		return p.PublicCloudSpecs.Providers()
+	}
+	return p.Providers
 }
 
 // getOldRecordIfMetricExists gets old record from cache if old metric exists
@@ -168,6 +266,12 @@ func (p Process) getOldRecordIfMetricExists(subAccountID string) (*kmccache.Reco
 
 	if oldRecord, ok := oldRecordObj.(kmccache.Record); ok {
 		if oldRecord.Metric != nil {
+			if oldRecord.Metric.Usage != nil {
+				// The usage of the old metric was already sent, it is included in the next new metric instead
+				metric := *oldRecord.Metric
+				metric.Usage = nil
+				oldRecord.Metric = &metric
+			}
 			return &oldRecord, nil
 		}
 	}
@@ -206,6 +310,17 @@ func (p *Process) pollKEBForRuntimes() {
 func (p Process) Start() {
 
 	var wg sync.WaitGroup
+	if p.RecordStore != nil {
+		p.restoreRecords()
+		p.saveRequests = make(chan struct{}, 1)
+		go p.runRecordSaver(wait.NeverStop)
+	}
+
+	if p.Sharder != nil {
+		p.Sharder.OnRebalance(p.queueAcquiredSubAccounts)
+		go p.Sharder.Run(wait.NeverStop)
+	}
+
 	go func() {
 		p.pollKEBForRuntimes()
 	}()
@@ -242,7 +357,6 @@ func (p *Process) execute(identifier int) {
 }
 
 func (p Process) processSubAccountID(subAccountID string, identifier int) {
-	var payload []byte
 	if strings.TrimSpace(subAccountID) == "" {
 		p.namedLogger().With(log.KeyWorkerID, identifier).Warn("cannot work with empty subAccountID")
 
@@ -252,6 +366,11 @@ func (p Process) processSubAccountID(subAccountID string, identifier int) {
 	p.namedLogger().With(log.KeySubAccountID, subAccountID).With(log.KeyWorkerID, identifier).
 		Debug("fetched subAccountID from queue")
 
+	if !p.ownsSubAccount(subAccountID) {
+		p.skipSubAccount(subAccountID, identifier)
+		return
+	}
+
 	record, isOldMetricValid, err := p.getRecordWithOldOrNewMetric(identifier, subAccountID)
 	if err != nil {
 		p.namedLoggerWithRuntime(record).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).With(log.KeyWorkerID, identifier).
@@ -270,31 +389,21 @@ func (p Process) processSubAccountID(subAccountID string, identifier int) {
 		return
 	}
 
-	// Convert metric to JSON
-	payload, err = json.Marshal(*record.Metric)
-	if err != nil {
-		p.namedLoggerWithRuntime(record).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
-			With(log.KeySubAccountID, subAccountID).With(log.KeyWorkerID, identifier).
-			Error("json.Marshal metric for subAccountID")
-
-		p.Queue.AddAfter(subAccountID, p.ScrapeInterval)
-		p.namedLoggerWithRuntime(record).With(log.KeyResult, log.ValueSuccess).With(log.KeyRequeue, log.ValueTrue).
-			With(log.KeySubAccountID, subAccountID).With(log.KeyWorkerID, identifier).
-			Debugf("requeued subAccountID after %v", p.ScrapeInterval)
-
-		// Nothing to do further
+	// The subAccountID could be taken over by another replica while the metric was generated
+	if !p.ownsSubAccount(subAccountID) {
+		p.skipSubAccount(subAccountID, identifier)
 		return
 	}
 
-	// Send metrics to EDP
-	// Note: EDP refers SubAccountID as tenant
+	// Send metrics to the sinks
 	p.namedLoggerWithRuntime(record).With(log.KeySubAccountID, subAccountID).
-		With(log.KeyWorkerID, identifier).Debugf("sending EventStreamToEDP: payload: %s", string(payload))
-	err = p.sendEventStreamToEDP(subAccountID, payload)
+		With(log.KeyWorkerID, identifier).Debugf("sending metric: %+v", *record.Metric)
+	err = p.Sink.Send(*record.Metric)
 	if err != nil {
 		p.namedLoggerWithRuntime(record).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
 			With(log.KeySubAccountID, subAccountID).With(log.KeyWorkerID, identifier).
-			Errorf("send metric to EDP for event-stream: %s", string(payload))
+			Error("send metric to sinks")
+		p.Statuses.failed(subAccountID, err)
 
 		p.Queue.AddAfter(subAccountID, p.ScrapeInterval)
 		p.namedLoggerWithRuntime(record).With(log.KeyResult, log.ValueSuccess).With(log.KeyRequeue, log.ValueTrue).
@@ -305,12 +414,20 @@ func (p Process) processSubAccountID(subAccountID string, identifier int) {
 		return
 	}
 	p.namedLoggerWithRuntime(record).With(log.KeyResult, log.ValueSuccess).With(log.KeySubAccountID, subAccountID).
-		With(log.KeyWorkerID, identifier).Infof("sent event stream, shoot: %s", record.ShootName)
+		With(log.KeyWorkerID, identifier).Infof("sent metric, shoot: %s", record.ShootName)
 
 	if !isOldMetricValid {
+		if p.Aggregator != nil {
+			p.Aggregator.Commit(subAccountID)
+		}
+		p.Statuses.succeeded(subAccountID)
 		p.Cache.Set(record.SubAccountID, *record, cache.NoExpiration)
 		p.namedLoggerWithRuntime(record).With(log.KeyResult, log.ValueSuccess).With(log.KeySubAccountID, record.SubAccountID).
 			With(log.KeyWorkerID, identifier).Debug("saved metric")
+		// The window of the next usage starts from the end of the sent usage, also after a restart
+		if record.Metric.Usage != nil {
+			p.requestRecordsSave()
+		}
 	}
 
 	// Requeue the subAccountID anyway
@@ -320,6 +437,38 @@ func (p Process) processSubAccountID(subAccountID string, identifier int) {
 	p.Queue.AddAfter(subAccountID, p.ScrapeInterval)
 }
 
+// ownsSubAccount returns whether the subAccountID is processed by this replica
+func (p Process) ownsSubAccount(subAccountID string) bool {
+	return p.Sharder == nil || p.Sharder.Owns(subAccountID)
+}
+
+// skipSubAccount stops processing the subAccountID owned by another replica. It is not requeued,
+// but queued again when the replica owns it after a rebalance.
+func (p Process) skipSubAccount(subAccountID string, identifier int) {
+	p.forgetSubAccount(subAccountID)
+	p.dropLastUsage(subAccountID)
+	p.namedLogger().With(log.KeyRequeue, log.ValueFalse).With(log.KeySubAccountID, subAccountID).
+		With(log.KeyWorkerID, identifier).Debug("subAccountID is owned by another replica")
+}
+
+// dropLastUsage removes the usage from the last metric of the subAccountID owned by another replica. The other
+// replica bills the usage since then, so the window does not start from it again when the replica owns the
+// subAccountID after another rebalance.
+func (p Process) dropLastUsage(subAccountID string) {
+	item, found := p.Cache.Get(subAccountID)
+	if !found {
+		return
+	}
+	record, ok := item.(kmccache.Record)
+	if !ok || record.Metric == nil || record.Metric.Usage == nil {
+		return
+	}
+	metric := *record.Metric
+	metric.Usage = nil
+	record.Metric = &metric
+	p.Cache.Set(subAccountID, record, cache.NoExpiration)
+}
+
 // getRecordWithOldOrNewMetric generates new metric or fetches the old metric along with a bool flag which
 // indicates whether it is an old metric or not(true, when it is old and false when it is new)
 func (p Process) getRecordWithOldOrNewMetric(identifier int, subAccountID string) (*kmccache.Record, bool, error) {
@@ -332,6 +481,7 @@ func (p Process) getRecordWithOldOrNewMetric(identifier int, subAccountID string
 		}
 		p.namedLoggerWithRuntime(&record).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).With(log.KeySubAccountID, subAccountID).
 			Error("generate new metric for subAccount")
+		p.Statuses.failed(subAccountID, err)
 		// Get old data
 		oldRecord, err := p.getOldRecordIfMetricExists(subAccountID)
 		if err != nil {
@@ -343,30 +493,6 @@ func (p Process) getRecordWithOldOrNewMetric(identifier int, subAccountID string
 	return &record, false, nil
 }
 
-func (p Process) sendEventStreamToEDP(tenant string, payload []byte) error {
-	edpRequest, err := p.EDPClient.NewRequest(tenant)
-	if err != nil {
-		return errors.Wrapf(err, "failed to create a new request for EDP")
-	}
-
-	resp, err := p.EDPClient.Send(edpRequest, payload)
-	if err != nil {
-		return errors.Wrapf(err, "failed to send event-stream to EDP")
-	}
-
-	if !isSuccess(resp.StatusCode) {
-		return fmt.Errorf("failed to send event-stream to EDP as it returned HTTP: %d", resp.StatusCode)
-	}
-	return nil
-}
-
-func isSuccess(status int) bool {
-	if status >= http.StatusOK && status < http.StatusMultipleChoices {
-		return true
-	}
-	return false
-}
-
 func isClusterTrackable(runtime *kebruntime.RuntimeDTO) bool {
 	if runtime.Status.Provisioning != nil &&
 		runtime.Status.Provisioning.State == "succeeded" &&
@@ -423,6 +549,7 @@ func (p *Process) populateCacheAndQueue(runtimes *kebruntime.RuntimesPage) {
 		if isFoundInCache {
 			// Cluster is not trackable but is found in cache should be deleted
 			p.Cache.Delete(runtime.SubAccountID)
+			p.forgetSubAccount(runtime.SubAccountID)
 			p.namedLogger().With(log.KeySubAccountID, runtime.SubAccountID).
 				With(log.KeyRuntimeID, runtime.RuntimeID).Debug("Deleted subAccount from cache")
 			continue
@@ -436,6 +563,7 @@ func (p *Process) populateCacheAndQueue(runtimes *kebruntime.RuntimesPage) {
 		if _, ok := validSubAccounts[sAccID]; !ok {
 			record, ok := recordObj.Object.(kmccache.Record)
 			p.Cache.Delete(sAccID)
+			p.forgetSubAccount(sAccID)
 			if !ok {
 				p.namedLogger().With(log.KeySubAccountID, sAccID).
 					Error("bad item from cache, could not cast to a record obj")
@@ -447,6 +575,100 @@ func (p *Process) populateCacheAndQueue(runtimes *kebruntime.RuntimesPage) {
 	}
 }
 
+// queueAcquiredSubAccounts queues the subAccountIDs from the cache which this replica owns after a rebalance,
+// but did not own before
+func (p *Process) queueAcquiredSubAccounts(wasOwned func(subAccountID string) bool) {
+	queued := 0
+	for subAccountID := range p.Cache.Items() {
+		if p.Sharder.Owns(subAccountID) && !wasOwned(subAccountID) {
+			p.Queue.Add(subAccountID)
+			queued++
+		}
+	}
+	p.namedLogger().Infof("queued %d subAccountIDs owned after rebalance", queued)
+}
+
+// restoreRecords adds the stored records with the last metrics to the cache and queues their subAccountIDs.
+// The subAccounts which are not returned by KEB anymore are removed from the cache once KEB is polled.
+func (p *Process) restoreRecords() {
+	records, err := p.RecordStore.Load()
+	if err != nil {
+		p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			Error("restore records from the store")
+		return
+	}
+
+	restored := 0
+	for _, record := range records {
+		if record.SubAccountID == "" {
+			continue
+		}
+		if err := p.Cache.Add(record.SubAccountID, record, cache.NoExpiration); err != nil {
+			continue
+		}
+		p.Queue.Add(record.SubAccountID)
+		restored++
+	}
+	p.namedLogger().Infof("restored %d records from the store", restored)
+}
+
+// runRecordSaver saves the records every RecordSaveInterval and when it is requested, until the stop channel is closed.
+// The records are saved by this goroutine only, so the requests made while saving are coalesced into one save.
+func (p *Process) runRecordSaver(stop <-chan struct{}) {
+	ticker := time.NewTicker(p.RecordSaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-p.saveRequests:
+		}
+		p.saveRecords()
+	}
+}
+
+// requestRecordsSave lets the records be saved without waiting for the next RecordSaveInterval
+func (p Process) requestRecordsSave() {
+	select {
+	case p.saveRequests <- struct{}{}:
+	default:
+	}
+}
+
+// saveRecords stores the records from the cache, so that the last metrics are not lost when KMC restarts
+func (p *Process) saveRecords() {
+	items := p.Cache.Items()
+	records := make([]kmccache.Record, 0, len(items))
+	for _, item := range items {
+		if record, ok := item.Object.(kmccache.Record); ok {
+			records = append(records, record)
+		}
+	}
+
+	if err := p.RecordStore.Save(records); err != nil {
+		p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			Error("save records in the store")
+		return
+	}
+	p.namedLogger().Debugf("saved %d records in the store", len(records))
+}
+
+// forgetSubAccount lets the sinks drop the state of the subAccount which is not tracked anymore,
+// stops watching its SKR, and drops its status
+func (p *Process) forgetSubAccount(subAccountID string) {
+	if p.SKRPool != nil {
+		p.SKRPool.Remove(subAccountID)
+	}
+	if p.Aggregator != nil {
+		p.Aggregator.Forget(subAccountID)
+	}
+	p.Statuses.forget(subAccountID)
+	if forgetter, ok := p.Sink.(sink.Forgetter); ok {
+		forgetter.Forget(subAccountID)
+	}
+}
+
 func (p *Process) namedLogger() *zap.SugaredLogger {
 	return p.Logger.With("component", "kmc")
 }
@@ -1,6 +1,7 @@
 package process
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	skrnode "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/node"
 	skrpvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/pvc"
+	skrstorageclass "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/storageclass"
 	skrsvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/svc"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 
@@ -30,6 +32,8 @@ import (
 
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
 	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/shard"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/sink"
 
 	"github.com/google/uuid"
 
@@ -365,6 +369,80 @@ func TestPopulateCacheAndQueue(t *testing.T) {
 	})
 }
 
+func TestRestoreAndSaveRecords(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	subAccID := uuid.New().String()
+	record := kmccache.Record{
+		SubAccountID: subAccID,
+		RuntimeID:    uuid.New().String(),
+		ShootName:    fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5)),
+		Metric:       NewMetric(),
+	}
+	store := kmccache.NewMemoryStore()
+	g.Expect(store.Save([]kmccache.Record{record})).Should(gomega.Succeed())
+
+	p := Process{
+		Queue:       workqueue.NewDelayingQueue(),
+		Cache:       gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		RecordStore: store,
+		Logger:      logger.NewLogger(zapcore.InfoLevel),
+	}
+
+	// Restored records are queued and their last metrics can be used
+	p.restoreRecords()
+	g.Expect(p.Queue.Len()).Should(gomega.Equal(1))
+	gotSubAccID, _ := p.Queue.Get()
+	g.Expect(gotSubAccID).Should(gomega.Equal(subAccID))
+	oldRecord, err := p.getOldRecordIfMetricExists(subAccID)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(*oldRecord).Should(gomega.Equal(record))
+
+	// Records are saved without kubeconfigs
+	withKubeconfig := record
+	withKubeconfig.KubeConfig = "kubeconfig"
+	p.Cache.Set(subAccID, withKubeconfig, gocache.NoExpiration)
+	p.saveRecords()
+	saved, err := store.Load()
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(saved).Should(gomega.Equal([]kmccache.Record{record}))
+}
+
+func TestRecordSaver(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	subAccID := uuid.New().String()
+	record := kmccache.Record{
+		SubAccountID: subAccID,
+		Metric:       NewMetric(),
+	}
+	store := kmccache.NewMemoryStore()
+	p := Process{
+		Queue:              workqueue.NewDelayingQueue(),
+		Cache:              gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		RecordStore:        store,
+		RecordSaveInterval: time.Hour,
+		Logger:             logger.NewLogger(zapcore.InfoLevel),
+		saveRequests:       make(chan struct{}, 1),
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go p.runRecordSaver(stop)
+
+	// The records are saved on request without waiting for the save interval,
+	// so the last sent usage is not billed again after a restart
+	p.Cache.Set(subAccID, record, gocache.NoExpiration)
+	p.requestRecordsSave()
+	g.Eventually(store.Load, timeout).Should(gomega.Equal([]kmccache.Record{record}))
+
+	// Requests made while a save is pending do not block
+	for i := 0; i < 3; i++ {
+		p.requestRecordsSave()
+	}
+
+	// Requests are ignored when there is no record store
+	withoutStore := Process{}
+	withoutStore.requestRecordsSave()
+}
+
 func TestExecute(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	subAccID := uuid.New().String()
@@ -432,7 +510,7 @@ func TestExecute(t *testing.T) {
 	fakeSvcClient := skrsvc.FakeSvcClient{}
 
 	newProcess := &Process{
-		EDPClient:      edpClient,
+		Sink:           sink.NewEDPSink(edpClient, nil),
 		Queue:          queue,
 		ShootClient:    shootClient,
 		SecretClient:   secretClient,
@@ -443,6 +521,8 @@ func TestExecute(t *testing.T) {
 		NodeConfig:     fakeNodeClient,
 		PVCConfig:      fakePVCClient,
 		SvcConfig:      fakeSvcClient,
+
+		StorageClassConfig: skrstorageclass.FakeStorageClassClient{},
 	}
 
 	go func() {
@@ -498,6 +578,83 @@ func TestExecute(t *testing.T) {
 	g.Eventually(newProcess.Queue.Len()).Should(gomega.Equal(0))
 }
 
+func TestProcessSubAccountIDOwnedByAnotherReplica(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	subAccID := uuid.New().String()
+	leases := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()).Resource(shard.LeaseGroupVersionResource()).Namespace("kcp-system")
+	sharder, err := shard.NewSharder(shard.Config{
+		Group:         "kmc",
+		Identity:      "kmc-a",
+		LeaseDuration: time.Minute,
+		RenewInterval: 10 * time.Second,
+	}, leases, logger.NewLogger(zapcore.InfoLevel))
+	g.Expect(err).Should(gomega.BeNil())
+
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	g.Expect(cache.Add(subAccID, kmccache.Record{SubAccountID: subAccID}, gocache.NoExpiration)).Should(gomega.Succeed())
+	p := Process{
+		Queue:   workqueue.NewDelayingQueue(),
+		Cache:   cache,
+		Sharder: sharder,
+		Logger:  logger.NewLogger(zapcore.InfoLevel),
+	}
+
+	// The replica does not own any subAccountID before its lease is held for the lease duration,
+	// so the subAccountID is neither scraped nor requeued
+	p.processSubAccountID(subAccID, 1)
+	g.Expect(p.Queue.Len()).Should(gomega.Equal(0))
+	record, found := p.Cache.Get(subAccID)
+	g.Expect(found).Should(gomega.BeTrue())
+	g.Expect(record).Should(gomega.Equal(kmccache.Record{SubAccountID: subAccID}))
+}
+
+func TestProcessSubAccountIDTakenOverByAnotherReplica(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	subAccID := uuid.New().String()
+	leases := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()).Resource(shard.LeaseGroupVersionResource()).Namespace("kcp-system")
+	sharder, err := shard.NewSharder(shard.Config{
+		Group:         "kmc",
+		Identity:      "kmc-a",
+		LeaseDuration: time.Minute,
+		RenewInterval: 10 * time.Second,
+	}, leases, logger.NewLogger(zapcore.InfoLevel))
+	g.Expect(err).Should(gomega.BeNil())
+
+	metric := &edp.ConsumptionMetrics{
+		RuntimeId: "runtime-id",
+		Usage: &edp.Usage{
+			StartTimestamp: "2022-01-01T10:00:00Z",
+			EndTimestamp:   "2022-01-01T11:00:00Z",
+		},
+	}
+	g.Expect(lastUsageEnd(metric).IsZero()).Should(gomega.BeFalse())
+
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	g.Expect(cache.Add(subAccID, kmccache.Record{SubAccountID: subAccID, Metric: metric}, gocache.NoExpiration)).Should(gomega.Succeed())
+	p := Process{
+		Queue:      workqueue.NewDelayingQueue(),
+		Cache:      cache,
+		Sharder:    sharder,
+		Aggregator: NewAggregator(),
+		Logger:     logger.NewLogger(zapcore.InfoLevel),
+	}
+
+	// The other replica bills the usage while it owns the subAccountID, so the window does not start
+	// from the last usage sent by this replica when the subAccountID is owned again after a rebalance
+	p.processSubAccountID(subAccID, 1)
+	g.Expect(p.Queue.Len()).Should(gomega.Equal(0))
+	item, found := p.Cache.Get(subAccID)
+	g.Expect(found).Should(gomega.BeTrue())
+	record := item.(kmccache.Record)
+	g.Expect(record.Metric).ShouldNot(gomega.BeNil())
+	g.Expect(record.Metric.RuntimeId).Should(gomega.Equal("runtime-id"))
+	g.Expect(record.Metric.Usage).Should(gomega.BeNil())
+	g.Expect(lastUsageEnd(record.Metric).IsZero()).Should(gomega.BeTrue())
+
+	// The metric shared with the previous record is not changed
+	g.Expect(metric.Usage).ShouldNot(gomega.BeNil())
+}
+
 func NewFakeShootClient(shoot *gardenerv1beta1.Shoot) (*gardenershoot.Client, error) {
 	scheme, err := commons.SetupSchemeOrDie()
 	if err != nil {
@@ -613,6 +770,9 @@ func NewMetric() *edp.ConsumptionMetrics {
 				SizeGbTotal:   30,
 				Count:         2,
 				SizeGbRounded: 64,
+				// The PVCs have no storage class, so the default one is used
+				StorageClasses: []edp.VolumeBreakdown{{Name: "default", Count: 2, SizeGbTotal: 30, SizeGbRounded: 64}},
+				DiskTypes:      []edp.VolumeBreakdown{{Name: "standardssd_lrs", Count: 2, SizeGbTotal: 30, SizeGbRounded: 64}},
 			},
 		},
 		Networking: edp.Networking{
@@ -621,3 +781,28 @@ func NewMetric() *edp.ConsumptionMetrics {
 		},
 	}
 }
+
+// failingStorageClassClient fails to create the client, e.g. when the kubeconfig lacks the permissions
+type failingStorageClassClient struct{}
+
+func (failingStorageClassClient) NewClient(string) (*skrstorageclass.Client, error) {
+	return nil, fmt.Errorf("forbidden")
+}
+
+func TestListSKRResourcesWithoutStorageClasses(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := Process{
+		Logger:             logger.NewLogger(zapcore.InfoLevel),
+		NodeConfig:         skrnode.FakeNodeClient{},
+		PVCConfig:          skrpvc.FakePVCClient{},
+		SvcConfig:          skrsvc.FakeSvcClient{},
+		StorageClassConfig: failingStorageClassClient{},
+	}
+
+	nodes, pvcList, svcList, storageClassList, err := p.listSKRResources(context.Background(), kmccache.Record{SubAccountID: uuid.New().String()})
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(nodes.Items).ShouldNot(gomega.BeEmpty())
+	g.Expect(pvcList).ShouldNot(gomega.BeNil())
+	g.Expect(svcList).ShouldNot(gomega.BeNil())
+	g.Expect(storageClassList).Should(gomega.BeNil())
+}
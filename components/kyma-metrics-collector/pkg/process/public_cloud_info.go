@@ -1,8 +1,10 @@
 package process
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -10,9 +12,10 @@ import (
 )
 
 type Providers struct {
-	Azure AzureMachines
-	AWS   AWSMachines
-	GCP   GCPMachines
+	Azure     AzureMachines
+	AWS       AWSMachines
+	GCP       GCPMachines
+	OpenStack OpenStackMachines
 }
 
 type AzureMachines map[string]Feature
@@ -21,6 +24,8 @@ type AWSMachines map[string]Feature
 
 type GCPMachines map[string]Feature
 
+type OpenStackMachines map[string]Feature
+
 type Feature struct {
 	CpuCores int     `json:"cpu_cores"`
 	Memory   float64 `json:"memory"`
@@ -44,6 +49,10 @@ func (p Providers) GetFeature(cloudProvider, vmType string) (f *Feature) {
 		if feature, ok := p.GCP[vmType]; ok {
 			return &feature
 		}
+	case OpenStack:
+		if feature, ok := p.OpenStack[vmType]; ok {
+			return &feature
+		}
 	}
 	return nil
 }
@@ -53,44 +62,85 @@ func LoadPublicCloudSpecs(cfg *env.Config) (*Providers, error) {
 	if cfg.PublicCloudSpecs == "" {
 		return nil, fmt.Errorf("public cloud specification is not configured")
 	}
+	return ParsePublicCloudSpecs([]byte(cfg.PublicCloudSpecs))
+}
 
+// ParsePublicCloudSpecs validates the public cloud specs and loads them to Providers object.
+// The machine types are lower-cased, as they are looked up with the lower-cased node instance type.
+func ParsePublicCloudSpecs(data []byte) (*Providers, error) {
 	var machineInfo MachineInfo
-	err := json.Unmarshal([]byte(cfg.PublicCloudSpecs), &machineInfo)
-	if err != nil {
+	if err := json.Unmarshal(data, &machineInfo); err != nil {
 		return nil, errors.Wrapf(err, "failed to unmarshal machine info")
 	}
-	awsMachinesData, err := machineInfo[AWS].MarshalJSON()
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to marshal AWS info")
+
+	providers := Providers{
+		AWS:       AWSMachines{},
+		Azure:     AzureMachines{},
+		GCP:       GCPMachines{},
+		OpenStack: OpenStackMachines{},
 	}
-	awsMachines := &AWSMachines{}
-	err = json.Unmarshal(awsMachinesData, &awsMachines)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to unmarshal AWS machines data")
+	machineTypes := map[string]map[string]Feature{
+		AWS:       providers.AWS,
+		Azure:     providers.Azure,
+		GCP:       providers.GCP,
+		OpenStack: providers.OpenStack,
 	}
-	azureMachinesData, err := machineInfo[Azure].MarshalJSON()
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to marshal Azure info")
+
+	count := 0
+	for provider, rawMachines := range machineInfo {
+		machines, found := machineTypes[provider]
+		if !found {
+			return nil, fmt.Errorf("unknown provider %q", provider)
+		}
+
+		features := map[string]Feature{}
+		decoder := json.NewDecoder(bytes.NewReader(rawMachines))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&features); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s machines data", provider)
+		}
+
+		for machineType, feature := range features {
+			if err := feature.validate(); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s machine type %q", provider, machineType)
+			}
+			machineType = strings.ToLower(machineType)
+			if _, duplicated := machines[machineType]; duplicated {
+				return nil, fmt.Errorf("duplicated %s machine type %q", provider, machineType)
+			}
+			machines[machineType] = feature
+			count++
+		}
 	}
-	azureMachines := &AzureMachines{}
-	err = json.Unmarshal(azureMachinesData, azureMachines)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to unmarshal Azure machines data")
+	if count == 0 {
+		return nil, fmt.Errorf("public cloud specification contains no machine types")
 	}
-	gcpMachinesData, err := machineInfo[GCP].MarshalJSON()
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to marshal GCP info")
+
+	return &providers, nil
+}
+
+func (f Feature) validate() error {
+	if f.CpuCores <= 0 {
+		return fmt.Errorf("cpu_cores must be positive")
 	}
-	gcpMachines := &GCPMachines{}
-	if err = json.Unmarshal(gcpMachinesData, gcpMachines); err != nil {
-		return nil, errors.Wrapf(err, "failed to unmarshal GCP machines data")
+	if f.Memory <= 0 {
+		return fmt.Errorf("memory must be positive")
 	}
-
-	providers := Providers{
-		AWS:   *awsMachines,
-		Azure: *azureMachines,
-		GCP:   *gcpMachines,
+	if f.Storage < 0 {
+		return fmt.Errorf("storage must not be negative")
 	}
+	if f.MaxNICs < 0 {
+		return fmt.Errorf("max_nics must not be negative")
+	}
+	return nil
+}
 
-	return &providers, nil
+// MachineTypesCount returns the number of machine types per provider
+func (p Providers) MachineTypesCount() map[string]int {
+	return map[string]int{
+		AWS:       len(p.AWS),
+		Azure:     len(p.Azure),
+		GCP:       len(p.GCP),
+		OpenStack: len(p.OpenStack),
+	}
 }
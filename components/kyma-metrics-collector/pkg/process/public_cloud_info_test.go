@@ -70,6 +70,18 @@ func TestGetFeature(t *testing.T) {
 			cloudProvider: "aws",
 			vmType:        "m5.2xlarge.foo",
 		},
+		{
+			cloudProvider: "openstack",
+			vmType:        "g_c8_m32",
+			expectedFeature: Feature{
+				CpuCores: 8,
+				Memory:   32,
+			},
+		},
+		{
+			cloudProvider: "openstack",
+			vmType:        "g_c8_m32_foo",
+		},
 		{
 			cloudProvider: "gcp",
 			vmType:        "n2-standard-8",
@@ -97,3 +109,66 @@ func TestGetFeature(t *testing.T) {
 		g.Expect(gotFeature).Should(gomega.BeNil())
 	}
 }
+
+func TestParsePublicCloudSpecs(t *testing.T) {
+	testCases := []struct {
+		name        string
+		specs       string
+		expectedErr bool
+	}{
+		{
+			name:  "valid specs with lower-cased machine types",
+			specs: `{"aws":{"M5.xlarge":{"cpu_cores":4,"memory":16}},"openstack":{"g_c4_m16":{"cpu_cores":4,"memory":16}}}`,
+		},
+		{
+			name:        "not a JSON",
+			specs:       `aws: {}`,
+			expectedErr: true,
+		},
+		{
+			name:        "unknown provider",
+			specs:       `{"alicloud":{"ecs.g6.large":{"cpu_cores":2,"memory":8}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "unknown field",
+			specs:       `{"aws":{"m5.xlarge":{"cpu_cores":4,"memory":16,"gpus":1}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "missing cpu cores",
+			specs:       `{"aws":{"m5.xlarge":{"memory":16}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "negative storage",
+			specs:       `{"azure":{"standard_d8_v3":{"cpu_cores":8,"memory":32,"storage":-1}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "duplicated machine type",
+			specs:       `{"aws":{"m5.xlarge":{"cpu_cores":4,"memory":16},"M5.XLARGE":{"cpu_cores":4,"memory":16}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "no machine types",
+			specs:       `{"aws":{}}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			providers, err := ParsePublicCloudSpecs([]byte(tc.specs))
+			if tc.expectedErr {
+				g.Expect(err).ShouldNot(gomega.BeNil())
+				g.Expect(providers).Should(gomega.BeNil())
+				return
+			}
+			g.Expect(err).Should(gomega.BeNil())
+			g.Expect(providers.GetFeature(AWS, "m5.xlarge")).Should(gomega.Equal(&Feature{CpuCores: 4, Memory: 16}))
+			g.Expect(providers.MachineTypesCount()).Should(gomega.Equal(map[string]int{AWS: 1, Azure: 0, GCP: 0, OpenStack: 1}))
+		})
+	}
+}
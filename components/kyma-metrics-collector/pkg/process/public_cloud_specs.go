@@ -0,0 +1,262 @@
+package process
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/env"
+	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+const (
+	specsSourceEnv       = "env"
+	specsSourceFile      = "file"
+	specsSourceConfigMap = "configmap"
+
+	specsTimeout = 30 * time.Second
+)
+
+// SpecsSource reads the public cloud specs together with the version of the read data
+type SpecsSource interface {
+	Name() string
+	Read() (data []byte, version string, err error)
+}
+
+// NewSpecsSource creates the source of the public cloud specs, the file takes precedence over the ConfigMap,
+// which takes precedence over the env var. The ConfigMaps client is used only by the ConfigMap source.
+func NewSpecsSource(cfg *env.Config, configMaps dynamic.ResourceInterface) (SpecsSource, error) {
+	switch {
+	case cfg.PublicCloudSpecsFile != "":
+		return NewFileSpecsSource(cfg.PublicCloudSpecsFile), nil
+	case cfg.PublicCloudSpecsConfigMapName != "":
+		if configMaps == nil {
+			return nil, fmt.Errorf("ConfigMaps client is required for the %s public cloud specs", specsSourceConfigMap)
+		}
+		return NewConfigMapSpecsSource(configMaps, cfg.PublicCloudSpecsConfigMapName, cfg.PublicCloudSpecsConfigMapKey), nil
+	case cfg.PublicCloudSpecs != "":
+		return NewEnvSpecsSource(cfg.PublicCloudSpecs), nil
+	default:
+		return nil, fmt.Errorf("public cloud specification is not configured")
+	}
+}
+
+// EnvSpecsSource returns the specs read from the env var at start
+type EnvSpecsSource struct {
+	specs string
+}
+
+func NewEnvSpecsSource(specs string) *EnvSpecsSource {
+	return &EnvSpecsSource{specs: specs}
+}
+
+func (s *EnvSpecsSource) Name() string {
+	return specsSourceEnv
+}
+
+func (s *EnvSpecsSource) Read() ([]byte, string, error) {
+	return []byte(s.specs), specsSourceEnv, nil
+}
+
+// FileSpecsSource reads the specs from a file, e.g. a mounted ConfigMap, the version is the modification time
+type FileSpecsSource struct {
+	path string
+}
+
+func NewFileSpecsSource(path string) *FileSpecsSource {
+	return &FileSpecsSource{path: path}
+}
+
+func (s *FileSpecsSource) Name() string {
+	return specsSourceFile
+}
+
+// Path returns the path of the file, so that it can be watched
+func (s *FileSpecsSource) Path() string {
+	return s.path
+}
+
+func (s *FileSpecsSource) Read() ([]byte, string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to stat public cloud specs file")
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to read public cloud specs file")
+	}
+	return data, info.ModTime().UTC().Format(time.RFC3339), nil
+}
+
+// ConfigMapSpecsSource reads the specs from a ConfigMap in the KCP cluster, the version is the resource version
+type ConfigMapSpecsSource struct {
+	configMaps dynamic.ResourceInterface
+	name       string
+	key        string
+}
+
+func NewConfigMapSpecsSource(configMaps dynamic.ResourceInterface, name, key string) *ConfigMapSpecsSource {
+	return &ConfigMapSpecsSource{configMaps: configMaps, name: name, key: key}
+}
+
+func (s *ConfigMapSpecsSource) Name() string {
+	return specsSourceConfigMap
+}
+
+func (s *ConfigMapSpecsSource) Read() ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), specsTimeout)
+	defer cancel()
+
+	unstructuredConfigMap, err := s.configMaps.Get(ctx, s.name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to get public cloud specs ConfigMap")
+	}
+	configMap := new(corev1.ConfigMap)
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(unstructuredConfigMap.Object, configMap); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to convert public cloud specs ConfigMap")
+	}
+
+	data, found := configMap.Data[s.key]
+	if !found {
+		return nil, "", fmt.Errorf("key %s not found in public cloud specs ConfigMap %s", s.key, s.name)
+	}
+	return []byte(data), configMap.ResourceVersion, nil
+}
+
+// PublicCloudSpecs keeps the active public cloud specs, which can be reloaded while KMC is running.
+// Invalid specs are rejected and the previous ones stay active.
+type PublicCloudSpecs struct {
+	source SpecsSource
+	logger *zap.SugaredLogger
+
+	mu        sync.RWMutex
+	providers *Providers
+	status    SpecsStatus
+}
+
+// SpecsStatus describes the active public cloud specs
+type SpecsStatus struct {
+	Source       string         `json:"source"`
+	Version      string         `json:"version"`
+	Checksum     string         `json:"checksum"`
+	LoadedAt     time.Time      `json:"loadedAt"`
+	MachineTypes map[string]int `json:"machineTypes"`
+	LastError    string         `json:"lastError,omitempty"`
+}
+
+// NewPublicCloudSpecs loads the specs from the source, it fails when they are not valid
+func NewPublicCloudSpecs(source SpecsSource, logger *zap.SugaredLogger) (*PublicCloudSpecs, error) {
+	specs := &PublicCloudSpecs{source: source, logger: logger}
+	if _, err := specs.reload(); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// Providers returns the active specs
+func (s *PublicCloudSpecs) Providers() *Providers {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.providers
+}
+
+// Status returns the version and checksum of the active specs
+func (s *PublicCloudSpecs) Status() SpecsStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Reload reads the specs from the source again, it's safe to call it from other goroutines
+func (s *PublicCloudSpecs) Reload() {
+	changed, err := s.reload()
+	if err != nil {
+		specsReloads.WithLabelValues(log.ValueFail).Inc()
+		s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			Error("reload public cloud specs, keeping the previous specs")
+		return
+	}
+	if changed {
+		specsReloads.WithLabelValues(log.ValueSuccess).Inc()
+		// The new specs may contain the machine types which were missing so far
+		unknownMachineTypes.Reset()
+		status := s.Status()
+		s.namedLogger().With(log.KeyResult, log.ValueSuccess).
+			Infof("reloaded public cloud specs version: %s checksum: %s", status.Version, status.Checksum)
+	}
+}
+
+// Run reloads the specs periodically until the stop channel is closed
+func (s *PublicCloudSpecs) Run(interval time.Duration, stop <-chan struct{}) {
+	wait.Until(s.Reload, interval, stop)
+}
+
+// ServeHTTP shows the status of the active specs
+func (s *PublicCloudSpecs) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(writer).Encode(s.Status()); err != nil {
+		s.namedLogger().With(log.KeyError, err.Error()).Error("write public cloud specs status")
+	}
+}
+
+// reload replaces the active specs when the read specs are valid and differ from the active ones
+func (s *PublicCloudSpecs) reload() (bool, error) {
+	data, version, err := s.source.Read()
+	if err != nil {
+		s.setLastError(err)
+		return false, err
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	if s.Status().Checksum == checksum {
+		s.setLastError(nil)
+		return false, nil
+	}
+
+	providers, err := ParsePublicCloudSpecs(data)
+	if err != nil {
+		err = errors.Wrapf(err, "invalid public cloud specs version: %s", version)
+		s.setLastError(err)
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers = providers
+	s.status = SpecsStatus{
+		Source:       s.source.Name(),
+		Version:      version,
+		Checksum:     checksum,
+		LoadedAt:     time.Now(),
+		MachineTypes: providers.MachineTypesCount(),
+	}
+	return true, nil
+}
+
+func (s *PublicCloudSpecs) setLastError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+}
+
+func (s *PublicCloudSpecs) namedLogger() *zap.SugaredLogger {
+	return s.logger.Named("public-cloud-specs").With("source", s.source.Name())
+}
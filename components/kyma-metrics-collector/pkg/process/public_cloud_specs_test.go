@@ -0,0 +1,111 @@
+package process
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/env"
+	kmccache "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/cache"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+const (
+	specsV1 = `{"aws":{"m5.xlarge":{"cpu_cores":4,"memory":16}}}`
+	specsV2 = `{"aws":{"m5.xlarge":{"cpu_cores":4,"memory":16},"m6i.xlarge":{"cpu_cores":4,"memory":16}}}`
+)
+
+func TestPublicCloudSpecs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	path := filepath.Join(t.TempDir(), "providers")
+	g.Expect(os.WriteFile(path, []byte(specsV1), 0600)).Should(gomega.Succeed())
+
+	specs, err := NewPublicCloudSpecs(NewFileSpecsSource(path), logger.NewLogger(zapcore.InfoLevel))
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(specs.Providers().GetFeature(AWS, "m6i.xlarge")).Should(gomega.BeNil())
+	status := specs.Status()
+	g.Expect(status.Source).Should(gomega.Equal(specsSourceFile))
+	g.Expect(status.Checksum).ShouldNot(gomega.BeEmpty())
+	g.Expect(status.MachineTypes[AWS]).Should(gomega.Equal(1))
+
+	// New machine types are available after reload
+	g.Expect(os.WriteFile(path, []byte(specsV2), 0600)).Should(gomega.Succeed())
+	specs.Reload()
+	g.Expect(specs.Providers().GetFeature(AWS, "m6i.xlarge")).ShouldNot(gomega.BeNil())
+	g.Expect(specs.Status().Checksum).ShouldNot(gomega.Equal(status.Checksum))
+	status = specs.Status()
+
+	// Invalid specs are rejected and the previous ones stay active
+	g.Expect(os.WriteFile(path, []byte(`{"aws":{"m5.xlarge":{"memory":16}}}`), 0600)).Should(gomega.Succeed())
+	specs.Reload()
+	g.Expect(specs.Providers().GetFeature(AWS, "m6i.xlarge")).ShouldNot(gomega.BeNil())
+	g.Expect(specs.Status().Checksum).Should(gomega.Equal(status.Checksum))
+	g.Expect(specs.Status().LastError).ShouldNot(gomega.BeEmpty())
+
+	// The status is served as JSON
+	recorder := httptest.NewRecorder()
+	specs.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/specs", nil))
+	g.Expect(recorder.Code).Should(gomega.Equal(http.StatusOK))
+	var served SpecsStatus
+	g.Expect(json.Unmarshal(recorder.Body.Bytes(), &served)).Should(gomega.Succeed())
+	g.Expect(served.Version).Should(gomega.Equal(status.Version))
+	g.Expect(served.Checksum).Should(gomega.Equal(status.Checksum))
+
+	// Invalid initial specs fail
+	g.Expect(os.WriteFile(path, []byte(`{}`), 0600)).Should(gomega.Succeed())
+	_, err = NewPublicCloudSpecs(NewFileSpecsSource(path), logger.NewLogger(zapcore.InfoLevel))
+	g.Expect(err).ShouldNot(gomega.BeNil())
+}
+
+func TestConfigMapSpecsSource(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "public-cloud-specs",
+			"namespace":       "kcp-system",
+			"resourceVersion": "42",
+		},
+		"data": map[string]interface{}{
+			"providers": specsV1,
+		},
+	}}
+	configMaps := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), configMap).
+		Resource(kmccache.ConfigMapGroupVersionResource()).Namespace("kcp-system")
+
+	data, version, err := NewConfigMapSpecsSource(configMaps, "public-cloud-specs", "providers").Read()
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(string(data)).Should(gomega.Equal(specsV1))
+	g.Expect(version).Should(gomega.Equal("42"))
+
+	_, _, err = NewConfigMapSpecsSource(configMaps, "public-cloud-specs", "foo").Read()
+	g.Expect(err).ShouldNot(gomega.BeNil())
+}
+
+func TestNewSpecsSource(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	source, err := NewSpecsSource(&env.Config{PublicCloudSpecs: specsV1, PublicCloudSpecsFile: "/specs/providers"}, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(source).Should(gomega.BeAssignableToTypeOf(&FileSpecsSource{}))
+
+	source, err = NewSpecsSource(&env.Config{PublicCloudSpecs: specsV1}, nil)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(source).Should(gomega.BeAssignableToTypeOf(&EnvSpecsSource{}))
+
+	_, err = NewSpecsSource(&env.Config{PublicCloudSpecsConfigMapName: "public-cloud-specs"}, nil)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+
+	_, err = NewSpecsSource(&env.Config{}, nil)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+}
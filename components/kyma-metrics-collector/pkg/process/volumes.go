@@ -0,0 +1,157 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+const (
+	workerPoolLabel                   = "worker.gardener.cloud/pool"
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+
+	// unknownVolume is the storage class or disk type of the volumes which cannot be resolved
+	unknownVolume = "unknown"
+)
+
+// diskTypeParameter is the StorageClass parameter of a provisioner which contains the disk type
+type diskTypeParameter struct {
+	// keys are the lower-cased parameter keys, as the provisioners compare them case-insensitively
+	keys []string
+	// defaultType is used by the provisioner when the parameter is not set
+	defaultType string
+}
+
+var diskTypeParameters = map[string]diskTypeParameter{
+	"disk.csi.azure.com":       {keys: []string{"skuname", "storageaccounttype"}, defaultType: "standardssd_lrs"},
+	"kubernetes.io/azure-disk": {keys: []string{"skuname", "storageaccounttype"}},
+	"ebs.csi.aws.com":          {keys: []string{"type"}, defaultType: "gp3"},
+	"kubernetes.io/aws-ebs":    {keys: []string{"type"}, defaultType: "gp2"},
+	"pd.csi.storage.gke.io":    {keys: []string{"type"}, defaultType: "pd-standard"},
+	"kubernetes.io/gce-pd":     {keys: []string{"type"}, defaultType: "pd-standard"},
+	"cinder.csi.openstack.org": {keys: []string{"type"}},
+	"kubernetes.io/cinder":     {keys: []string{"type"}},
+}
+
+// volumeBreakdowns sums up the volumes by name
+type volumeBreakdowns map[string]*edp.VolumeBreakdown
+
+func (v volumeBreakdowns) add(name string, count int, sizeGB int64) {
+	breakdown, found := v[name]
+	if !found {
+		breakdown = &edp.VolumeBreakdown{Name: name}
+		v[name] = breakdown
+	}
+	breakdown.Count += count
+	breakdown.SizeGbTotal += int64(count) * sizeGB
+	breakdown.SizeGbRounded += int64(count) * getVolumeRoundedToFactor(sizeGB)
+}
+
+// sorted returns the breakdowns sorted by name, or nil when there are none
+func (v volumeBreakdowns) sorted() []edp.VolumeBreakdown {
+	if len(v) == 0 {
+		return nil
+	}
+	result := make([]edp.VolumeBreakdown, 0, len(v))
+	for _, breakdown := range v {
+		result = append(result, *breakdown)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// storageClasses resolves the storage class and the disk type of the PVCs
+type storageClasses struct {
+	byName       map[string]storagev1.StorageClass
+	defaultClass string
+}
+
+func newStorageClasses(storageClassList *storagev1.StorageClassList) storageClasses {
+	classes := storageClasses{byName: map[string]storagev1.StorageClass{}}
+	if storageClassList == nil {
+		return classes
+	}
+	for _, storageClass := range storageClassList.Items {
+		classes.byName[storageClass.Name] = storageClass
+		if storageClass.Annotations[defaultStorageClassAnnotation] == "true" || storageClass.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+			classes.defaultClass = storageClass.Name
+		}
+	}
+	return classes
+}
+
+// classOf returns the storage class of the PVC. As in Kubernetes, the beta annotation takes precedence over the
+// storage class name. The default storage class is used when the PVC has none, and an empty storage class name
+// means the PVC is bound to a volume without storage class.
+func (c storageClasses) classOf(pvc corev1.PersistentVolumeClaim) string {
+	if className, found := pvc.Annotations[corev1.BetaStorageClassAnnotation]; found {
+		return classOrUnknown(className)
+	}
+	if pvc.Spec.StorageClassName != nil {
+		return classOrUnknown(*pvc.Spec.StorageClassName)
+	}
+	return classOrUnknown(c.defaultClass)
+}
+
+func classOrUnknown(className string) string {
+	if className == "" {
+		return unknownVolume
+	}
+	return className
+}
+
+// diskTypeOf returns the lower-cased disk type from the parameters of the storage class
+func (c storageClasses) diskTypeOf(className string) string {
+	storageClass, found := c.byName[className]
+	if !found {
+		return unknownVolume
+	}
+	parameter, found := diskTypeParameters[storageClass.Provisioner]
+	if !found {
+		return unknownVolume
+	}
+	for key, value := range storageClass.Parameters {
+		for _, parameterKey := range parameter.keys {
+			if strings.ToLower(key) == parameterKey && value != "" {
+				return strings.ToLower(value)
+			}
+		}
+	}
+	if parameter.defaultType != "" {
+		return parameter.defaultType
+	}
+	return unknownVolume
+}
+
+// addBootVolumes adds the boot volumes of the workers multiplied by their nodes
+func addBootVolumes(workers []gardencorev1beta1.Worker, nodesPerWorker map[string]int, bootVolumes, diskTypes volumeBreakdowns) error {
+	for _, worker := range workers {
+		nodes := nodesPerWorker[worker.Name]
+		if worker.Volume == nil || nodes == 0 {
+			continue
+		}
+		size, err := resource.ParseQuantity(worker.Volume.VolumeSize)
+		if err != nil {
+			return fmt.Errorf("invalid volume size of worker %s: %v", worker.Name, err)
+		}
+		diskType := unknownVolume
+		if worker.Volume.Type != nil && *worker.Volume.Type != "" {
+			diskType = strings.ToLower(*worker.Volume.Type)
+		}
+
+		sizeGB := getSizeInGB(&size)
+		bootVolumes.add(worker.Name, nodes, sizeGB)
+		diskTypes.add(diskType, nodes, sizeGB)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package reload
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+// Watcher notifies about changes to files mounted inside a kubernetes Pod, like Secrets or ConfigMaps.
+// Mounted files are symbolic links which are replaced on update, which breaks watches on the files,
+// so the directories of the files are watched instead.
+type Watcher interface {
+	// Run starts the watcher loop until the context is done (blocking call)
+	Run(ctx context.Context)
+}
+
+type watcher struct {
+	name       string
+	filePaths  []string
+	batchDelay time.Duration
+	notifyFunc func()
+	logger     *zap.SugaredLogger
+}
+
+// NewWatcher creates a watcher which calls notifyFunc when the files change.
+// Changes that occur within the batchDelay are batched in a single notification.
+func NewWatcher(name string, filePaths []string, batchDelay time.Duration, notifyFunc func(), logger *zap.SugaredLogger) Watcher {
+	return &watcher{
+		name:       name,
+		filePaths:  filePaths,
+		batchDelay: batchDelay,
+		notifyFunc: notifyFunc,
+		logger:     logger,
+	}
+}
+
+func (w *watcher) Run(ctx context.Context) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Error("create file watcher")
+		return
+	}
+	defer func() {
+		if err := fw.Close(); err != nil {
+			w.namedLogger().With(log.KeyError, err.Error()).Warn("close file watcher")
+		}
+	}()
+
+	for _, dir := range uniqueDirNames(w.filePaths) {
+		if err := fw.Add(dir); err != nil {
+			w.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Errorf("watch %s", dir)
+			return
+		}
+		w.namedLogger().Infof("watching %s for changes", dir)
+	}
+
+	w.watchFileEvents(ctx, fw.Events)
+}
+
+// watchFileEvents batches the file events, so that notifyFunc is called at most once per batchDelay
+func (w *watcher) watchFileEvents(ctx context.Context, events <-chan fsnotify.Event) {
+	var timer *time.Timer
+	var timeChan <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			w.namedLogger().Debugf("file event: %s", event.String())
+			if timer != nil || event.Op == fsnotify.Chmod {
+				// a notification is already pending or the content did not change
+				continue
+			}
+			timer = time.NewTimer(w.batchDelay)
+			timeChan = timer.C
+		case <-timeChan:
+			timer.Stop()
+			timer = nil
+			timeChan = nil
+			w.namedLogger().Info("files changed, notifying")
+			w.notifyFunc()
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+	}
+}
+
+func (w *watcher) namedLogger() *zap.SugaredLogger {
+	return w.logger.Named("watcher").With("name", w.name)
+}
+
+// uniqueDirNames returns the directories of the files without duplicates
+func uniqueDirNames(filePaths []string) []string {
+	dirs := []string{}
+	found := map[string]bool{}
+	for _, path := range filePaths {
+		dir := filepath.Dir(path)
+		if !found[dir] {
+			found[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,51 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+func TestWatcher(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "specs.json")
+	g.Expect(os.WriteFile(path, []byte("{}"), 0600)).Should(gomega.Succeed())
+
+	var notifications int32
+	watcher := NewWatcher("test", []string{path}, 100*time.Millisecond, func() {
+		atomic.AddInt32(&notifications, 1)
+	}, logger.NewLogger(zapcore.InfoLevel))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	// give the watcher time to add the watch
+	time.Sleep(100 * time.Millisecond)
+
+	// changes within the batch delay are notified once
+	g.Expect(os.WriteFile(path, []byte(`{"aws":{}}`), 0600)).Should(gomega.Succeed())
+	g.Expect(os.WriteFile(path, []byte(`{"gcp":{}}`), 0600)).Should(gomega.Succeed())
+	g.Eventually(func() int32 {
+		return atomic.LoadInt32(&notifications)
+	}, 2*time.Second, 10*time.Millisecond).Should(gomega.Equal(int32(1)))
+	g.Consistently(func() int32 {
+		return atomic.LoadInt32(&notifications)
+	}, 300*time.Millisecond, 10*time.Millisecond).Should(gomega.Equal(int32(1)))
+}
+
+func TestUniqueDirNames(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(uniqueDirNames([]string{"/a/b/c.json", "/a/b/d.json", "/a/e.json"})).Should(gomega.Equal([]string{"/a/b", "/a"}))
+	g.Expect(uniqueDirNames(nil)).Should(gomega.BeEmpty())
+}
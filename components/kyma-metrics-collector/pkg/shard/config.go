@@ -0,0 +1,13 @@
+package shard
+
+import "time"
+
+type Config struct {
+	Enabled       bool          `envconfig:"SHARDING_ENABLED" default:"false"`
+	Group         string        `envconfig:"SHARDING_GROUP" default:"kyma-metrics-collector"`
+	Identity      string        `envconfig:"SHARDING_IDENTITY"`
+	Namespace     string        `envconfig:"SHARDING_NAMESPACE" default:"kcp-system"`
+	LeaseDuration time.Duration `envconfig:"SHARDING_LEASE_DURATION" default:"30s"`
+	RenewInterval time.Duration `envconfig:"SHARDING_RENEW_INTERVAL" default:"10s"`
+	VirtualNodes  int           `envconfig:"SHARDING_VIRTUAL_NODES" default:"100"`
+}
@@ -0,0 +1,39 @@
+package shard
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	renewSuccess = "success"
+	renewFailure = "failure"
+)
+
+var (
+	members = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "kmc",
+			Subsystem: "shard",
+			Name:      "members",
+			Help:      "Number of KMC replicas sharing the subaccounts.",
+		},
+	)
+	rebalances = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "shard",
+			Name:      "rebalances_total",
+			Help:      "Total number of times the subaccounts were rebalanced across the replicas.",
+		},
+	)
+	leaseRenewals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "shard",
+			Name:      "lease_renewals_total",
+			Help:      "Total number of lease renewals of the replica.",
+		},
+		[]string{"status"},
+	)
+)
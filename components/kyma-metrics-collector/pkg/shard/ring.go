@@ -0,0 +1,75 @@
+package shard
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"strconv"
+)
+
+// Ring assigns keys to members with consistent hashing. Every member is placed on the ring
+// as virtual nodes, so that a joining or leaving member moves only about 1/n of the keys.
+type Ring struct {
+	members []string
+	points  []uint64
+	owners  map[uint64]string
+}
+
+// NewRing creates the ring of the members, every member gets the given number of virtual nodes
+func NewRing(members []string, virtualNodes int) *Ring {
+	if virtualNodes < 1 {
+		virtualNodes = 1
+	}
+	sorted := append([]string{}, members...)
+	sort.Strings(sorted)
+
+	ring := &Ring{members: sorted, owners: map[uint64]string{}}
+	for _, member := range sorted {
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+			if _, found := ring.owners[point]; found {
+				// the members are sorted, so the collisions are resolved the same way on all replicas
+				continue
+			}
+			ring.points = append(ring.points, point)
+			ring.owners[point] = member
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// Owner returns the member owning the key, it is empty when the ring has no members
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	point := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= point })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Members returns the sorted members of the ring
+func (r *Ring) Members() []string {
+	return append([]string{}, r.members...)
+}
+
+func (r *Ring) equal(members []string) bool {
+	if len(r.members) != len(members) {
+		return false
+	}
+	for i := range members {
+		if r.members[i] != members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hash(value string) uint64 {
+	sum := sha256.Sum256([]byte(value))
+	return binary.BigEndian.Uint64(sum[:8])
+}
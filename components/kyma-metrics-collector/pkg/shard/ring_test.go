@@ -0,0 +1,56 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestRing(t *testing.T) {
+	keys := make([]string, 3000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("subaccount-%d", i)
+	}
+
+	t.Run("has no owner without members", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		g.Expect(NewRing(nil, 100).Owner("subaccount")).Should(gomega.BeEmpty())
+	})
+
+	t.Run("spreads the keys across the members", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		ring := NewRing([]string{"kmc-a", "kmc-b", "kmc-c"}, 100)
+		owned := map[string]int{}
+		for _, key := range keys {
+			owned[ring.Owner(key)]++
+		}
+		g.Expect(owned).Should(gomega.HaveLen(3))
+		for _, count := range owned {
+			g.Expect(count).Should(gomega.BeNumerically("~", len(keys)/3, len(keys)/10))
+		}
+	})
+
+	t.Run("is independent of the order of the members", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		ring := NewRing([]string{"kmc-a", "kmc-b", "kmc-c"}, 100)
+		reordered := NewRing([]string{"kmc-c", "kmc-a", "kmc-b"}, 100)
+		for _, key := range keys {
+			g.Expect(reordered.Owner(key)).Should(gomega.Equal(ring.Owner(key)))
+		}
+	})
+
+	t.Run("moves only the keys of the joining member", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		ring := NewRing([]string{"kmc-a", "kmc-b", "kmc-c"}, 100)
+		joined := NewRing([]string{"kmc-a", "kmc-b", "kmc-c", "kmc-d"}, 100)
+		moved := 0
+		for _, key := range keys {
+			if owner := joined.Owner(key); owner != ring.Owner(key) {
+				g.Expect(owner).Should(gomega.Equal("kmc-d"))
+				moved++
+			}
+		}
+		g.Expect(moved).Should(gomega.BeNumerically("~", len(keys)/4, len(keys)/10))
+	})
+}
@@ -0,0 +1,301 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+const groupLabel = "kyma-project.io/kmc-shard-group"
+
+// Sharder splits the subaccounts across the KMC replicas. Every replica renews its own Lease, and the replicas
+// with a valid Lease are the members of a consistent hash ring. A replica owns a key only when it is the owner
+// in every ring which was current during the last lease duration, and only while its own Lease is valid.
+// So a replica takes over the keys of a joining, leaving or failed replica only after that replica has
+// seen the change or its Lease has expired, and each key is owned by at most one replica at a time.
+type Sharder struct {
+	config Config
+	leases dynamic.ResourceInterface
+	logger *zap.SugaredLogger
+
+	mu sync.RWMutex
+	// history are the rings which were current during the last lease duration, the oldest first
+	history     []view
+	settled     *Ring
+	lastRenewed time.Time
+	released    bool
+	onRebalance func(wasOwned func(key string) bool)
+
+	timeNow func() time.Time
+}
+
+type view struct {
+	ring  *Ring
+	since time.Time
+}
+
+// NewSharder creates the sharder of the replica with the config identity, using the Leases of the namespace
+func NewSharder(config Config, leases dynamic.ResourceInterface, logger *zap.SugaredLogger) (*Sharder, error) {
+	if config.Identity == "" {
+		return nil, fmt.Errorf("identity of the replica is not set")
+	}
+	if config.RenewInterval <= 0 || config.LeaseDuration <= config.RenewInterval {
+		return nil, fmt.Errorf("lease duration %v must be greater than renew interval %v", config.LeaseDuration, config.RenewInterval)
+	}
+	return &Sharder{
+		config:  config,
+		leases:  leases,
+		logger:  logger,
+		timeNow: time.Now,
+	}, nil
+}
+
+// OnRebalance sets the handler called when the members are settled after a change. The handler gets a function
+// telling whether a key was owned by the replica before the change, so that the newly owned keys can be picked up.
+// It must be set before Run.
+func (s *Sharder) OnRebalance(handler func(wasOwned func(key string) bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRebalance = handler
+}
+
+// Run renews the Lease and updates the members every renew interval until the stop channel is closed
+func (s *Sharder) Run(stop <-chan struct{}) {
+	s.sync()
+	ticker := time.NewTicker(s.config.RenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.sync()
+		}
+	}
+}
+
+// Owns returns whether the key is owned by the replica
+func (s *Sharder) Owns(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.timeNow()
+	if len(s.history) == 0 || now.Sub(s.lastRenewed) > s.config.LeaseDuration || now.Sub(s.history[0].since) < s.config.LeaseDuration {
+		return false
+	}
+	for _, v := range s.history {
+		if v.ring.Owner(key) != s.config.Identity {
+			return false
+		}
+	}
+	return true
+}
+
+// Members returns the replicas of the current ring
+func (s *Sharder) Members() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.history) == 0 {
+		return nil
+	}
+	return s.history[len(s.history)-1].ring.Members()
+}
+
+// Release gives up all keys and deletes the Lease, so that the other replicas take over without waiting
+// for the Lease to expire
+func (s *Sharder) Release() {
+	s.mu.Lock()
+	s.released = true
+	s.history = nil
+	s.settled = nil
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.RenewInterval)
+	defer cancel()
+	if err := s.leases.Delete(ctx, s.leaseName(), metaV1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Warn("delete lease")
+		return
+	}
+	s.namedLogger().Info("released lease")
+}
+
+func (s *Sharder) sync() {
+	now := s.timeNow()
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.RenewInterval)
+	defer cancel()
+
+	s.mu.RLock()
+	released := s.released
+	s.mu.RUnlock()
+	if released {
+		return
+	}
+
+	err := s.renew(ctx, now)
+	var current []string
+	if err == nil {
+		current, err = s.listMembers(ctx, now)
+	}
+
+	s.mu.Lock()
+	if err != nil {
+		leaseRenewals.WithLabelValues(renewFailure).Inc()
+		s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Warn("renew lease")
+		if len(s.history) > 0 && now.Sub(s.lastRenewed) > s.config.LeaseDuration {
+			// the other replicas may have taken over the keys already
+			s.history = nil
+			s.settled = nil
+			s.namedLogger().Warn("lease expired, giving up all subaccounts")
+		}
+		s.mu.Unlock()
+		return
+	}
+	leaseRenewals.WithLabelValues(renewSuccess).Inc()
+	s.lastRenewed = now
+
+	if len(s.history) == 0 || !s.history[len(s.history)-1].ring.equal(current) {
+		s.history = append(s.history, view{ring: NewRing(current, s.config.VirtualNodes), since: now})
+		members.Set(float64(len(current)))
+		s.namedLogger().Infof("members changed: %v", current)
+	}
+	// drop the rings which were replaced before the last lease duration
+	for len(s.history) > 1 && !s.history[1].since.After(now.Add(-s.config.LeaseDuration)) {
+		s.history = s.history[1:]
+	}
+
+	var handler func(wasOwned func(key string) bool)
+	var previous *Ring
+	if len(s.history) == 1 && !s.history[0].since.After(now.Add(-s.config.LeaseDuration)) && s.history[0].ring != s.settled {
+		previous = s.settled
+		s.settled = s.history[0].ring
+		handler = s.onRebalance
+		rebalances.Inc()
+		s.namedLogger().Infof("rebalanced subaccounts across members: %v", s.settled.Members())
+	}
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(func(key string) bool {
+			return previous != nil && previous.Owner(key) == s.config.Identity
+		})
+	}
+}
+
+// renew creates or updates the Lease of the replica
+func (s *Sharder) renew(ctx context.Context, now time.Time) error {
+	renewTime := metaV1.NewMicroTime(now)
+	leaseDurationSeconds := int32(s.config.LeaseDuration.Seconds())
+
+	object, err := s.leases.Get(ctx, s.leaseName(), metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		lease := &coordinationv1.Lease{
+			TypeMeta: metaV1.TypeMeta{Kind: "Lease", APIVersion: coordinationv1.SchemeGroupVersion.String()},
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   s.leaseName(),
+				Labels: map[string]string{groupLabel: s.config.Group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &s.config.Identity,
+				LeaseDurationSeconds: &leaseDurationSeconds,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		unstructuredLease, err := toUnstructured(lease)
+		if err != nil {
+			return err
+		}
+		_, err = s.leases.Create(ctx, unstructuredLease, metaV1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lease := new(coordinationv1.Lease)
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(object.Object, lease); err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = &s.config.Identity
+	lease.Spec.LeaseDurationSeconds = &leaseDurationSeconds
+	lease.Spec.RenewTime = &renewTime
+	unstructuredLease, err := toUnstructured(lease)
+	if err != nil {
+		return err
+	}
+	_, err = s.leases.Update(ctx, unstructuredLease, metaV1.UpdateOptions{})
+	return err
+}
+
+// listMembers returns the sorted identities of the replicas with a valid Lease, and deletes the Leases
+// which expired more than a lease duration ago
+func (s *Sharder) listMembers(ctx context.Context, now time.Time) ([]string, error) {
+	list, err := s.leases.List(ctx, metaV1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", groupLabel, s.config.Group)})
+	if err != nil {
+		return nil, err
+	}
+
+	// the replica is a member as its Lease was just renewed
+	current := []string{s.config.Identity}
+	for _, item := range list.Items {
+		lease := new(coordinationv1.Lease)
+		if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(item.Object, lease); err != nil {
+			return nil, err
+		}
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiresAt := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if *lease.Spec.HolderIdentity == s.config.Identity {
+			continue
+		}
+		if now.Before(expiresAt) {
+			current = append(current, *lease.Spec.HolderIdentity)
+			continue
+		}
+		if now.Sub(expiresAt) > s.config.LeaseDuration {
+			if err := s.leases.Delete(ctx, lease.Name, metaV1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+				s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Warnf("delete expired lease %s", lease.Name)
+			}
+		}
+	}
+	sort.Strings(current)
+	return current, nil
+}
+
+func (s *Sharder) leaseName() string {
+	return fmt.Sprintf("%s-%s", s.config.Group, s.config.Identity)
+}
+
+func (s *Sharder) namedLogger() *zap.SugaredLogger {
+	return s.logger.Named("sharder")
+}
+
+func toUnstructured(lease *coordinationv1.Lease) (*unstructured.Unstructured, error) {
+	object, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(lease)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: object}, nil
+}
+
+func LeaseGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Version:  coordinationv1.SchemeGroupVersion.Version,
+		Group:    coordinationv1.SchemeGroupVersion.Group,
+		Resource: "leases",
+	}
+}
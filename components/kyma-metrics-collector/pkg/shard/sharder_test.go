@@ -0,0 +1,186 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+const (
+	testLeaseDuration = 30 * time.Second
+	testRenewInterval = 10 * time.Second
+)
+
+func newFakeClient() *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{LeaseGroupVersionResource(): "LeaseList"})
+}
+
+func newTestSharder(t *testing.T, client *dynamicfake.FakeDynamicClient, identity string, now *time.Time) *Sharder {
+	sharder, err := NewSharder(Config{
+		Group:         "kmc",
+		Identity:      identity,
+		LeaseDuration: testLeaseDuration,
+		RenewInterval: testRenewInterval,
+		VirtualNodes:  100,
+	}, client.Resource(LeaseGroupVersionResource()).Namespace("kcp-system"), logger.NewLogger(zapcore.InfoLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharder.timeNow = func() time.Time { return *now }
+	return sharder
+}
+
+func ownedKeys(sharder *Sharder, keys []string) int {
+	owned := 0
+	for _, key := range keys {
+		if sharder.Owns(key) {
+			owned++
+		}
+	}
+	return owned
+}
+
+func TestSharder(t *testing.T) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("subaccount-%d", i)
+	}
+	start := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("hands over the keys when replicas join and leave", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		client := newFakeClient()
+		now := start
+		sharderA := newTestSharder(t, client, "kmc-a", &now)
+		sharderB := newTestSharder(t, client, "kmc-b", &now)
+		acquired := map[string]int{}
+		for _, sharder := range []*Sharder{sharderA, sharderB} {
+			sharder := sharder
+			sharder.OnRebalance(func(wasOwned func(key string) bool) {
+				for _, key := range keys {
+					if sharder.Owns(key) && !wasOwned(key) {
+						acquired[sharder.config.Identity]++
+					}
+				}
+			})
+		}
+		// every key is owned by at most one replica at any time
+		expectExclusive := func() {
+			for _, key := range keys {
+				g.Expect(sharderA.Owns(key) && sharderB.Owns(key)).Should(gomega.BeFalse(), key)
+			}
+		}
+		advance := func(sharders ...*Sharder) {
+			now = now.Add(testRenewInterval)
+			for _, sharder := range sharders {
+				sharder.sync()
+				expectExclusive()
+			}
+		}
+
+		// The keys are owned after the lease duration
+		sharderA.sync()
+		g.Expect(ownedKeys(sharderA, keys)).Should(gomega.BeZero())
+		for i := 0; i < 3; i++ {
+			advance(sharderA)
+		}
+		g.Expect(ownedKeys(sharderA, keys)).Should(gomega.Equal(len(keys)))
+		g.Expect(acquired["kmc-a"]).Should(gomega.Equal(len(keys)))
+
+		// The joining replica takes over its keys after the lease duration
+		advance(sharderB, sharderA)
+		g.Expect(sharderA.Members()).Should(gomega.Equal([]string{"kmc-a", "kmc-b"}))
+		g.Expect(ownedKeys(sharderB, keys)).Should(gomega.BeZero())
+		ownedByA := ownedKeys(sharderA, keys)
+		g.Expect(ownedByA).Should(gomega.BeNumerically("<", len(keys)))
+		for i := 0; i < 3; i++ {
+			advance(sharderB, sharderA)
+		}
+		g.Expect(ownedKeys(sharderB, keys)).Should(gomega.Equal(len(keys) - ownedByA))
+		g.Expect(acquired["kmc-b"]).Should(gomega.Equal(len(keys) - ownedByA))
+
+		// The leaving replica releases its keys
+		sharderB.Release()
+		g.Expect(ownedKeys(sharderB, keys)).Should(gomega.BeZero())
+		advance(sharderA)
+		g.Expect(sharderA.Members()).Should(gomega.Equal([]string{"kmc-a"}))
+		g.Expect(ownedKeys(sharderA, keys)).Should(gomega.Equal(ownedByA))
+		for i := 0; i < 3; i++ {
+			advance(sharderA, sharderB)
+		}
+		g.Expect(ownedKeys(sharderA, keys)).Should(gomega.Equal(len(keys)))
+		g.Expect(ownedKeys(sharderB, keys)).Should(gomega.BeZero())
+	})
+
+	t.Run("takes over the keys of failed replicas after their lease expired", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		client := newFakeClient()
+		now := start
+		sharderA := newTestSharder(t, client, "kmc-a", &now)
+		sharderB := newTestSharder(t, client, "kmc-b", &now)
+		for i := 0; i < 4; i++ {
+			sharderA.sync()
+			sharderB.sync()
+			now = now.Add(testRenewInterval)
+		}
+		ownedByA := ownedKeys(sharderA, keys)
+		g.Expect(ownedByA + ownedKeys(sharderB, keys)).Should(gomega.Equal(len(keys)))
+
+		// kmc-b stops renewing its lease
+		for i := 0; i < 3; i++ {
+			sharderA.sync()
+			now = now.Add(testRenewInterval)
+		}
+		g.Expect(ownedKeys(sharderA, keys)).Should(gomega.Equal(ownedByA))
+		g.Expect(ownedKeys(sharderB, keys)).Should(gomega.BeZero())
+		for i := 0; i < 4; i++ {
+			sharderA.sync()
+			now = now.Add(testRenewInterval)
+		}
+		g.Expect(ownedKeys(sharderA, keys)).Should(gomega.Equal(len(keys)))
+
+		// The expired lease is deleted
+		list, err := client.Resource(LeaseGroupVersionResource()).Namespace("kcp-system").List(context.Background(), metaV1.ListOptions{})
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(list.Items).Should(gomega.HaveLen(1))
+	})
+
+	t.Run("gives up the keys when the lease cannot be renewed", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		client := newFakeClient()
+		now := start
+		sharder := newTestSharder(t, client, "kmc-a", &now)
+		for i := 0; i < 4; i++ {
+			sharder.sync()
+			now = now.Add(testRenewInterval)
+		}
+		g.Expect(ownedKeys(sharder, keys)).Should(gomega.Equal(len(keys)))
+
+		client.PrependReactor("update", "leases", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+			return true, nil, fmt.Errorf("connection refused")
+		})
+		for i := 0; i < 4; i++ {
+			sharder.sync()
+			now = now.Add(testRenewInterval)
+		}
+		g.Expect(ownedKeys(sharder, keys)).Should(gomega.BeZero())
+	})
+
+	t.Run("requires an identity", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		_, err := NewSharder(Config{LeaseDuration: testLeaseDuration, RenewInterval: testRenewInterval}, nil, logger.NewLogger(zapcore.InfoLevel))
+		g.Expect(err).ShouldNot(gomega.BeNil())
+	})
+}
@@ -0,0 +1,17 @@
+package sink
+
+import "time"
+
+type Config struct {
+	EDPEnabled        bool          `envconfig:"SINK_EDP_ENABLED" default:"true"`
+	QueueSize         int           `envconfig:"SINK_QUEUE_SIZE" default:"1000"`
+	PrometheusEnabled bool          `envconfig:"SINK_PROMETHEUS_ENABLED" default:"false"`
+	FileEnabled       bool          `envconfig:"SINK_FILE_ENABLED" default:"false"`
+	FilePath          string        `envconfig:"SINK_FILE_PATH" default:"/tmp/kmc-metrics.jsonl"`
+	HTTPEnabled       bool          `envconfig:"SINK_HTTP_ENABLED" default:"false"`
+	HTTPURL           string        `envconfig:"SINK_HTTP_URL"`
+	HTTPFormat        string        `envconfig:"SINK_HTTP_FORMAT" default:"json"`
+	HTTPToken         string        `envconfig:"SINK_HTTP_TOKEN"`
+	HTTPTimeout       time.Duration `envconfig:"SINK_HTTP_TIMEOUT" default:"30s"`
+	HTTPRetry         int           `envconfig:"SINK_HTTP_RETRY" default:"3"`
+}
@@ -0,0 +1,59 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+const EDPSinkName = "edp"
+
+// EDPSink sends the metrics to EDP, through the outbox when it is enabled
+type EDPSink struct {
+	client *edp.Client
+	outbox *edp.Outbox
+}
+
+func NewEDPSink(client *edp.Client, outbox *edp.Outbox) *EDPSink {
+	return &EDPSink{client: client, outbox: outbox}
+}
+
+func (s *EDPSink) Name() string {
+	return EDPSinkName
+}
+
+// Send sends the metric as an event stream, EDP refers SubAccountID as tenant
+func (s *EDPSink) Send(metric edp.ConsumptionMetrics) error {
+	payload, err := json.Marshal(metric)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal metric")
+	}
+
+	// The event is sent from the outbox, so that it is not lost when EDP is unavailable
+	if s.outbox != nil {
+		return errors.Wrapf(s.outbox.Enqueue(metric.SubAccountId, payload), "failed to store event-stream in EDP outbox")
+	}
+
+	edpRequest, err := s.client.NewRequest(metric.SubAccountId)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create a new request for EDP")
+	}
+
+	resp, err := s.client.Send(edpRequest, payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send event-stream to EDP")
+	}
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("failed to send event-stream to EDP as it returned HTTP: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func isSuccess(status int) bool {
+	return status >= http.StatusOK && status < http.StatusMultipleChoices
+}
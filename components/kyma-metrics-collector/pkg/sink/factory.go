@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+// NewSinks creates the fan-out to the enabled sinks, EDP is the primary sink
+func NewSinks(config Config, edpClient *edp.Client, edpOutbox *edp.Outbox, logger *zap.SugaredLogger) (*FanOut, error) {
+	var primary Sink
+	if config.EDPEnabled {
+		primary = NewEDPSink(edpClient, edpOutbox)
+	}
+
+	var sinks []Sink
+	if config.PrometheusEnabled {
+		sinks = append(sinks, NewPrometheusSink())
+	}
+	if config.FileEnabled {
+		fileSink, err := NewFileSink(config.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+	if config.HTTPEnabled {
+		httpSink, err := NewHTTPSink(config)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, httpSink)
+	}
+
+	return NewFanOut(logger, config.QueueSize, primary, sinks...), nil
+}
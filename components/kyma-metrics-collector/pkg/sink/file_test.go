@@ -0,0 +1,34 @@
+package sink
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+func TestFileSink(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	path := filepath.Join(t.TempDir(), "metrics", "metrics.jsonl")
+
+	fileSink, err := NewFileSink(path)
+	g.Expect(err).Should(gomega.BeNil())
+	metric := newTestMetric()
+	g.Expect(fileSink.Send(metric)).Should(gomega.Succeed())
+	g.Expect(fileSink.Send(metric)).Should(gomega.Succeed())
+	g.Expect(fileSink.Close()).Should(gomega.Succeed())
+
+	data, err := os.ReadFile(path)
+	g.Expect(err).Should(gomega.BeNil())
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	g.Expect(lines).Should(gomega.HaveLen(2))
+
+	var written edp.ConsumptionMetrics
+	g.Expect(json.Unmarshal([]byte(lines[1]), &written)).Should(gomega.Succeed())
+	g.Expect(written).Should(gomega.Equal(metric))
+}
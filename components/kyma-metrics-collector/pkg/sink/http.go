@@ -0,0 +1,116 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+const (
+	HTTPSinkName = "http"
+
+	// HTTPFormatJSON posts the metric as JSON
+	HTTPFormatJSON = "json"
+	// HTTPFormatKafkaREST posts the metric as a record keyed by the subaccount to a Kafka REST Proxy topic
+	HTTPFormatKafkaREST = "kafka-rest"
+
+	jsonContentType      = "application/json"
+	kafkaRESTContentType = "application/vnd.kafka.json.v2+json"
+	userAgentKMC         = "kyma-metrics-collector"
+)
+
+// HTTPSink posts the metrics to a generic HTTP endpoint
+type HTTPSink struct {
+	httpClient *http.Client
+	url        string
+	format     string
+	token      string
+	backoff    wait.Backoff
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string                 `json:"key"`
+	Value edp.ConsumptionMetrics `json:"value"`
+}
+
+func NewHTTPSink(config Config) (*HTTPSink, error) {
+	if config.HTTPURL == "" {
+		return nil, fmt.Errorf("URL is required for the %s sink", HTTPSinkName)
+	}
+	if config.HTTPFormat != HTTPFormatJSON && config.HTTPFormat != HTTPFormatKafkaREST {
+		return nil, fmt.Errorf("unknown %s sink format %q", HTTPSinkName, config.HTTPFormat)
+	}
+
+	return &HTTPSink{
+		httpClient: &http.Client{Transport: http.DefaultTransport, Timeout: config.HTTPTimeout},
+		url:        config.HTTPURL,
+		format:     config.HTTPFormat,
+		token:      config.HTTPToken,
+		backoff: wait.Backoff{
+			Steps:    config.HTTPRetry,
+			Duration: time.Second,
+			Factor:   2.0,
+			Jitter:   0.1,
+		},
+	}, nil
+}
+
+func (s *HTTPSink) Name() string {
+	return HTTPSinkName
+}
+
+func (s *HTTPSink) Send(metric edp.ConsumptionMetrics) error {
+	contentType := jsonContentType
+	var body interface{} = metric
+	if s.format == HTTPFormatKafkaREST {
+		contentType = kafkaRESTContentType
+		body = kafkaRecords{Records: []kafkaRecord{{Key: metric.SubAccountId, Value: metric}}}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal metric")
+	}
+
+	err = retry.OnError(s.backoff, func(error) bool { return true }, func() error {
+		return s.post(contentType, payload)
+	})
+	return errors.Wrapf(err, "failed to POST metric to %s", s.url)
+}
+
+func (s *HTTPSink) post(contentType string, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgentKMC)
+	req.Header.Set("Content-Type", contentType)
+	if s.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if !isSuccess(resp.StatusCode) {
+		return fmt.Errorf("endpoint returned HTTP: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package sink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+func TestHTTPSink(t *testing.T) {
+	metric := newTestMetric()
+
+	t.Run("posts metric as JSON and retries on failure", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			calls++
+			g.Expect(req.Header.Get("Content-Type")).Should(gomega.Equal(jsonContentType))
+			g.Expect(req.Header.Get("Authorization")).Should(gomega.Equal("Bearer token"))
+
+			var received edp.ConsumptionMetrics
+			g.Expect(json.NewDecoder(req.Body).Decode(&received)).Should(gomega.Succeed())
+			g.Expect(received).Should(gomega.Equal(metric))
+
+			if calls == 1 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		httpSink, err := NewHTTPSink(Config{HTTPURL: srv.URL, HTTPFormat: HTTPFormatJSON, HTTPToken: "token", HTTPTimeout: time.Second, HTTPRetry: 2})
+		g.Expect(err).Should(gomega.BeNil())
+		httpSink.backoff.Duration = time.Millisecond
+
+		g.Expect(httpSink.Send(metric)).Should(gomega.Succeed())
+		g.Expect(calls).Should(gomega.Equal(2))
+	})
+
+	t.Run("posts metric as Kafka REST Proxy record", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			g.Expect(req.Header.Get("Content-Type")).Should(gomega.Equal(kafkaRESTContentType))
+
+			var received kafkaRecords
+			g.Expect(json.NewDecoder(req.Body).Decode(&received)).Should(gomega.Succeed())
+			g.Expect(received.Records).Should(gomega.Equal([]kafkaRecord{{Key: "subaccount", Value: metric}}))
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		httpSink, err := NewHTTPSink(Config{HTTPURL: srv.URL, HTTPFormat: HTTPFormatKafkaREST, HTTPTimeout: time.Second, HTTPRetry: 1})
+		g.Expect(err).Should(gomega.BeNil())
+
+		g.Expect(httpSink.Send(metric)).Should(gomega.Succeed())
+	})
+
+	t.Run("fails after retries", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		httpSink, err := NewHTTPSink(Config{HTTPURL: srv.URL, HTTPFormat: HTTPFormatJSON, HTTPTimeout: time.Second, HTTPRetry: 2})
+		g.Expect(err).Should(gomega.BeNil())
+		httpSink.backoff.Duration = time.Millisecond
+
+		err = httpSink.Send(metric)
+		g.Expect(err).ShouldNot(gomega.BeNil())
+		g.Expect(err.Error()).Should(gomega.ContainSubstring("endpoint returned HTTP: 500"))
+	})
+
+	t.Run("fails for unknown format", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		_, err := NewHTTPSink(Config{HTTPURL: "http://localhost", HTTPFormat: "xml"})
+		g.Expect(err).ShouldNot(gomega.BeNil())
+	})
+}
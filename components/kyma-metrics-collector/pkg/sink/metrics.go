@@ -0,0 +1,121 @@
+package sink
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	namespace = "kmc"
+	subsystem = "sink"
+
+	runtimeSubsystem = "runtime"
+
+	successStatusLabel = "success"
+	failureStatusLabel = "failure"
+)
+
+var (
+	sentTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "sent_total",
+			Help:      "Total number of metrics sent to the sinks.",
+		},
+		[]string{"sink", "status"},
+	)
+
+	droppedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "dropped_total",
+			Help:      "Total number of metrics dropped as the queue of the sink was full.",
+		},
+		[]string{"sink"},
+	)
+
+	sendDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "send_duration_seconds",
+			Help:      "Duration of sending metrics to the sinks in seconds.",
+			Buckets:   []float64{0.01, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"sink"},
+	)
+
+	runtimeProvisionedCPUs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: runtimeSubsystem,
+			Name:      "provisioned_cpus",
+			Help:      "Number of CPUs provisioned for the runtime.",
+		},
+		runtimeLabels,
+	)
+
+	runtimeProvisionedRAM = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: runtimeSubsystem,
+			Name:      "provisioned_ram_gb",
+			Help:      "Memory provisioned for the runtime in GB.",
+		},
+		runtimeLabels,
+	)
+
+	runtimeProvisionedVolumes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: runtimeSubsystem,
+			Name:      "provisioned_volumes",
+			Help:      "Number of volumes provisioned for the runtime.",
+		},
+		runtimeLabels,
+	)
+
+	runtimeProvisionedVolumesSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: runtimeSubsystem,
+			Name:      "provisioned_volumes_size_gb",
+			Help:      "Total size of the volumes provisioned for the runtime in GB.",
+		},
+		runtimeLabels,
+	)
+
+	runtimeProvisionedIPs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: runtimeSubsystem,
+			Name:      "provisioned_ips",
+			Help:      "Number of IPs provisioned for the runtime.",
+		},
+		runtimeLabels,
+	)
+
+	runtimeProvisionedVnets = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: runtimeSubsystem,
+			Name:      "provisioned_vnets",
+			Help:      "Number of virtual networks provisioned for the runtime.",
+		},
+		runtimeLabels,
+	)
+
+	runtimeVMs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: runtimeSubsystem,
+			Name:      "vms",
+			Help:      "Number of VMs of the given type provisioned for the runtime.",
+		},
+		append(runtimeLabels, "vm_type"),
+	)
+)
+
+var runtimeLabels = []string{"runtime_id", "sub_account_id", "shoot_name"}
@@ -0,0 +1,95 @@
+package sink
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+)
+
+const PrometheusSinkName = "prometheus"
+
+// PrometheusSink exposes the last metrics of every runtime as gauges labelled with the runtime and subaccount
+type PrometheusSink struct {
+	mu sync.Mutex
+	// exposed holds the labels of the gauges set for the subaccount, so that they can be deleted
+	exposed map[string]exposedRuntime
+}
+
+type exposedRuntime struct {
+	labels  prometheus.Labels
+	vmTypes []string
+}
+
+func NewPrometheusSink() *PrometheusSink {
+	return &PrometheusSink{exposed: map[string]exposedRuntime{}}
+}
+
+func (s *PrometheusSink) Name() string {
+	return PrometheusSinkName
+}
+
+func (s *PrometheusSink) Send(metric edp.ConsumptionMetrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The runtime or shoot of the subaccount may have changed, so the old gauges are removed first
+	s.deleteLocked(metric.SubAccountId)
+
+	labels := prometheus.Labels{
+		"runtime_id":     metric.RuntimeId,
+		"sub_account_id": metric.SubAccountId,
+		"shoot_name":     metric.ShootName,
+	}
+	runtimeProvisionedCPUs.With(labels).Set(float64(metric.Compute.ProvisionedCpus))
+	runtimeProvisionedRAM.With(labels).Set(metric.Compute.ProvisionedRAMGb)
+	runtimeProvisionedVolumes.With(labels).Set(float64(metric.Compute.ProvisionedVolumes.Count))
+	runtimeProvisionedVolumesSize.With(labels).Set(float64(metric.Compute.ProvisionedVolumes.SizeGbTotal))
+	runtimeProvisionedIPs.With(labels).Set(float64(metric.Networking.ProvisionedIPs))
+	runtimeProvisionedVnets.With(labels).Set(float64(metric.Networking.ProvisionedVnets))
+
+	vmCounts := map[string]int{}
+	for _, vmType := range metric.Compute.VMTypes {
+		vmCounts[vmType.Name] += vmType.Count
+	}
+	vmTypes := make([]string, 0, len(vmCounts))
+	for vmType, count := range vmCounts {
+		runtimeVMs.With(withVMType(labels, vmType)).Set(float64(count))
+		vmTypes = append(vmTypes, vmType)
+	}
+
+	s.exposed[metric.SubAccountId] = exposedRuntime{labels: labels, vmTypes: vmTypes}
+	return nil
+}
+
+// Forget removes the gauges of the subaccount which is not tracked anymore
+func (s *PrometheusSink) Forget(subAccountID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteLocked(subAccountID)
+}
+
+func (s *PrometheusSink) deleteLocked(subAccountID string) {
+	exposed, found := s.exposed[subAccountID]
+	if !found {
+		return
+	}
+
+	for _, gauge := range []*prometheus.GaugeVec{runtimeProvisionedCPUs, runtimeProvisionedRAM, runtimeProvisionedVolumes,
+		runtimeProvisionedVolumesSize, runtimeProvisionedIPs, runtimeProvisionedVnets} {
+		gauge.Delete(exposed.labels)
+	}
+	for _, vmType := range exposed.vmTypes {
+		runtimeVMs.Delete(withVMType(exposed.labels, vmType))
+	}
+	delete(s.exposed, subAccountID)
+}
+
+func withVMType(labels prometheus.Labels, vmType string) prometheus.Labels {
+	vmLabels := prometheus.Labels{"vm_type": vmType}
+	for name, value := range labels {
+		vmLabels[name] = value
+	}
+	return vmLabels
+}
@@ -0,0 +1,35 @@
+package sink
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusSink(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	promSink := NewPrometheusSink()
+	metric := newTestMetric()
+	labels := prometheus.Labels{"runtime_id": "runtime", "sub_account_id": "subaccount", "shoot_name": "shoot"}
+
+	g.Expect(promSink.Send(metric)).Should(gomega.Succeed())
+	g.Expect(testutil.ToFloat64(runtimeProvisionedCPUs.With(labels))).Should(gomega.Equal(float64(16)))
+	g.Expect(testutil.ToFloat64(runtimeProvisionedRAM.With(labels))).Should(gomega.Equal(float64(64)))
+	g.Expect(testutil.ToFloat64(runtimeProvisionedVolumesSize.With(labels))).Should(gomega.Equal(float64(80)))
+	g.Expect(testutil.ToFloat64(runtimeProvisionedIPs.With(labels))).Should(gomega.Equal(float64(2)))
+	g.Expect(testutil.ToFloat64(runtimeVMs.With(withVMType(labels, "m5.xlarge")))).Should(gomega.Equal(float64(2)))
+	g.Expect(testutil.CollectAndCount(runtimeVMs)).Should(gomega.Equal(2))
+
+	// Gauges of the previous shoot are removed
+	metric.ShootName = "new-shoot"
+	metric.Compute.VMTypes = metric.Compute.VMTypes[:1]
+	g.Expect(promSink.Send(metric)).Should(gomega.Succeed())
+	g.Expect(testutil.CollectAndCount(runtimeProvisionedCPUs)).Should(gomega.Equal(1))
+	g.Expect(testutil.CollectAndCount(runtimeVMs)).Should(gomega.Equal(1))
+
+	promSink.Forget("subaccount")
+	g.Expect(testutil.CollectAndCount(runtimeProvisionedCPUs)).Should(gomega.Equal(0))
+	g.Expect(testutil.CollectAndCount(runtimeVMs)).Should(gomega.Equal(0))
+}
@@ -0,0 +1,141 @@
+package sink
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+// Sink is a destination of the metrics generated for the runtimes. Sinks retry sending on their own.
+type Sink interface {
+	Name() string
+	Send(metric edp.ConsumptionMetrics) error
+}
+
+// Forgetter is implemented by the sinks which keep state of the runtimes, for example Prometheus gauges
+type Forgetter interface {
+	Forget(subAccountID string)
+}
+
+// FanOut sends the metrics to the primary sink and queues them for the auxiliary sinks. Every auxiliary sink
+// sends from its own bounded queue, so that a slow or failing auxiliary sink neither delays the workers nor fails
+// the send of the metric.
+type FanOut struct {
+	primary   Sink
+	auxiliary []*queuedSink
+	logger    *zap.SugaredLogger
+}
+
+// queuedSink sends the queued metrics and forgets the queued subaccounts in the order they were queued
+type queuedSink struct {
+	sink  Sink
+	queue chan queuedItem
+}
+
+type queuedItem struct {
+	metric       *edp.ConsumptionMetrics
+	subAccountID string
+}
+
+// NewFanOut creates the fan-out to the primary sink, which can be nil, and to the auxiliary sinks. Every auxiliary
+// sink queues at most queueSize metrics.
+func NewFanOut(logger *zap.SugaredLogger, queueSize int, primary Sink, auxiliary ...Sink) *FanOut {
+	fanOut := &FanOut{primary: primary, logger: logger}
+	for _, sink := range auxiliary {
+		queued := &queuedSink{
+			sink:  sink,
+			queue: make(chan queuedItem, queueSize),
+		}
+		go fanOut.run(queued)
+		fanOut.auxiliary = append(fanOut.auxiliary, queued)
+	}
+	return fanOut
+}
+
+func (f *FanOut) Name() string {
+	return "fan-out"
+}
+
+// Send returns the error of the primary sink only. The metric is dropped for the auxiliary sinks with a full queue.
+func (f *FanOut) Send(metric edp.ConsumptionMetrics) error {
+	for _, queued := range f.auxiliary {
+		select {
+		case queued.queue <- queuedItem{metric: &metric}:
+		default:
+			droppedTotal.WithLabelValues(queued.sink.Name()).Inc()
+			f.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeySubAccountID, metric.SubAccountId).
+				With(log.KeyRuntimeID, metric.RuntimeId).Warnf("queue of %s sink is full, dropping metric", queued.sink.Name())
+		}
+	}
+
+	if f.primary == nil {
+		return nil
+	}
+	if err := f.send(f.primary, metric); err != nil {
+		return fmt.Errorf("%s sink: %w", f.primary.Name(), err)
+	}
+	return nil
+}
+
+func (f *FanOut) run(queued *queuedSink) {
+	for item := range queued.queue {
+		if item.metric != nil {
+			_ = f.send(queued.sink, *item.metric)
+			continue
+		}
+		if forgetter, ok := queued.sink.(Forgetter); ok {
+			forgetter.Forget(item.subAccountID)
+		}
+	}
+}
+
+func (f *FanOut) send(sink Sink, metric edp.ConsumptionMetrics) error {
+	timer := prometheus.NewTimer(sendDuration.WithLabelValues(sink.Name()))
+	err := sink.Send(metric)
+	timer.ObserveDuration()
+
+	if err != nil {
+		sentTotal.WithLabelValues(sink.Name(), failureStatusLabel).Inc()
+		f.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			With(log.KeySubAccountID, metric.SubAccountId).With(log.KeyRuntimeID, metric.RuntimeId).
+			Errorf("send metric to %s sink", sink.Name())
+		return err
+	}
+	sentTotal.WithLabelValues(sink.Name(), successStatusLabel).Inc()
+	return nil
+}
+
+// Forget is queued for the auxiliary sinks, so that the state of the subaccount is not restored by a metric
+// queued before
+func (f *FanOut) Forget(subAccountID string) {
+	if forgetter, ok := f.primary.(Forgetter); ok {
+		forgetter.Forget(subAccountID)
+	}
+	for _, queued := range f.auxiliary {
+		if _, ok := queued.sink.(Forgetter); !ok {
+			continue
+		}
+		// unlike a dropped metric, a dropped Forget would keep the state of the subaccount forever
+		queued.queue <- queuedItem{subAccountID: subAccountID}
+	}
+}
+
+// Names returns the names of the sinks the metrics are sent to, the primary sink first
+func (f *FanOut) Names() []string {
+	var names []string
+	if f.primary != nil {
+		names = append(names, f.primary.Name())
+	}
+	for _, queued := range f.auxiliary {
+		names = append(names, queued.sink.Name())
+	}
+	return names
+}
+
+func (f *FanOut) namedLogger() *zap.SugaredLogger {
+	return f.logger.With("component", "sink")
+}
@@ -0,0 +1,167 @@
+package sink
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/edp"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+type fakeSink struct {
+	name string
+	err  error
+	// block makes Send wait until it is closed
+	block chan struct{}
+
+	mu        sync.Mutex
+	sent      []edp.ConsumptionMetrics
+	forgotten []string
+}
+
+func (s *fakeSink) Name() string {
+	return s.name
+}
+
+func (s *fakeSink) Send(metric edp.ConsumptionMetrics) error {
+	if s.block != nil {
+		<-s.block
+	}
+	if s.err != nil {
+		return s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, metric)
+	return nil
+}
+
+func (s *fakeSink) Forget(subAccountID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forgotten = append(s.forgotten, subAccountID)
+}
+
+func (s *fakeSink) getSent() []edp.ConsumptionMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]edp.ConsumptionMetrics{}, s.sent...)
+}
+
+func (s *fakeSink) getForgotten() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.forgotten...)
+}
+
+func newTestMetric() edp.ConsumptionMetrics {
+	return edp.ConsumptionMetrics{
+		RuntimeId:    "runtime",
+		SubAccountId: "subaccount",
+		ShootName:    "shoot",
+		Compute: edp.Compute{
+			VMTypes:            []edp.VMType{{Name: "m5.xlarge", Count: 2}, {Name: "m5.2xlarge", Count: 1}},
+			ProvisionedCpus:    16,
+			ProvisionedRAMGb:   64,
+			ProvisionedVolumes: edp.ProvisionedVolumes{SizeGbTotal: 80, Count: 2, SizeGbRounded: 96},
+		},
+		Networking: edp.Networking{ProvisionedVnets: 1, ProvisionedIPs: 2},
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	log := logger.NewLogger(zapcore.InfoLevel)
+	metric := newTestMetric()
+
+	t.Run("returns the error of the primary sink", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		sentTotal.Reset()
+
+		primary := &fakeSink{name: "primary", err: fmt.Errorf("endpoint returned HTTP: 503")}
+		auxiliary := &fakeSink{name: "auxiliary"}
+		fanOut := NewFanOut(log, 10, primary, auxiliary)
+
+		err := fanOut.Send(metric)
+		g.Expect(err).ShouldNot(gomega.BeNil())
+		g.Expect(err.Error()).Should(gomega.Equal("primary sink: endpoint returned HTTP: 503"))
+		g.Eventually(auxiliary.getSent).Should(gomega.Equal([]edp.ConsumptionMetrics{metric}))
+
+		g.Expect(testutil.ToFloat64(sentTotal.WithLabelValues("primary", failureStatusLabel))).Should(gomega.Equal(float64(1)))
+		g.Eventually(func() float64 {
+			return testutil.ToFloat64(sentTotal.WithLabelValues("auxiliary", successStatusLabel))
+		}).Should(gomega.Equal(float64(1)))
+	})
+
+	t.Run("does not fail or wait for the auxiliary sinks", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		sentTotal.Reset()
+		droppedTotal.Reset()
+
+		primary := &fakeSink{name: "primary"}
+		failing := &fakeSink{name: "failing", err: fmt.Errorf("endpoint returned HTTP: 503")}
+		blocked := &fakeSink{name: "blocked", block: make(chan struct{})}
+		fanOut := NewFanOut(log, 1, primary, failing, blocked)
+
+		// the first metric is taken from the queue of the blocked sink, the second one waits in it,
+		// and the third one is dropped
+		g.Expect(fanOut.Send(metric)).Should(gomega.Succeed())
+		g.Eventually(func() int { return len(fanOut.auxiliary[1].queue) }).Should(gomega.Equal(0))
+		g.Expect(fanOut.Send(metric)).Should(gomega.Succeed())
+		g.Expect(fanOut.Send(metric)).Should(gomega.Succeed())
+		g.Expect(primary.getSent()).Should(gomega.HaveLen(3))
+		g.Expect(testutil.ToFloat64(droppedTotal.WithLabelValues("blocked"))).Should(gomega.Equal(float64(1)))
+
+		g.Eventually(func() float64 {
+			return testutil.ToFloat64(sentTotal.WithLabelValues("failing", failureStatusLabel))
+		}).Should(gomega.Equal(float64(3)))
+
+		close(blocked.block)
+		g.Eventually(blocked.getSent).Should(gomega.HaveLen(2))
+	})
+
+	t.Run("forgets the subaccount after the queued metrics", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		primary := &fakeSink{name: "primary"}
+		auxiliary := &fakeSink{name: "auxiliary", block: make(chan struct{})}
+		fanOut := NewFanOut(log, 10, primary, auxiliary)
+
+		g.Expect(fanOut.Send(metric)).Should(gomega.Succeed())
+		fanOut.Forget("subaccount")
+		g.Expect(primary.getForgotten()).Should(gomega.Equal([]string{"subaccount"}))
+		g.Expect(auxiliary.getForgotten()).Should(gomega.BeEmpty())
+
+		close(auxiliary.block)
+		g.Eventually(auxiliary.getForgotten).Should(gomega.Equal([]string{"subaccount"}))
+		g.Expect(auxiliary.getSent()).Should(gomega.HaveLen(1))
+		g.Expect(fanOut.Names()).Should(gomega.Equal([]string{"primary", "auxiliary"}))
+	})
+
+	t.Run("sends only to the auxiliary sinks without the primary sink", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		auxiliary := &fakeSink{name: "auxiliary"}
+		fanOut := NewFanOut(log, 10, nil, auxiliary)
+
+		g.Expect(fanOut.Send(metric)).Should(gomega.Succeed())
+		g.Eventually(auxiliary.getSent).Should(gomega.HaveLen(1))
+		g.Expect(fanOut.Names()).Should(gomega.Equal([]string{"auxiliary"}))
+	})
+}
+
+func TestNewSinks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	log := logger.NewLogger(zapcore.InfoLevel)
+
+	sinks, err := NewSinks(Config{EDPEnabled: true, PrometheusEnabled: true}, nil, nil, log)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(sinks.Names()).Should(gomega.Equal([]string{EDPSinkName, PrometheusSinkName}))
+
+	_, err = NewSinks(Config{HTTPEnabled: true, HTTPFormat: HTTPFormatJSON}, nil, nil, log)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+}
@@ -6,14 +6,16 @@ import (
 )
 
 const (
-	SuccessListingSVCLabel   = "success_listing_svc"
-	SuccessListingPVCLabel   = "success_listing_pvc"
-	SuccessListingNodesLabel = "success_listing_nodes"
-	SuccessStatusLabel       = "success"
-	CallsTotalLabel          = "calls_total"
-	ListingNodesLabel        = "listing_nodes"
-	ListingPVCLabel          = "listing_pvc"
-	ListingSVCLabel          = "listing_svc"
+	SuccessListingSVCLabel            = "success_listing_svc"
+	SuccessListingPVCLabel            = "success_listing_pvc"
+	SuccessListingNodesLabel          = "success_listing_nodes"
+	SuccessListingStorageClassesLabel = "success_listing_storage_classes"
+	SuccessStatusLabel                = "success"
+	CallsTotalLabel                   = "calls_total"
+	ListingNodesLabel                 = "listing_nodes"
+	ListingPVCLabel                   = "listing_pvc"
+	ListingSVCLabel                   = "listing_svc"
+	ListingStorageClassesLabel        = "listing_storage_classes"
 )
 
 var (
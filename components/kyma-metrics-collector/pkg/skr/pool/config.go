@@ -0,0 +1,15 @@
+package pool
+
+import "time"
+
+type Config struct {
+	Enabled        bool          `envconfig:"SKR_POOL_ENABLED" default:"false"`
+	MaxConnections int           `envconfig:"SKR_POOL_MAX_CONNECTIONS" default:"1000"`
+	MemoryBudgetMB int           `envconfig:"SKR_POOL_MEMORY_BUDGET_MB" default:"512"`
+	SyncTimeout    time.Duration `envconfig:"SKR_POOL_SYNC_TIMEOUT" default:"1m"`
+	// EvictionIdleTime is the minimum time a cache was not used before it is stopped to make room for another runtime,
+	// so that the caches are not restarted on every scrape when more runtimes than MaxConnections are scraped in turn
+	EvictionIdleTime time.Duration `envconfig:"SKR_POOL_EVICTION_IDLE_TIME" default:"30m"`
+	// StaleTimeout is the time after which a cache whose watch keeps failing is stopped instead of being used
+	StaleTimeout time.Duration `envconfig:"SKR_POOL_STALE_TIMEOUT" default:"5m"`
+}
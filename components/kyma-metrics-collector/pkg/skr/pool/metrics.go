@@ -0,0 +1,50 @@
+package pool
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	evictedRemoved           = "removed"
+	evictedKubeconfigChanged = "kubeconfig_changed"
+	evictedMaxConnections    = "max_connections"
+	evictedMemoryBudget      = "memory_budget"
+	evictedStale             = "stale"
+)
+
+var (
+	runtimes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_pool",
+			Name:      "runtimes",
+			Help:      "Number of runtimes with started informers.",
+		},
+	)
+	cachedBytes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_pool",
+			Name:      "cached_bytes",
+			Help:      "Estimated size of the SKR resources cached by the informers in bytes.",
+		},
+	)
+	evictions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_pool",
+			Name:      "evictions_total",
+			Help:      "Total number of runtimes whose informers were stopped.",
+		},
+		[]string{"reason"},
+	)
+	overflows = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_pool",
+			Name:      "overflows_total",
+			Help:      "Total number of scrapes listing the SKR resources as the pool was full.",
+		},
+	)
+)
@@ -0,0 +1,258 @@
+package pool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+
+	log "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+)
+
+const megabyte = 1 << 20
+
+// ErrPoolFull is returned by Get when the runtime has no cache and no cache is idle long enough to be stopped,
+// the resources of the runtime are listed instead
+var ErrPoolFull = errors.New("SKR pool is full")
+
+// Pool keeps a RuntimeCache per runtime, so that the nodes, PVCs, Services and StorageClasses are watched instead of listed
+// on every scrape. The caches are started lazily and shared by all collectors of the runtime. When there are more
+// runtimes than MaxConnections or the cached objects exceed the MemoryBudgetMB, the least recently used caches not used
+// for the EvictionIdleTime are stopped, and started again on the next use. When no cache is idle that long, for example
+// when all runtimes are scraped in turn, the pool does not start a cache for the runtime, so that the caches are not
+// restarted on every scrape.
+type Pool struct {
+	config   Config
+	observer Observer
+	logger   *zap.SugaredLogger
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	newClient func(kubeconfig string) (dynamic.Interface, error)
+	timeNow   func() time.Time
+}
+
+// Observer is notified about the nodes, PVCs and Services added, updated or deleted in the watched SKRs.
+// The objects are *corev1.Node, *corev1.PersistentVolumeClaim or *corev1.Service.
+type Observer interface {
+	Observe(key string, object k8sruntime.Object, deleted bool)
+}
+
+type entry struct {
+	kubeconfigHash string
+	lastUsed       time.Time
+	// ready is closed when the cache is started or failed to start
+	ready chan struct{}
+	cache *RuntimeCache
+	err   error
+}
+
+// NewPool creates the pool, the observer is optional
+func NewPool(config Config, observer Observer, logger *zap.SugaredLogger) *Pool {
+	return &Pool{
+		config:    config,
+		observer:  observer,
+		logger:    logger,
+		entries:   map[string]*entry{},
+		newClient: newDynamicClient,
+		timeNow:   time.Now,
+	}
+}
+
+// Get returns the cache of the runtime identified by the key, it is started when it does not exist yet
+// or when the kubeconfig changed. It returns ErrPoolFull when the pool has no room for the cache, and
+// an error when the watch of the cache has been failing for longer than the StaleTimeout.
+func (p *Pool) Get(key, kubeconfig string) (*RuntimeCache, error) {
+	kubeconfigHash := hash(kubeconfig)
+
+	p.mu.Lock()
+	e, found := p.entries[key]
+	if found && e.kubeconfigHash != kubeconfigHash {
+		p.removeLocked(key, evictedKubeconfigChanged)
+		found = false
+	}
+	if !found {
+		for p.config.MaxConnections > 0 && len(p.entries) >= p.config.MaxConnections {
+			if !p.evictLeastRecentlyUsedLocked(key, evictedMaxConnections) {
+				p.updateMetricsLocked()
+				p.mu.Unlock()
+				overflows.Inc()
+				return nil, ErrPoolFull
+			}
+		}
+		e = &entry{kubeconfigHash: kubeconfigHash, ready: make(chan struct{})}
+		p.entries[key] = e
+		e.lastUsed = p.timeNow()
+		p.mu.Unlock()
+
+		p.start(key, kubeconfig, e)
+	} else {
+		e.lastUsed = p.timeNow()
+		p.mu.Unlock()
+	}
+
+	<-e.ready
+	if e.err != nil {
+		if errors.Is(e.err, ErrPoolFull) {
+			overflows.Inc()
+		}
+		return nil, e.err
+	}
+	if since, failing := e.cache.FailingSince(); failing && p.config.StaleTimeout > 0 && p.timeNow().Sub(since) > p.config.StaleTimeout {
+		p.mu.Lock()
+		if p.entries[key] == e {
+			p.namedLogger().With(log.KeySubAccountID, key).Debugf("stop informers, reason: %s", evictedStale)
+			p.removeLocked(key, evictedStale)
+			p.updateMetricsLocked()
+		}
+		p.mu.Unlock()
+		return nil, fmt.Errorf("watch of the SKR resources failing since %s", since.Format(time.RFC3339))
+	}
+	return e.cache, nil
+}
+
+// Remove stops the cache of the runtime, e.g. when the runtime is not returned by KEB anymore
+func (p *Pool) Remove(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removeLocked(key, evictedRemoved)
+	p.updateMetricsLocked()
+}
+
+// Len returns the number of runtimes with a cache
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+func (p *Pool) start(key, kubeconfig string, e *entry) {
+	defer close(e.ready)
+
+	var runtimeCache *RuntimeCache
+	client, err := p.newClient(kubeconfig)
+	if err == nil {
+		runtimeCache, err = newRuntimeCache(client, p.config.SyncTimeout, p.notify(key))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.cache = runtimeCache
+	if err != nil {
+		e.err = err
+		if p.entries[key] == e {
+			delete(p.entries, key)
+		}
+		p.namedLogger().With(log.KeySubAccountID, key).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			Warn("start informers")
+		return
+	}
+	if p.entries[key] != e {
+		// removed while starting
+		e.cache.Stop()
+		return
+	}
+
+	if !p.evictLocked(key) {
+		// there is no room for the new cache
+		e.err = ErrPoolFull
+		p.removeLocked(key, evictedMemoryBudget)
+	}
+	p.updateMetricsLocked()
+}
+
+// evictLocked stops the least recently used idle caches, except the one of the given key, until the memory budget
+// is not exceeded anymore. It returns false when the budget is still exceeded.
+func (p *Pool) evictLocked(key string) bool {
+	for p.config.MemoryBudgetMB > 0 && p.sizeLocked() > int64(p.config.MemoryBudgetMB)*megabyte {
+		if !p.evictLeastRecentlyUsedLocked(key, evictedMemoryBudget) {
+			return false
+		}
+	}
+	return true
+}
+
+// evictLeastRecentlyUsedLocked stops the least recently used cache, which was not used for the EvictionIdleTime,
+// except the one of the given key. It returns false when there is no such cache.
+func (p *Pool) evictLeastRecentlyUsedLocked(key, reason string) bool {
+	idleSince := p.timeNow().Add(-p.config.EvictionIdleTime)
+	var leastRecentlyUsed string
+	for k, e := range p.entries {
+		if k == key || e.cache == nil || e.lastUsed.After(idleSince) {
+			// the cache is in use, still starting or not idle
+			continue
+		}
+		if leastRecentlyUsed == "" || e.lastUsed.Before(p.entries[leastRecentlyUsed].lastUsed) {
+			leastRecentlyUsed = k
+		}
+	}
+	if leastRecentlyUsed == "" {
+		return false
+	}
+
+	p.namedLogger().With(log.KeySubAccountID, leastRecentlyUsed).Debugf("stop informers, reason: %s", reason)
+	p.removeLocked(leastRecentlyUsed, reason)
+	return true
+}
+
+func (p *Pool) removeLocked(key, reason string) {
+	e, found := p.entries[key]
+	if !found {
+		return
+	}
+	delete(p.entries, key)
+	if e.cache != nil {
+		e.cache.Stop()
+	}
+	evictions.WithLabelValues(reason).Inc()
+}
+
+func (p *Pool) sizeLocked() int64 {
+	var size int64
+	for _, e := range p.entries {
+		if e.cache != nil {
+			size += e.cache.Size()
+		}
+	}
+	return size
+}
+
+func (p *Pool) updateMetricsLocked() {
+	runtimes.Set(float64(len(p.entries)))
+	cachedBytes.Set(float64(p.sizeLocked()))
+}
+
+// notify returns the function passing the events of the runtime to the observer
+func (p *Pool) notify(key string) func(object k8sruntime.Object, deleted bool) {
+	if p.observer == nil {
+		return nil
+	}
+	return func(object k8sruntime.Object, deleted bool) {
+		p.observer.Observe(key, object, deleted)
+	}
+}
+
+func (p *Pool) namedLogger() *zap.SugaredLogger {
+	return p.logger.Named("skr-pool")
+}
+
+func newDynamicClient(kubeconfig string) (dynamic.Interface, error) {
+	restClientConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(restClientConfig)
+}
+
+func hash(kubeconfig string) string {
+	sum := sha256.Sum256([]byte(kubeconfig))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,283 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/gardener/commons"
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/logger"
+	skrnode "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/node"
+	skrpvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/pvc"
+	skrstorageclass "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/storageclass"
+	skrsvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/svc"
+	kmctesting "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/testing"
+)
+
+// fakeClients returns a fake SKR per kubeconfig
+type fakeClients struct {
+	clients map[string]dynamic.Interface
+	created int
+}
+
+func newFakeClients() *fakeClients {
+	return &fakeClients{clients: map[string]dynamic.Interface{}}
+}
+
+func (f *fakeClients) add(t *testing.T, kubeconfig string, objects ...k8sruntime.Object) dynamic.Interface {
+	scheme, err := commons.SetupSchemeOrDie()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{
+			skrnode.GroupVersionResource():         "NodeList",
+			skrpvc.GroupVersionResource():          "PersistentVolumeClaimList",
+			skrsvc.GroupVersionResource():          "ServiceList",
+			skrstorageclass.GroupVersionResource(): "StorageClassList",
+		}, objects...)
+	f.clients[kubeconfig] = client
+	return client
+}
+
+func (f *fakeClients) newClient(kubeconfig string) (dynamic.Interface, error) {
+	client, found := f.clients[kubeconfig]
+	if !found {
+		return nil, fmt.Errorf("invalid kubeconfig")
+	}
+	f.created++
+	return client, nil
+}
+
+func newTestPool(config Config, clients *fakeClients) *Pool {
+	config.SyncTimeout = 5 * time.Second
+	pool := NewPool(config, nil, logger.NewLogger(zapcore.InfoLevel))
+	pool.newClient = clients.newClient
+	return pool
+}
+
+func TestPool(t *testing.T) {
+	t.Run("caches and watches the SKR resources", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		node := kmctesting.GetNode("node1", "Standard_D8_v3")
+		node.Annotations = map[string]string{"node.alpha.kubernetes.io/ttl": "0"}
+		clients := newFakeClients()
+		storageClass := kmctesting.GetAzureStorageClasses().Items[0]
+		client := clients.add(t, "kubeconfig", &node, kmctesting.GetPV("pvc1", "default", "20Gi"), kmctesting.GetSvc("svc1", "default"), &storageClass)
+		pool := newTestPool(Config{}, clients)
+
+		runtimeCache, err := pool.Get("subaccount", "kubeconfig")
+		g.Expect(err).Should(gomega.BeNil())
+		nodes, err := runtimeCache.Nodes()
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(nodes.Items).Should(gomega.HaveLen(1))
+		g.Expect(nodes.Items[0].Labels).Should(gomega.HaveKeyWithValue("node.kubernetes.io/instance-type", "Standard_D8_v3"))
+		g.Expect(nodes.Items[0].Annotations).Should(gomega.BeEmpty())
+		pvcs, err := runtimeCache.PVCs()
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(pvcs.Items).Should(gomega.HaveLen(1))
+		services, err := runtimeCache.Services()
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(services.Items).Should(gomega.HaveLen(1))
+		storageClasses, err := runtimeCache.StorageClasses()
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(storageClasses.Items).Should(gomega.HaveLen(1))
+		// The annotation marking the default StorageClass is kept
+		g.Expect(storageClasses.Items[0].Annotations).Should(gomega.HaveKeyWithValue("storageclass.kubernetes.io/is-default-class", "true"))
+		g.Expect(runtimeCache.Size()).Should(gomega.BeNumerically(">", 0))
+
+		// New nodes are added by the watch
+		newNode := kmctesting.GetNode("node2", "Standard_D8_v3")
+		object, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(&newNode)
+		g.Expect(err).Should(gomega.BeNil())
+		_, err = client.Resource(skrnode.GroupVersionResource()).Create(context.Background(), &unstructured.Unstructured{Object: object}, metaV1.CreateOptions{})
+		g.Expect(err).Should(gomega.BeNil())
+		g.Eventually(func() int {
+			nodes, _ := runtimeCache.Nodes()
+			return len(nodes.Items)
+		}, 5*time.Second, 10*time.Millisecond).Should(gomega.Equal(2))
+
+		// The cache is shared
+		sameCache, err := pool.Get("subaccount", "kubeconfig")
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(sameCache).Should(gomega.BeIdenticalTo(runtimeCache))
+		g.Expect(clients.created).Should(gomega.Equal(1))
+
+		pool.Remove("subaccount")
+		g.Expect(pool.Len()).Should(gomega.Equal(0))
+	})
+
+	t.Run("restarts the cache when the kubeconfig changes", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clients := newFakeClients()
+		clients.add(t, "kubeconfig", kmctesting.Get2Nodes())
+		clients.add(t, "rotated-kubeconfig", kmctesting.Get2Nodes())
+		pool := newTestPool(Config{}, clients)
+
+		runtimeCache, err := pool.Get("subaccount", "kubeconfig")
+		g.Expect(err).Should(gomega.BeNil())
+		rotatedCache, err := pool.Get("subaccount", "rotated-kubeconfig")
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(rotatedCache).ShouldNot(gomega.BeIdenticalTo(runtimeCache))
+		g.Expect(pool.Len()).Should(gomega.Equal(1))
+		g.Expect(clients.created).Should(gomega.Equal(2))
+	})
+
+	t.Run("stops the least recently used cache over max connections", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clients := newFakeClients()
+		for _, kubeconfig := range []string{"kubeconfig-a", "kubeconfig-b", "kubeconfig-c"} {
+			clients.add(t, kubeconfig, kmctesting.Get2Nodes())
+		}
+		pool := newTestPool(Config{MaxConnections: 2}, clients)
+		now := time.Now()
+		pool.timeNow = func() time.Time { return now }
+
+		for _, key := range []string{"a", "b", "a", "c"} {
+			now = now.Add(time.Second)
+			_, err := pool.Get(key, "kubeconfig-"+key)
+			g.Expect(err).Should(gomega.BeNil())
+		}
+
+		g.Expect(pool.Len()).Should(gomega.Equal(2))
+		g.Expect(pool.entries).Should(gomega.HaveKey("a"))
+		g.Expect(pool.entries).Should(gomega.HaveKey("c"))
+	})
+
+	t.Run("does not restart the caches when the runtimes are used in turn", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clients := newFakeClients()
+		for _, kubeconfig := range []string{"kubeconfig-a", "kubeconfig-b", "kubeconfig-c"} {
+			clients.add(t, kubeconfig, kmctesting.Get2Nodes())
+		}
+		pool := newTestPool(Config{MaxConnections: 2, EvictionIdleTime: 10 * time.Minute}, clients)
+		now := time.Now()
+		pool.timeNow = func() time.Time { return now }
+
+		for i := 0; i < 3; i++ {
+			for _, key := range []string{"a", "b", "c"} {
+				now = now.Add(time.Minute)
+				_, err := pool.Get(key, "kubeconfig-"+key)
+				if key == "c" {
+					g.Expect(err).Should(gomega.Equal(ErrPoolFull))
+				} else {
+					g.Expect(err).Should(gomega.BeNil())
+				}
+			}
+		}
+		g.Expect(clients.created).Should(gomega.Equal(2))
+
+		// The idle cache is stopped for a new runtime
+		now = now.Add(time.Hour)
+		_, err := pool.Get("b", "kubeconfig-b")
+		g.Expect(err).Should(gomega.BeNil())
+		_, err = pool.Get("c", "kubeconfig-c")
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(pool.entries).Should(gomega.HaveKey("b"))
+		g.Expect(pool.entries).Should(gomega.HaveKey("c"))
+	})
+
+	t.Run("stops the least recently used cache over memory budget", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clients := newFakeClients()
+		for _, kubeconfig := range []string{"kubeconfig-a", "kubeconfig-b"} {
+			node := kmctesting.GetNode("node1", "Standard_D8_v3")
+			node.Labels["large"] = strings.Repeat("x", 600*1024)
+			clients.add(t, kubeconfig, &node)
+		}
+		pool := newTestPool(Config{MemoryBudgetMB: 1}, clients)
+
+		_, err := pool.Get("a", "kubeconfig-a")
+		g.Expect(err).Should(gomega.BeNil())
+		_, err = pool.Get("b", "kubeconfig-b")
+		g.Expect(err).Should(gomega.BeNil())
+
+		g.Expect(pool.Len()).Should(gomega.Equal(1))
+		g.Expect(pool.entries).Should(gomega.HaveKey("b"))
+	})
+
+	t.Run("stops the cache when the watch keeps failing", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		clients := newFakeClients()
+		client := clients.add(t, "kubeconfig", kmctesting.Get2Nodes())
+		client.(*dynamicfake.FakeDynamicClient).PrependWatchReactor("nodes", func(action k8stesting.Action) (bool, watch.Interface, error) {
+			return true, nil, fmt.Errorf("connection refused")
+		})
+		pool := newTestPool(Config{StaleTimeout: time.Minute}, clients)
+
+		runtimeCache, err := pool.Get("subaccount", "kubeconfig")
+		g.Expect(err).Should(gomega.BeNil())
+		g.Eventually(func() bool {
+			_, failing := runtimeCache.FailingSince()
+			return failing
+		}, 5*time.Second, 10*time.Millisecond).Should(gomega.BeTrue())
+
+		// The cache is used until the stale timeout is exceeded
+		_, err = pool.Get("subaccount", "kubeconfig")
+		g.Expect(err).Should(gomega.BeNil())
+
+		pool.timeNow = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		_, err = pool.Get("subaccount", "kubeconfig")
+		g.Expect(err).ShouldNot(gomega.BeNil())
+		g.Expect(pool.Len()).Should(gomega.Equal(0))
+	})
+
+	t.Run("fails for invalid kubeconfig", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		pool := newTestPool(Config{}, newFakeClients())
+
+		_, err := pool.Get("subaccount", "invalid")
+		g.Expect(err).ShouldNot(gomega.BeNil())
+		g.Expect(pool.Len()).Should(gomega.Equal(0))
+	})
+}
+
+func TestTrim(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	node := corev1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: "node1", Annotations: map[string]string{"foo": "bar"}},
+		Status: corev1.NodeStatus{
+			Images:     []corev1.ContainerImage{{Names: []string{"eu.gcr.io/kyma-project/foo:1.0"}}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady}},
+		},
+	}
+	object, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(&node)
+	g.Expect(err).Should(gomega.BeNil())
+
+	trimmed := &unstructured.Unstructured{Object: object}
+	trim(trimmed, skrnode.GroupVersionResource())
+	g.Expect(trimmed.GetName()).Should(gomega.Equal("node1"))
+	g.Expect(trimmed.GetAnnotations()).Should(gomega.BeEmpty())
+	_, found, _ := unstructured.NestedSlice(trimmed.Object, "status", "images")
+	g.Expect(found).Should(gomega.BeFalse())
+}
+
+func TestTrimKeepsStorageClassAnnotation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pvc := kmctesting.GetPV("pvc1", "default", "20Gi")
+	pvc.Annotations = map[string]string{
+		corev1.BetaStorageClassAnnotation:               "managed-premium",
+		"pv.kubernetes.io/bind-completed":               "yes",
+		"volume.beta.kubernetes.io/storage-provisioner": "disk.csi.azure.com",
+	}
+	object, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(pvc)
+	g.Expect(err).Should(gomega.BeNil())
+
+	trimmed := &unstructured.Unstructured{Object: object}
+	trim(trimmed, skrpvc.GroupVersionResource())
+	g.Expect(trimmed.GetAnnotations()).Should(gomega.Equal(map[string]string{corev1.BetaStorageClassAnnotation: "managed-premium"}))
+}
@@ -0,0 +1,321 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+
+	skrnode "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/node"
+	skrpvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/pvc"
+	skrstorageclass "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/storageclass"
+	skrsvc "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/svc"
+)
+
+// trimmedFields are removed from the cached objects as they are not used for the metrics
+var trimmedFields = map[schema.GroupVersionResource][][]string{
+	skrnode.GroupVersionResource(): {
+		{"metadata", "managedFields"},
+		{"metadata", "annotations"},
+		{"status", "images"},
+		{"status", "conditions"},
+		{"status", "volumesAttached"},
+		{"status", "volumesInUse"},
+	},
+	// the annotations of the PVCs are trimmed except keptAnnotations
+	skrpvc.GroupVersionResource(): {
+		{"metadata", "managedFields"},
+	},
+	skrsvc.GroupVersionResource(): {
+		{"metadata", "managedFields"},
+		{"metadata", "annotations"},
+	},
+	// the annotations of the StorageClasses mark the default one
+	skrstorageclass.GroupVersionResource(): {
+		{"metadata", "managedFields"},
+	},
+}
+
+// keptAnnotations are the annotations used for the metrics, the other annotations of the objects are removed
+var keptAnnotations = map[schema.GroupVersionResource][]string{
+	// the beta annotation takes precedence over the storage class name of the PVC
+	skrpvc.GroupVersionResource(): {corev1.BetaStorageClassAnnotation},
+}
+
+// RuntimeCache keeps the nodes, PVCs, Services and StorageClasses of a runtime up to date with informers
+type RuntimeCache struct {
+	nodes          cache.SharedIndexInformer
+	pvcs           cache.SharedIndexInformer
+	services       cache.SharedIndexInformer
+	storageClasses cache.SharedIndexInformer
+
+	notify  func(object k8sruntime.Object, deleted bool)
+	stop    chan struct{}
+	stopped int32
+	size    int64
+
+	mu sync.Mutex
+	// failingSince is the time of the first failure of the list or watch of each resource since it was last watched
+	failingSince map[schema.GroupVersionResource]time.Time
+	timeNow      func() time.Time
+}
+
+// newRuntimeCache starts the informers and waits until they are synced, the typed objects of the events
+// are passed to notify when it is set
+func newRuntimeCache(client dynamic.Interface, syncTimeout time.Duration, notify func(object k8sruntime.Object, deleted bool)) (*RuntimeCache, error) {
+	runtimeCache := &RuntimeCache{
+		stop:         make(chan struct{}),
+		notify:       notify,
+		failingSince: map[schema.GroupVersionResource]time.Time{},
+		timeNow:      time.Now,
+	}
+	runtimeCache.nodes = runtimeCache.newInformer(client, skrnode.GroupVersionResource())
+	runtimeCache.pvcs = runtimeCache.newInformer(client, skrpvc.GroupVersionResource())
+	runtimeCache.services = runtimeCache.newInformer(client, skrsvc.GroupVersionResource())
+	runtimeCache.storageClasses = runtimeCache.newInformer(client, skrstorageclass.GroupVersionResource())
+
+	informers := []cache.SharedIndexInformer{runtimeCache.nodes, runtimeCache.pvcs, runtimeCache.services, runtimeCache.storageClasses}
+	for _, informer := range informers {
+		go informer.Run(runtimeCache.stop)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), informers[0].HasSynced, informers[1].HasSynced, informers[2].HasSynced, informers[3].HasSynced) {
+		runtimeCache.Stop()
+		return nil, fmt.Errorf("informers not synced within %v", syncTimeout)
+	}
+	return runtimeCache, nil
+}
+
+func (c *RuntimeCache) newInformer(client dynamic.Interface, gvr schema.GroupVersionResource) cache.SharedIndexInformer {
+	resource := client.Resource(gvr).Namespace(corev1.NamespaceAll)
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metaV1.ListOptions) (k8sruntime.Object, error) {
+			list, err := resource.List(context.Background(), options)
+			if err != nil {
+				c.watchFailed(gvr)
+				return nil, err
+			}
+			for i := range list.Items {
+				trim(&list.Items[i], gvr)
+			}
+			return list, nil
+		},
+		WatchFunc: func(options metaV1.ListOptions) (watch.Interface, error) {
+			watcher, err := resource.Watch(context.Background(), options)
+			if err != nil {
+				// the reflector retries refused connections without calling the watch error handler
+				c.watchFailed(gvr)
+				return nil, err
+			}
+			c.watchStarted(gvr)
+			return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+				if object, ok := event.Object.(*unstructured.Unstructured); ok {
+					trim(object, gvr)
+				}
+				return event, true
+			}), nil
+		},
+	}
+
+	informer := cache.NewSharedIndexInformer(listWatch, &unstructured.Unstructured{}, 0, cache.Indexers{})
+	// the handler can only fail when the informer is already started
+	_ = informer.SetWatchErrorHandler(func(reflector *cache.Reflector, err error) {
+		c.watchFailed(gvr)
+		cache.DefaultWatchErrorHandler(reflector, err)
+	})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			atomic.AddInt64(&c.size, objectSize(obj))
+			c.notifyTyped(gvr, obj, false)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			atomic.AddInt64(&c.size, objectSize(newObj)-objectSize(oldObj))
+			c.notifyTyped(gvr, newObj, false)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			atomic.AddInt64(&c.size, -objectSize(obj))
+			c.notifyTyped(gvr, obj, true)
+		},
+	})
+	return informer
+}
+
+func (c *RuntimeCache) notifyTyped(gvr schema.GroupVersionResource, obj interface{}, deleted bool) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if c.notify == nil || !ok {
+		return
+	}
+
+	var typed k8sruntime.Object
+	switch gvr {
+	case skrnode.GroupVersionResource():
+		typed = &corev1.Node{}
+	case skrpvc.GroupVersionResource():
+		typed = &corev1.PersistentVolumeClaim{}
+	case skrsvc.GroupVersionResource():
+		typed = &corev1.Service{}
+	default:
+		return
+	}
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(object.Object, typed); err != nil {
+		return
+	}
+	c.notify(typed, deleted)
+}
+
+func (c *RuntimeCache) watchFailed(gvr schema.GroupVersionResource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, failing := c.failingSince[gvr]; !failing {
+		c.failingSince[gvr] = c.timeNow()
+	}
+}
+
+func (c *RuntimeCache) watchStarted(gvr schema.GroupVersionResource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.failingSince, gvr)
+}
+
+// FailingSince returns the time since when the list or watch of any of the resources is failing, the cached
+// resources are not updated anymore then. The second return value is false when all resources are watched.
+func (c *RuntimeCache) FailingSince() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var since time.Time
+	for _, failingSince := range c.failingSince {
+		if since.IsZero() || failingSince.Before(since) {
+			since = failingSince
+		}
+	}
+	return since, !since.IsZero()
+}
+
+// Nodes returns the cached nodes sorted by name
+func (c *RuntimeCache) Nodes() (*corev1.NodeList, error) {
+	nodeList := &corev1.NodeList{}
+	for _, object := range sortedObjects(c.nodes) {
+		node := corev1.Node{}
+		if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &node); err != nil {
+			return nil, err
+		}
+		nodeList.Items = append(nodeList.Items, node)
+	}
+	return nodeList, nil
+}
+
+// PVCs returns the cached PVCs sorted by namespace and name
+func (c *RuntimeCache) PVCs() (*corev1.PersistentVolumeClaimList, error) {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	for _, object := range sortedObjects(c.pvcs) {
+		pvc := corev1.PersistentVolumeClaim{}
+		if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &pvc); err != nil {
+			return nil, err
+		}
+		pvcList.Items = append(pvcList.Items, pvc)
+	}
+	return pvcList, nil
+}
+
+// Services returns the cached Services sorted by namespace and name
+func (c *RuntimeCache) Services() (*corev1.ServiceList, error) {
+	svcList := &corev1.ServiceList{}
+	for _, object := range sortedObjects(c.services) {
+		svc := corev1.Service{}
+		if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &svc); err != nil {
+			return nil, err
+		}
+		svcList.Items = append(svcList.Items, svc)
+	}
+	return svcList, nil
+}
+
+// StorageClasses returns the cached StorageClasses sorted by name
+func (c *RuntimeCache) StorageClasses() (*storagev1.StorageClassList, error) {
+	storageClassList := &storagev1.StorageClassList{}
+	for _, object := range sortedObjects(c.storageClasses) {
+		storageClass := storagev1.StorageClass{}
+		if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &storageClass); err != nil {
+			return nil, err
+		}
+		storageClassList.Items = append(storageClassList.Items, storageClass)
+	}
+	return storageClassList, nil
+}
+
+// Size returns the estimated size of the cached objects in bytes
+func (c *RuntimeCache) Size() int64 {
+	return atomic.LoadInt64(&c.size)
+}
+
+// Stop stops the informers, it is safe to call it more than once
+func (c *RuntimeCache) Stop() {
+	if atomic.CompareAndSwapInt32(&c.stopped, 0, 1) {
+		close(c.stop)
+	}
+}
+
+func sortedObjects(informer cache.SharedIndexInformer) []*unstructured.Unstructured {
+	objects := []*unstructured.Unstructured{}
+	for _, item := range informer.GetStore().List() {
+		if object, ok := item.(*unstructured.Unstructured); ok {
+			objects = append(objects, object)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].GetNamespace() != objects[j].GetNamespace() {
+			return objects[i].GetNamespace() < objects[j].GetNamespace()
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+	return objects
+}
+
+func trim(object *unstructured.Unstructured, gvr schema.GroupVersionResource) {
+	for _, fields := range trimmedFields[gvr] {
+		unstructured.RemoveNestedField(object.Object, fields...)
+	}
+	if kept, found := keptAnnotations[gvr]; found {
+		annotations := map[string]string{}
+		for _, key := range kept {
+			if value, found := object.GetAnnotations()[key]; found {
+				annotations[key] = value
+			}
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		object.SetAnnotations(annotations)
+	}
+}
+
+// objectSize estimates the memory used by the object with the size of its JSON representation
+func objectSize(obj interface{}) int64 {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return 0
+	}
+	data, err := json.Marshal(object.Object)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
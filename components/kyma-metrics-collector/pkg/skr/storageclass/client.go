@@ -0,0 +1,63 @@
+package storageclass
+
+import (
+	"context"
+	"encoding/json"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+
+	skrcommons "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/commons"
+)
+
+type Client struct {
+	Resource dynamic.NamespaceableResourceInterface
+}
+
+func (c Config) NewClient(kubeconfig string) (*Client, error) {
+	restClientConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	resourceClient := dynamicClient.Resource(GroupVersionResource())
+	return &Client{Resource: resourceClient}, nil
+}
+
+func (c Client) List(ctx context.Context) (*storagev1.StorageClassList, error) {
+	skrcommons.TotalCalls.WithLabelValues(skrcommons.CallsTotalLabel, skrcommons.ListingStorageClassesLabel).Inc()
+	unstructuredStorageClassList, err := c.Resource.List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	skrcommons.TotalCalls.WithLabelValues(skrcommons.SuccessStatusLabel, skrcommons.SuccessListingStorageClassesLabel).Inc()
+	return convertUnstructuredListToStorageClassList(unstructuredStorageClassList)
+}
+
+func convertUnstructuredListToStorageClassList(unstructuredStorageClassList *unstructured.UnstructuredList) (*storagev1.StorageClassList, error) {
+	storageClassList := new(storagev1.StorageClassList)
+	storageClassListBytes, err := unstructuredStorageClassList.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(storageClassListBytes, storageClassList)
+	if err != nil {
+		return nil, err
+	}
+	return storageClassList, nil
+}
+
+func GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Version:  storagev1.SchemeGroupVersion.Version,
+		Group:    storagev1.SchemeGroupVersion.Group,
+		Resource: "storageclasses",
+	}
+}
@@ -0,0 +1,42 @@
+package storageclass
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	skrcommons "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/skr/commons"
+	kmctesting "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/testing"
+)
+
+func TestList(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ctx := context.Background()
+	storageClassList := kmctesting.GetAzureStorageClasses()
+	client, err := FakeStorageClassClient{}.NewClient("")
+	g.Expect(err).Should(gomega.BeNil())
+
+	gotStorageClassList, err := client.List(ctx)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(gotStorageClassList.Items).To(gomega.Equal(storageClassList.Items))
+
+	// Tests metric
+	callsSuccess, err := skrcommons.TotalCalls.GetMetricWithLabelValues(skrcommons.SuccessStatusLabel, skrcommons.SuccessListingStorageClassesLabel)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(testutil.ToFloat64(callsSuccess)).Should(gomega.Equal(float64(1)))
+	callsTotal, err := skrcommons.TotalCalls.GetMetricWithLabelValues(skrcommons.CallsTotalLabel, skrcommons.ListingStorageClassesLabel)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(testutil.ToFloat64(callsTotal)).Should(gomega.Equal(float64(1)))
+
+	// Delete all the StorageClasses
+	for _, storageClass := range storageClassList.Items {
+		err := client.Resource.Delete(ctx, storageClass.Name, metaV1.DeleteOptions{})
+		g.Expect(err).Should(gomega.BeNil())
+	}
+	gotStorageClassList, err = client.List(ctx)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(gotStorageClassList.Items).To(gomega.BeEmpty())
+}
@@ -0,0 +1,27 @@
+package storageclass
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/gardener/commons"
+	kmctesting "github.com/kyma-project/control-plane/components/kyma-metrics-collector/pkg/testing"
+)
+
+type FakeStorageClassClient struct{}
+
+func (fakeStorageClassClient FakeStorageClassClient) NewClient(string) (*Client, error) {
+	storageClassList := kmctesting.GetAzureStorageClasses()
+	scheme, err := commons.SetupSchemeOrDie()
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{
+			GroupVersionResource(): "StorageClassList",
+		}, storageClassList)
+
+	resourceClient := dynamicClient.Resource(GroupVersionResource())
+	return &Client{Resource: resourceClient}, nil
+}
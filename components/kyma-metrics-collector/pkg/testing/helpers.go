@@ -18,6 +18,7 @@ import (
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/onsi/gomega"
@@ -191,6 +192,26 @@ func WithAzureProviderAndFooVMType(shoot *gardencorev1beta1.Shoot) {
 	}
 }
 
+func WithOpenStackProviderAndGC4M16VMs(shoot *gardencorev1beta1.Shoot) {
+	shoot.Spec.Provider = gardencorev1beta1.Provider{
+		Type: "openstack",
+		InfrastructureConfig: &runtime.RawExtension{
+			Raw: []byte(`{"apiVersion":"openstack.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig","floatingPoolName":"FloatingIP-external","networks":{"workers":"10.250.0.0/19"}}`),
+		},
+		Workers: []gardencorev1beta1.Worker{
+			{
+				Name: "cpu-worker-0",
+				Machine: gardencorev1beta1.Machine{
+					Type: "g_c4_m16",
+					Image: &gardencorev1beta1.ShootMachineImage{
+						Name: "gardenlinux",
+					},
+				},
+			},
+		},
+	}
+}
+
 func Get2Nodes() *corev1.NodeList {
 	node1 := GetNode("node1", "Standard_D8_v3")
 	node2 := GetNode("node2", "Standard_D8_v3")
@@ -237,6 +258,69 @@ func GetNode(name, vmType string) corev1.Node {
 	}
 }
 
+// GetNodeWithCapacity returns a node with the given CPU and memory capacity, e.g. "4" and "16Gi"
+func GetNodeWithCapacity(name, vmType, cpu, memory string) corev1.Node {
+	node := GetNode(name, vmType)
+	node.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+	return node
+}
+
+// GetNodeInWorkerPool returns a node of the given worker pool of the Shoot
+func GetNodeInWorkerPool(name, vmType, workerPool string) corev1.Node {
+	node := GetNode(name, vmType)
+	node.Labels["worker.gardener.cloud/pool"] = workerPool
+	return node
+}
+
+// WithWorkerVolumes sets a 50Gi StandardSSD_LRS boot volume for all workers of the Shoot
+func WithWorkerVolumes(shoot *gardencorev1beta1.Shoot) {
+	volumeType := "StandardSSD_LRS"
+	for i := range shoot.Spec.Provider.Workers {
+		shoot.Spec.Provider.Workers[i].Volume = &gardencorev1beta1.Volume{
+			Type:       &volumeType,
+			VolumeSize: "50Gi",
+		}
+	}
+}
+
+// GetAzureStorageClasses returns the default StandardSSD_LRS storage class and a Premium_LRS storage class
+func GetAzureStorageClasses() *storagev1.StorageClassList {
+	defaultClass := GetStorageClass("default", "disk.csi.azure.com", map[string]string{"skuname": "StandardSSD_LRS"})
+	defaultClass.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+	premiumClass := GetStorageClass("managed-premium", "kubernetes.io/azure-disk", map[string]string{"storageaccounttype": "Premium_LRS"})
+	return &storagev1.StorageClassList{
+		TypeMeta: metaV1.TypeMeta{
+			Kind:       "StorageClassList",
+			APIVersion: "storage.k8s.io/v1",
+		},
+		Items: []storagev1.StorageClass{defaultClass, premiumClass},
+	}
+}
+
+func GetStorageClass(name, provisioner string, parameters map[string]string) storagev1.StorageClass {
+	return storagev1.StorageClass{
+		TypeMeta: metaV1.TypeMeta{
+			Kind:       "StorageClass",
+			APIVersion: "storage.k8s.io/v1",
+		},
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: name,
+		},
+		Provisioner: provisioner,
+		Parameters:  parameters,
+	}
+}
+
+// GetPVWithStorageClass returns a bound PVC of the given storage class
+func GetPVWithStorageClass(name, namespace, capacity, storageClass string) *corev1.PersistentVolumeClaim {
+	pvc := GetPV(name, namespace, capacity)
+	pvc.Spec.StorageClassName = &storageClass
+	return pvc
+}
+
 const (
 	letterBytes   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ" // 52 possibilities
 	letterIdxBits = 6                                                      // 6 bits to represent 64 possibilities / indexes
@@ -10,7 +10,9 @@ CREATE TABLE cluster
     creation_timestamp timestamp without time zone NOT NULL,
     deleted boolean default false,
     sub_account_id varchar(256),
-    is_kubeconfig_encrypted boolean NOT NULL
+    is_kubeconfig_encrypted boolean NOT NULL,
+    deletion_protection boolean NOT NULL DEFAULT false,
+    deletion_timestamp timestamp without time zone
 );
 
 -- Cluster Config
@@ -56,7 +58,9 @@ CREATE TABLE gardener_config
 CREATE TYPE operation_state AS ENUM (
     'IN_PROGRESS',
     'SUCCEEDED',
-    'FAILED'
+    'FAILED',
+    'PENDING',
+    'CANCELED'
     );
 
 CREATE TYPE operation_type AS ENUM (
@@ -67,7 +71,8 @@ CREATE TYPE operation_type AS ENUM (
     'UPGRADE_SHOOT',
     'HIBERNATE',
     'PROVISION_NO_INSTALL',
-    'DEPROVISION_NO_INSTALL'
+    'DEPROVISION_NO_INSTALL',
+    'ROTATE_CREDENTIALS'
     );
 
 CREATE TABLE operation
@@ -84,7 +89,10 @@ CREATE TABLE operation
     last_transition timestamp without time zone,
     err_message text NOT NULL,
     reason text NOT NULL,
-    component text NOT NULL
+    component text NOT NULL,
+    pipeline_version varchar(64) NOT NULL DEFAULT '',
+    action varchar(32) NOT NULL DEFAULT '',
+    diagnostics jsonb
 );
 
 -- Kyma Release
@@ -155,6 +163,75 @@ CREATE TABLE runtime_upgrade
     foreign key (post_upgrade_kyma_config_id) REFERENCES kyma_config (id) ON DELETE CASCADE
 );
 
+-- Shoot Upgrade
+
+CREATE TABLE shoot_upgrade
+(
+    operation_id uuid PRIMARY KEY,
+    pre_upgrade_gardener_config jsonb NOT NULL,
+    foreign key (operation_id) REFERENCES operation (id) ON DELETE CASCADE
+);
+
+CREATE TABLE operation_stage
+(
+    operation_id uuid NOT NULL,
+    stage varchar(256) NOT NULL,
+    state varchar(32) NOT NULL,
+    start_timestamp timestamp without time zone NOT NULL,
+    end_timestamp timestamp without time zone,
+    PRIMARY KEY (operation_id, stage),
+    foreign key (operation_id) REFERENCES operation (id) ON DELETE CASCADE
+);
+
+-- Credentials rotation
+
+CREATE TABLE credentials_rotation
+(
+    operation_id uuid PRIMARY KEY,
+    kinds varchar(256) NOT NULL,
+    phase varchar(32) NOT NULL,
+    foreign key (operation_id) REFERENCES operation (id) ON DELETE CASCADE
+);
+
+CREATE TABLE credentials_rotation_status
+(
+    cluster_id uuid NOT NULL,
+    kind varchar(64) NOT NULL,
+    phase varchar(32) NOT NULL,
+    last_initiation_time timestamp without time zone,
+    last_completion_time timestamp without time zone,
+    PRIMARY KEY (cluster_id, kind),
+    foreign key (cluster_id) REFERENCES cluster (id) ON DELETE CASCADE
+);
+
+-- Bulk operations
+
+CREATE TABLE bulk_operation
+(
+    id uuid PRIMARY KEY CHECK (id <> '00000000-0000-0000-0000-000000000000'),
+    type operation_type NOT NULL,
+    state varchar(32) NOT NULL,
+    message text NOT NULL DEFAULT '',
+    selector jsonb NOT NULL,
+    input jsonb NOT NULL,
+    max_parallel integer NOT NULL,
+    max_failure_ratio double precision NOT NULL,
+    start_timestamp timestamp without time zone NOT NULL,
+    end_timestamp timestamp without time zone
+);
+
+CREATE TABLE bulk_operation_child
+(
+    bulk_operation_id uuid NOT NULL,
+    runtime_id uuid NOT NULL,
+    operation_id uuid,
+    state varchar(32) NOT NULL,
+    message text NOT NULL DEFAULT '',
+    PRIMARY KEY (bulk_operation_id, runtime_id),
+    foreign key (bulk_operation_id) REFERENCES bulk_operation (id) ON DELETE CASCADE,
+    foreign key (runtime_id) REFERENCES cluster (id) ON DELETE CASCADE
+);
+
 -- Cluster administrators
 
 CREATE TABLE cluster_administrator
@@ -212,3 +289,21 @@ CREATE TABLE dns_providers
     type varchar(256) NOT NULL,
     foreign key (dns_config_id) REFERENCES dns_config (id) ON DELETE CASCADE
 );
+
+-- Tenant quota
+
+CREATE TABLE tenant_quota
+(
+    tenant varchar(256) PRIMARY KEY,
+    max_runtimes integer,
+    max_operations_in_progress integer
+);
+
+CREATE TABLE tenant_provider_quota
+(
+    tenant varchar(256) NOT NULL,
+    provider varchar(256) NOT NULL,
+    max_nodes integer NOT NULL,
+    PRIMARY KEY (tenant, provider),
+    foreign key (tenant) REFERENCES tenant_quota (tenant) ON DELETE CASCADE
+);
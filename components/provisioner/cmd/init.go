@@ -2,6 +2,7 @@ package main
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"os"
@@ -13,10 +14,15 @@ import (
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
 
+	"github.com/kyma-project/control-plane/components/provisioner/internal/authn"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/graphql"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/oauth"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/classification"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/deprovisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
 	"github.com/pkg/errors"
@@ -34,11 +40,12 @@ func newProvisioningService(
 	gardenerProject string,
 	provisioner provisioning.Provisioner,
 	dbsFactory dbsession.Factory,
-	directorService director.DirectorClient,
+	runtimeRegistry director.RuntimeRegistry,
 	shootProvider gardener.ShootProvider,
 	provisioningQueue queue.OperationQueue,
 	deprovisioningQueue queue.OperationQueue,
 	shootUpgradeQueue queue.OperationQueue,
+	options provisioning.ServiceOptions,
 	defaultEnableKubernetesVersionAutoUpdate,
 	defaultEnableMachineImageVersionAutoUpdate bool) provisioning.Service {
 
@@ -47,7 +54,18 @@ func newProvisioningService(
 	inputConverter := provisioning.NewInputConverter(uuidGenerator, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
 	graphQLConverter := provisioning.NewGraphQLConverter()
 
-	return provisioning.NewProvisioningService(inputConverter, graphQLConverter, directorService, dbsFactory, provisioner, uuidGenerator, shootProvider, provisioningQueue, deprovisioningQueue, shootUpgradeQueue)
+	return provisioning.NewProvisioningService(inputConverter, graphQLConverter, runtimeRegistry, dbsFactory, provisioner, uuidGenerator, shootProvider, provisioningQueue, deprovisioningQueue, shootUpgradeQueue, options)
+}
+
+func newRuntimeRegistry(config config) (director.RuntimeRegistry, error) {
+	switch config.RuntimeRegistry {
+	case director.RegistryDirector:
+		return newDirectorClient(config)
+	case director.RegistryLocal:
+		return director.NewLocalRegistry(uuid.NewUUIDGenerator()), nil
+	default:
+		return nil, fmt.Errorf("unknown runtime registry %s", config.RuntimeRegistry)
+	}
 }
 
 func newDirectorClient(config config) (director.DirectorClient, error) {
@@ -110,3 +128,95 @@ func newHTTPClient(skipCertVerification bool) *http.Client {
 		Timeout: 30 * time.Second,
 	}
 }
+
+const (
+	authenticationModeNone = "none"
+	authenticationModeJWT  = "jwt"
+	authenticationModeMTLS = "mtls"
+)
+
+func newAuthenticator(cfg config) (authn.Authenticator, error) {
+	switch cfg.Authentication.Mode {
+	case authenticationModeJWT:
+		return authn.NewJWTAuthenticator(cfg.Authentication.JWT, newHTTPClient(false))
+	case authenticationModeMTLS:
+		return authn.NewCertificateAuthenticator(), nil
+	default:
+		return nil, fmt.Errorf("unknown authentication mode: %s", cfg.Authentication.Mode)
+	}
+}
+
+func newServerTLSConfig(cfg config) (*tls.Config, error) {
+	clientCA, err := os.ReadFile(cfg.Authentication.MTLS.ClientCAPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA from path %s: %s", cfg.Authentication.MTLS.ClientCAPath, err.Error())
+	}
+
+	clientCAPool := x509.NewCertPool()
+	if !clientCAPool.AppendCertsFromPEM(clientCA) {
+		return nil, fmt.Errorf("failed to parse client CA from path %s", cfg.Authentication.MTLS.ClientCAPath)
+	}
+
+	return &tls.Config{
+		ClientCAs:  clientCAPool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+func newClusterCleanupSelectors(cfg config) ([]deprovisioning.ResourceSelector, error) {
+	if !cfg.ClusterCleanup.Enabled {
+		return nil, nil
+	}
+
+	if cfg.ClusterCleanup.ConfigPath == "" {
+		return deprovisioning.DefaultCleanupSelectors(), nil
+	}
+
+	return deprovisioning.LoadCleanupSelectors(cfg.ClusterCleanup.ConfigPath)
+}
+
+func newPipelineDefinitions(cfg config) (queue.PipelineDefinitions, error) {
+	if cfg.PipelineDefinitionsPath == "" {
+		return queue.PipelineDefinitions{}, nil
+	}
+
+	return queue.LoadPipelineDefinitions(cfg.PipelineDefinitionsPath)
+}
+
+func newErrorClassifier(cfg config) (*classification.Classifier, error) {
+	rules := classification.DefaultRules()
+	if cfg.ErrorClassification.ConfigPath != "" {
+		var err error
+		rules, err = classification.LoadRules(cfg.ErrorClassification.ConfigPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return classification.NewClassifier(rules, cfg.ErrorClassification), nil
+}
+
+func newProvisioningFailureHandler(cfg config, shootClient failure.ShootClient, runtimeRegistry director.RuntimeRegistry) (operations.FailureHandler, error) {
+	switch cfg.FailureHandler.Provisioning {
+	case failure.HandlerNoop:
+		return failure.NewNoopFailureHandler(), nil
+	case failure.HandlerUnregister:
+		return failure.NewProvisioningRollbackHandler(shootClient, runtimeRegistry, false), nil
+	case failure.HandlerDeprovision:
+		return failure.NewProvisioningRollbackHandler(shootClient, runtimeRegistry, true), nil
+	default:
+		return nil, fmt.Errorf("unknown provisioning failure handler: %s", cfg.FailureHandler.Provisioning)
+	}
+}
+
+func newShootUpgradeFailureHandler(cfg config, dbsFactory dbsession.Factory, shootUpgrader failure.ShootUpgrader) (operations.FailureHandler, error) {
+	switch cfg.FailureHandler.ShootUpgrade {
+	case failure.HandlerNoop:
+		return failure.NewNoopFailureHandler(), nil
+	case failure.HandlerRestore:
+		return failure.NewShootUpgradeRestoreHandler(dbsFactory, shootUpgrader), nil
+	default:
+		return nil, fmt.Errorf("unknown Shoot upgrade failure handler: %s", cfg.FailureHandler.ShootUpgrade)
+	}
+}
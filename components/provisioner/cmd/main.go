@@ -15,17 +15,26 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/avast/retry-go"
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/authn"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/healthz"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/metrics"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/classification"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
 	provisioningStages "github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/provisioning"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/orphans"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/database"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/quota"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/runtime"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util/k8s"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
@@ -33,6 +42,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 	"github.com/vrischmann/envconfig"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 )
@@ -40,12 +50,26 @@ import (
 const connStringFormat string = "host=%s port=%s user=%s password=%s dbname=%s sslmode=%s sslrootcert=%s"
 
 type config struct {
-	Address                      string `envconfig:"default=127.0.0.1:3000"`
-	APIEndpoint                  string `envconfig:"default=/graphql"`
-	PlaygroundAPIEndpoint        string `envconfig:"default=/graphql"`
-	DirectorURL                  string `envconfig:"default=http://compass-director.compass-system.svc.cluster.local:3000/graphql"`
-	SkipDirectorCertVerification bool   `envconfig:"default=false"`
-	DirectorOAuthPath            string `envconfig:"APP_DIRECTOR_OAUTH_PATH,default=./dev/director.yaml"`
+	Address                       string        `envconfig:"default=127.0.0.1:3000"`
+	APIEndpoint                   string        `envconfig:"default=/graphql"`
+	PlaygroundAPIEndpoint         string        `envconfig:"default=/graphql"`
+	SubscriptionKeepAliveInterval time.Duration `envconfig:"default=10s"`
+	DirectorURL                   string        `envconfig:"default=http://compass-director.compass-system.svc.cluster.local:3000/graphql"`
+	SkipDirectorCertVerification  bool          `envconfig:"default=false"`
+	DirectorOAuthPath             string        `envconfig:"APP_DIRECTOR_OAUTH_PATH,default=./dev/director.yaml"`
+	RuntimeRegistry               string        `envconfig:"default=director"`
+
+	Authentication struct {
+		// Mode is one of: none, jwt, mtls
+		Mode                 string `envconfig:"default=none"`
+		IdentitiesConfigPath string `envconfig:"optional"`
+		JWT                  authn.JWTConfig
+		MTLS                 struct {
+			ServerCertPath string `envconfig:"optional"`
+			ServerKeyPath  string `envconfig:"optional"`
+			ClientCAPath   string `envconfig:"optional"`
+		}
+	}
 
 	Database struct {
 		User        string `envconfig:"default=postgres"`
@@ -62,17 +86,38 @@ type config struct {
 	DeprovisioningTimeout queue.DeprovisioningTimeouts
 	HibernationTimeout    queue.HibernationTimeouts
 
+	Deprovisioning provisioning.DeprovisioningConfig
+
+	BulkOperations provisioning.BulkOperationsConfig
+
 	OperatorRoleBinding provisioningStages.OperatorRoleBinding
 
+	ClusterCleanup struct {
+		Enabled    bool   `envconfig:"default=true"`
+		ConfigPath string `envconfig:"optional"`
+	}
+
+	FailureHandler failure.Config
+
+	PipelineDefinitionsPath string `envconfig:"optional"`
+
+	ErrorClassification classification.Config
+
+	Diagnostics gardener.DiagnosticsConfig
+
+	Quota quota.Config
+
+	OrphanScanner orphans.Config
+
 	Gardener struct {
 		Project                                    string `envconfig:"default=gardenerProject"`
 		KubeconfigPath                             string `envconfig:"default=./dev/kubeconfig.yaml"`
 		AuditLogsPolicyConfigMap                   string `envconfig:"optional"`
 		AuditLogsTenantConfigPath                  string `envconfig:"optional"`
 		MaintenanceWindowConfigPath                string `envconfig:"optional"`
-		ClusterCleanupResourceSelector             string `envconfig:"default=https://service-manager."`
 		DefaultEnableKubernetesVersionAutoUpdate   bool   `envconfig:"default=false"`
 		DefaultEnableMachineImageVersionAutoUpdate bool   `envconfig:"default=false"`
+		SeedSelection                              gardener.SeedSelectionConfig
 	}
 
 	LatestDownloadedReleases int  `envconfig:"default=5"`
@@ -87,7 +132,8 @@ type config struct {
 
 func (c *config) String() string {
 	return fmt.Sprintf("Address: %s, APIEndpoint: %s, DirectorURL: %s, "+
-		"SkipDirectorCertVerification: %v, DirectorOAuthPath: %s, "+
+		"SkipDirectorCertVerification: %v, DirectorOAuthPath: %s, RuntimeRegistry: %s, "+
+		"AuthenticationMode: %s, "+
 		"DatabaseUser: %s, DatabaseHost: %s, DatabasePort: %s, "+
 		"DatabaseName: %s, DatabaseSSLMode: %s, "+
 		"ProvisioningTimeoutClusterCreation: %s "+
@@ -97,11 +143,13 @@ func (c *config) String() string {
 		"ShootUpgradeTimeout: %s, "+
 		"OperatorRoleBindingL2SubjectName: %s, OperatorRoleBindingL3SubjectName: %s, OperatorRoleBindingCreatingForAdmin: %t "+
 		"GardenerProject: %s, GardenerKubeconfigPath: %s, GardenerAuditLogsPolicyConfigMap: %s, AuditLogsTenantConfigPath: %s, "+
+		"GardenerSeedSelectionStrategy: %s, "+
 		"LatestDownloadedReleases: %d, DownloadPreReleases: %v, "+
 		"EnqueueInProgressOperations: %v"+
 		"LogLevel: %s",
 		c.Address, c.APIEndpoint, c.DirectorURL,
-		c.SkipDirectorCertVerification, c.DirectorOAuthPath,
+		c.SkipDirectorCertVerification, c.DirectorOAuthPath, c.RuntimeRegistry,
+		c.Authentication.Mode,
 		c.Database.User, c.Database.Host, c.Database.Port,
 		c.Database.Name, c.Database.SSLMode,
 		c.ProvisioningTimeout.ClusterCreation.String(),
@@ -111,6 +159,7 @@ func (c *config) String() string {
 		c.ProvisioningTimeout.ShootUpgrade.String(),
 		c.OperatorRoleBinding.L2SubjectName, c.OperatorRoleBinding.L3SubjectName, c.OperatorRoleBinding.CreatingForAdmin,
 		c.Gardener.Project, c.Gardener.KubeconfigPath, c.Gardener.AuditLogsPolicyConfigMap, c.Gardener.AuditLogsTenantConfigPath,
+		c.Gardener.SeedSelection.Strategy,
 		c.LatestDownloadedReleases, c.DownloadPreReleases,
 		c.EnqueueInProgressOperations,
 		c.LogLevel)
@@ -164,8 +213,11 @@ func main() {
 
 	shootClient := gardenerClientSet.Shoots(gardenerNamespace)
 
-	directorClient, err := newDirectorClient(cfg)
-	exitOnError(err, "Failed to initialize Director client")
+	runtimeRegistry, err := newRuntimeRegistry(cfg)
+	exitOnError(err, "Failed to initialize runtime registry")
+
+	// Without Compass there is no Director client, stages which need it are skipped
+	directorClient, compassEnabled := runtimeRegistry.(director.DirectorClient)
 
 	k8sClientProvider := k8s.NewK8sClientProvider()
 
@@ -173,7 +225,30 @@ func main() {
 	adminKubeconfigRequest := gardenerClient.SubResource("adminkubeconfig")
 	kubeconfigProvider := gardener.NewKubeconfigProvider(shootClient, adminKubeconfigRequest, secretsInterface)
 
-	provisioningQueue := queue.CreateProvisioningQueue(
+	eventBroker := events.NewBroker()
+
+	provisioner := gardener.NewProvisioner(gardenerNamespace, shootClient, dbsFactory, cfg.Gardener.AuditLogsPolicyConfigMap, cfg.Gardener.MaintenanceWindowConfigPath)
+
+	provisioningFailureHandler, err := newProvisioningFailureHandler(cfg, shootClient, runtimeRegistry)
+	exitOnError(err, "Failed to create provisioning failure handler")
+
+	shootUpgradeFailureHandler, err := newShootUpgradeFailureHandler(cfg, dbsFactory, provisioner)
+	exitOnError(err, "Failed to create Shoot upgrade failure handler")
+
+	pipelineDefinitions, err := newPipelineDefinitions(cfg)
+	exitOnError(err, "Failed to load pipeline definitions")
+
+	errorClassifier, err := newErrorClassifier(cfg)
+	exitOnError(err, "Failed to load error classification")
+
+	diagnosticsCollector := gardener.NewDiagnosticsCollector(shootClient, k8sCoreClientSet.CoreV1().Events(gardenerNamespace), cfg.Diagnostics)
+
+	pipelinesConfig := queue.PipelinesConfig{
+		Definitions:    pipelineDefinitions,
+		CompassEnabled: compassEnabled,
+	}
+
+	provisioningQueue, err := queue.CreateProvisioningQueue(
 		cfg.ProvisioningTimeout,
 		dbsFactory,
 		directorClient,
@@ -181,13 +256,30 @@ func main() {
 		cfg.OperatorRoleBinding,
 		k8sClientProvider,
 		runtimeConfigurator,
-		kubeconfigProvider)
+		kubeconfigProvider,
+		queue.QueueOptions{Pipelines: pipelinesConfig, FailureHandler: provisioningFailureHandler, ErrorClassifier: errorClassifier, DiagnosticsCollector: diagnosticsCollector, EventPublisher: eventBroker})
+	exitOnError(err, "Failed to create provisioning queue")
 
-	deprovisioningQueue := queue.CreateDeprovisioningQueue(cfg.DeprovisioningTimeout, dbsFactory, directorClient, shootClient)
+	cleanupSelectors, err := newClusterCleanupSelectors(cfg)
+	exitOnError(err, "Failed to load cluster cleanup config")
 
-	shootUpgradeQueue := queue.CreateShootUpgradeQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, shootClient, cfg.OperatorRoleBinding, k8sClientProvider, kubeconfigProvider)
+	deprovisioningQueue, err := queue.CreateDeprovisioningQueue(
+		cfg.DeprovisioningTimeout,
+		dbsFactory,
+		runtimeRegistry,
+		shootClient,
+		kubeconfigProvider,
+		k8s.NewDynamicClientProvider(),
+		cleanupSelectors,
+		queue.QueueOptions{Pipelines: pipelinesConfig, ErrorClassifier: errorClassifier, DiagnosticsCollector: diagnosticsCollector, EventPublisher: eventBroker})
+	exitOnError(err, "Failed to create deprovisioning queue")
+
+	shootUpgradeQueue, err := queue.CreateShootUpgradeQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, shootClient, cfg.OperatorRoleBinding, k8sClientProvider, kubeconfigProvider, queue.QueueOptions{Pipelines: pipelinesConfig, FailureHandler: shootUpgradeFailureHandler, ErrorClassifier: errorClassifier, DiagnosticsCollector: diagnosticsCollector, EventPublisher: eventBroker})
+	exitOnError(err, "Failed to create Shoot upgrade queue")
+
+	credentialsRotationQueue, err := queue.CreateCredentialsRotationQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, shootClient, kubeconfigProvider, queue.QueueOptions{Pipelines: pipelinesConfig, ErrorClassifier: errorClassifier, DiagnosticsCollector: diagnosticsCollector, EventPublisher: eventBroker})
+	exitOnError(err, "Failed to create credentials rotation queue")
 
-	provisioner := gardener.NewProvisioner(gardenerNamespace, shootClient, dbsFactory, cfg.Gardener.AuditLogsPolicyConfigMap, cfg.Gardener.MaintenanceWindowConfigPath)
 	shootController, err := newShootController(gardenerNamespace, gardenerClusterConfig, dbsFactory, cfg.Gardener.AuditLogsTenantConfigPath)
 	exitOnError(err, "Failed to create Shoot controller.")
 	go func() {
@@ -195,20 +287,33 @@ func main() {
 		exitOnError(err, "Failed to start Shoot Controller")
 	}()
 
+	seedSelector, err := gardener.NewSeedSelector(cfg.Gardener.SeedSelection, gardenerClientSet.Seeds(), gardenerClientSet.Shoots(metav1.NamespaceAll), gardener.NewAuditLogConfigurator(cfg.Gardener.AuditLogsTenantConfigPath))
+	exitOnError(err, "Failed to create seed selector")
+
+	orphanScanner := orphans.NewScanner(cfg.OrphanScanner, dbsFactory, runtimeRegistry, shootClient)
+
 	provisioningSVC := newProvisioningService(
 		cfg.Gardener.Project,
 		provisioner,
 		dbsFactory,
-		directorClient,
+		runtimeRegistry,
 		gardener.NewShootProvider(shootClient),
 		provisioningQueue,
 		deprovisioningQueue,
 		shootUpgradeQueue,
+		provisioning.ServiceOptions{
+			CredentialsRotationQueue:  credentialsRotationQueue,
+			EventSubscriber:           eventBroker,
+			QuotaManager:              quota.NewManager(cfg.Quota, dbsFactory),
+			OrphanScanner:             orphanScanner,
+			SeedSelector:              seedSelector,
+			DeprovisioningGracePeriod: cfg.Deprovisioning.GracePeriod,
+		},
 		cfg.Gardener.DefaultEnableKubernetesVersionAutoUpdate,
 		cfg.Gardener.DefaultEnableMachineImageVersionAutoUpdate)
 
 	tenantUpdater := api.NewTenantUpdater(dbsFactory.NewReadWriteSession())
-	validator := api.NewValidator()
+	validator := api.NewValidator(compassEnabled)
 	resolver := api.NewResolver(provisioningSVC, validator, tenantUpdater)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -220,6 +325,15 @@ func main() {
 
 	shootUpgradeQueue.Run(ctx.Done())
 
+	credentialsRotationQueue.Run(ctx.Done())
+
+	provisioning.NewDeprovisioningScheduler(cfg.Deprovisioning, dbsFactory, provisioner, deprovisioningQueue).Run(ctx.Done())
+	provisioning.NewBulkOperationScheduler(cfg.BulkOperations, dbsFactory, provisioningSVC).Run(ctx.Done())
+
+	if cfg.OrphanScanner.Enabled {
+		orphanScanner.Run(ctx.Done())
+	}
+
 	gqlCfg := gqlschema.Config{
 		Resolvers: resolver,
 	}
@@ -236,18 +350,38 @@ func main() {
 	gqlHandler := handler.New(executableSchema)
 	gqlHandler.AddTransport(transport.POST{})
 	gqlHandler.AddTransport(transport.GET{})
+	gqlHandler.AddTransport(transport.Websocket{
+		Upgrader:              websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 1024},
+		InitFunc:              middlewares.ExtractTenantFromInitPayload,
+		KeepAlivePingInterval: cfg.SubscriptionKeepAliveInterval,
+	})
 	gqlHandler.Use(extension.Introspection{})
 	gqlHandler.SetErrorPresenter(presenter.Do)
-	router.Handle(cfg.APIEndpoint, gqlHandler)
-	router.HandleFunc("/healthz", healthz.NewHTTPHandler(log.StandardLogger()))
+
+	if cfg.Authentication.Mode == authenticationModeNone {
+		log.Warnf("Authentication is disabled, the API is accessible for every caller")
+		router.Handle(cfg.APIEndpoint, gqlHandler)
+	} else {
+		authenticator, err := newAuthenticator(cfg)
+		exitOnError(err, "Failed to create authenticator")
+
+		identities, err := authn.LoadIdentities(cfg.Authentication.IdentitiesConfigPath)
+		exitOnError(err, "Failed to load identities")
+
+		router.Handle(cfg.APIEndpoint, middlewares.Authenticate(authenticator, identities)(gqlHandler))
+	}
+	healthzHandler := healthz.NewHTTPHandler(log.StandardLogger())
+	router.HandleFunc("/healthz", healthzHandler)
 
 	// Metrics
 	err = metrics.Register(dbsFactory.NewReadSession())
 	exitOnError(err, "Failed to register metrics collectors")
 
-	// Expose metrics on different port as it cannot be secured with mTLS
+	// Expose metrics on different port as it cannot be secured with mTLS. The health check is served there as well,
+	// so that the probes do not need a client certificate.
 	metricsRouter := mux.NewRouter()
 	metricsRouter.Handle("/metrics", promhttp.Handler())
+	metricsRouter.HandleFunc("/healthz", healthzHandler)
 
 	metricsServer := &http.Server{
 		Handler: metricsRouter,
@@ -263,6 +397,21 @@ func main() {
 	go func() {
 		defer wg.Done()
 
+		if cfg.Authentication.Mode == authenticationModeMTLS {
+			tlsConfig, err := newServerTLSConfig(cfg)
+			exitOnError(err, "Failed to create server TLS config")
+
+			server := &http.Server{
+				Handler:   router,
+				Addr:      cfg.Address,
+				TLSConfig: tlsConfig,
+			}
+			if err := server.ListenAndServeTLS(cfg.Authentication.MTLS.ServerCertPath, cfg.Authentication.MTLS.ServerKeyPath); err != nil {
+				log.Errorf("Error starting server: %s", err.Error())
+			}
+			return
+		}
+
 		if err := http.ListenAndServe(cfg.Address, router); err != nil {
 			log.Errorf("Error starting server: %s", err.Error())
 		}
@@ -275,14 +424,14 @@ func main() {
 	}()
 
 	if cfg.EnqueueInProgressOperations {
-		err = enqueueOperationsInProgress(dbsFactory, provisioningQueue, deprovisioningQueue, shootUpgradeQueue)
+		err = enqueueOperationsInProgress(dbsFactory, provisioningQueue, deprovisioningQueue, shootUpgradeQueue, credentialsRotationQueue)
 		exitOnError(err, "Failed to enqueue in progress operations")
 	}
 
 	wg.Wait()
 }
 
-func enqueueOperationsInProgress(dbFactory dbsession.Factory, provisioningQueue, deprovisioningQueue, shootUpgradeQueue queue.OperationQueue) error {
+func enqueueOperationsInProgress(dbFactory dbsession.Factory, provisioningQueue, deprovisioningQueue, shootUpgradeQueue, credentialsRotationQueue queue.OperationQueue) error {
 	readSession := dbFactory.NewReadSession()
 
 	var inProgressOps []model.Operation
@@ -310,6 +459,8 @@ func enqueueOperationsInProgress(dbFactory dbsession.Factory, provisioningQueue,
 			deprovisioningQueue.Add(op.ID)
 		case model.UpgradeShoot:
 			shootUpgradeQueue.Add(op.ID)
+		case model.RotateCredentials:
+			credentialsRotationQueue.Add(op.ID)
 		}
 	}
 
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/authn"
+	log "github.com/sirupsen/logrus"
+)
+
+// Authenticate rejects requests of unknown callers and stores identity of the caller in the request context
+func Authenticate(authenticator authn.Authenticator, identities authn.Identities) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name, err := authenticator.Authenticate(r)
+			if err != nil {
+				log.Warnf("Failed to authenticate request: %s", err.Error())
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			identity, found := identities.Get(name)
+			if !found {
+				log.Warnf("Identity %s is not allowed to access the API", name)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			handler.ServeHTTP(w, r.WithContext(authn.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
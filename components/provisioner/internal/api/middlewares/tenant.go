@@ -3,6 +3,8 @@ package middlewares
 import (
 	"context"
 	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 )
 
 type Header string
@@ -31,3 +33,16 @@ func ExtractTenant(handler http.Handler) http.Handler {
 		handler.ServeHTTP(w, reqWithCtx)
 	})
 }
+
+// ExtractTenantFromInitPayload allows websocket clients, which cannot set custom headers, to pass tenant in the connection init payload
+func ExtractTenantFromInitPayload(ctx context.Context, initPayload transport.InitPayload) (context.Context, error) {
+	if tenant := initPayload.GetString(string(Tenant)); tenant != "" {
+		ctx = context.WithValue(ctx, Tenant, tenant)
+	}
+
+	if subAccount := initPayload.GetString(string(SubAccountID)); subAccount != "" {
+		ctx = context.WithValue(ctx, SubAccountID, subAccount)
+	}
+
+	return ctx, nil
+}
@@ -15,8 +15,8 @@ type TenantUpdater struct {
 	mock.Mock
 }
 
-// GetAndUpdateTenant provides a mock function with given fields: runtimeID, ctx
-func (_m *TenantUpdater) GetAndUpdateTenant(runtimeID string, ctx context.Context) apperrors.AppError {
+// GetAndVerifyTenant provides a mock function with given fields: runtimeID, ctx
+func (_m *TenantUpdater) GetAndVerifyTenant(runtimeID string, ctx context.Context) apperrors.AppError {
 	ret := _m.Called(runtimeID, ctx)
 
 	var r0 apperrors.AppError
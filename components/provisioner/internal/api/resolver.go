@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/authn"
 	"github.com/pkg/errors"
 
 	log "github.com/sirupsen/logrus"
@@ -34,6 +36,14 @@ func (r *Resolver) Query() gqlschema.QueryResolver {
 	}
 }
 
+func (r *Resolver) Subscription() gqlschema.SubscriptionResolver {
+	return &Resolver{
+		provisioning:  r.provisioning,
+		validator:     r.validator,
+		tenantUpdater: r.tenantUpdater,
+	}
+}
+
 func NewResolver(provisioningService provisioning.Service, validator Validator, tenantUpdater TenantUpdater) *Resolver {
 	return &Resolver{
 		provisioning:  provisioningService,
@@ -43,6 +53,11 @@ func NewResolver(provisioningService provisioning.Service, validator Validator,
 }
 
 func (r *Resolver) ProvisionRuntime(ctx context.Context, config gqlschema.ProvisionRuntimeInput) (*gqlschema.OperationStatus, error) {
+	if err := authorize(ctx, authn.ScopeRuntimeWrite); err != nil {
+		log.Errorf("Failed to provision Runtime %s: %s", config.RuntimeInput.Name, err)
+		return nil, err
+	}
+
 	err := r.validator.ValidateProvisioningInput(config)
 	if err != nil {
 		log.Errorf("Failed to provision Runtime %s", err)
@@ -69,16 +84,21 @@ func (r *Resolver) ProvisionRuntime(ctx context.Context, config gqlschema.Provis
 	return operationStatus, nil
 }
 
-func (r *Resolver) DeprovisionRuntime(ctx context.Context, id string) (string, error) {
+func (r *Resolver) DeprovisionRuntime(ctx context.Context, id string, force *bool) (string, error) {
 	log.Infof("Requested deprovisioning of Runtime %s.", id)
 
-	err := r.tenantUpdater.GetAndUpdateTenant(id, ctx)
+	if err := authorize(ctx, authn.ScopeRuntimeDelete); err != nil {
+		log.Errorf("Failed to deprovision Runtime %s: %s", id, err)
+		return "", err
+	}
+
+	err := r.tenantUpdater.GetAndVerifyTenant(id, ctx)
 	if err != nil {
 		log.Errorf("Failed to deprovision Runtime %s: %s", id, err)
 		return "", err
 	}
 
-	operationID, err := r.provisioning.DeprovisionRuntime(id)
+	operationID, err := r.provisioning.DeprovisionRuntime(id, force != nil && *force)
 	if err != nil {
 		log.Errorf("Failed to deprovision Runtime %s: %s", id, err)
 		return "", err
@@ -88,6 +108,54 @@ func (r *Resolver) DeprovisionRuntime(ctx context.Context, id string) (string, e
 	return operationID, nil
 }
 
+func (r *Resolver) CancelDeprovisioning(ctx context.Context, id string) (*gqlschema.OperationStatus, error) {
+	log.Infof("Requested to cancel deprovisioning of Runtime %s.", id)
+
+	if err := authorize(ctx, authn.ScopeRuntimeDelete); err != nil {
+		log.Errorf("Failed to cancel deprovisioning of Runtime %s: %s", id, err)
+		return nil, err
+	}
+
+	err := r.tenantUpdater.GetAndVerifyTenant(id, ctx)
+	if err != nil {
+		log.Errorf("Failed to cancel deprovisioning of Runtime %s: %s", id, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.CancelDeprovisioning(id)
+	if err != nil {
+		log.Errorf("Failed to cancel deprovisioning of Runtime %s: %s", id, err)
+		return nil, err
+	}
+	log.Infof("Deprovisioning of Runtime %s canceled.", id)
+
+	return status, nil
+}
+
+func (r *Resolver) SetDeletionProtection(ctx context.Context, id string, enabled bool) (*gqlschema.RuntimeStatus, error) {
+	log.Infof("Requested to set deletion protection of Runtime %s to %t.", id, enabled)
+
+	if err := authorize(ctx, authn.ScopeRuntimeDelete); err != nil {
+		log.Errorf("Failed to set deletion protection of Runtime %s: %s", id, err)
+		return nil, err
+	}
+
+	err := r.tenantUpdater.GetAndVerifyTenant(id, ctx)
+	if err != nil {
+		log.Errorf("Failed to set deletion protection of Runtime %s: %s", id, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.SetDeletionProtection(id, enabled)
+	if err != nil {
+		log.Errorf("Failed to set deletion protection of Runtime %s: %s", id, err)
+		return nil, err
+	}
+	log.Infof("Deletion protection of Runtime %s set to %t.", id, enabled)
+
+	return status, nil
+}
+
 func (r *Resolver) UpgradeRuntime(_ context.Context, runtimeID string, _ gqlschema.UpgradeRuntimeInput) (*gqlschema.OperationStatus, error) {
 	message := fmt.Sprintf("failed to upgrade cluster: %s Kyma configuration of the cluster is managed by Reconciler", runtimeID)
 
@@ -107,7 +175,12 @@ func (r *Resolver) ReconnectRuntimeAgent(context.Context, string) (string, error
 func (r *Resolver) RuntimeStatus(ctx context.Context, runtimeID string) (*gqlschema.RuntimeStatus, error) {
 	log.Infof("Requested to get status for Runtime %s.", runtimeID)
 
-	err := r.tenantUpdater.GetAndUpdateTenant(runtimeID, ctx)
+	if err := authorize(ctx, authn.ScopeRuntimeRead); err != nil {
+		log.Errorf("Failed to get status for Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	err := r.tenantUpdater.GetAndVerifyTenant(runtimeID, ctx)
 	if err != nil {
 		log.Errorf("Failed to get status for Runtime %s: %s", runtimeID, err)
 		return nil, err
@@ -126,13 +199,18 @@ func (r *Resolver) RuntimeStatus(ctx context.Context, runtimeID string) (*gqlsch
 func (r *Resolver) RuntimeOperationStatus(ctx context.Context, operationID string) (*gqlschema.OperationStatus, error) {
 	log.Infof("Requested to get Runtime operation status for Operation %s.", operationID)
 
+	if err := authorize(ctx, authn.ScopeRuntimeRead); err != nil {
+		log.Errorf("Failed to get Runtime operation status: %s Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
 	status, err := r.provisioning.RuntimeOperationStatus(operationID)
 	if err != nil {
 		log.Errorf("Failed to get Runtime operation status: %s Operation ID: %s", err, operationID)
 		return nil, err
 	}
 
-	err = r.tenantUpdater.GetAndUpdateTenant(*status.RuntimeID, ctx)
+	err = r.tenantUpdater.GetAndVerifyTenant(*status.RuntimeID, ctx)
 	if err != nil {
 		log.Errorf("Failed to get Runtime operation status: %s, Operation ID: %s", err, operationID)
 		return nil, err
@@ -143,10 +221,44 @@ func (r *Resolver) RuntimeOperationStatus(ctx context.Context, operationID strin
 	return status, nil
 }
 
+func (r *Resolver) RuntimeOperationDiagnostics(ctx context.Context, operationID string) (*gqlschema.OperationDiagnostics, error) {
+	log.Infof("Requested to get Runtime operation diagnostics for Operation %s.", operationID)
+
+	if err := authorize(ctx, authn.ScopeRuntimeRead); err != nil {
+		log.Errorf("Failed to get Runtime operation diagnostics: %s Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	status, err := r.provisioning.RuntimeOperationStatus(operationID)
+	if err != nil {
+		log.Errorf("Failed to get Runtime operation diagnostics: %s Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	err = r.tenantUpdater.GetAndVerifyTenant(*status.RuntimeID, ctx)
+	if err != nil {
+		log.Errorf("Failed to get Runtime operation diagnostics: %s, Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	diagnostics, err := r.provisioning.RuntimeOperationDiagnostics(operationID)
+	if err != nil {
+		log.Errorf("Failed to get Runtime operation diagnostics: %s Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	return diagnostics, nil
+}
+
 func (r *Resolver) UpgradeShoot(ctx context.Context, runtimeID string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, error) {
 	log.Infof("Requested to upgrade Gardener Shoot cluster specification for Runtime : %s.", runtimeID)
 
-	err := r.tenantUpdater.GetAndUpdateTenant(runtimeID, ctx)
+	if err := authorize(ctx, authn.ScopeRuntimeWrite); err != nil {
+		log.Errorf("Failed to upgrade Gardener Shoot cluster specification for Runtime  %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	err := r.tenantUpdater.GetAndVerifyTenant(runtimeID, ctx)
 	if err != nil {
 		log.Errorf("Failed to upgrade Gardener Shoot cluster specification for Runtime  %s: %s", runtimeID, err)
 		return nil, err
@@ -169,6 +281,244 @@ func (r *Resolver) UpgradeShoot(ctx context.Context, runtimeID string, input gql
 	return status, nil
 }
 
+func (r *Resolver) RotateCredentials(ctx context.Context, id string, kinds []gqlschema.CredentialsRotationKind, phase gqlschema.CredentialsRotationPhase) (*gqlschema.OperationStatus, error) {
+	log.Infof("Requested %s phase of credentials rotation for Runtime %s.", phase, id)
+
+	if err := authorize(ctx, authn.ScopeRuntimeWrite); err != nil {
+		log.Errorf("Failed to rotate credentials of Runtime %s: %s", id, err)
+		return nil, err
+	}
+
+	err := r.tenantUpdater.GetAndVerifyTenant(id, ctx)
+	if err != nil {
+		log.Errorf("Failed to rotate credentials of Runtime %s: %s", id, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.RotateCredentials(id, kinds, phase)
+	if err != nil {
+		log.Errorf("Failed to rotate credentials of Runtime %s: %s", id, err)
+		return nil, err
+	}
+	log.Infof("Credentials rotation started for Runtime %s. Operation id %s", id, *status.ID)
+
+	return status, nil
+}
+
+func (r *Resolver) OperationStatusChanged(ctx context.Context, operationID string) (<-chan *gqlschema.OperationStatus, error) {
+	log.Infof("Requested to subscribe to Runtime operation status for Operation %s.", operationID)
+
+	if err := authorize(ctx, authn.ScopeRuntimeRead); err != nil {
+		log.Errorf("Failed to subscribe to Runtime operation status: %s Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	status, err := r.provisioning.RuntimeOperationStatus(operationID)
+	if err != nil {
+		log.Errorf("Failed to subscribe to Runtime operation status: %s Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	err = r.tenantUpdater.GetAndVerifyTenant(*status.RuntimeID, ctx)
+	if err != nil {
+		log.Errorf("Failed to subscribe to Runtime operation status: %s, Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	statuses, err := r.provisioning.SubscribeOperationStatus(ctx, operationID)
+	if err != nil {
+		log.Errorf("Failed to subscribe to Runtime operation status: %s, Operation ID: %s", err, operationID)
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func (r *Resolver) RuntimeEvents(ctx context.Context, runtimeID string) (<-chan *gqlschema.RuntimeEvent, error) {
+	log.Infof("Requested to subscribe to events for Runtime %s.", runtimeID)
+
+	if err := authorize(ctx, authn.ScopeRuntimeRead); err != nil {
+		log.Errorf("Failed to subscribe to events for Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	err := r.tenantUpdater.GetAndVerifyTenant(runtimeID, ctx)
+	if err != nil {
+		log.Errorf("Failed to subscribe to events for Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	runtimeEvents, err := r.provisioning.SubscribeRuntimeEvents(ctx, runtimeID)
+	if err != nil {
+		log.Errorf("Failed to subscribe to events for Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	return runtimeEvents, nil
+}
+
+func (r *Resolver) SetTenantQuota(ctx context.Context, tenant string, quota gqlschema.TenantQuotaInput) (*gqlschema.TenantQuota, error) {
+	log.Infof("Requested to set quota for tenant %s.", tenant)
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to set quota for tenant %s: %s", tenant, err)
+		return nil, err
+	}
+
+	tenantQuota, err := r.provisioning.SetTenantQuota(tenant, quota)
+	if err != nil {
+		log.Errorf("Failed to set quota for tenant %s: %s", tenant, err)
+		return nil, err
+	}
+	log.Infof("Quota for tenant %s set.", tenant)
+
+	return tenantQuota, nil
+}
+
+func (r *Resolver) TenantUsage(ctx context.Context, tenant string) (*gqlschema.TenantUsage, error) {
+	log.Infof("Requested to get usage of tenant %s.", tenant)
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to get usage of tenant %s: %s", tenant, err)
+		return nil, err
+	}
+
+	usage, err := r.provisioning.TenantUsage(tenant)
+	if err != nil {
+		log.Errorf("Failed to get usage of tenant %s: %s", tenant, err)
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+func (r *Resolver) TenantsUsage(ctx context.Context) ([]*gqlschema.TenantUsage, error) {
+	log.Infof("Requested to get usage of all tenants.")
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to get usage of tenants: %s", err)
+		return nil, err
+	}
+
+	usages, err := r.provisioning.TenantsUsage()
+	if err != nil {
+		log.Errorf("Failed to get usage of tenants: %s", err)
+		return nil, err
+	}
+
+	return usages, nil
+}
+
+func (r *Resolver) FindOrphans(ctx context.Context) (*gqlschema.OrphansReport, error) {
+	log.Infof("Requested to find orphans.")
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to find orphans: %s", err)
+		return nil, err
+	}
+
+	report, err := r.provisioning.FindOrphans()
+	if err != nil {
+		log.Errorf("Failed to find orphans: %s", err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *Resolver) BulkUpgradeShoot(ctx context.Context, selector gqlschema.RuntimeSelectorInput, config gqlschema.UpgradeShootInput, strategy gqlschema.BulkOperationStrategyInput) (*gqlschema.BulkOperationStatus, error) {
+	log.Infof("Requested bulk upgrade of Gardener Shoot clusters.")
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to start bulk upgrade of Gardener Shoot clusters: %s", err)
+		return nil, err
+	}
+
+	err := r.validator.ValidateUpgradeShootInput(config)
+	if err != nil {
+		log.Errorf("Failed to start bulk upgrade of Gardener Shoot clusters: %s", err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.BulkUpgradeShoot(selector, config, strategy)
+	if err != nil {
+		log.Errorf("Failed to start bulk upgrade of Gardener Shoot clusters: %s", err)
+		return nil, err
+	}
+
+	log.Infof("Bulk upgrade of Gardener Shoot clusters %s started for %d Runtimes", status.ID, status.Total)
+
+	return status, nil
+}
+
+func (r *Resolver) BulkOperationStatus(ctx context.Context, id string) (*gqlschema.BulkOperationStatus, error) {
+	log.Infof("Requested to get status of bulk operation %s.", id)
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to get status of bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.BulkOperationStatus(id)
+	if err != nil {
+		log.Errorf("Failed to get status of bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (r *Resolver) PauseBulkOperation(ctx context.Context, id string) (*gqlschema.BulkOperationStatus, error) {
+	log.Infof("Requested to pause bulk operation %s.", id)
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to pause bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.PauseBulkOperation(id)
+	if err != nil {
+		log.Errorf("Failed to pause bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (r *Resolver) ResumeBulkOperation(ctx context.Context, id string) (*gqlschema.BulkOperationStatus, error) {
+	log.Infof("Requested to resume bulk operation %s.", id)
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to resume bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.ResumeBulkOperation(id)
+	if err != nil {
+		log.Errorf("Failed to resume bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (r *Resolver) CancelBulkOperation(ctx context.Context, id string) (*gqlschema.BulkOperationStatus, error) {
+	log.Infof("Requested to cancel bulk operation %s.", id)
+
+	if err := authorize(ctx, authn.ScopeAdmin); err != nil {
+		log.Errorf("Failed to cancel bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.CancelBulkOperation(id)
+	if err != nil {
+		log.Errorf("Failed to cancel bulk operation %s: %s", id, err)
+		return nil, err
+	}
+
+	return status, nil
+}
+
 func (r *Resolver) HibernateRuntime(context.Context, string) (*gqlschema.OperationStatus, error) {
 	return nil, nil
 }
@@ -180,3 +530,17 @@ func getSubAccount(ctx context.Context) string {
 	}
 	return subAccount
 }
+
+// authorize checks scopes of the authenticated caller, requests are not restricted when authentication is disabled
+func authorize(ctx context.Context, scope authn.Scope) apperrors.AppError {
+	identity, authenticated := authn.IdentityFromContext(ctx)
+	if !authenticated {
+		return nil
+	}
+
+	if !identity.HasScope(scope) {
+		return apperrors.Forbidden("identity %s does not have %s scope", identity.Name, scope)
+	}
+
+	return nil
+}
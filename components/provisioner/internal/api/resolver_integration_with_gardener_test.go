@@ -11,6 +11,7 @@ import (
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/fake/seeds"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/fake/shoots"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
 
 	provisioning2 "github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/provisioning"
@@ -36,6 +37,7 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/testutils"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/quota"
 	runtimeConfig "github.com/kyma-project/control-plane/components/provisioner/internal/runtime"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
@@ -144,7 +146,10 @@ func TestProvisioning_ProvisionRuntimeWithDatabase(t *testing.T) {
 	kubeconfigProviderMock.On("FetchFromRequest", mock.AnythingOfType("string")).Return([]byte(mockedKubeconfig), nil)
 	kubeconfigProviderMock.On("FetchFromShoot", mock.AnythingOfType("string")).Return([]byte(mockedKubeconfig), nil)
 
-	provisioningQueue := queue.CreateProvisioningQueue(
+	eventBroker := events.NewBroker()
+	queueOptions := queue.QueueOptions{Pipelines: queue.PipelinesConfig{CompassEnabled: true}, EventPublisher: eventBroker}
+
+	provisioningQueue, err := queue.CreateProvisioningQueue(
 		testProvisioningTimeouts(),
 		dbsFactory,
 		directorServiceMock,
@@ -152,13 +157,17 @@ func TestProvisioning_ProvisionRuntimeWithDatabase(t *testing.T) {
 		testOperatorRoleBinding(),
 		mockK8sClientProvider,
 		runtimeConfigurator,
-		kubeconfigProviderMock)
+		kubeconfigProviderMock,
+		queueOptions)
+	require.NoError(t, err)
 	provisioningQueue.Run(queueCtx.Done())
 
-	deprovisioningQueue := queue.CreateDeprovisioningQueue(testDeprovisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface)
+	deprovisioningQueue, err := queue.CreateDeprovisioningQueue(testDeprovisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface, kubeconfigProviderMock, nil, nil, queueOptions)
+	require.NoError(t, err)
 	deprovisioningQueue.Run(queueCtx.Done())
 
-	shootUpgradeQueue := queue.CreateShootUpgradeQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface, testOperatorRoleBinding(), mockK8sClientProvider, kubeconfigProviderMock)
+	shootUpgradeQueue, err := queue.CreateShootUpgradeQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface, testOperatorRoleBinding(), mockK8sClientProvider, kubeconfigProviderMock, queueOptions)
+	require.NoError(t, err)
 	shootUpgradeQueue.Run(queueCtx.Done())
 
 	controler, err := gardener.NewShootController(mgr, dbsFactory, auditLogsConfigPath)
@@ -209,9 +218,12 @@ func TestProvisioning_ProvisionRuntimeWithDatabase(t *testing.T) {
 			inputConverter := provisioning.NewInputConverter(uuidGenerator, "Project", defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
 			graphQLConverter := provisioning.NewGraphQLConverter()
 
-			provisioningService := provisioning.NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, dbsFactory, provisioner, uuidGenerator, gardener.NewShootProvider(shootInterface), provisioningQueue, deprovisioningQueue, shootUpgradeQueue)
+			seedSelector, err := gardener.NewSeedSelector(gardener.SeedSelectionConfig{Strategy: gardener.SeedSelectionNone}, nil, nil, nil)
+			require.NoError(t, err)
+
+			provisioningService := provisioning.NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, dbsFactory, provisioner, uuidGenerator, gardener.NewShootProvider(shootInterface), provisioningQueue, deprovisioningQueue, shootUpgradeQueue, provisioning.ServiceOptions{EventSubscriber: eventBroker, QuotaManager: quota.NewManager(quota.Config{}, dbsFactory), SeedSelector: seedSelector})
 
-			validator := api.NewValidator()
+			validator := api.NewValidator(true)
 
 			tenantUpdater := api.NewTenantUpdater(dbsFactory.NewReadWriteSession())
 
@@ -349,7 +361,7 @@ func testDeprovisionRuntime(t *testing.T, ctx context.Context, resolver *api.Res
 	require.NoError(t, err)
 
 	// when
-	deprovisionRuntimeID, err := resolver.DeprovisionRuntime(ctx, runtimeID)
+	deprovisionRuntimeID, err := resolver.DeprovisionRuntime(ctx, runtimeID, nil)
 	require.NoError(t, err)
 	require.NotEmpty(t, deprovisionRuntimeID)
 
@@ -408,11 +420,13 @@ func testProvisioningTimeouts() queue.ProvisioningTimeouts {
 		ShootRefresh:           5 * time.Minute,
 		AgentConfiguration:     5 * time.Minute,
 		AgentConnection:        5 * time.Minute,
+		CredentialsRotation:    5 * time.Minute,
 	}
 }
 
 func testDeprovisioningTimeouts() queue.DeprovisioningTimeouts {
 	return queue.DeprovisioningTimeouts{
+		ClusterCleanup:            5 * time.Minute,
 		ClusterDeletion:           5 * time.Minute,
 		WaitingForClusterDeletion: 5 * time.Minute,
 	}
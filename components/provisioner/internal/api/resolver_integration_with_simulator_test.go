@@ -0,0 +1,169 @@
+package api_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/api"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener/simulator"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/database"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/testutils"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/quota"
+	runtimeConfig "github.com/kyma-project/control-plane/components/provisioner/internal/runtime"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util/k8s"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	simulatorOperationDelay = 2 * time.Second
+	simulatorWaitTimeout    = 2 * time.Minute
+)
+
+func TestProvisioning_WithGardenerSimulator(t *testing.T) {
+	//given
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+	ctx = context.WithValue(ctx, middlewares.SubAccountID, subAccountId)
+
+	cleanupNetwork, err := testutils.EnsureTestNetworkForDB(t, ctx)
+	require.NoError(t, err)
+	defer cleanupNetwork()
+
+	containerCleanupFunc, connString, err := testutils.InitTestDBContainer(t, ctx, "postgres_database_simulator")
+	require.NoError(t, err)
+	defer containerCleanupFunc()
+
+	connection, err := database.InitializeDatabaseConnection(connString, 5)
+	require.NoError(t, err)
+	require.NotNil(t, connection)
+	defer testutils.CloseDatabase(t, connection)
+
+	err = database.SetupSchema(connection, testutils.SchemaFilePath)
+	require.NoError(t, err)
+
+	dbsFactory, _ := dbsession.NewFactory(connection, "qbl92bqtl6zshtjb4bvbwwc2qk7vtw2d")
+
+	kubeconfig, err := simulator.Kubeconfig(cfg)
+	require.NoError(t, err)
+
+	gardenerSimulator := simulator.New(namespace, simulator.Config{
+		OperationDelay: simulatorOperationDelay,
+		Domain:         "simulator.local",
+		Seed:           gardenerGenSeed,
+		Kubeconfig:     kubeconfig,
+	})
+	shootInterface := gardenerSimulator.Shoots()
+
+	uuidGenerator := uuid.NewUUIDGenerator()
+	registry := director.NewLocalRegistry(uuidGenerator)
+	k8sClientProvider := k8s.NewK8sClientProvider()
+	kubeconfigProvider := gardener.NewKubeconfigProvider(shootInterface, gardenerSimulator.AdminKubeconfigRequests(), gardenerSimulator.Secrets())
+	eventBroker := events.NewBroker()
+	queueOptions := queue.QueueOptions{Pipelines: queue.PipelinesConfig{CompassEnabled: false}, EventPublisher: eventBroker}
+
+	queueCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provisioningQueue, err := queue.CreateProvisioningQueue(
+		testProvisioningTimeouts(),
+		dbsFactory,
+		nil,
+		shootInterface,
+		testOperatorRoleBinding(),
+		k8sClientProvider,
+		runtimeConfig.NewRuntimeConfigurator(k8sClientProvider, nil),
+		kubeconfigProvider,
+		queueOptions)
+	require.NoError(t, err)
+	provisioningQueue.Run(queueCtx.Done())
+
+	deprovisioningQueue, err := queue.CreateDeprovisioningQueue(testDeprovisioningTimeouts(), dbsFactory, registry, shootInterface, kubeconfigProvider, nil, nil, queueOptions)
+	require.NoError(t, err)
+	deprovisioningQueue.Run(queueCtx.Done())
+
+	shootUpgradeQueue, err := queue.CreateShootUpgradeQueue(testProvisioningTimeouts(), dbsFactory, nil, shootInterface, testOperatorRoleBinding(), k8sClientProvider, kubeconfigProvider, queueOptions)
+	require.NoError(t, err)
+	shootUpgradeQueue.Run(queueCtx.Done())
+
+	credentialsRotationQueue, err := queue.CreateCredentialsRotationQueue(testProvisioningTimeouts(), dbsFactory, nil, shootInterface, kubeconfigProvider, queueOptions)
+	require.NoError(t, err)
+	credentialsRotationQueue.Run(queueCtx.Done())
+
+	provisioner := gardener.NewProvisioner(namespace, shootInterface, dbsFactory, auditLogPolicyCMName, filepath.Join("testdata", "maintwindow.json"))
+	inputConverter := provisioning.NewInputConverter(uuidGenerator, "Project", defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
+
+	seedSelector, err := gardener.NewSeedSelector(gardener.SeedSelectionConfig{Strategy: gardener.SeedSelectionNone}, nil, nil, nil)
+	require.NoError(t, err)
+
+	provisioningService := provisioning.NewProvisioningService(inputConverter, provisioning.NewGraphQLConverter(), registry, dbsFactory, provisioner, uuidGenerator, gardener.NewShootProvider(shootInterface), provisioningQueue, deprovisioningQueue, shootUpgradeQueue, provisioning.ServiceOptions{CredentialsRotationQueue: credentialsRotationQueue, EventSubscriber: eventBroker, QuotaManager: quota.NewManager(quota.Config{}, dbsFactory), SeedSelector: seedSelector})
+	resolver := api.NewResolver(provisioningService, api.NewValidator(false), api.NewTenantUpdater(dbsFactory.NewReadWriteSession()))
+
+	clusterConfig := azureGardenerClusterConfigInputNoSeed()
+	runtimeInput := gqlschema.RuntimeInput{Name: "simulated runtime", Description: new(string)}
+
+	// when Provisioning Runtime
+	provisionOperation, err := resolver.ProvisionRuntime(ctx, gqlschema.ProvisionRuntimeInput{RuntimeInput: &runtimeInput, ClusterConfig: &clusterConfig})
+	require.NoError(t, err)
+
+	// then
+	waitForOperationState(t, ctx, resolver, *provisionOperation.ID, gqlschema.OperationStateSucceeded)
+
+	shoot, err := shootInterface.Get(context.Background(), clusterConfig.GardenerConfig.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, gardener_types.LastOperationStateSucceeded, shoot.Status.LastOperation.State)
+
+	runtimeID := *provisionOperation.RuntimeID
+	runtimeStatus, err := resolver.RuntimeStatus(ctx, runtimeID)
+	require.NoError(t, err)
+	assert.Equal(t, gardenerGenSeed, *runtimeStatus.RuntimeConfiguration.ClusterConfig.Seed)
+	assert.Equal(t, string(kubeconfig), *runtimeStatus.RuntimeConfiguration.Kubeconfig)
+
+	// when Upgrading Shoot
+	upgradeOperation, err := resolver.UpgradeShoot(ctx, runtimeID, NewUpgradeShootInput())
+	require.NoError(t, err)
+
+	// then
+	waitForOperationState(t, ctx, resolver, *upgradeOperation.ID, gqlschema.OperationStateSucceeded)
+
+	shoot, err = shootInterface.Get(context.Background(), clusterConfig.GardenerConfig.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, gardener_types.LastOperationTypeReconcile, shoot.Status.LastOperation.Type)
+	assert.Equal(t, shoot.Generation, shoot.Status.ObservedGeneration)
+
+	// when Deprovisioning Runtime
+	deprovisionOperationID, err := resolver.DeprovisionRuntime(ctx, runtimeID, nil)
+	require.NoError(t, err)
+
+	// then
+	waitForOperationState(t, ctx, resolver, deprovisionOperationID, gqlschema.OperationStateSucceeded)
+
+	_, err = shootInterface.Get(context.Background(), clusterConfig.GardenerConfig.Name, metav1.GetOptions{})
+	require.Error(t, err)
+
+	cluster, err := dbsFactory.NewReadSession().GetCluster(runtimeID)
+	require.NoError(t, err)
+	assert.True(t, cluster.Deleted)
+}
+
+func waitForOperationState(t *testing.T, ctx context.Context, resolver *api.Resolver, operationID string, state gqlschema.OperationState) {
+	require.Eventually(t, func() bool {
+		status, err := resolver.RuntimeOperationStatus(ctx, operationID)
+		require.NoError(t, err)
+		require.NotEqual(t, gqlschema.OperationStateFailed, status.State, "operation %s failed: %s", operationID, *status.Message)
+
+		return status.State == state
+	}, simulatorWaitTimeout, simulatorOperationDelay)
+}
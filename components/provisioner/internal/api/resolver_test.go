@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/authn"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api"
 
@@ -209,11 +210,31 @@ func TestResolver_DeprovisionRuntime(t *testing.T) {
 
 		expectedID := "ec781980-0533-4098-aab7-96b535569732"
 
-		provisioningService.On("DeprovisionRuntime", runtimeID).Return(expectedID, nil)
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		provisioningService.On("DeprovisionRuntime", runtimeID, false).Return(expectedID, nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 
 		//when
-		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID)
+		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID, nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, expectedID, operationID)
+	})
+
+	t.Run("Should pass force flag", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		tenantUpdater := &validatorMocks.TenantUpdater{}
+		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
+
+		expectedID := "ec781980-0533-4098-aab7-96b535569732"
+
+		provisioningService.On("DeprovisionRuntime", runtimeID, true).Return(expectedID, nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
+
+		//when
+		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID, util.BoolPtr(true))
 
 		//then
 		require.NoError(t, err)
@@ -226,11 +247,11 @@ func TestResolver_DeprovisionRuntime(t *testing.T) {
 		validator := &validatorMocks.Validator{}
 		tenantUpdater := &validatorMocks.TenantUpdater{}
 		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
-		provisioningService.On("DeprovisionRuntime", runtimeID).Return("", apperrors.Internal("Deprovisioning fails because reasons"))
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		provisioningService.On("DeprovisionRuntime", runtimeID, false).Return("", apperrors.Internal("Deprovisioning fails because reasons"))
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 
 		//when
-		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID)
+		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID, nil)
 
 		//then
 		require.Error(t, err)
@@ -248,15 +269,56 @@ func TestResolver_DeprovisionRuntime(t *testing.T) {
 
 		ctx := context.Background()
 
-		provisioningService.On("DeprovisionRuntime", runtimeID).Return(expectedID, nil, nil)
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(apperrors.BadRequest("tenant header not passed"))
+		provisioningService.On("DeprovisionRuntime", runtimeID, false).Return(expectedID, nil, nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(apperrors.BadRequest("tenant header not passed"))
+
+		//when
+		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID, nil)
+
+		//then
+		require.Error(t, err)
+		require.Empty(t, operationID)
+	})
+	t.Run("Should fail when caller does not have delete scope", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		tenantUpdater := &validatorMocks.TenantUpdater{}
+		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
+
+		identity := authn.Identity{Name: "broker", Tenants: []string{tenant}, Scopes: []authn.Scope{authn.ScopeRuntimeRead, authn.ScopeRuntimeWrite}}
+		ctx := authn.WithIdentity(ctx, identity)
 
 		//when
-		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID)
+		operationID, err := provisioner.DeprovisionRuntime(ctx, runtimeID, nil)
 
 		//then
 		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
 		require.Empty(t, operationID)
+		provisioningService.AssertNotCalled(t, "DeprovisionRuntime", runtimeID)
+		tenantUpdater.AssertNotCalled(t, "GetAndVerifyTenant", runtimeID, ctx)
+	})
+
+	t.Run("Should start deprovisioning when caller has delete scope", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		tenantUpdater := &validatorMocks.TenantUpdater{}
+		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
+
+		identity := authn.Identity{Name: "broker", Tenants: []string{tenant}, Scopes: []authn.Scope{authn.ScopeRuntimeDelete}}
+		ctx := authn.WithIdentity(ctx, identity)
+
+		provisioningService.On("DeprovisionRuntime", runtimeID, false).Return(operationID, nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
+
+		//when
+		id, err := provisioner.DeprovisionRuntime(ctx, runtimeID, nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, operationID, id)
 	})
 }
 
@@ -288,7 +350,7 @@ func TestResolver_RuntimeStatus(t *testing.T) {
 		}
 
 		provisioningService.On("RuntimeStatus", runtimeID).Return(status, nil)
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 
 		//when
 		runtimeStatus, err := provisioner.RuntimeStatus(ctx, runtimeID)
@@ -307,7 +369,7 @@ func TestResolver_RuntimeStatus(t *testing.T) {
 		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
 
 		provisioningService.On("RuntimeStatus", runtimeID).Return(nil, apperrors.Internal("Runtime status fails"))
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 
 		//when
 		status, err := provisioner.RuntimeStatus(ctx, runtimeID)
@@ -343,7 +405,7 @@ func TestResolver_RuntimeOperationStatus(t *testing.T) {
 		}
 
 		provisioningService.On("RuntimeOperationStatus", operationID).Return(operationStatus, nil)
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 
 		//when
 		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
@@ -363,7 +425,7 @@ func TestResolver_RuntimeOperationStatus(t *testing.T) {
 		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
 
 		provisioningService.On("RuntimeOperationStatus", operationID).Return(nil, apperrors.Internal("Some error"))
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 
 		//when
 		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
@@ -375,6 +437,113 @@ func TestResolver_RuntimeOperationStatus(t *testing.T) {
 	})
 }
 
+func TestResolver_RuntimeOperationDiagnostics(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
+	operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
+
+	operationStatus := &gqlschema.OperationStatus{
+		ID:        &operationID,
+		Operation: gqlschema.OperationTypeProvision,
+		State:     gqlschema.OperationStateInProgress,
+		RuntimeID: &runtimeID,
+	}
+
+	t.Run("Should return operation diagnostics", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		tenantUpdater := &validatorMocks.TenantUpdater{}
+
+		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
+
+		diagnostics := &gqlschema.OperationDiagnostics{
+			OperationID: operationID,
+			RuntimeID:   runtimeID,
+			Stage:       "WaitingForClusterCreation",
+		}
+
+		provisioningService.On("RuntimeOperationStatus", operationID).Return(operationStatus, nil)
+		provisioningService.On("RuntimeOperationDiagnostics", operationID).Return(diagnostics, nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
+
+		//when
+		result, err := provisioner.RuntimeOperationDiagnostics(ctx, operationID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, diagnostics, result)
+	})
+
+	t.Run("Should not return diagnostics when tenant check fails", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		tenantUpdater := &validatorMocks.TenantUpdater{}
+
+		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
+
+		provisioningService.On("RuntimeOperationStatus", operationID).Return(operationStatus, nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(apperrors.Forbidden("not allowed"))
+
+		//when
+		result, err := provisioner.RuntimeOperationDiagnostics(ctx, operationID)
+
+		//then
+		require.Error(t, err)
+		assert.Nil(t, result)
+		provisioningService.AssertNotCalled(t, "RuntimeOperationDiagnostics", operationID)
+	})
+}
+
+func TestResolver_BulkUpgradeShoot(t *testing.T) {
+	selector := gqlschema.RuntimeSelectorInput{Providers: []string{"aws"}}
+	config := gqlschema.UpgradeShootInput{GardenerConfig: &gqlschema.GardenerUpgradeInput{}}
+	strategy := gqlschema.BulkOperationStrategyInput{MaxParallel: 5, MaxFailureRatio: 0.1}
+
+	t.Run("Should start bulk upgrade when caller has admin scope", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		tenantUpdater := &validatorMocks.TenantUpdater{}
+
+		identity := authn.Identity{Name: "operator", Scopes: []authn.Scope{authn.ScopeAdmin}}
+		ctx := authn.WithIdentity(context.Background(), identity)
+
+		bulkStatus := &gqlschema.BulkOperationStatus{ID: "bulk-operation", State: gqlschema.BulkOperationStateInProgress, Total: 3}
+		validator.On("ValidateUpgradeShootInput", config).Return(nil)
+		provisioningService.On("BulkUpgradeShoot", selector, config, strategy).Return(bulkStatus, nil)
+
+		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
+
+		//when
+		status, err := provisioner.BulkUpgradeShoot(ctx, selector, config, strategy)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, bulkStatus, status)
+	})
+
+	t.Run("Should fail when caller does not have admin scope", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		tenantUpdater := &validatorMocks.TenantUpdater{}
+
+		identity := authn.Identity{Name: "broker", Tenants: []string{tenant}, Scopes: []authn.Scope{authn.ScopeRuntimeWrite}}
+		ctx := authn.WithIdentity(context.Background(), identity)
+
+		provisioner := api.NewResolver(provisioningService, validator, tenantUpdater)
+
+		//when
+		_, err := provisioner.BulkUpgradeShoot(ctx, selector, config, strategy)
+
+		//then
+		require.Error(t, err)
+		provisioningService.AssertNotCalled(t, "BulkUpgradeShoot", selector, config, strategy)
+	})
+}
+
 func TestResolver_UpgradeShoot(t *testing.T) {
 	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
 
@@ -394,7 +563,7 @@ func TestResolver_UpgradeShoot(t *testing.T) {
 			RuntimeID: util.StringPtr(runtimeID),
 		}
 
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 		validator.On("ValidateUpgradeShootInput", upgradeShootInput).Return(nil)
 		provisioningService.On("UpgradeGardenerShoot", runtimeID, upgradeShootInput).Return(operation, nil)
 
@@ -418,7 +587,7 @@ func TestResolver_UpgradeShoot(t *testing.T) {
 		tenantUpdater := &validatorMocks.TenantUpdater{}
 
 		validator.On("ValidateUpgradeShootInput", upgradeShootInput).Return(apperrors.BadRequest("error"))
-		tenantUpdater.On("GetAndUpdateTenant", runtimeID, ctx).Return(nil)
+		tenantUpdater.On("GetAndVerifyTenant", runtimeID, ctx).Return(nil)
 
 		resolver := api.NewResolver(provisioningService, validator, tenantUpdater)
 
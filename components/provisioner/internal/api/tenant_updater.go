@@ -5,13 +5,14 @@ import (
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/authn"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
 )
 
 //go:generate mockery --name=TenantUpdater
 type TenantUpdater interface {
 	GetTenant(ctx context.Context) (string, apperrors.AppError)
-	GetAndUpdateTenant(runtimeID string, ctx context.Context) apperrors.AppError
+	GetAndVerifyTenant(runtimeID string, ctx context.Context) apperrors.AppError
 }
 
 type updater struct {
@@ -30,10 +31,16 @@ func (u *updater) GetTenant(ctx context.Context) (string, apperrors.AppError) {
 		return "", apperrors.BadRequest("tenant header is empty")
 	}
 
+	if identity, authenticated := authn.IdentityFromContext(ctx); authenticated && !identity.CanAccessTenant(tenant) {
+		return "", apperrors.Forbidden("identity %s is not allowed to access tenant %s", identity.Name, tenant)
+	}
+
 	return tenant, nil
 }
 
-func (u *updater) GetAndUpdateTenant(runtimeID string, ctx context.Context) apperrors.AppError {
+// GetAndVerifyTenant checks that the tenant from the request is the tenant of the runtime. The tenant of the runtime
+// is never changed from the request, so that the runtime of another tenant cannot be taken over.
+func (u *updater) GetAndVerifyTenant(runtimeID string, ctx context.Context) apperrors.AppError {
 	tenant, err := u.GetTenant(ctx)
 	if err != nil {
 		return err
@@ -44,10 +51,7 @@ func (u *updater) GetAndUpdateTenant(runtimeID string, ctx context.Context) appe
 	}
 
 	if tenant != dbTenant {
-		dberr := u.readWriteSession.UpdateTenant(runtimeID, tenant)
-		if dberr != nil {
-			return dberr
-		}
+		return apperrors.Forbidden("tenant %s is not allowed to access Runtime %s", tenant, runtimeID)
 	}
 	return nil
 }
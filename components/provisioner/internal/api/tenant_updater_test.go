@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/authn"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,10 +32,32 @@ func TestTenantUpdater_GetTenant(t *testing.T) {
 		_, appError := tenantUpdater.GetTenant(ctx)
 		require.Error(t, appError)
 	})
+
+	t.Run("should return error when caller is not allowed to access tenant", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), middlewares.Tenant, "tenant")
+		ctx = authn.WithIdentity(ctx, authn.Identity{Name: "broker", Tenants: []string{"other-tenant"}})
+
+		tenantUpdater := NewTenantUpdater(nil)
+
+		_, appError := tenantUpdater.GetTenant(ctx)
+		require.Error(t, appError)
+		assert.Equal(t, apperrors.CodeForbidden, appError.Code())
+	})
+
+	t.Run("should extract tenant when caller is allowed to access any tenant", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), middlewares.Tenant, "tenant")
+		ctx = authn.WithIdentity(ctx, authn.Identity{Name: "broker", Tenants: []string{authn.AnyTenant}})
+
+		tenantUpdater := NewTenantUpdater(nil)
+
+		ctxTenant, appError := tenantUpdater.GetTenant(ctx)
+		require.NoError(t, appError)
+		assert.Equal(t, "tenant", ctxTenant)
+	})
 }
 
-func TestTenantUpdater_GetAndUpdateTenant(t *testing.T) {
-	t.Run("should update tenant when differs from db tenant", func(t *testing.T) {
+func TestTenantUpdater_GetAndVerifyTenant(t *testing.T) {
+	t.Run("should return error when tenant differs from db tenant", func(t *testing.T) {
 		newTenant := "tenant"
 		dbTenant := "tenet"
 		runtimeId := "runtimeID"
@@ -43,13 +67,13 @@ func TestTenantUpdater_GetAndUpdateTenant(t *testing.T) {
 		tenantUpdater := NewTenantUpdater(rwsMock)
 
 		rwsMock.On("GetTenant", runtimeId).Return(dbTenant, nil)
-		rwsMock.On("UpdateTenant", runtimeId, newTenant).Return(nil)
 
-		err := tenantUpdater.GetAndUpdateTenant(runtimeId, ctx)
-		require.NoError(t, err)
+		err := tenantUpdater.GetAndVerifyTenant(runtimeId, ctx)
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeForbidden, err.Code())
 		rwsMock.AssertExpectations(t)
 	})
-	t.Run("should not update tenant when equal to db tenant", func(t *testing.T) {
+	t.Run("should accept tenant equal to db tenant", func(t *testing.T) {
 		newTenant := "tenant"
 		dbTenant := "tenant"
 		runtimeId := "runtimeID"
@@ -60,8 +84,23 @@ func TestTenantUpdater_GetAndUpdateTenant(t *testing.T) {
 
 		rwsMock.On("GetTenant", runtimeId).Return(dbTenant, nil)
 
-		err := tenantUpdater.GetAndUpdateTenant(runtimeId, ctx)
+		err := tenantUpdater.GetAndVerifyTenant(runtimeId, ctx)
 		require.NoError(t, err)
 		rwsMock.AssertExpectations(t)
 	})
+	t.Run("should return error when caller is not allowed to access runtime tenant", func(t *testing.T) {
+		runtimeId := "runtimeID"
+		ctx := context.WithValue(context.Background(), middlewares.Tenant, "tenant")
+		ctx = authn.WithIdentity(ctx, authn.Identity{Name: "broker", Tenants: []string{"tenant"}})
+
+		rwsMock := &mocks.ReadWriteSession{}
+		tenantUpdater := NewTenantUpdater(rwsMock)
+
+		rwsMock.On("GetTenant", runtimeId).Return("other-tenant", nil)
+
+		err := tenantUpdater.GetAndVerifyTenant(runtimeId, ctx)
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeForbidden, err.Code())
+		rwsMock.AssertExpectations(t)
+	})
 }
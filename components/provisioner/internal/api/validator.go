@@ -18,10 +18,13 @@ type Validator interface {
 }
 
 type validator struct {
+	compassEnabled bool
 }
 
-func NewValidator() Validator {
-	return &validator{}
+func NewValidator(compassEnabled bool) Validator {
+	return &validator{
+		compassEnabled: compassEnabled,
+	}
 }
 
 func (v *validator) ValidateProvisioningInput(input gqlschema.ProvisionRuntimeInput) apperrors.AppError {
@@ -74,7 +77,7 @@ func (v *validator) validateKymaConfig(kymaConfig *gqlschema.KymaConfigInput) ap
 		return appError
 	}
 
-	if !configContainsRuntimeAgentComponent(kymaConfig.Components) {
+	if v.compassEnabled && !configContainsRuntimeAgentComponent(kymaConfig.Components) {
 		return apperrors.BadRequest("error: Kyma components list does not contain Compass Runtime Agent")
 	}
 
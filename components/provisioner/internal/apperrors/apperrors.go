@@ -23,9 +23,10 @@ const (
 )
 
 const (
-	ErrProvisionerInternal     ErrReason = "err_provisioner_internal"
-	ErrProvisionerTimeout      ErrReason = "err_provisioner_timeout"
-	ErrProvisionerStepNotFound ErrReason = "err_provisioner_step_not_found"
+	ErrProvisionerInternal         ErrReason = "err_provisioner_internal"
+	ErrProvisionerTimeout          ErrReason = "err_provisioner_timeout"
+	ErrProvisionerStepNotFound     ErrReason = "err_provisioner_step_not_found"
+	ErrProvisionerPipelineNotFound ErrReason = "err_provisioner_pipeline_not_found"
 
 	ErrDirectorNilResponse       ErrReason = "err_director_nil_response"
 	ErrDirectorRuntimeIDMismatch ErrReason = "err_director_runtime_id_mismatch"
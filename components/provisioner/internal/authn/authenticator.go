@@ -0,0 +1,183 @@
+package authn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+const (
+	defaultIdentityClaim  = "sub"
+	jwksMinRefreshPeriod  = time.Minute
+	tokenValidationLeeway = 30 * time.Second
+)
+
+var ErrUnauthenticated = errors.New("request is not authenticated")
+
+//go:generate mockery --name=Authenticator
+type Authenticator interface {
+	// Authenticate returns name of the identity which sent the request
+	Authenticate(r *http.Request) (string, error)
+}
+
+type JWTConfig struct {
+	Issuer        string `envconfig:"optional"`
+	JWKSURL       string `envconfig:"optional"`
+	Audience      string `envconfig:"optional"`
+	IdentityClaim string `envconfig:"default=sub"`
+}
+
+type jwtAuthenticator struct {
+	config     JWTConfig
+	httpClient *http.Client
+
+	mu            sync.RWMutex
+	keys          jose.JSONWebKeySet
+	lastRefreshed time.Time
+}
+
+// NewJWTAuthenticator validates OAuth2 bearer tokens signed with keys published under JWKS URL of the issuer
+func NewJWTAuthenticator(config JWTConfig, httpClient *http.Client) (Authenticator, error) {
+	if config.Issuer == "" || config.JWKSURL == "" {
+		return nil, fmt.Errorf("issuer and JWKS URL are required for JWT authentication")
+	}
+	if config.IdentityClaim == "" {
+		config.IdentityClaim = defaultIdentityClaim
+	}
+
+	return &jwtAuthenticator{
+		config:     config,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (string, error) {
+	rawToken, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || rawToken == "" {
+		return "", ErrUnauthenticated
+	}
+
+	token, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse token: %s", err.Error())
+	}
+	if len(token.Headers) != 1 {
+		return "", fmt.Errorf("token must have exactly one signature")
+	}
+
+	key, err := a.getKey(r.Context(), token.Headers[0].KeyID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.Claims{}
+	customClaims := map[string]interface{}{}
+	if err := token.Claims(key, &claims, &customClaims); err != nil {
+		return "", fmt.Errorf("failed to verify token signature: %s", err.Error())
+	}
+
+	expected := jwt.Expected{Issuer: a.config.Issuer, Time: time.Now()}
+	if a.config.Audience != "" {
+		expected.Audience = jwt.Audience{a.config.Audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, tokenValidationLeeway); err != nil {
+		return "", fmt.Errorf("invalid token claims: %s", err.Error())
+	}
+
+	identity, ok := customClaims[a.config.IdentityClaim].(string)
+	if !ok || identity == "" {
+		return "", fmt.Errorf("token does not contain %s claim", a.config.IdentityClaim)
+	}
+
+	return identity, nil
+}
+
+func (a *jwtAuthenticator) getKey(ctx context.Context, keyID string) (*jose.JSONWebKey, error) {
+	if key, found := a.findKey(keyID); found {
+		return key, nil
+	}
+
+	if err := a.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	if key, found := a.findKey(keyID); found {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("signing key %s not found", keyID)
+}
+
+func (a *jwtAuthenticator) findKey(keyID string) (*jose.JSONWebKey, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	keys := a.keys.Key(keyID)
+	if len(keys) == 0 {
+		return nil, false
+	}
+	return &keys[0], true
+}
+
+func (a *jwtAuthenticator) refreshKeys(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Tokens with unknown key IDs must not cause flooding the issuer with requests
+	if time.Since(a.lastRefreshed) < jwksMinRefreshPeriod {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.config.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create JWKS request: %s", err.Error())
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: unexpected status code %d", resp.StatusCode)
+	}
+
+	keys := jose.JSONWebKeySet{}
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %s", err.Error())
+	}
+
+	a.keys = keys
+	a.lastRefreshed = time.Now()
+
+	return nil
+}
+
+type certificateAuthenticator struct{}
+
+// NewCertificateAuthenticator identifies callers by the common name of client certificate verified during TLS handshake
+func NewCertificateAuthenticator() Authenticator {
+	return &certificateAuthenticator{}
+}
+
+func (a *certificateAuthenticator) Authenticate(r *http.Request) (string, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", ErrUnauthenticated
+	}
+
+	commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	if commonName == "" {
+		return "", fmt.Errorf("client certificate does not contain common name")
+	}
+
+	return commonName, nil
+}
@@ -0,0 +1,166 @@
+package authn
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	issuer   = "https://issuer.example.com"
+	audience = "provisioner"
+	keyID    = "key-1"
+)
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &privateKey.PublicKey, KeyID: keyID, Algorithm: string(jose.RS256), Use: "sig"}}}
+		_ = json.NewEncoder(w).Encode(keys)
+	}))
+	defer jwksServer.Close()
+
+	authenticator, err := NewJWTAuthenticator(JWTConfig{Issuer: issuer, JWKSURL: jwksServer.URL, Audience: audience}, jwksServer.Client())
+	require.NoError(t, err)
+
+	validClaims := jwt.Claims{
+		Issuer:   issuer,
+		Subject:  "kyma-environment-broker",
+		Audience: jwt.Audience{audience},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	for _, testCase := range []struct {
+		description string
+		key         interface{}
+		claims      jwt.Claims
+		valid       bool
+	}{
+		{
+			description: "valid token",
+			key:         privateKey,
+			claims:      validClaims,
+			valid:       true,
+		},
+		{
+			description: "expired token",
+			key:         privateKey,
+			claims: func() jwt.Claims {
+				c := validClaims
+				c.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return c
+			}(),
+		},
+		{
+			description: "invalid issuer",
+			key:         privateKey,
+			claims: func() jwt.Claims {
+				c := validClaims
+				c.Issuer = "https://other.example.com"
+				return c
+			}(),
+		},
+		{
+			description: "invalid audience",
+			key:         privateKey,
+			claims: func() jwt.Claims {
+				c := validClaims
+				c.Audience = jwt.Audience{"other"}
+				return c
+			}(),
+		},
+		{
+			description: "token signed with unknown key",
+			key: func() *rsa.PrivateKey {
+				key, err := rsa.GenerateKey(rand.Reader, 2048)
+				require.NoError(t, err)
+				return key
+			}(),
+			claims: validClaims,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			// given
+			token := signToken(t, testCase.key, testCase.claims)
+
+			req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			// when
+			identity, err := authenticator.Authenticate(req)
+
+			// then
+			if testCase.valid {
+				require.NoError(t, err)
+				assert.Equal(t, "kyma-environment-broker", identity)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("should return error when token is missing", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+
+		// when
+		_, err := authenticator.Authenticate(req)
+
+		// then
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+}
+
+func TestCertificateAuthenticator_Authenticate(t *testing.T) {
+	t.Run("should return common name of verified client certificate", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		req.TLS = &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "kyma-environment-broker"}}}},
+		}
+
+		// when
+		identity, err := NewCertificateAuthenticator().Authenticate(req)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "kyma-environment-broker", identity)
+	})
+
+	t.Run("should return error when client certificate is not verified", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+
+		// when
+		_, err := NewCertificateAuthenticator().Authenticate(req)
+
+		// then
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+}
+
+func signToken(t *testing.T, key interface{}, claims jwt.Claims) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyID))
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
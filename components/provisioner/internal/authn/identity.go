@@ -0,0 +1,97 @@
+package authn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type Scope string
+
+const (
+	ScopeRuntimeRead   Scope = "runtime:read"
+	ScopeRuntimeWrite  Scope = "runtime:write"
+	ScopeRuntimeDelete Scope = "runtime:delete"
+	// ScopeAdmin allows for managing the Provisioner itself, for example tenant quotas
+	ScopeAdmin Scope = "admin"
+
+	// AnyTenant grants access to all tenants
+	AnyTenant = "*"
+)
+
+type identityKey struct{}
+
+type Identity struct {
+	Name    string   `json:"name"`
+	Tenants []string `json:"tenants"`
+	Scopes  []Scope  `json:"scopes"`
+}
+
+func (i Identity) HasScope(scope Scope) bool {
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (i Identity) CanAccessTenant(tenant string) bool {
+	for _, t := range i.Tenants {
+		if t == AnyTenant || t == tenant {
+			return true
+		}
+	}
+	return false
+}
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns false if the request was not authenticated, which is the case when authentication is disabled
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Identities maps authenticated identity names (token subjects or certificate common names) to their permissions
+type Identities map[string]Identity
+
+func (i Identities) Get(name string) (Identity, bool) {
+	identity, ok := i[name]
+	return identity, ok
+}
+
+// LoadIdentities reads identities from the JSON file in format: {"identities": [{"name": "...", "tenants": ["..."], "scopes": ["runtime:read"]}]}
+func LoadIdentities(path string) (Identities, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open identities file: %s", err.Error())
+	}
+
+	defer file.Close()
+
+	var config struct {
+		Identities []Identity `json:"identities"`
+	}
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode identities file: %s", err.Error())
+	}
+
+	identities := Identities{}
+	for _, identity := range config.Identities {
+		if identity.Name == "" {
+			return nil, fmt.Errorf("identity name must not be empty")
+		}
+		for _, scope := range identity.Scopes {
+			if scope != ScopeRuntimeRead && scope != ScopeRuntimeWrite && scope != ScopeRuntimeDelete && scope != ScopeAdmin {
+				return nil, fmt.Errorf("identity %s has unknown scope %s", identity.Name, scope)
+			}
+		}
+		identities[identity.Name] = identity
+	}
+
+	return identities, nil
+}
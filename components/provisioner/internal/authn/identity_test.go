@@ -0,0 +1,31 @@
+package authn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadIdentities(t *testing.T) {
+	// when
+	identities, err := LoadIdentities("testdata/identities.json")
+
+	// then
+	require.NoError(t, err)
+
+	broker, found := identities.Get("kyma-environment-broker")
+	require.True(t, found)
+	assert.True(t, broker.CanAccessTenant("any-tenant"))
+	assert.True(t, broker.HasScope(ScopeRuntimeDelete))
+
+	viewer, found := identities.Get("viewer")
+	require.True(t, found)
+	assert.True(t, viewer.CanAccessTenant("tenant"))
+	assert.False(t, viewer.CanAccessTenant("other-tenant"))
+	assert.True(t, viewer.HasScope(ScopeRuntimeRead))
+	assert.False(t, viewer.HasScope(ScopeRuntimeWrite))
+
+	_, found = identities.Get("unknown")
+	assert.False(t, found)
+}
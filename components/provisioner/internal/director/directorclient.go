@@ -20,15 +20,15 @@ const (
 	TenantHeader        = "Tenant"
 )
 
+// DirectorClient registers Runtimes in Compass Director and manages their Compass specific data
+//
 //go:generate mockery --name=DirectorClient
 type DirectorClient interface {
-	CreateRuntime(config *gqlschema.RuntimeInput, tenant string) (string, apperrors.AppError)
+	RuntimeRegistry
 	GetRuntime(id, tenant string) (graphql.RuntimeExt, apperrors.AppError)
 	UpdateRuntime(id string, config *graphql.RuntimeUpdateInput, tenant string) apperrors.AppError
-	DeleteRuntime(id, tenant string) apperrors.AppError
 	SetRuntimeStatusCondition(id string, statusCondition graphql.RuntimeStatusCondition, tenant string) apperrors.AppError
 	GetConnectionToken(id, tenant string) (graphql.OneTimeTokenForRuntimeExt, apperrors.AppError)
-	RuntimeExists(gardenerClusterName, tenant string) (bool, apperrors.AppError)
 }
 
 type directorClient struct {
@@ -0,0 +1,34 @@
+package director
+
+import (
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+)
+
+// localRegistry replaces Director on landscapes without Compass, it only generates Runtime IDs
+type localRegistry struct {
+	uuidGenerator uuid.UUIDGenerator
+}
+
+func NewLocalRegistry(uuidGenerator uuid.UUIDGenerator) RuntimeRegistry {
+	return &localRegistry{
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (r *localRegistry) CreateRuntime(config *gqlschema.RuntimeInput, _ string) (string, apperrors.AppError) {
+	if config == nil {
+		return "", apperrors.BadRequest("Cannot register runtime: missing Runtime config")
+	}
+
+	return r.uuidGenerator.New(), nil
+}
+
+func (r *localRegistry) DeleteRuntime(_, _ string) apperrors.AppError {
+	return nil
+}
+
+func (r *localRegistry) RuntimeExists(_, _ string) (bool, apperrors.AppError) {
+	return false, nil
+}
@@ -0,0 +1,50 @@
+package director
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalRegistry(t *testing.T) {
+	t.Run("Should generate Runtime ID", func(t *testing.T) {
+		// given
+		uuidGenerator := &mocks.UUIDGenerator{}
+		uuidGenerator.On("New").Return(runtimeTestingID)
+
+		registry := NewLocalRegistry(uuidGenerator)
+
+		// when
+		id, err := registry.CreateRuntime(&gqlschema.RuntimeInput{Name: runtimeTestingName}, tenantValue)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, runtimeTestingID, id)
+	})
+
+	t.Run("Should fail to register Runtime without config", func(t *testing.T) {
+		// given
+		registry := NewLocalRegistry(&mocks.UUIDGenerator{})
+
+		// when
+		_, err := registry.CreateRuntime(nil, tenantValue)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("Should report that Runtime does not exist", func(t *testing.T) {
+		// given
+		registry := NewLocalRegistry(&mocks.UUIDGenerator{})
+
+		// when
+		exists, err := registry.RuntimeExists(runtimeTestingID, tenantValue)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
@@ -102,20 +102,20 @@ func (_m *DirectorClient) GetRuntime(id string, tenant string) (graphql.RuntimeE
 	return r0, r1
 }
 
-// RuntimeExists provides a mock function with given fields: gardenerClusterName, tenant
-func (_m *DirectorClient) RuntimeExists(gardenerClusterName string, tenant string) (bool, apperrors.AppError) {
-	ret := _m.Called(gardenerClusterName, tenant)
+// RuntimeExists provides a mock function with given fields: id, tenant
+func (_m *DirectorClient) RuntimeExists(id string, tenant string) (bool, apperrors.AppError) {
+	ret := _m.Called(id, tenant)
 
 	var r0 bool
 	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
-		r0 = rf(gardenerClusterName, tenant)
+		r0 = rf(id, tenant)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 apperrors.AppError
 	if rf, ok := ret.Get(1).(func(string, string) apperrors.AppError); ok {
-		r1 = rf(gardenerClusterName, tenant)
+		r1 = rf(id, tenant)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(apperrors.AppError)
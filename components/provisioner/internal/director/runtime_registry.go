@@ -0,0 +1,20 @@
+package director
+
+import (
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+)
+
+const (
+	RegistryDirector = "director"
+	RegistryLocal    = "local"
+)
+
+// RuntimeRegistry keeps track of the Runtimes managed by Provisioner, either in Compass Director or locally
+//
+//go:generate mockery --name=RuntimeRegistry
+type RuntimeRegistry interface {
+	CreateRuntime(config *gqlschema.RuntimeInput, tenant string) (string, apperrors.AppError)
+	DeleteRuntime(id, tenant string) apperrors.AppError
+	RuntimeExists(id, tenant string) (bool, apperrors.AppError)
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultSubscriptionBufferSize = 20
+
+// OperationEvent is emitted whenever operation changes its stage or state
+type OperationEvent struct {
+	Operation model.Operation
+	Timestamp time.Time
+}
+
+//go:generate mockery --name=Publisher
+type Publisher interface {
+	Publish(event OperationEvent)
+}
+
+//go:generate mockery --name=Subscriber
+type Subscriber interface {
+	SubscribeOperation(ctx context.Context, operationID string) <-chan OperationEvent
+	SubscribeRuntime(ctx context.Context, runtimeID string) <-chan OperationEvent
+}
+
+type subscription struct {
+	events chan OperationEvent
+}
+
+type Broker struct {
+	mu         sync.RWMutex
+	operations map[string]map[*subscription]struct{}
+	runtimes   map[string]map[*subscription]struct{}
+	bufferSize int
+
+	log logrus.FieldLogger
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		operations: map[string]map[*subscription]struct{}{},
+		runtimes:   map[string]map[*subscription]struct{}{},
+		bufferSize: defaultSubscriptionBufferSize,
+		log:        logrus.WithField("Component", "EventBroker"),
+	}
+}
+
+// Publish delivers the event to all subscribers of the operation and its runtime.
+// It never blocks, events are dropped for subscribers which do not keep up.
+func (b *Broker) Publish(event OperationEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.operations[event.Operation.ID] {
+		b.deliver(sub, event)
+	}
+	for sub := range b.runtimes[event.Operation.ClusterID] {
+		b.deliver(sub, event)
+	}
+}
+
+func (b *Broker) SubscribeOperation(ctx context.Context, operationID string) <-chan OperationEvent {
+	return b.subscribe(ctx, b.operations, operationID)
+}
+
+func (b *Broker) SubscribeRuntime(ctx context.Context, runtimeID string) <-chan OperationEvent {
+	return b.subscribe(ctx, b.runtimes, runtimeID)
+}
+
+func (b *Broker) subscribe(ctx context.Context, subscriptions map[string]map[*subscription]struct{}, key string) <-chan OperationEvent {
+	sub := &subscription{events: make(chan OperationEvent, b.bufferSize)}
+
+	b.mu.Lock()
+	if subscriptions[key] == nil {
+		subscriptions[key] = map[*subscription]struct{}{}
+	}
+	subscriptions[key][sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(subscriptions[key], sub)
+		if len(subscriptions[key]) == 0 {
+			delete(subscriptions, key)
+		}
+		close(sub.events)
+	}()
+
+	return sub.events
+}
+
+func (b *Broker) deliver(sub *subscription, event OperationEvent) {
+	select {
+	case sub.events <- event:
+	default:
+		b.log.Warnf("Subscriber for operation %s is too slow, dropping event", event.Operation.ID)
+	}
+}
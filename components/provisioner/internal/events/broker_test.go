@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	operationID = "operationID"
+	runtimeID   = "runtimeID"
+)
+
+func TestBroker(t *testing.T) {
+
+	t.Run("should deliver event to operation and runtime subscribers", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		broker := NewBroker()
+
+		operationEvents := broker.SubscribeOperation(ctx, operationID)
+		runtimeEvents := broker.SubscribeRuntime(ctx, runtimeID)
+		otherEvents := broker.SubscribeOperation(ctx, "otherOperationID")
+
+		event := OperationEvent{
+			Operation: model.Operation{ID: operationID, ClusterID: runtimeID, State: model.InProgress},
+			Timestamp: time.Now(),
+		}
+
+		// when
+		broker.Publish(event)
+
+		// then
+		assert.Equal(t, event, receive(t, operationEvents))
+		assert.Equal(t, event, receive(t, runtimeEvents))
+		assert.Empty(t, otherEvents)
+	})
+
+	t.Run("should close subscription when context is cancelled", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+
+		broker := NewBroker()
+		operationEvents := broker.SubscribeOperation(ctx, operationID)
+
+		// when
+		cancel()
+
+		// then
+		select {
+		case _, ok := <-operationEvents:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("subscription was not closed")
+		}
+		assert.Eventually(t, func() bool {
+			broker.mu.RLock()
+			defer broker.mu.RUnlock()
+			return len(broker.operations) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should not block when subscriber does not consume events", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		broker := NewBroker()
+		operationEvents := broker.SubscribeOperation(ctx, operationID)
+
+		// when
+		for i := 0; i < defaultSubscriptionBufferSize+5; i++ {
+			broker.Publish(OperationEvent{Operation: model.Operation{ID: operationID}})
+		}
+
+		// then
+		assert.Len(t, operationEvents, defaultSubscriptionBufferSize)
+	})
+}
+
+func receive(t *testing.T, events <-chan OperationEvent) OperationEvent {
+	select {
+	case event, ok := <-events:
+		require.True(t, ok)
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+	return OperationEvent{}
+}
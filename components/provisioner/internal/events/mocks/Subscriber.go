@@ -0,0 +1,61 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	events "github.com/kyma-project/control-plane/components/provisioner/internal/events"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Subscriber is an autogenerated mock type for the Subscriber type
+type Subscriber struct {
+	mock.Mock
+}
+
+// SubscribeOperation provides a mock function with given fields: ctx, operationID
+func (_m *Subscriber) SubscribeOperation(ctx context.Context, operationID string) <-chan events.OperationEvent {
+	ret := _m.Called(ctx, operationID)
+
+	var r0 <-chan events.OperationEvent
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan events.OperationEvent); ok {
+		r0 = rf(ctx, operationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan events.OperationEvent)
+		}
+	}
+
+	return r0
+}
+
+// SubscribeRuntime provides a mock function with given fields: ctx, runtimeID
+func (_m *Subscriber) SubscribeRuntime(ctx context.Context, runtimeID string) <-chan events.OperationEvent {
+	ret := _m.Called(ctx, runtimeID)
+
+	var r0 <-chan events.OperationEvent
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan events.OperationEvent); ok {
+		r0 = rf(ctx, runtimeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan events.OperationEvent)
+		}
+	}
+
+	return r0
+}
+
+// NewSubscriber creates a new instance of Subscriber. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSubscriber(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Subscriber {
+	mock := &Subscriber{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -44,3 +44,12 @@ func getRuntimeId(shoot gardener_types.Shoot) string {
 
 	return runtimeID
 }
+
+// RuntimeID returns ID of the Runtime the Shoot was provisioned for or empty string if the Shoot is not managed by the Provisioner
+func RuntimeID(shoot gardener_types.Shoot) string {
+	if runtimeID := getRuntimeId(shoot); runtimeID != "" {
+		return runtimeID
+	}
+
+	return shoot.Annotations[legacyRuntimeIDAnnotation]
+}
@@ -22,6 +22,7 @@ const (
 
 type AuditLogConfigurator interface {
 	CanEnableAuditLogsForShoot(seedName string) bool
+	HasAuditLogTenant(provider, region string) bool
 	ConfigureAuditLogs(logger logrus.FieldLogger, shoot *gardener_types.Shoot, seed gardener_types.Seed) (bool, error)
 }
 
@@ -41,6 +42,17 @@ func (a *auditLogConfigurator) CanEnableAuditLogsForShoot(seedName string) bool
 	return seedName != "" && a.auditLogTenantConfigPath != ""
 }
 
+// HasAuditLogTenant checks if audit log tenant is configured for the seed provider and the Shoot region
+func (a *auditLogConfigurator) HasAuditLogTenant(provider, region string) bool {
+	auditLogConfig, err := a.getConfigFromFile()
+	if err != nil {
+		return false
+	}
+
+	_, found := auditLogConfig[provider][region]
+	return found
+}
+
 // AuditlogConfig configuration resource
 type AuditlogExtensionConfig struct {
 	metav1.TypeMeta `json:",inline"`
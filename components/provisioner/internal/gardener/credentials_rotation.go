@@ -0,0 +1,108 @@
+package gardener
+
+import (
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CompletedRotationPhase is the phase reported for credentials rotated in a single phase once the rotation finished
+const CompletedRotationPhase = string(gardener_types.RotationCompleted)
+
+// CredentialsRotationOperation returns the value of the Shoot operation annotation triggering the rotation phase
+func CredentialsRotationOperation(kind model.CredentialsRotationKind, phase model.CredentialsRotationPhase) string {
+	complete := phase == model.RotationComplete
+
+	switch kind {
+	case model.CertificateAuthorities:
+		if complete {
+			return v1beta1constants.OperationRotateCAComplete
+		}
+		return v1beta1constants.OperationRotateCAStart
+	case model.ServiceAccountKey:
+		if complete {
+			return v1beta1constants.OperationRotateServiceAccountKeyComplete
+		}
+		return v1beta1constants.OperationRotateServiceAccountKeyStart
+	case model.ETCDEncryptionKey:
+		if complete {
+			return v1beta1constants.OperationRotateETCDEncryptionKeyComplete
+		}
+		return v1beta1constants.OperationRotateETCDEncryptionKeyStart
+	case model.ObservabilityCredentials:
+		return v1beta1constants.ShootOperationRotateObservabilityCredentials
+	case model.SSHKeypair:
+		return v1beta1constants.ShootOperationRotateSSHKeypair
+	}
+
+	return ""
+}
+
+// CredentialsRotationStatus reads the rotation status of the credentials from the Shoot status
+func CredentialsRotationStatus(shoot gardener_types.Shoot, kind model.CredentialsRotationKind) model.CredentialsRotationStatus {
+	status := model.CredentialsRotationStatus{Kind: kind}
+
+	rotation := shoot.Status.Credentials
+	if rotation == nil || rotation.Rotation == nil {
+		return status
+	}
+
+	switch kind {
+	case model.CertificateAuthorities:
+		if r := rotation.Rotation.CertificateAuthorities; r != nil {
+			status.Phase = string(r.Phase)
+			status.LastInitiationTime, status.LastCompletionTime = toTime(r.LastInitiationTime), toTime(r.LastCompletionTime)
+		}
+	case model.ServiceAccountKey:
+		if r := rotation.Rotation.ServiceAccountKey; r != nil {
+			status.Phase = string(r.Phase)
+			status.LastInitiationTime, status.LastCompletionTime = toTime(r.LastInitiationTime), toTime(r.LastCompletionTime)
+		}
+	case model.ETCDEncryptionKey:
+		if r := rotation.Rotation.ETCDEncryptionKey; r != nil {
+			status.Phase = string(r.Phase)
+			status.LastInitiationTime, status.LastCompletionTime = toTime(r.LastInitiationTime), toTime(r.LastCompletionTime)
+		}
+	case model.ObservabilityCredentials:
+		if r := rotation.Rotation.Observability; r != nil {
+			status.LastInitiationTime, status.LastCompletionTime = toTime(r.LastInitiationTime), toTime(r.LastCompletionTime)
+		}
+	case model.SSHKeypair:
+		if r := rotation.Rotation.SSHKeypair; r != nil {
+			status.LastInitiationTime, status.LastCompletionTime = toTime(r.LastInitiationTime), toTime(r.LastCompletionTime)
+		}
+	}
+
+	if !kind.TwoPhase() && status.LastCompletionTime != nil {
+		status.Phase = CompletedRotationPhase
+	}
+
+	return status
+}
+
+// CredentialsRotationFinished is true when the rotation phase of the credentials finished after the given time
+func CredentialsRotationFinished(status model.CredentialsRotationStatus, phase model.CredentialsRotationPhase, since time.Time) bool {
+	// Gardener reports times with the precision of seconds
+	since = since.Truncate(time.Second)
+
+	if status.Kind.TwoPhase() && phase == model.RotationPrepare {
+		return status.Phase == string(gardener_types.RotationPrepared) && isAfter(status.LastInitiationTime, since)
+	}
+
+	return status.Phase == CompletedRotationPhase && isAfter(status.LastCompletionTime, since)
+}
+
+func toTime(t *metav1.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func isAfter(t *time.Time, since time.Time) bool {
+	return t != nil && !t.Before(since)
+}
@@ -0,0 +1,78 @@
+package gardener
+
+import (
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCredentialsRotationFinished(t *testing.T) {
+	start := time.Date(2026, 10, 19, 12, 0, 0, 500, time.UTC)
+	before := v1.NewTime(start.Add(-time.Hour))
+	after := v1.NewTime(start.Truncate(time.Second))
+
+	for _, testCase := range []struct {
+		description string
+		rotation    gardener_types.ShootCredentialsRotation
+		kind        model.CredentialsRotationKind
+		phase       model.CredentialsRotationPhase
+		finished    bool
+	}{
+		{
+			description: "CA rotation prepared after start",
+			rotation: gardener_types.ShootCredentialsRotation{
+				CertificateAuthorities: &gardener_types.CARotation{Phase: gardener_types.RotationPrepared, LastInitiationTime: &after},
+			},
+			kind:     model.CertificateAuthorities,
+			phase:    model.RotationPrepare,
+			finished: true,
+		},
+		{
+			description: "CA rotation prepared before start",
+			rotation: gardener_types.ShootCredentialsRotation{
+				CertificateAuthorities: &gardener_types.CARotation{Phase: gardener_types.RotationPrepared, LastInitiationTime: &before},
+			},
+			kind:  model.CertificateAuthorities,
+			phase: model.RotationPrepare,
+		},
+		{
+			description: "CA rotation not yet completed",
+			rotation: gardener_types.ShootCredentialsRotation{
+				CertificateAuthorities: &gardener_types.CARotation{Phase: gardener_types.RotationCompleting, LastInitiationTime: &before},
+			},
+			kind:  model.CertificateAuthorities,
+			phase: model.RotationComplete,
+		},
+		{
+			description: "SSH keypair rotated after start",
+			rotation: gardener_types.ShootCredentialsRotation{
+				SSHKeypair: &gardener_types.ShootSSHKeypairRotation{LastInitiationTime: &after, LastCompletionTime: &after},
+			},
+			kind:     model.SSHKeypair,
+			phase:    model.RotationPrepare,
+			finished: true,
+		},
+		{
+			description: "observability credentials never rotated",
+			kind:        model.ObservabilityCredentials,
+			phase:       model.RotationPrepare,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			// given
+			shoot := gardener_types.Shoot{Status: gardener_types.ShootStatus{
+				Credentials: &gardener_types.ShootCredentials{Rotation: &testCase.rotation},
+			}}
+
+			// when
+			status := CredentialsRotationStatus(shoot, testCase.kind)
+
+			// then
+			assert.Equal(t, testCase.finished, CredentialsRotationFinished(status, testCase.phase, start))
+		})
+	}
+}
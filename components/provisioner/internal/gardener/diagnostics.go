@@ -0,0 +1,194 @@
+package gardener
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	v12 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const maxDiagnosticsMessageLength = 1024
+
+type DiagnosticsConfig struct {
+	MaxEvents int `envconfig:"default=20"`
+	// MaxSize limits the size in bytes of the diagnostics stored for the operation
+	MaxSize int `envconfig:"default=65536"`
+}
+
+type EventLister interface {
+	List(ctx context.Context, opts v1.ListOptions) (*v12.EventList, error)
+}
+
+// DiagnosticsCollector captures Shoot status and events for operations whose stage failed
+type DiagnosticsCollector struct {
+	shootClient Client
+	events      EventLister
+	config      DiagnosticsConfig
+}
+
+func NewDiagnosticsCollector(shootClient Client, events EventLister, config DiagnosticsConfig) *DiagnosticsCollector {
+	return &DiagnosticsCollector{
+		shootClient: shootClient,
+		events:      events,
+		config:      config,
+	}
+}
+
+func (c *DiagnosticsCollector) Collect(cluster model.Cluster, operation model.Operation) (model.OperationDiagnostics, error) {
+	shootName := cluster.ClusterConfig.Name
+
+	shoot, err := c.shootClient.Get(context.Background(), shootName, v1.GetOptions{})
+	if err != nil {
+		return model.OperationDiagnostics{}, fmt.Errorf("failed to get Shoot %s: %s", shootName, err.Error())
+	}
+
+	events, err := c.events.List(context.Background(), v1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=Shoot,involvedObject.name=%s", shootName),
+	})
+	if err != nil {
+		return model.OperationDiagnostics{}, fmt.Errorf("failed to list events of Shoot %s: %s", shootName, err.Error())
+	}
+
+	diagnostics := model.OperationDiagnostics{
+		OperationID: operation.ID,
+		Stage:       operation.Stage,
+		CapturedAt:  time.Now(),
+		Conditions:  toShootConditions(shoot.Status.Conditions),
+		Constraints: toShootConditions(shoot.Status.Constraints),
+		LastErrors:  toShootLastErrors(shoot.Status.LastErrors),
+		Events:      c.latestEvents(shootName, events.Items),
+	}
+
+	return boundDiagnostics(diagnostics, c.config.MaxSize), nil
+}
+
+// latestEvents returns at most the configured number of events, starting from the oldest one
+func (c *DiagnosticsCollector) latestEvents(shootName string, items []v12.Event) []model.ShootEvent {
+	events := make([]model.ShootEvent, 0, len(items))
+	for _, item := range items {
+		if item.InvolvedObject.Kind != "Shoot" || item.InvolvedObject.Name != shootName {
+			continue
+		}
+
+		// Events created with the events.k8s.io API only have the event time set
+		lastTimestamp := item.LastTimestamp.Time
+		if lastTimestamp.IsZero() {
+			lastTimestamp = item.EventTime.Time
+		}
+
+		events = append(events, model.ShootEvent{
+			Type:           item.Type,
+			Reason:         item.Reason,
+			Message:        truncate(item.Message),
+			Count:          item.Count,
+			FirstTimestamp: toOptionalTime(item.FirstTimestamp),
+			LastTimestamp:  &lastTimestamp,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(*events[j].LastTimestamp)
+	})
+
+	if c.config.MaxEvents >= 0 && len(events) > c.config.MaxEvents {
+		events = events[len(events)-c.config.MaxEvents:]
+	}
+
+	return events
+}
+
+// boundDiagnostics drops the oldest events, and then the last errors, constraints and conditions starting from the last ones,
+// until the encoded diagnostics fit within the maximum size
+func boundDiagnostics(diagnostics model.OperationDiagnostics, maxSize int) model.OperationDiagnostics {
+	for len(diagnostics.Events) > 0 && encodedSize(diagnostics) > maxSize {
+		diagnostics.Events = diagnostics.Events[1:]
+		diagnostics.Truncated = true
+	}
+
+	for len(diagnostics.LastErrors) > 0 && encodedSize(diagnostics) > maxSize {
+		diagnostics.LastErrors = diagnostics.LastErrors[:len(diagnostics.LastErrors)-1]
+		diagnostics.Truncated = true
+	}
+
+	for len(diagnostics.Constraints) > 0 && encodedSize(diagnostics) > maxSize {
+		diagnostics.Constraints = diagnostics.Constraints[:len(diagnostics.Constraints)-1]
+		diagnostics.Truncated = true
+	}
+
+	for len(diagnostics.Conditions) > 0 && encodedSize(diagnostics) > maxSize {
+		diagnostics.Conditions = diagnostics.Conditions[:len(diagnostics.Conditions)-1]
+		diagnostics.Truncated = true
+	}
+
+	return diagnostics
+}
+
+func encodedSize(diagnostics model.OperationDiagnostics) int {
+	encoded, err := json.Marshal(diagnostics)
+	if err != nil {
+		return 0
+	}
+
+	return len(encoded)
+}
+
+func toShootConditions(conditions []gardener_types.Condition) []model.ShootCondition {
+	result := make([]model.ShootCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		result = append(result, model.ShootCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            truncate(condition.Message),
+			Codes:              toCodes(condition.Codes),
+			LastTransitionTime: toOptionalTime(condition.LastTransitionTime),
+		})
+	}
+
+	return result
+}
+
+func toShootLastErrors(lastErrors []gardener_types.LastError) []model.ShootLastError {
+	result := make([]model.ShootLastError, 0, len(lastErrors))
+	for _, lastError := range lastErrors {
+		result = append(result, model.ShootLastError{
+			Description:    truncate(lastError.Description),
+			TaskID:         lastError.TaskID,
+			Codes:          toCodes(lastError.Codes),
+			LastUpdateTime: toTime(lastError.LastUpdateTime),
+		})
+	}
+
+	return result
+}
+
+func toCodes(codes []gardener_types.ErrorCode) []string {
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		result = append(result, string(code))
+	}
+
+	return result
+}
+
+func toOptionalTime(t v1.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return toTime(&t)
+}
+
+func truncate(message string) string {
+	if len(message) <= maxDiagnosticsMessageLength {
+		return message
+	}
+
+	return message[:maxDiagnosticsMessageLength] + "..."
+}
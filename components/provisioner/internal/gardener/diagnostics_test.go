@@ -0,0 +1,143 @@
+package gardener
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gardener/gardener/pkg/client/core/clientset/versioned/fake"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v12 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sFake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDiagnosticsCollector_Collect(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	shoot := &gardener_types.Shoot{
+		ObjectMeta: v1.ObjectMeta{Name: clusterName, Namespace: gardenerNamespace},
+		Status: gardener_types.ShootStatus{
+			Conditions: []gardener_types.Condition{
+				{Type: gardener_types.ShootAPIServerAvailable, Status: gardener_types.ConditionTrue, Reason: "HealthzRequestSucceeded"},
+				{Type: gardener_types.ShootEveryNodeReady, Status: gardener_types.ConditionFalse, Reason: "NodesUnhealthy", Message: strings.Repeat("x", 2000)},
+			},
+			Constraints: []gardener_types.Condition{
+				{Type: gardener_types.ShootHibernationPossible, Status: gardener_types.ConditionFalse, Codes: []gardener_types.ErrorCode{gardener_types.ErrorProblematicWebhook}},
+			},
+			LastErrors: []gardener_types.LastError{
+				{Description: "quota exceeded", TaskID: util.StringPtr("task"), Codes: []gardener_types.ErrorCode{gardener_types.ErrorInfraQuotaExceeded}},
+			},
+		},
+	}
+
+	newEvent := func(name, shootName string, lastTimestamp time.Time) *v12.Event {
+		return &v12.Event{
+			ObjectMeta:     v1.ObjectMeta{Name: name, Namespace: gardenerNamespace},
+			InvolvedObject: v12.ObjectReference{Kind: "Shoot", Name: shootName},
+			Type:           v12.EventTypeWarning,
+			Reason:         "ReconcileError",
+			Message:        name,
+			Count:          1,
+			LastTimestamp:  v1.NewTime(lastTimestamp),
+		}
+	}
+
+	cluster := model.Cluster{ID: runtimeId, ClusterConfig: model.GardenerConfig{Name: clusterName}}
+	operation := model.Operation{ID: operationId, Stage: model.WaitingForClusterCreation}
+
+	t.Run("should capture Shoot status and latest events", func(t *testing.T) {
+		// given
+		shootClient := fake.NewSimpleClientset(shoot).CoreV1beta1().Shoots(gardenerNamespace)
+		events := k8sFake.NewSimpleClientset(
+			newEvent("second", clusterName, now.Add(-time.Minute)),
+			newEvent("first", clusterName, now.Add(-time.Hour)),
+			newEvent("third", clusterName, now),
+			newEvent("other", "other-cluster", now),
+		).CoreV1().Events(gardenerNamespace)
+
+		collector := NewDiagnosticsCollector(shootClient, events, DiagnosticsConfig{MaxEvents: 2, MaxSize: 65536})
+
+		// when
+		diagnostics, err := collector.Collect(cluster, operation)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, operationId, diagnostics.OperationID)
+		assert.Equal(t, model.WaitingForClusterCreation, diagnostics.Stage)
+		require.Len(t, diagnostics.Conditions, 2)
+		assert.Equal(t, maxDiagnosticsMessageLength+len("..."), len(diagnostics.Conditions[1].Message))
+		require.Len(t, diagnostics.Constraints, 1)
+		assert.Equal(t, []string{string(gardener_types.ErrorProblematicWebhook)}, diagnostics.Constraints[0].Codes)
+		require.Len(t, diagnostics.LastErrors, 1)
+		assert.Equal(t, "task", *diagnostics.LastErrors[0].TaskID)
+		assert.Equal(t, []string{string(gardener_types.ErrorInfraQuotaExceeded)}, diagnostics.LastErrors[0].Codes)
+		require.Len(t, diagnostics.Events, 2)
+		assert.Equal(t, "second", diagnostics.Events[0].Message)
+		assert.Equal(t, "third", diagnostics.Events[1].Message)
+		assert.False(t, diagnostics.Truncated)
+	})
+
+	t.Run("should drop events and Shoot status to fit within size limit", func(t *testing.T) {
+		// given
+		shootClient := fake.NewSimpleClientset(shoot).CoreV1beta1().Shoots(gardenerNamespace)
+		events := k8sFake.NewSimpleClientset(
+			newEvent("first", clusterName, now.Add(-time.Hour)),
+			newEvent("second", clusterName, now),
+		).CoreV1().Events(gardenerNamespace)
+
+		collector := NewDiagnosticsCollector(shootClient, events, DiagnosticsConfig{MaxEvents: 20, MaxSize: 1})
+
+		// when
+		diagnostics, err := collector.Collect(cluster, operation)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics.Events)
+		assert.Empty(t, diagnostics.LastErrors)
+		assert.Empty(t, diagnostics.Constraints)
+		assert.Empty(t, diagnostics.Conditions)
+		assert.True(t, diagnostics.Truncated)
+	})
+
+	t.Run("should return error when Shoot does not exist", func(t *testing.T) {
+		// given
+		shootClient := fake.NewSimpleClientset().CoreV1beta1().Shoots(gardenerNamespace)
+		events := k8sFake.NewSimpleClientset().CoreV1().Events(gardenerNamespace)
+
+		collector := NewDiagnosticsCollector(shootClient, events, DiagnosticsConfig{MaxEvents: 20, MaxSize: 65536})
+
+		// when
+		_, err := collector.Collect(cluster, operation)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestBoundDiagnostics(t *testing.T) {
+	// given
+	diagnostics := model.OperationDiagnostics{
+		OperationID: operationId,
+		Conditions:  []model.ShootCondition{{Type: "EveryNodeReady", Status: "False", Message: strings.Repeat("x", 100)}},
+		LastErrors: []model.ShootLastError{
+			{Description: strings.Repeat("a", 100)},
+			{Description: strings.Repeat("b", 100)},
+		},
+		Events: []model.ShootEvent{{Type: "Warning", Message: strings.Repeat("e", 100)}},
+	}
+	expected := diagnostics
+	expected.LastErrors = expected.LastErrors[:1]
+	expected.Events = []model.ShootEvent{}
+	expected.Truncated = true
+
+	// when
+	bounded := boundDiagnostics(diagnostics, encodedSize(expected))
+
+	// then
+	assert.Equal(t, expected, bounded)
+}
@@ -150,7 +150,7 @@ func (g *GardenerProvisioner) DeprovisionCluster(cluster model.Cluster, operatio
 	annotate(shoot, operationIDAnnotation, operationId)
 	annotate(shoot, legacyOperationIDAnnotation, operationId)
 
-	annotateWithConfirmDeletion(shoot)
+	AnnotateWithConfirmDeletion(shoot)
 
 	setObjectFields(shoot)
 
@@ -168,10 +168,10 @@ func (g *GardenerProvisioner) DeprovisionCluster(cluster model.Cluster, operatio
 
 	message := fmt.Sprintf("Deprovisioning started")
 
-	return newDeprovisionOperation(operationId, cluster.ID, message, model.InProgress, model.DeleteCluster, deletionTime), nil
+	return newDeprovisionOperation(operationId, cluster.ID, message, model.InProgress, model.CleanupCluster, deletionTime), nil
 }
 
-func annotateWithConfirmDeletion(shoot *gardener_types.Shoot) {
+func AnnotateWithConfirmDeletion(shoot *gardener_types.Shoot) {
 	if shoot.Annotations == nil {
 		shoot.Annotations = map[string]string{}
 	}
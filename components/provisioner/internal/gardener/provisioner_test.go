@@ -117,6 +117,7 @@ func TestGardenerProvisioner_DeprovisionCluster(t *testing.T) {
 		assert.Equal(t, operationId, operation.ID)
 		assert.Equal(t, runtimeId, operation.ClusterID)
 		assert.Equal(t, model.DeprovisionNoInstall, operation.Type)
+		assert.Equal(t, model.CleanupCluster, operation.Stage)
 
 		_, err := shootClient.Get(context.Background(), clusterName, v1.GetOptions{})
 		assert.NoError(t, err)
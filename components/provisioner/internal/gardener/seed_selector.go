@@ -0,0 +1,180 @@
+package gardener
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	gardener_core "github.com/gardener/gardener/pkg/apis/core"
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gardener/gardener/pkg/apis/core/v1beta1/helper"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	SeedSelectionNone       = "none"
+	SeedSelectionPreference = "preference"
+	SeedSelectionLeastLoad  = "least-load"
+)
+
+type SeedSelectionConfig struct {
+	// Strategy is one of: none, preference, least-load
+	Strategy string `envconfig:"default=none"`
+	// PreferredSeeds are used in the given order by the preference strategy, the least loaded seed is used if none of them is eligible
+	PreferredSeeds []string `envconfig:"optional"`
+	// EuAccessSeedSelector is the label selector of the seeds eligible for Shoots with EU access, for example
+	// "example.com/eu-access=true". Seeds of Shoots with EU access are left to Gardener if it is not set.
+	EuAccessSeedSelector string `envconfig:"optional"`
+}
+
+//go:generate mockery --name=SeedClient
+type SeedClient interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*gardener_types.SeedList, error)
+}
+
+type SeedSelector struct {
+	config     SeedSelectionConfig
+	seedClient SeedClient
+	// shootClient lists the Shoots of all projects, as the capacity of a seed is shared by all of them
+	shootClient          ShootClient
+	auditLogConfigurator AuditLogConfigurator
+	euAccessSelector     labels.Selector
+}
+
+// NewSeedSelector creates the seed selector, the Shoot client has to list the Shoots in all namespaces
+func NewSeedSelector(config SeedSelectionConfig, seedClient SeedClient, shootClient ShootClient, auditLogConfigurator AuditLogConfigurator) (*SeedSelector, error) {
+	switch config.Strategy {
+	case SeedSelectionNone, SeedSelectionPreference, SeedSelectionLeastLoad:
+	default:
+		return nil, fmt.Errorf("unknown seed selection strategy: %s", config.Strategy)
+	}
+
+	var euAccessSelector labels.Selector
+	if config.EuAccessSeedSelector != "" {
+		var err error
+		euAccessSelector, err = labels.Parse(config.EuAccessSeedSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EU access seed selector: %s", err.Error())
+		}
+	}
+
+	return &SeedSelector{
+		config:               config,
+		seedClient:           seedClient,
+		shootClient:          shootClient,
+		auditLogConfigurator: auditLogConfigurator,
+		euAccessSelector:     euAccessSelector,
+	}, nil
+}
+
+// SelectSeed returns name of the seed for the Shoot and the reason why it was chosen. Empty name means that Gardener schedules the Shoot.
+func (s *SeedSelector) SelectSeed(config model.GardenerConfig) (string, string, apperrors.AppError) {
+	if s.config.Strategy == SeedSelectionNone {
+		return "", "", nil
+	}
+
+	if config.EuAccess && s.euAccessSelector == nil {
+		return "", "", nil
+	}
+
+	seeds, err := s.seedClient.List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return "", "", util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient).Append("failed to list seeds")
+	}
+
+	eligible := make(map[string]gardener_types.Seed)
+	for _, seed := range seeds.Items {
+		if s.isEligible(seed, config) {
+			eligible[seed.Name] = seed
+		}
+	}
+
+	if len(eligible) == 0 {
+		return "", "", apperrors.BadRequest("no seed available for %s provider in %s region (EU access: %t)", config.Provider, config.Region, config.EuAccess)
+	}
+
+	if s.config.Strategy == SeedSelectionPreference {
+		for _, name := range s.config.PreferredSeeds {
+			if _, found := eligible[name]; found {
+				return name, fmt.Sprintf("Seed %s selected by preference out of %d eligible seeds", name, len(eligible)), nil
+			}
+		}
+	}
+
+	shootsPerSeed, appErr := s.countShootsPerSeed(eligible)
+	if appErr != nil {
+		return "", "", appErr
+	}
+
+	candidates := make([]string, 0, len(eligible))
+	for name, seed := range eligible {
+		allocatable, found := seed.Status.Allocatable[gardener_types.ResourceShoots]
+		if found && int64(shootsPerSeed[name]) >= allocatable.Value() {
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+
+	if len(candidates) == 0 {
+		return "", "", apperrors.BadRequest("all %d eligible seeds for %s provider in %s region reached their capacity", len(eligible), config.Provider, config.Region)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if shootsPerSeed[candidates[i]] != shootsPerSeed[candidates[j]] {
+			return shootsPerSeed[candidates[i]] < shootsPerSeed[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+
+	selected := candidates[0]
+	return selected, fmt.Sprintf("Seed %s selected as the least loaded (%d Shoots) out of %d eligible seeds", selected, shootsPerSeed[selected], len(eligible)), nil
+}
+
+func (s *SeedSelector) isEligible(seed gardener_types.Seed, config model.GardenerConfig) bool {
+	if seed.DeletionTimestamp != nil {
+		return false
+	}
+
+	if seed.Spec.Settings != nil && seed.Spec.Settings.Scheduling != nil && !seed.Spec.Settings.Scheduling.Visible {
+		return false
+	}
+
+	if condition := helper.GetCondition(seed.Status.Conditions, gardener_types.SeedGardenletReady); condition != nil && condition.Status != gardener_types.ConditionTrue {
+		return false
+	}
+
+	if seed.Spec.Provider.Region != config.Region || seed.Spec.Provider.Type != config.Provider {
+		return false
+	}
+
+	if config.EuAccess && !s.euAccessSelector.Matches(labels.Set(seed.Labels)) {
+		return false
+	}
+
+	if s.auditLogConfigurator.CanEnableAuditLogsForShoot(seed.Name) && !s.auditLogConfigurator.HasAuditLogTenant(seed.Spec.Provider.Type, config.Region) {
+		return false
+	}
+
+	return true
+}
+
+// countShootsPerSeed counts the Shoots of all projects scheduled on the seeds
+func (s *SeedSelector) countShootsPerSeed(seeds map[string]gardener_types.Seed) (map[string]int, apperrors.AppError) {
+	shootsPerSeed := map[string]int{}
+	for name := range seeds {
+		shoots, err := s.shootClient.List(context.Background(), metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector(gardener_core.ShootSeedName, name).String(),
+		})
+		if err != nil {
+			return nil, util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient).Append("failed to list Shoots on seed %s", name)
+		}
+		shootsPerSeed[name] = len(shoots.Items)
+	}
+
+	return shootsPerSeed, nil
+}
@@ -0,0 +1,212 @@
+package gardener
+
+import (
+	"context"
+	"testing"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+func TestSeedSelector_SelectSeed(t *testing.T) {
+	seeds := &gardener_types.SeedList{
+		Items: []gardener_types.Seed{
+			fixSeed("az-westus2-1", "azure", "westus2"),
+			fixSeed("az-westus2-2", "azure", "westus2"),
+			fixSeed("az-eastus-1", "azure", "eastus"),
+			fixSeed("aws-westus2-1", "aws", "westus2"),
+			withEuAccess(fixSeed("az-eu-1", "azure", "eu-central-1")),
+			fixSeed("az-eu-2", "azure", "eu-central-1"),
+			withCapacity(fixSeed("az-full-1", "azure", "northeurope"), 2),
+			withCapacity(fixSeed("az-full-2", "azure", "northeurope"), 2),
+			notReady(fixSeed("az-notready", "azure", "japaneast")),
+		},
+	}
+	shoots := &gardener_types.ShootList{
+		Items: []gardener_types.Shoot{
+			fixShootOnSeed("garden-kyma", "az-westus2-1"),
+			fixShootOnSeed("garden-other", "az-westus2-1"),
+			fixShootOnSeed("garden-kyma", "az-westus2-2"),
+			fixShootOnSeed("garden-kyma", "az-full-1"),
+			fixShootOnSeed("garden-other", "az-full-1"),
+			fixShootOnSeed("garden-other", "az-full-2"),
+			fixShootOnSeed("garden-other", "az-full-2"),
+		},
+	}
+
+	for _, testCase := range []struct {
+		description    string
+		config         SeedSelectionConfig
+		auditLogConfig string
+		gardenerConfig model.GardenerConfig
+		expectedSeed   string
+		expectedReason string
+		expectedError  bool
+	}{
+		{
+			description:    "should leave scheduling to Gardener",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionNone},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "westus2"},
+		},
+		{
+			description:    "should select the least loaded seed",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "westus2"},
+			expectedSeed:   "az-westus2-2",
+			expectedReason: "Seed az-westus2-2 selected as the least loaded (1 Shoots) out of 2 eligible seeds",
+		},
+		{
+			description:    "should select preferred seed",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionPreference, PreferredSeeds: []string{"az-eastus-1", "az-westus2-1"}},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "westus2"},
+			expectedSeed:   "az-westus2-1",
+			expectedReason: "Seed az-westus2-1 selected by preference out of 2 eligible seeds",
+		},
+		{
+			description:    "should fall back to the least loaded seed if preferred seeds are not eligible",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionPreference, PreferredSeeds: []string{"az-eastus-1"}},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "westus2"},
+			expectedSeed:   "az-westus2-2",
+			expectedReason: "Seed az-westus2-2 selected as the least loaded (1 Shoots) out of 2 eligible seeds",
+		},
+		{
+			description:    "should select seed with EU access",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad, EuAccessSeedSelector: "example.com/eu-access=true"},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "eu-central-1", EuAccess: true},
+			expectedSeed:   "az-eu-1",
+			expectedReason: "Seed az-eu-1 selected as the least loaded (0 Shoots) out of 1 eligible seeds",
+		},
+		{
+			description:    "should leave seed with EU access to Gardener without EU access seed selector",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "eu-central-1", EuAccess: true},
+		},
+		{
+			description:    "should fail if no seed matches the EU access seed selector",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad, EuAccessSeedSelector: "example.com/eu-access=true"},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "westus2", EuAccess: true},
+			expectedError:  true,
+		},
+		{
+			description:    "should select seed with audit log tenant",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad},
+			auditLogConfig: "testdata/config.json",
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "westus2"},
+			expectedSeed:   "az-westus2-2",
+			expectedReason: "Seed az-westus2-2 selected as the least loaded (1 Shoots) out of 2 eligible seeds",
+		},
+		{
+			description:    "should fail if there is no audit log tenant for the region",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad},
+			auditLogConfig: "testdata/config.json",
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "eastus"},
+			expectedError:  true,
+		},
+		{
+			description:    "should fail if all seeds reached capacity",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "northeurope"},
+			expectedError:  true,
+		},
+		{
+			description:    "should fail if seed is not ready",
+			config:         SeedSelectionConfig{Strategy: SeedSelectionLeastLoad},
+			gardenerConfig: model.GardenerConfig{Provider: "azure", Region: "japaneast"},
+			expectedError:  true,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			// given
+			seedClient := &mocks.SeedClient{}
+			seedClient.On("List", mock.Anything, mock.Anything).Return(seeds, nil)
+			shootClient := &mocks.ShootClient{}
+			shootClient.On("List", mock.Anything, mock.Anything).Return(shootsOnSeed(shoots), nil)
+
+			selector, err := NewSeedSelector(testCase.config, seedClient, shootClient, NewAuditLogConfigurator(testCase.auditLogConfig))
+			require.NoError(t, err)
+
+			// when
+			seed, reason, appErr := selector.SelectSeed(testCase.gardenerConfig)
+
+			// then
+			if testCase.expectedError {
+				require.Error(t, appErr)
+				return
+			}
+			require.NoError(t, appErr)
+			assert.Equal(t, testCase.expectedSeed, seed)
+			assert.Equal(t, testCase.expectedReason, reason)
+		})
+	}
+
+	t.Run("should reject invalid EU access seed selector", func(t *testing.T) {
+		// when
+		_, err := NewSeedSelector(SeedSelectionConfig{Strategy: SeedSelectionLeastLoad, EuAccessSeedSelector: "example.com/eu-access in"}, nil, nil, nil)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should reject unknown strategy", func(t *testing.T) {
+		// when
+		_, err := NewSeedSelector(SeedSelectionConfig{Strategy: "random"}, nil, nil, nil)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func fixSeed(name, provider, region string) gardener_types.Seed {
+	return gardener_types.Seed{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: gardener_types.SeedSpec{
+			Provider: gardener_types.SeedProvider{Type: provider, Region: region},
+			Settings: &gardener_types.SeedSettings{Scheduling: &gardener_types.SeedSettingScheduling{Visible: true}},
+		},
+	}
+}
+
+func withEuAccess(seed gardener_types.Seed) gardener_types.Seed {
+	seed.Labels = map[string]string{"example.com/eu-access": "true"}
+	return seed
+}
+
+func withCapacity(seed gardener_types.Seed, shoots int64) gardener_types.Seed {
+	seed.Status.Allocatable = corev1.ResourceList{gardener_types.ResourceShoots: *resource.NewQuantity(shoots, resource.DecimalSI)}
+	return seed
+}
+
+func notReady(seed gardener_types.Seed) gardener_types.Seed {
+	seed.Status.Conditions = []gardener_types.Condition{{Type: gardener_types.SeedGardenletReady, Status: gardener_types.ConditionFalse}}
+	return seed
+}
+
+func fixShootOnSeed(namespace, seed string) gardener_types.Shoot {
+	return gardener_types.Shoot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		Spec:       gardener_types.ShootSpec{SeedName: util.StringPtr(seed)},
+	}
+}
+
+// shootsOnSeed filters the Shoots by the seed field selector like the Gardener API server does
+func shootsOnSeed(shoots *gardener_types.ShootList) func(context.Context, metav1.ListOptions) *gardener_types.ShootList {
+	return func(_ context.Context, opts metav1.ListOptions) *gardener_types.ShootList {
+		selector := fields.ParseSelectorOrDie(opts.FieldSelector)
+		filtered := &gardener_types.ShootList{}
+		for _, shoot := range shoots.Items {
+			if selector.Matches(fields.Set{"spec.seedName": *shoot.Spec.SeedName}) {
+				filtered.Items = append(filtered.Items, shoot)
+			}
+		}
+		return filtered
+	}
+}
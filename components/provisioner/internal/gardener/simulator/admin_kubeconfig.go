@@ -0,0 +1,69 @@
+package simulator
+
+import (
+	"context"
+
+	"github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AdminKubeconfigRequests creates the adminkubeconfig subresource of Shoots
+type AdminKubeconfigRequests struct {
+	simulator *Simulator
+}
+
+func (r *AdminKubeconfigRequests) Create(_ context.Context, obj client.Object, subResource client.Object, _ ...client.SubResourceCreateOption) error {
+	request, ok := subResource.(*v1alpha1.AdminKubeconfigRequest)
+	if !ok {
+		return errors.Errorf("unsupported subresource %T", subResource)
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	s := r.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.fillAdminKubeconfig(accessor.GetName(), request)
+}
+
+// Kubeconfig builds the kubeconfig for the API server, it is used as kubeconfig of all simulated Shoots
+func Kubeconfig(config *rest.Config) ([]byte, error) {
+	const name = "simulator"
+
+	kubeconfig := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			name: {
+				Server:                   config.Host,
+				CertificateAuthorityData: config.CAData,
+				InsecureSkipTLSVerify:    config.Insecure,
+			},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			name: {
+				ClientCertificateData: config.CertData,
+				ClientKeyData:         config.KeyData,
+				Token:                 config.BearerToken,
+				Username:              config.Username,
+				Password:              config.Password,
+			},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			name: {
+				Cluster:  name,
+				AuthInfo: name,
+			},
+		},
+		CurrentContext: name,
+	}
+
+	return clientcmd.Write(kubeconfig)
+}
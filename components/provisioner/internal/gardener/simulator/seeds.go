@@ -0,0 +1,138 @@
+package simulator
+
+import (
+	"context"
+	"sort"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var seedsResource = gardener_types.SchemeGroupVersion.WithResource("seeds").GroupResource()
+
+type seeds struct {
+	simulator *Simulator
+}
+
+func (c *seeds) Create(_ context.Context, seed *gardener_types.Seed, _ metav1.CreateOptions) (*gardener_types.Seed, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.seeds[seed.Name]; found {
+		return nil, k8serrors.NewAlreadyExists(seedsResource, seed.Name)
+	}
+
+	created := seed.DeepCopy()
+	created.UID = uuid.NewUUID()
+	created.CreationTimestamp = metav1.NewTime(s.now())
+	created.Generation = 1
+	created.ResourceVersion = s.nextResourceVersion()
+	s.seeds[created.Name] = created
+
+	return created.DeepCopy(), nil
+}
+
+func (c *seeds) Update(_ context.Context, seed *gardener_types.Seed, _ metav1.UpdateOptions) (*gardener_types.Seed, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, found := s.seeds[seed.Name]
+	if !found {
+		return nil, k8serrors.NewNotFound(seedsResource, seed.Name)
+	}
+
+	updated := seed.DeepCopy()
+	updated.Status = existing.Status
+	updated.ResourceVersion = s.nextResourceVersion()
+	s.seeds[updated.Name] = updated
+
+	return updated.DeepCopy(), nil
+}
+
+func (c *seeds) UpdateStatus(_ context.Context, seed *gardener_types.Seed, _ metav1.UpdateOptions) (*gardener_types.Seed, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, found := s.seeds[seed.Name]
+	if !found {
+		return nil, k8serrors.NewNotFound(seedsResource, seed.Name)
+	}
+
+	existing.Status = *seed.Status.DeepCopy()
+	existing.ResourceVersion = s.nextResourceVersion()
+
+	return existing.DeepCopy(), nil
+}
+
+func (c *seeds) Delete(_ context.Context, name string, _ metav1.DeleteOptions) error {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.seeds[name]; !found {
+		return k8serrors.NewNotFound(seedsResource, name)
+	}
+	delete(s.seeds, name)
+
+	return nil
+}
+
+func (c *seeds) DeleteCollection(_ context.Context, _ metav1.DeleteOptions, _ metav1.ListOptions) error {
+	return k8serrors.NewMethodNotSupported(seedsResource, "deletecollection")
+}
+
+func (c *seeds) Get(_ context.Context, name string, _ metav1.GetOptions) (*gardener_types.Seed, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seed, found := s.seeds[name]
+	if !found {
+		return nil, k8serrors.NewNotFound(seedsResource, name)
+	}
+
+	return seed.DeepCopy(), nil
+}
+
+func (c *seeds) List(_ context.Context, opts metav1.ListOptions) (*gardener_types.SeedList, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+
+	names := make([]string, 0, len(s.seeds))
+	for name := range s.seeds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := &gardener_types.SeedList{Items: []gardener_types.Seed{}}
+	for _, name := range names {
+		seed := s.seeds[name]
+		if selector.Matches(labels.Set(seed.Labels)) {
+			list.Items = append(list.Items, *seed.DeepCopy())
+		}
+	}
+
+	return list, nil
+}
+
+func (c *seeds) Watch(_ context.Context, _ metav1.ListOptions) (watch.Interface, error) {
+	return nil, k8serrors.NewMethodNotSupported(seedsResource, "watch")
+}
+
+func (c *seeds) Patch(_ context.Context, _ string, _ types.PatchType, _ []byte, _ metav1.PatchOptions, _ ...string) (*gardener_types.Seed, error) {
+	return nil, k8serrors.NewMethodNotSupported(seedsResource, "patch")
+}
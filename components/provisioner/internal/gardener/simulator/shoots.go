@@ -0,0 +1,284 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+type shoots struct {
+	simulator *Simulator
+}
+
+func (c *shoots) Create(_ context.Context, shoot *gardener_types.Shoot, _ metav1.CreateOptions) (*gardener_types.Shoot, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.shoots[shoot.Name]; found {
+		return nil, k8serrors.NewAlreadyExists(shootsResource, shoot.Name)
+	}
+
+	created := shoot.DeepCopy()
+	created.Namespace = s.namespace
+	created.UID = uuid.NewUUID()
+	created.CreationTimestamp = metav1.NewTime(s.now())
+	created.Generation = 1
+	created.ResourceVersion = s.nextResourceVersion()
+	created.Finalizers = append(created.Finalizers, gardenerFinalizer)
+	created.Status = gardener_types.ShootStatus{}
+
+	if (created.Spec.DNS == nil || created.Spec.DNS.Domain == nil) && s.config.Domain != "" {
+		domain := fmt.Sprintf("%s.%s", created.Name, s.config.Domain)
+		created.Spec.DNS = &gardener_types.DNS{Domain: &domain}
+	}
+	if (created.Spec.SeedName == nil || *created.Spec.SeedName == "") && s.config.Seed != "" {
+		seed := s.config.Seed
+		created.Spec.SeedName = &seed
+	}
+
+	s.startOperation(created, gardener_types.LastOperationTypeCreate)
+	s.shoots[created.Name] = created
+
+	return created.DeepCopy(), nil
+}
+
+func (c *shoots) Update(_ context.Context, shoot *gardener_types.Shoot, _ metav1.UpdateOptions) (*gardener_types.Shoot, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateShoot(shoot, true)
+}
+
+func (c *shoots) UpdateStatus(_ context.Context, shoot *gardener_types.Shoot, _ metav1.UpdateOptions) (*gardener_types.Shoot, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.getShoot(shoot.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Status = *shoot.Status.DeepCopy()
+	existing.ResourceVersion = s.nextResourceVersion()
+
+	return existing.DeepCopy(), nil
+}
+
+func (c *shoots) Delete(_ context.Context, name string, _ metav1.DeleteOptions) error {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shoot, err := s.getShoot(name)
+	if err != nil {
+		return err
+	}
+
+	if shoot.Annotations[confirmDeletionAnnotation] != "true" {
+		return k8serrors.NewForbidden(shootsResource, name, errors.Errorf("Shoot %s has to be annotated with %s before deletion", name, confirmDeletionAnnotation))
+	}
+
+	if shoot.DeletionTimestamp != nil {
+		return nil
+	}
+
+	deletionTimestamp := metav1.NewTime(s.now())
+	shoot.DeletionTimestamp = &deletionTimestamp
+	shoot.ResourceVersion = s.nextResourceVersion()
+	s.startOperation(shoot, gardener_types.LastOperationTypeDelete)
+
+	return nil
+}
+
+func (c *shoots) DeleteCollection(_ context.Context, _ metav1.DeleteOptions, _ metav1.ListOptions) error {
+	return k8serrors.NewMethodNotSupported(shootsResource, "deletecollection")
+}
+
+func (c *shoots) Get(_ context.Context, name string, _ metav1.GetOptions) (*gardener_types.Shoot, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shoot, err := s.getShoot(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return shoot.DeepCopy(), nil
+}
+
+func (c *shoots) List(_ context.Context, opts metav1.ListOptions) (*gardener_types.ShootList, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+
+	names := make([]string, 0, len(s.shoots))
+	for name := range s.shoots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := &gardener_types.ShootList{Items: []gardener_types.Shoot{}}
+	for _, name := range names {
+		shoot, err := s.getShoot(name)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if selector.Matches(labels.Set(shoot.Labels)) {
+			list.Items = append(list.Items, *shoot.DeepCopy())
+		}
+	}
+
+	return list, nil
+}
+
+func (c *shoots) Watch(_ context.Context, _ metav1.ListOptions) (watch.Interface, error) {
+	return nil, k8serrors.NewMethodNotSupported(shootsResource, "watch")
+}
+
+// Patch supports apply and merge patches of the Shoot resource, both are applied as JSON merge patch
+func (c *shoots) Patch(_ context.Context, name string, pt types.PatchType, data []byte, _ metav1.PatchOptions, subresources ...string) (*gardener_types.Shoot, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(subresources) > 0 || (pt != types.ApplyPatchType && pt != types.MergePatchType) {
+		return nil, k8serrors.NewMethodNotSupported(shootsResource, fmt.Sprintf("patch %s", pt))
+	}
+
+	shoot, err := s.getShoot(name)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := json.Marshal(shoot)
+	if err != nil {
+		return nil, k8serrors.NewInternalError(err)
+	}
+
+	patchedData, err := jsonpatch.MergePatch(original, data)
+	if err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+
+	var patched gardener_types.Shoot
+	if err := json.Unmarshal(patchedData, &patched); err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+
+	return s.updateShoot(&patched, false)
+}
+
+func (c *shoots) CreateAdminKubeconfigRequest(_ context.Context, shootName string, request *v1alpha1.AdminKubeconfigRequest, _ metav1.CreateOptions) (*v1alpha1.AdminKubeconfigRequest, error) {
+	s := c.simulator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	created := request.DeepCopy()
+	if err := s.fillAdminKubeconfig(shootName, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (c *shoots) UpdateBinding(_ context.Context, _ string, _ *gardener_types.Shoot, _ metav1.UpdateOptions) (*gardener_types.Shoot, error) {
+	return nil, k8serrors.NewMethodNotSupported(shootsResource, "update binding")
+}
+
+// getShoot returns the stored Shoot with its operation advanced
+func (s *Simulator) getShoot(name string) (*gardener_types.Shoot, error) {
+	shoot, found := s.shoots[name]
+	if !found {
+		return nil, k8serrors.NewNotFound(shootsResource, name)
+	}
+
+	exists, err := s.advance(shoot)
+	if err != nil {
+		return nil, k8serrors.NewInternalError(err)
+	}
+	if !exists {
+		return nil, k8serrors.NewNotFound(shootsResource, name)
+	}
+
+	return shoot, nil
+}
+
+// updateShoot updates metadata and specification of the Shoot, changes of the specification trigger the reconciliation
+func (s *Simulator) updateShoot(shoot *gardener_types.Shoot, checkVersion bool) (*gardener_types.Shoot, error) {
+	existing, err := s.getShoot(shoot.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkVersion && shoot.ResourceVersion != "" && shoot.ResourceVersion != existing.ResourceVersion {
+		return nil, k8serrors.NewConflict(shootsResource, shoot.Name, errors.New("the object has been modified"))
+	}
+
+	existing.Labels = shoot.DeepCopy().Labels
+	existing.Annotations = shoot.DeepCopy().Annotations
+	existing.Finalizers = shoot.DeepCopy().Finalizers
+	existing.ResourceVersion = s.nextResourceVersion()
+
+	if !equality.Semantic.DeepEqual(existing.Spec, shoot.Spec) && existing.DeletionTimestamp == nil {
+		existing.Spec = *shoot.Spec.DeepCopy()
+		existing.Generation++
+		s.startOperation(existing, gardener_types.LastOperationTypeReconcile)
+	}
+
+	if _, err := s.advance(existing); err != nil {
+		return nil, k8serrors.NewInternalError(err)
+	}
+
+	return existing.DeepCopy(), nil
+}
+
+func (s *Simulator) fillAdminKubeconfig(shootName string, request *v1alpha1.AdminKubeconfigRequest) error {
+	shoot, err := s.getShoot(shootName)
+	if err != nil {
+		return err
+	}
+
+	if shoot.Status.LastOperation == nil || (shoot.Status.LastOperation.Type == gardener_types.LastOperationTypeCreate &&
+		shoot.Status.LastOperation.State != gardener_types.LastOperationStateSucceeded) {
+		return k8serrors.NewBadRequest(fmt.Sprintf("Shoot %s is not created yet", shootName))
+	}
+
+	expiration := time.Hour
+	if request.Spec.ExpirationSeconds != nil {
+		expiration = time.Duration(*request.Spec.ExpirationSeconds) * time.Second
+	}
+
+	request.Status = v1alpha1.AdminKubeconfigRequestStatus{
+		Kubeconfig:          s.config.Kubeconfig,
+		ExpirationTimestamp: metav1.NewTime(s.now().Add(expiration)),
+	}
+
+	return nil
+}
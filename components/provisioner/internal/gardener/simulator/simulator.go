@@ -0,0 +1,199 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardener_apis "github.com/gardener/gardener/pkg/client/core/clientset/versioned/typed/core/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	gardenerFinalizer            = "gardener"
+	confirmDeletionAnnotation    = "confirmation.gardener.cloud/deletion"
+	kubeconfigSecretNameTemplate = "%s.kubeconfig"
+)
+
+var shootsResource = gardener_types.SchemeGroupVersion.WithResource("shoots").GroupResource()
+
+type Config struct {
+	// OperationDelay is the time the Shoot stays in Processing state before its operation finishes
+	OperationDelay time.Duration
+	// Domain is the suffix of the DNS domain assigned to created Shoots
+	Domain string
+	// Seed is assigned to Shoots created without a seed
+	Seed string
+	// Kubeconfig is served for every Shoot, usually pointing to an envtest API server
+	Kubeconfig []byte
+}
+
+// Failure is the outcome of the next operation of the Shoot
+type Failure struct {
+	Description string
+	Codes       []gardener_types.ErrorCode
+}
+
+// Simulator implements Gardener APIs used by the Provisioner in memory.
+// Operations of Shoots are advanced whenever Shoots are read, so no background processing is needed.
+type Simulator struct {
+	mu sync.Mutex
+
+	namespace string
+	config    Config
+	now       func() time.Time
+
+	shoots          map[string]*gardener_types.Shoot
+	seeds           map[string]*gardener_types.Seed
+	failures        map[string][]Failure
+	secrets         v1core.SecretInterface
+	resourceVersion int
+}
+
+func New(namespace string, config Config) *Simulator {
+	return &Simulator{
+		namespace: namespace,
+		config:    config,
+		now:       time.Now,
+		shoots:    map[string]*gardener_types.Shoot{},
+		seeds:     map[string]*gardener_types.Seed{},
+		failures:  map[string][]Failure{},
+		secrets:   fake.NewSimpleClientset().CoreV1().Secrets(namespace),
+	}
+}
+
+func (s *Simulator) Shoots() gardener_apis.ShootInterface {
+	return &shoots{simulator: s}
+}
+
+func (s *Simulator) Seeds() gardener_apis.SeedInterface {
+	return &seeds{simulator: s}
+}
+
+// Secrets holds kubeconfig Secrets of Shoots in the Gardener project namespace
+func (s *Simulator) Secrets() v1core.SecretInterface {
+	return s.secrets
+}
+
+func (s *Simulator) AdminKubeconfigRequests() *AdminKubeconfigRequests {
+	return &AdminKubeconfigRequests{simulator: s}
+}
+
+// FailOperations makes next operations of the Shoot fail, one failure per operation
+func (s *Simulator) FailOperations(shootName string, failures ...Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[shootName] = append(s.failures[shootName], failures...)
+}
+
+func (s *Simulator) nextResourceVersion() string {
+	s.resourceVersion++
+	return strconv.Itoa(s.resourceVersion)
+}
+
+func (s *Simulator) startOperation(shoot *gardener_types.Shoot, operationType gardener_types.LastOperationType) {
+	shoot.Status.ObservedGeneration = shoot.Generation
+	shoot.Status.LastErrors = nil
+	shoot.Status.LastOperation = &gardener_types.LastOperation{
+		Type:           operationType,
+		State:          gardener_types.LastOperationStateProcessing,
+		Description:    fmt.Sprintf("%s of Shoot cluster in progress", operationType),
+		LastUpdateTime: metav1.NewTime(s.now()),
+	}
+}
+
+// advance finishes the operation of the Shoot when its delay passed, returns false when the Shoot was removed
+func (s *Simulator) advance(shoot *gardener_types.Shoot) (bool, error) {
+	lastOperation := shoot.Status.LastOperation
+
+	if lastOperation != nil && lastOperation.State == gardener_types.LastOperationStateProcessing &&
+		!s.now().Before(lastOperation.LastUpdateTime.Add(s.config.OperationDelay)) {
+		if err := s.finishOperation(shoot); err != nil {
+			return true, err
+		}
+		shoot.ResourceVersion = s.nextResourceVersion()
+	}
+
+	if shoot.DeletionTimestamp != nil && len(shoot.Finalizers) == 0 {
+		delete(s.shoots, shoot.Name)
+		return false, s.deleteKubeconfigSecret(shoot.Name)
+	}
+
+	return true, nil
+}
+
+func (s *Simulator) finishOperation(shoot *gardener_types.Shoot) error {
+	lastOperation := shoot.Status.LastOperation
+	lastOperation.LastUpdateTime = metav1.NewTime(s.now())
+
+	if failures := s.failures[shoot.Name]; len(failures) > 0 {
+		s.failures[shoot.Name] = failures[1:]
+
+		lastOperation.State = gardener_types.LastOperationStateFailed
+		lastOperation.Description = failures[0].Description
+		shoot.Status.LastErrors = []gardener_types.LastError{{
+			Description:    failures[0].Description,
+			Codes:          failures[0].Codes,
+			LastUpdateTime: &lastOperation.LastUpdateTime,
+		}}
+		return nil
+	}
+
+	lastOperation.State = gardener_types.LastOperationStateSucceeded
+	lastOperation.Progress = 100
+	lastOperation.Description = fmt.Sprintf("%s of Shoot cluster succeeded", lastOperation.Type)
+
+	switch lastOperation.Type {
+	case gardener_types.LastOperationTypeCreate, gardener_types.LastOperationTypeReconcile:
+		return s.ensureKubeconfigSecret(shoot.Name)
+	case gardener_types.LastOperationTypeDelete:
+		shoot.Finalizers = removeFinalizer(shoot.Finalizers, gardenerFinalizer)
+	}
+
+	return nil
+}
+
+func (s *Simulator) ensureKubeconfigSecret(shootName string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(kubeconfigSecretNameTemplate, shootName),
+			Namespace: s.namespace,
+		},
+		Data: map[string][]byte{"kubeconfig": s.config.Kubeconfig},
+	}
+
+	_, err := s.secrets.Create(context.Background(), secret, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		_, err = s.secrets.Update(context.Background(), secret, metav1.UpdateOptions{})
+	}
+
+	return err
+}
+
+func (s *Simulator) deleteKubeconfigSecret(shootName string) error {
+	err := s.secrets.Delete(context.Background(), fmt.Sprintf(kubeconfigSecretNameTemplate, shootName), metav1.DeleteOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+func removeFinalizer(finalizers []string, finalizer string) []string {
+	var result []string
+	for _, f := range finalizers {
+		if f != finalizer {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
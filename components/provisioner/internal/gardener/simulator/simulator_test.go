@@ -0,0 +1,301 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	namespace = "garden-project"
+	shootName = "shoot"
+	delay     = time.Minute
+)
+
+func TestSimulator_Provisioning(t *testing.T) {
+	t.Run("should create Shoot and finish operation after delay", func(t *testing.T) {
+		// given
+		simulator, clock := newTestSimulator()
+		shoots := simulator.Shoots()
+
+		// when
+		_, err := shoots.Create(context.Background(), fixShoot(), metav1.CreateOptions{})
+		require.NoError(t, err)
+		shoot, err := shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, gardener_types.LastOperationTypeCreate, shoot.Status.LastOperation.Type)
+		assert.Equal(t, gardener_types.LastOperationStateProcessing, shoot.Status.LastOperation.State)
+		assert.Equal(t, "shoot.simulator.local", *shoot.Spec.DNS.Domain)
+		assert.Equal(t, "seed", *shoot.Spec.SeedName)
+		assert.Contains(t, shoot.Finalizers, gardenerFinalizer)
+
+		_, err = simulator.Secrets().Get(context.Background(), "shoot.kubeconfig", metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		// when
+		clock.Add(delay)
+		shoot, err = shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, gardener_types.LastOperationStateSucceeded, shoot.Status.LastOperation.State)
+		assert.Equal(t, shoot.Generation, shoot.Status.ObservedGeneration)
+
+		secret, err := simulator.Secrets().Get(context.Background(), "shoot.kubeconfig", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("kubeconfig"), secret.Data["kubeconfig"])
+	})
+
+	t.Run("should fail operation with configured error codes", func(t *testing.T) {
+		// given
+		simulator, clock := newTestSimulator()
+		simulator.FailOperations(shootName, Failure{
+			Description: "quota exceeded",
+			Codes:       []gardener_types.ErrorCode{gardener_types.ErrorInfraQuotaExceeded},
+		})
+
+		// when
+		_, err := simulator.Shoots().Create(context.Background(), fixShoot(), metav1.CreateOptions{})
+		require.NoError(t, err)
+		clock.Add(delay)
+		shoot, err := simulator.Shoots().Get(context.Background(), shootName, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, gardener_types.LastOperationStateFailed, shoot.Status.LastOperation.State)
+		require.Len(t, shoot.Status.LastErrors, 1)
+		assert.Equal(t, []gardener_types.ErrorCode{gardener_types.ErrorInfraQuotaExceeded}, shoot.Status.LastErrors[0].Codes)
+	})
+
+	t.Run("should serve kubeconfig to provider", func(t *testing.T) {
+		// given
+		simulator, clock := newTestSimulator()
+		_, err := simulator.Shoots().Create(context.Background(), fixShoot(), metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		provider := gardener.NewKubeconfigProvider(simulator.Shoots(), simulator.AdminKubeconfigRequests(), simulator.Secrets())
+
+		// when
+		_, err = provider.FetchFromRequest(shootName)
+
+		// then
+		require.Error(t, err)
+
+		// when
+		clock.Add(delay)
+		fromRequest, err := provider.FetchFromRequest(shootName)
+		require.NoError(t, err)
+		fromShoot, err := provider.FetchFromShoot(shootName)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, []byte("kubeconfig"), fromRequest)
+		assert.Equal(t, []byte("kubeconfig"), fromShoot)
+	})
+}
+
+func TestSimulator_Upgrade(t *testing.T) {
+	// given
+	simulator, clock := newTestSimulator()
+	shoots := simulator.Shoots()
+
+	_, err := shoots.Create(context.Background(), fixShoot(), metav1.CreateOptions{})
+	require.NoError(t, err)
+	clock.Add(delay)
+
+	shoot, err := shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+	require.NoError(t, err)
+	shoot.Spec.Kubernetes.Version = "1.26"
+	data, err := json.Marshal(shoot)
+	require.NoError(t, err)
+
+	// when
+	_, err = shoots.Patch(context.Background(), shootName, types.ApplyPatchType, data, metav1.PatchOptions{})
+	require.NoError(t, err)
+	shoot, err = shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, int64(2), shoot.Generation)
+	assert.Equal(t, "1.26", shoot.Spec.Kubernetes.Version)
+	assert.Equal(t, gardener_types.LastOperationTypeReconcile, shoot.Status.LastOperation.Type)
+	assert.Equal(t, gardener_types.LastOperationStateProcessing, shoot.Status.LastOperation.State)
+
+	// when
+	clock.Add(delay)
+	shoot, err = shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, gardener_types.LastOperationStateSucceeded, shoot.Status.LastOperation.State)
+	assert.Equal(t, int64(2), shoot.Status.ObservedGeneration)
+}
+
+func TestSimulator_Deletion(t *testing.T) {
+	t.Run("should require deletion confirmation", func(t *testing.T) {
+		// given
+		simulator, _ := newTestSimulator()
+		_, err := simulator.Shoots().Create(context.Background(), fixShoot(), metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		// when
+		err = simulator.Shoots().Delete(context.Background(), shootName, metav1.DeleteOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, k8serrors.IsForbidden(err))
+	})
+
+	t.Run("should remove Shoot when deletion finished", func(t *testing.T) {
+		// given
+		simulator, clock := newTestSimulator()
+		shoots := simulator.Shoots()
+
+		_, err := shoots.Create(context.Background(), fixConfirmedShoot(), metav1.CreateOptions{})
+		require.NoError(t, err)
+		clock.Add(delay)
+
+		// when
+		err = shoots.Delete(context.Background(), shootName, metav1.DeleteOptions{})
+		require.NoError(t, err)
+		shoot, err := shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		// then
+		assert.NotNil(t, shoot.DeletionTimestamp)
+		assert.Equal(t, gardener_types.LastOperationTypeDelete, shoot.Status.LastOperation.Type)
+
+		// when
+		clock.Add(delay)
+		_, err = shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+
+		// then
+		assert.True(t, k8serrors.IsNotFound(err))
+		_, err = simulator.Secrets().Get(context.Background(), "shoot.kubeconfig", metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("should keep Shoot until other finalizers are removed", func(t *testing.T) {
+		// given
+		simulator, clock := newTestSimulator()
+		shoots := simulator.Shoots()
+
+		shoot := fixConfirmedShoot()
+		shoot.Finalizers = []string{"custom"}
+		_, err := shoots.Create(context.Background(), shoot, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		// when
+		err = shoots.Delete(context.Background(), shootName, metav1.DeleteOptions{})
+		require.NoError(t, err)
+		clock.Add(delay)
+		shoot, err = shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, []string{"custom"}, shoot.Finalizers)
+
+		// when
+		shoot.Finalizers = nil
+		_, err = shoots.Update(context.Background(), shoot, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		_, err = shoots.Get(context.Background(), shootName, metav1.GetOptions{})
+
+		// then
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+}
+
+func TestSimulator_List(t *testing.T) {
+	// given
+	simulator, _ := newTestSimulator()
+
+	labeled := fixShoot()
+	labeled.Name = "labeled"
+	labeled.Labels = map[string]string{"account": "a1"}
+
+	for _, shoot := range []*gardener_types.Shoot{fixShoot(), labeled} {
+		_, err := simulator.Shoots().Create(context.Background(), shoot, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// when
+	all, err := simulator.Shoots().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	selected, err := simulator.Shoots().List(context.Background(), metav1.ListOptions{LabelSelector: "account=a1"})
+	require.NoError(t, err)
+
+	// then
+	assert.Len(t, all.Items, 2)
+	require.Len(t, selected.Items, 1)
+	assert.Equal(t, "labeled", selected.Items[0].Name)
+}
+
+func TestKubeconfig(t *testing.T) {
+	// when
+	kubeconfig, err := Kubeconfig(&rest.Config{Host: "https://127.0.0.1:6443", BearerToken: "token"})
+	require.NoError(t, err)
+
+	// then
+	config, err := k8s.ParseToK8sConfig(kubeconfig)
+	require.NoError(t, err)
+	assert.Equal(t, "https://127.0.0.1:6443", config.Host)
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestSimulator() (*Simulator, *testClock) {
+	clock := &testClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+
+	simulator := New(namespace, Config{
+		OperationDelay: delay,
+		Domain:         "simulator.local",
+		Seed:           "seed",
+		Kubeconfig:     []byte("kubeconfig"),
+	})
+	simulator.now = clock.Now
+
+	return simulator, clock
+}
+
+func fixShoot() *gardener_types.Shoot {
+	return &gardener_types.Shoot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      shootName,
+			Namespace: namespace,
+		},
+		Spec: gardener_types.ShootSpec{
+			Kubernetes: gardener_types.Kubernetes{Version: "1.25"},
+		},
+	}
+}
+
+func fixConfirmedShoot() *gardener_types.Shoot {
+	shoot := fixShoot()
+	gardener.AnnotateWithConfirmDeletion(shoot)
+
+	return shoot
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var classifiedErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: prometheusNamespace,
+	Subsystem: prometheusSubsystem,
+	Name:      "classified_errors_total",
+	Help:      "The number of errors returned by operation stages per action chosen for them",
+}, []string{"operation", "action", "code"})
+
+// ObserveClassifiedError counts the error by the action chosen for it and the Gardener error code or Kubernetes reason it was classified by
+func ObserveClassifiedError(operationType model.OperationType, action, code string) {
+	classifiedErrors.WithLabelValues(string(operationType), action, code).Inc()
+}
@@ -13,5 +13,10 @@ func Register(opsStatsGetter OperationsStatsGetter) error {
 		return err
 	}
 
+	err = prometheus.Register(classifiedErrors)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+type BulkOperationState string
+
+const (
+	BulkInProgress BulkOperationState = "IN_PROGRESS"
+	BulkPaused     BulkOperationState = "PAUSED"
+	BulkCanceled   BulkOperationState = "CANCELED"
+	BulkSucceeded  BulkOperationState = "SUCCEEDED"
+	BulkFailed     BulkOperationState = "FAILED"
+)
+
+// RuntimeSelector selects Runtimes matching all of the non-empty criteria
+type RuntimeSelector struct {
+	Tenants            []string `json:"tenants,omitempty"`
+	Providers          []string `json:"providers,omitempty"`
+	Regions            []string `json:"regions,omitempty"`
+	KubernetesVersions []string `json:"kubernetesVersions,omitempty"`
+}
+
+// IsEmpty returns true when the selector has no criteria and would select all Runtimes
+func (s RuntimeSelector) IsEmpty() bool {
+	return len(s.Tenants) == 0 && len(s.Providers) == 0 && len(s.Regions) == 0 && len(s.KubernetesVersions) == 0
+}
+
+type BulkOperationStrategy struct {
+	// MaxParallel limits the number of child operations in progress at the same time
+	MaxParallel int
+	// MaxFailureRatio stops starting child operations once the ratio of failed ones to all children exceeds it
+	MaxFailureRatio float64
+}
+
+// BulkOperation starts an operation of the same type with the same input for every selected Runtime
+type BulkOperation struct {
+	ID       string
+	Type     OperationType
+	State    BulkOperationState
+	Message  string
+	Selector RuntimeSelector
+	// Input is the JSON encoded input of the child operations
+	Input          string
+	Strategy       BulkOperationStrategy
+	StartTimestamp time.Time
+	EndTimestamp   *time.Time
+}
+
+type BulkOperationChild struct {
+	BulkOperationID string
+	RuntimeID       string
+	// OperationID is empty until the child operation is started
+	OperationID string
+	State       OperationState
+	Message     string
+}
+
+// FailureRatioExceeded is true when more children failed than the strategy allows
+func (o BulkOperation) FailureRatioExceeded(children []BulkOperationChild) bool {
+	if len(children) == 0 {
+		return false
+	}
+
+	failed := 0
+	for _, child := range children {
+		if child.State == Failed {
+			failed++
+		}
+	}
+
+	return float64(failed)/float64(len(children)) > o.Strategy.MaxFailureRatio
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type CredentialsRotationKind string
+
+const (
+	CertificateAuthorities   CredentialsRotationKind = "CERTIFICATE_AUTHORITIES"
+	ServiceAccountKey        CredentialsRotationKind = "SERVICE_ACCOUNT_KEY"
+	ETCDEncryptionKey        CredentialsRotationKind = "ETCD_ENCRYPTION_KEY"
+	ObservabilityCredentials CredentialsRotationKind = "OBSERVABILITY"
+	SSHKeypair               CredentialsRotationKind = "SSH_KEYPAIR"
+)
+
+// TwoPhase is true for credentials which are rotated in prepare and complete phases, other credentials are rotated at once in the prepare phase
+func (k CredentialsRotationKind) TwoPhase() bool {
+	return k == CertificateAuthorities || k == ServiceAccountKey || k == ETCDEncryptionKey
+}
+
+type CredentialsRotationPhase string
+
+const (
+	RotationPrepare  CredentialsRotationPhase = "PREPARE"
+	RotationComplete CredentialsRotationPhase = "COMPLETE"
+)
+
+// CredentialsRotation is requested by the credentials rotation operation
+type CredentialsRotation struct {
+	OperationID string
+	Kinds       []CredentialsRotationKind
+	Phase       CredentialsRotationPhase
+}
+
+func (r CredentialsRotation) Includes(kind CredentialsRotationKind) bool {
+	for _, k := range r.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CredentialsRotationStatus mirrors the rotation status of credentials reported by Gardener
+type CredentialsRotationStatus struct {
+	Kind               CredentialsRotationKind
+	Phase              string
+	LastInitiationTime *time.Time
+	LastCompletionTime *time.Time
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// OperationDiagnostics is the state of the Shoot captured when a stage of the operation failed
+type OperationDiagnostics struct {
+	OperationID string           `json:"operationID"`
+	Stage       OperationStage   `json:"stage"`
+	CapturedAt  time.Time        `json:"capturedAt"`
+	Conditions  []ShootCondition `json:"conditions"`
+	Constraints []ShootCondition `json:"constraints"`
+	LastErrors  []ShootLastError `json:"lastErrors"`
+	Events      []ShootEvent     `json:"events"`
+	// Truncated is true when events or messages were dropped to keep the diagnostics within the size limit
+	Truncated bool `json:"truncated"`
+}
+
+type ShootCondition struct {
+	Type               string     `json:"type"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason"`
+	Message            string     `json:"message"`
+	Codes              []string   `json:"codes,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+type ShootLastError struct {
+	Description    string     `json:"description"`
+	TaskID         *string    `json:"taskID,omitempty"`
+	Codes          []string   `json:"codes,omitempty"`
+	LastUpdateTime *time.Time `json:"lastUpdateTime,omitempty"`
+}
+
+type ShootEvent struct {
+	Type           string     `json:"type"`
+	Reason         string     `json:"reason"`
+	Message        string     `json:"message"`
+	Count          int32      `json:"count"`
+	FirstTimestamp *time.Time `json:"firstTimestamp,omitempty"`
+	LastTimestamp  *time.Time `json:"lastTimestamp,omitempty"`
+}
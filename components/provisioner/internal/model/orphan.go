@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+type OrphanCategory string
+
+const (
+	// DirectorRuntimeWithoutCluster is a Runtime registered in Director for which there is no active cluster in the database,
+	// it is reported together with the ShootWithoutCluster orphan when the Shoot of the Runtime still exists
+	DirectorRuntimeWithoutCluster OrphanCategory = "DirectorRuntimeWithoutCluster"
+	// ShootWithoutCluster is a Shoot annotated with a Runtime ID for which there is no active cluster in the database
+	ShootWithoutCluster OrphanCategory = "ShootWithoutCluster"
+	// ClusterWithoutShoot is a cluster not marked as deleted in the database for which the Shoot no longer exists
+	ClusterWithoutShoot OrphanCategory = "ClusterWithoutShoot"
+)
+
+type Orphan struct {
+	Category         OrphanCategory
+	RuntimeID        string
+	Tenant           string
+	ShootName        string
+	Remediated       bool
+	RemediationError string
+}
+
+type OrphansReport struct {
+	ScanTime time.Time
+	Orphans  []Orphan
+}
+
+// ClusterReference identifies the cluster in Director and Gardener
+type ClusterReference struct {
+	ID        string
+	Tenant    string
+	ShootName string
+	Deleted   bool
+	// DeletionTimestamp is set when the cluster is marked as deleted
+	DeletionTimestamp *time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+// TenantQuota holds limits of the tenant. Nil limit means that the default one is used.
+type TenantQuota struct {
+	Tenant                  string
+	MaxRuntimes             *int
+	MaxOperationsInProgress *int
+	MaxNodesPerProvider     map[string]int
+
+	// DefaultMaxNodesPerProvider applies to providers not listed in MaxNodesPerProvider, it is not stored per tenant
+	DefaultMaxNodesPerProvider *int `db:"-"`
+}
+
+type TenantUsage struct {
+	Tenant               string
+	Runtimes             int
+	OperationsInProgress int
+	NodesPerProvider     map[string]int
+}
@@ -7,9 +7,11 @@ import (
 type OperationState string
 
 const (
+	Pending    OperationState = "PENDING"
 	InProgress OperationState = "IN_PROGRESS"
 	Succeeded  OperationState = "SUCCEEDED"
 	Failed     OperationState = "FAILED"
+	Canceled   OperationState = "CANCELED"
 )
 
 type OperationType string
@@ -23,6 +25,7 @@ const (
 	DeprovisionNoInstall OperationType = "DEPROVISION_NO_INSTALL"
 	ReconnectRuntime     OperationType = "RECONNECT_RUNTIME"
 	Hibernate            OperationType = "HIBERNATE"
+	RotateCredentials    OperationType = "ROTATE_CREDENTIALS"
 )
 
 type OperationStage string
@@ -36,10 +39,11 @@ const (
 	ConnectRuntimeAgent          OperationStage = "ConnectRuntimeAgent"
 	WaitForAgentToConnect        OperationStage = "WaitForAgentToConnect"
 
-	TriggerKymaUninstall   OperationStage = "TriggerKymaUninstall"
-	WaitForClusterDeletion OperationStage = "WaitForClusterDeletion"
-	DeleteCluster          OperationStage = "DeprovisionCluster"
-	CleanupCluster         OperationStage = "CleanupCluster"
+	WaitingForDeprovisioningGracePeriod OperationStage = "WaitingForDeprovisioningGracePeriod"
+	TriggerKymaUninstall                OperationStage = "TriggerKymaUninstall"
+	WaitForClusterDeletion              OperationStage = "WaitForClusterDeletion"
+	DeleteCluster                       OperationStage = "DeprovisionCluster"
+	CleanupCluster                      OperationStage = "CleanupCluster"
 
 	StartingUpgrade      OperationStage = "StartingUpgrade"
 	UpdatingUpgradeState OperationStage = "UpdatingUpgradeState"
@@ -49,6 +53,9 @@ const (
 
 	WaitForHibernation OperationStage = "WaitForHibernation"
 
+	RotatingCredentials  OperationStage = "RotatingCredentials"
+	RefreshingKubeconfig OperationStage = "RefreshingKubeconfig"
+
 	FinishedStage OperationStage = "Finished"
 )
 
@@ -66,12 +73,15 @@ type Cluster struct {
 	KymaConfig    *KymaConfig    `db:"-"`
 
 	IsKubeconfigEncrypted bool
+	DeletionProtection    bool
 }
 
 type LastError struct {
 	ErrMessage string
 	Reason     string
 	Component  string
+	// Action taken by the executor for the error, one of: retry, wait_for_user, fail_fast
+	Action string
 }
 
 type Operation struct {
@@ -85,6 +95,25 @@ type Operation struct {
 	Stage          OperationStage
 	LastTransition *time.Time
 	LastError
+	PipelineVersion string
+}
+
+// LegacyPipelineVersion marks operations started before stage pipelines were introduced
+const LegacyPipelineVersion = "legacy"
+
+type StageState string
+
+const (
+	StageInProgress StageState = "IN_PROGRESS"
+	StageSucceeded  StageState = "SUCCEEDED"
+	StageSkipped    StageState = "SKIPPED"
+)
+
+type OperationStageStatus struct {
+	Stage          OperationStage
+	State          StageState
+	StartTimestamp time.Time
+	EndTimestamp   *time.Time
 }
 
 type RuntimeAgentConnectionStatus int
@@ -100,6 +129,7 @@ type RuntimeStatus struct {
 	RuntimeConnectionStatus RuntimeAgentConnectionStatus
 	RuntimeConfiguration    Cluster
 	HibernationStatus       HibernationStatus
+	CredentialsRotation     []CredentialsRotationStatus
 }
 
 type OperationsCount struct {
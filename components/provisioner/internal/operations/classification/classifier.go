@@ -0,0 +1,182 @@
+package classification
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Action is taken by the executor for the error returned by a stage
+type Action string
+
+const (
+	// Retry requeues the operation with exponential backoff
+	Retry Action = "retry"
+	// WaitForUser keeps the operation in progress and checks it again after a long delay, until the stage times out
+	WaitForUser Action = "wait_for_user"
+	// FailFast fails the operation at once
+	FailFast Action = "fail_fast"
+)
+
+// severity orders actions when several error codes match
+var severity = map[Action]int{
+	Retry:       1,
+	WaitForUser: 2,
+	FailFast:    3,
+}
+
+type Config struct {
+	ConfigPath        string        `envconfig:"optional"`
+	RetryInitialDelay time.Duration `envconfig:"default=5s"`
+	RetryMaxDelay     time.Duration `envconfig:"default=5m"`
+	WaitForUserDelay  time.Duration `envconfig:"default=5m"`
+}
+
+// Rules map Gardener error codes and reasons of Kubernetes API errors to actions
+type Rules struct {
+	GardenerErrorCodes map[string]Action `json:"gardenerErrorCodes"`
+	KubernetesReasons  map[string]Action `json:"kubernetesReasons"`
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		GardenerErrorCodes: map[string]Action{
+			string(gardener_types.ErrorInfraRateLimitsExceeded):       Retry,
+			string(gardener_types.ErrorInfraResourcesDepleted):        Retry,
+			string(gardener_types.ErrorRetryableInfraDependencies):    Retry,
+			string(gardener_types.ErrorRetryableConfigurationProblem): Retry,
+			string(gardener_types.ErrorInfraQuotaExceeded):            WaitForUser,
+			string(gardener_types.ErrorInfraDependencies):             WaitForUser,
+			string(gardener_types.ErrorInfraUnauthenticated):          WaitForUser,
+			string(gardener_types.ErrorInfraUnauthorized):             WaitForUser,
+			string(gardener_types.ErrorCleanupClusterResources):       WaitForUser,
+			string(gardener_types.ErrorProblematicWebhook):            WaitForUser,
+			string(gardener_types.ErrorConfigurationProblem):          FailFast,
+		},
+		KubernetesReasons: map[string]Action{
+			string(metav1.StatusReasonTooManyRequests):       Retry,
+			string(metav1.StatusReasonServerTimeout):         Retry,
+			string(metav1.StatusReasonTimeout):               Retry,
+			string(metav1.StatusReasonInternalError):         Retry,
+			string(metav1.StatusReasonServiceUnavailable):    Retry,
+			string(metav1.StatusReasonConflict):              Retry,
+			string(metav1.StatusReasonUnauthorized):          WaitForUser,
+			string(metav1.StatusReasonForbidden):             WaitForUser,
+			string(metav1.StatusReasonRequestEntityTooLarge): FailFast,
+		},
+	}
+}
+
+// LoadRules reads the rules from the file, they override the default rules for the same codes and reasons
+func LoadRules(path string) (Rules, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("failed to read error classification file: %s", err.Error())
+	}
+
+	var rules Rules
+	if err := yaml.Unmarshal(file, &rules); err != nil {
+		return Rules{}, fmt.Errorf("failed to decode error classification file: %s", err.Error())
+	}
+
+	if err := rules.validate(); err != nil {
+		return Rules{}, err
+	}
+
+	merged := DefaultRules()
+	for code, action := range rules.GardenerErrorCodes {
+		merged.GardenerErrorCodes[code] = action
+	}
+	for reason, action := range rules.KubernetesReasons {
+		merged.KubernetesReasons[reason] = action
+	}
+
+	return merged, nil
+}
+
+func (r Rules) validate() error {
+	for _, actions := range []map[string]Action{r.GardenerErrorCodes, r.KubernetesReasons} {
+		for code, action := range actions {
+			if _, found := severity[action]; !found {
+				return fmt.Errorf("invalid action %s for %s, expected one of: %s, %s, %s", action, code, Retry, WaitForUser, FailFast)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Classification is the action chosen for the error
+type Classification struct {
+	Action Action
+	// Code is the Gardener error code or Kubernetes reason the action was chosen for, it is empty for the default action
+	Code string
+}
+
+type Classifier struct {
+	rules  Rules
+	config Config
+}
+
+func NewClassifier(rules Rules, config Config) *Classifier {
+	return &Classifier{
+		rules:  rules,
+		config: config,
+	}
+}
+
+// Classify chooses the action for the recoverable error, errors not matching any rule are retried.
+// Errors which are not recoverable always fail fast, rules must not keep a terminally failed operation in progress.
+func (c *Classifier) Classify(err apperrors.AppError, recoverable bool) Classification {
+	if !recoverable {
+		return Classification{Action: FailFast}
+	}
+
+	var classification Classification
+
+	switch err.Component() {
+	case apperrors.ErrGardener:
+		// Gardener error codes are joined in the reason of the error
+		for _, code := range strings.Split(string(err.Reason()), ", ") {
+			action, found := c.rules.GardenerErrorCodes[code]
+			if found && severity[action] > severity[classification.Action] {
+				classification = Classification{Action: action, Code: code}
+			}
+		}
+	case apperrors.ErrGardenerClient, apperrors.ErrClusterK8SClient, apperrors.ErrProvisionerK8SClient:
+		reason := string(err.Reason())
+		if action, found := c.rules.KubernetesReasons[reason]; found {
+			classification = Classification{Action: action, Code: reason}
+		}
+	}
+
+	if classification.Action != "" {
+		return classification
+	}
+
+	return Classification{Action: Retry}
+}
+
+// RetryDelay doubles the delay with each consecutive retry of the operation up to the maximum delay
+func (c *Classifier) RetryDelay(retries int) time.Duration {
+	delay := c.config.RetryInitialDelay
+	for i := 0; i < retries && delay < c.config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > c.config.RetryMaxDelay {
+		return c.config.RetryMaxDelay
+	}
+
+	return delay
+}
+
+func (c *Classifier) WaitForUserDelay() time.Duration {
+	return c.config.WaitForUserDelay
+}
@@ -0,0 +1,106 @@
+package classification
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifier_Classify(t *testing.T) {
+	classifier := NewClassifier(DefaultRules(), Config{})
+
+	for _, testCase := range []struct {
+		description string
+		err         apperrors.AppError
+		recoverable bool
+		expected    Classification
+	}{
+		{
+			description: "should choose the most severe action for Gardener error codes",
+			err:         apperrors.External("error").SetComponent(apperrors.ErrGardener).SetReason("ERR_INFRA_RATE_LIMITS_EXCEEDED, ERR_INFRA_QUOTA_EXCEEDED"),
+			recoverable: true,
+			expected:    Classification{Action: WaitForUser, Code: "ERR_INFRA_QUOTA_EXCEEDED"},
+		},
+		{
+			description: "should classify Kubernetes API error by reason",
+			err:         apperrors.Internal("error").SetComponent(apperrors.ErrGardenerClient).SetReason("TooManyRequests"),
+			recoverable: true,
+			expected:    Classification{Action: Retry, Code: "TooManyRequests"},
+		},
+		{
+			description: "should not classify Kubernetes reasons of other components",
+			err:         apperrors.Internal("error").SetComponent(apperrors.ErrKymaInstaller).SetReason("Invalid"),
+			recoverable: true,
+			expected:    Classification{Action: Retry},
+		},
+		{
+			description: "should fail fast on non recoverable error matching a rule",
+			err:         apperrors.External("error").SetComponent(apperrors.ErrGardener).SetReason("ERR_INFRA_QUOTA_EXCEEDED"),
+			expected:    Classification{Action: FailFast},
+		},
+		{
+			description: "should retry Kubernetes bad request",
+			err:         apperrors.Internal("error").SetComponent(apperrors.ErrClusterK8SClient).SetReason("BadRequest"),
+			recoverable: true,
+			expected:    Classification{Action: Retry},
+		},
+		{
+			description: "should fail fast on unclassified non recoverable error",
+			err:         apperrors.External("error").SetComponent(apperrors.ErrGardener).SetReason("ERR_UNKNOWN"),
+			expected:    Classification{Action: FailFast},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			// when
+			classification := classifier.Classify(testCase.err, testCase.recoverable)
+
+			// then
+			assert.Equal(t, testCase.expected, classification)
+		})
+	}
+}
+
+func TestClassifier_RetryDelay(t *testing.T) {
+	// given
+	classifier := NewClassifier(Rules{}, Config{RetryInitialDelay: 5 * time.Second, RetryMaxDelay: time.Minute})
+
+	// then
+	assert.Equal(t, 5*time.Second, classifier.RetryDelay(0))
+	assert.Equal(t, 20*time.Second, classifier.RetryDelay(2))
+	assert.Equal(t, time.Minute, classifier.RetryDelay(10))
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Run("should override default rules", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "rules.yaml")
+		err := os.WriteFile(path, []byte("gardenerErrorCodes:\n  ERR_INFRA_QUOTA_EXCEEDED: fail_fast\n"), 0600)
+		require.NoError(t, err)
+
+		// when
+		rules, err := LoadRules(path)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, FailFast, rules.GardenerErrorCodes["ERR_INFRA_QUOTA_EXCEEDED"])
+		assert.Equal(t, Retry, rules.GardenerErrorCodes["ERR_INFRA_RATE_LIMITS_EXCEEDED"])
+	})
+
+	t.Run("should reject unknown action", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "rules.yaml")
+		err := os.WriteFile(path, []byte("kubernetesReasons:\n  Forbidden: ignore\n"), 0600)
+		require.NoError(t, err)
+
+		// when
+		_, err = LoadRules(path)
+
+		// then
+		require.Error(t, err)
+	})
+}
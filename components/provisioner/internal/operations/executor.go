@@ -3,6 +3,8 @@ package operations
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
@@ -10,7 +12,10 @@ import (
 	retry "github.com/avast/retry-go"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/metrics"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/classification"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
 	"github.com/sirupsen/logrus"
 )
@@ -22,29 +27,71 @@ const (
 
 var ErrKubeconfigNil = errors.New("cluster kubeconfig is nil")
 
+// ExecutorOptions groups the optional dependencies of the Executor
+type ExecutorOptions struct {
+	// Pipelines are used for new operations starting from the first stage of the current pipeline,
+	// operations starting from another stage and operations without pipelines are processed with stages
+	Pipelines Pipelines
+	// ErrorClassifier decides how failed stages are retried, without it recoverable errors are retried with the constant delay
+	ErrorClassifier *classification.Classifier
+	// DiagnosticsCollector captures the Shoot state when a stage fails, without it no diagnostics are stored
+	DiagnosticsCollector DiagnosticsCollector
+	// EventPublisher is notified about operation changes, without it no events are published
+	EventPublisher events.Publisher
+}
+
 func NewExecutor(
 	session dbsession.ReadWriteSession,
 	operation model.OperationType,
 	stages map[model.OperationStage]Step,
 	failureHandler FailureHandler,
-	directorClient director.DirectorClient) *Executor {
+	directorClient director.DirectorClient,
+	options ExecutorOptions) *Executor {
+
+	errorClassifier := options.ErrorClassifier
+	if errorClassifier == nil {
+		errorClassifier = classification.NewClassifier(classification.Rules{}, classification.Config{
+			RetryInitialDelay: defaultDelay,
+			RetryMaxDelay:     defaultDelay,
+			WaitForUserDelay:  defaultDelay,
+		})
+	}
 
 	return &Executor{
-		dbSession:      session,
-		stages:         stages,
-		operation:      operation,
-		failureHandler: failureHandler,
-		log:            logrus.WithFields(logrus.Fields{"Component": "Executor", "OperationType": operation}),
-		directorClient: directorClient,
+		dbSession:            session,
+		stages:               stages,
+		pipelines:            options.Pipelines,
+		operation:            operation,
+		failureHandler:       failureHandler,
+		errorClassifier:      errorClassifier,
+		diagnosticsCollector: options.DiagnosticsCollector,
+		retries:              map[string]int{},
+		diagnosedStages:      map[string]model.OperationStage{},
+		log:                  logrus.WithFields(logrus.Fields{"Component": "Executor", "OperationType": operation}),
+		directorClient:       directorClient,
+		eventPublisher:       options.EventPublisher,
 	}
 }
 
 type Executor struct {
 	dbSession      dbsession.ReadWriteSession
 	stages         map[model.OperationStage]Step
+	pipelines      Pipelines
 	operation      model.OperationType
 	failureHandler FailureHandler
 	directorClient director.DirectorClient
+	eventPublisher events.Publisher
+
+	errorClassifier *classification.Classifier
+	// retries counts consecutive retries of operations for the exponential backoff
+	retries      map[string]int
+	retriesMutex sync.Mutex
+
+	// diagnosticsCollector captures the Shoot state when a stage fails, it is optional
+	diagnosticsCollector DiagnosticsCollector
+	// diagnosedStages holds the stage of each operation for which the diagnostics were captured
+	diagnosedStages      map[string]model.OperationStage
+	diagnosedStagesMutex sync.Mutex
 
 	log logrus.FieldLogger
 }
@@ -64,6 +111,9 @@ func (e *Executor) Execute(operationID string) ProcessingResult {
 
 	if operation.State != model.InProgress {
 		log.Infof("Operation not InProgress. State: %s", operation.State)
+		// The operation could be finished or canceled outside of the executor
+		e.resetRetries(operation.ID)
+		e.forgetDiagnosedStage(operation.ID)
 		return ProcessingResult{Requeue: false}
 	}
 
@@ -77,22 +127,41 @@ func (e *Executor) Execute(operationID string) ProcessingResult {
 
 	if operation.Type == e.operation {
 		requeue, delay, err := e.process(operation, cluster, log)
-		e.updateOperationLastError(log, operation.ID, err)
-		if err != nil {
-			nonRecoverable := NonRecoverableError{}
-			if errors.As(err, &nonRecoverable) {
-				log.Errorf("unrecoverable error occurred while processing operation: %s", err.Error())
-				e.handleOperationFailure(operation, cluster, log)
-				e.updateOperationStatus(log, operation.ID, nonRecoverable.Error(), model.Failed, time.Now())
-				e.setRuntimeStatusCondition(log, cluster.ID, cluster.Tenant)
-
-				return ProcessingResult{Requeue: false}
+		if err == nil {
+			e.updateOperationLastError(log, operation.ID, nil, "")
+			e.resetRetries(operation.ID)
+			if !requeue {
+				e.forgetDiagnosedStage(operation.ID)
 			}
 
-			return ProcessingResult{Requeue: true, Delay: defaultDelay}
+			return ProcessingResult{Requeue: requeue, Delay: delay}
 		}
 
-		return ProcessingResult{Requeue: requeue, Delay: delay}
+		nonRecoverable := NonRecoverableError{}
+		recoverable := !errors.As(err, &nonRecoverable)
+		classified := e.errorClassifier.Classify(ConvertToAppError(err), recoverable)
+		metrics.ObserveClassifiedError(operation.Type, string(classified.Action), classified.Code)
+		operation.LastError = e.updateOperationLastError(log, operation.ID, err, classified.Action)
+		e.captureDiagnostics(log, operation, cluster, classified.Action == classification.FailFast)
+
+		switch classified.Action {
+		case classification.FailFast:
+			log.Errorf("unrecoverable error occurred while processing operation: %s", err.Error())
+			e.resetRetries(operation.ID)
+			e.forgetDiagnosedStage(operation.ID)
+			e.handleOperationFailure(operation, cluster, log)
+			e.updateOperationStatus(log, operation, err.Error(), model.Failed, time.Now())
+			e.setRuntimeStatusCondition(log, cluster.ID, cluster.Tenant)
+
+			return ProcessingResult{Requeue: false}
+		case classification.WaitForUser:
+			log.Warnf("waiting for user action to resolve error: %s", err.Error())
+			e.resetRetries(operation.ID)
+
+			return ProcessingResult{Requeue: true, Delay: e.errorClassifier.WaitForUserDelay()}
+		default:
+			return ProcessingResult{Requeue: true, Delay: e.errorClassifier.RetryDelay(e.nextRetry(operation.ID))}
+		}
 	}
 
 	return ProcessingResult{
@@ -102,6 +171,61 @@ func (e *Executor) Execute(operationID string) ProcessingResult {
 }
 
 func (e *Executor) process(operation model.Operation, cluster model.Cluster, logger logrus.FieldLogger) (bool, time.Duration, error) {
+	pipeline, err := e.pipelineFor(operation, logger)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if pipeline != nil {
+		return e.processPipeline(pipeline, operation, cluster, logger.WithField("PipelineVersion", pipeline.Version()))
+	}
+
+	return e.processStages(operation, cluster, logger)
+}
+
+// pipelineFor returns nil for operations processed with the linear chain of stages
+func (e *Executor) pipelineFor(operation model.Operation, logger logrus.FieldLogger) (*Pipeline, error) {
+	if operation.PipelineVersion == model.LegacyPipelineVersion {
+		return nil, nil
+	}
+
+	if operation.PipelineVersion == "" {
+		current, found := e.pipelines.Current()
+		if !found {
+			return nil, nil
+		}
+
+		// The pipeline would run the stages before the start stage of the operation, e.g. the cleanup of a cluster
+		// which is already being deleted, so such operations are processed with the linear chain of stages
+		if operation.Stage != current.FirstStage() {
+			dberr := e.dbSession.UpdateOperationPipelineVersion(operation.ID, model.LegacyPipelineVersion)
+			if dberr != nil {
+				return nil, dberr
+			}
+			logger.Infof("Processing operation started from stage %s with stages", operation.Stage)
+
+			return nil, nil
+		}
+
+		dberr := e.dbSession.UpdateOperationPipelineVersion(operation.ID, current.Version())
+		if dberr != nil {
+			return nil, dberr
+		}
+		logger.Infof("Processing operation with pipeline %s", current.Version())
+
+		return current, nil
+	}
+
+	pipeline, found := e.pipelines.Find(operation.PipelineVersion)
+	if !found {
+		msg := fmt.Sprintf("error: pipeline %s not found", operation.PipelineVersion)
+		return nil, NewNonRecoverableError(apperrors.Internal(msg).SetReason(apperrors.ErrProvisionerPipelineNotFound))
+	}
+
+	return pipeline, nil
+}
+
+func (e *Executor) processStages(operation model.Operation, cluster model.Cluster, logger logrus.FieldLogger) (bool, time.Duration, error) {
 
 	step, found := e.stages[operation.Stage]
 	if !found {
@@ -130,13 +254,14 @@ func (e *Executor) process(operation model.Operation, cluster model.Cluster, log
 
 		if result.Stage == model.FinishedStage {
 			log.Infof("Finished processing operation")
-			e.updateOperationStage(log, operation.ID, "Provisioning steps finished", model.FinishedStage, time.Now())
+			e.updateOperationStage(log, operation, "Provisioning steps finished", model.FinishedStage, time.Now())
+			operation.Stage = model.FinishedStage
 			break
 		}
 
 		if result.Stage != step.Name() {
 			transitionTime := time.Now()
-			e.updateOperationStage(log, operation.ID, fmt.Sprintf("Operation in progress. Stage %s", result.Stage), result.Stage, transitionTime)
+			e.updateOperationStage(log, operation, fmt.Sprintf("Operation in progress. Stage %s", result.Stage), result.Stage, transitionTime)
 			step = e.stages[result.Stage]
 			operation.Stage = result.Stage
 			operation.LastTransition = &transitionTime
@@ -149,11 +274,141 @@ func (e *Executor) process(operation model.Operation, cluster model.Cluster, log
 	}
 
 	logger.Infof("Setting operation to succeeded")
-	e.updateOperationStatus(logger, operation.ID, "Operation succeeded", model.Succeeded, time.Now())
+	e.updateOperationStatus(logger, operation, "Operation succeeded", model.Succeeded, time.Now())
+
+	return false, 0, nil
+}
+
+func (e *Executor) processPipeline(pipeline *Pipeline, operation model.Operation, cluster model.Cluster, logger logrus.FieldLogger) (bool, time.Duration, error) {
+	for {
+		stageStatuses, dberr := e.dbSession.GetOperationStages(operation.ID)
+		if dberr != nil {
+			return false, 0, dberr
+		}
+
+		statuses := make(map[model.OperationStage]model.OperationStageStatus, len(stageStatuses))
+		for _, status := range stageStatuses {
+			statuses[status.Stage] = status
+		}
+
+		run, skip, finished := pipeline.Next(cluster, statuses)
+		if finished {
+			logger.Infof("Finished processing operation")
+			e.updateOperationStage(logger, operation, "Provisioning steps finished", model.FinishedStage, time.Now())
+			break
+		}
+
+		if len(skip) > 0 {
+			for _, stage := range skip {
+				logger.WithField("Stage", stage.Step.Name()).Infof("Skipping stage")
+				dberr := e.dbSession.InsertOperationStage(operation.ID, stage.Step.Name(), model.StageSkipped, time.Now())
+				if dberr != nil {
+					return false, 0, dberr
+				}
+			}
+			// Skipped stages may unblock the ones depending on them
+			continue
+		}
+
+		waiting, delay, err := e.runStages(run, statuses, operation, cluster, logger)
+		if err != nil {
+			return false, 0, err
+		}
+
+		if waiting && delay > 0 {
+			return true, delay, nil
+		}
+	}
+
+	logger.Infof("Setting operation to succeeded")
+	e.updateOperationStatus(logger, operation, "Operation succeeded", model.Succeeded, time.Now())
 
 	return false, 0, nil
 }
 
+type stageRun struct {
+	result StageResult
+	err    error
+}
+
+// runStages runs the stages in parallel and reports whether any of them is still waiting, with the shortest requested delay
+func (e *Executor) runStages(stages []PipelineStage, statuses map[model.OperationStage]model.OperationStageStatus, operation model.Operation, cluster model.Cluster, logger logrus.FieldLogger) (bool, time.Duration, error) {
+	now := time.Now()
+
+	var started []string
+	for _, stage := range stages {
+		name := stage.Step.Name()
+
+		status, found := statuses[name]
+		if !found {
+			dberr := e.dbSession.InsertOperationStage(operation.ID, name, model.StageInProgress, now)
+			if dberr != nil {
+				return false, 0, dberr
+			}
+			status = model.OperationStageStatus{Stage: name, State: model.StageInProgress, StartTimestamp: now}
+			started = append(started, string(name))
+		}
+
+		if now.Sub(status.StartTimestamp) > stage.Step.TimeLimit() {
+			logger.WithField("Stage", name).Errorf("Timeout reached for operation")
+			return false, 0, NewNonRecoverableError(apperrors.Internal("error: timeout while processing operation").SetReason(apperrors.ErrProvisionerTimeout))
+		}
+	}
+
+	if len(started) > 0 {
+		e.updateOperationStage(logger, operation, fmt.Sprintf("Operation in progress. Stage %s", strings.Join(started, ", ")), model.OperationStage(started[0]), now)
+	}
+
+	results := make([]stageRun, len(stages))
+	var waitGroup sync.WaitGroup
+	for i, stage := range stages {
+		waitGroup.Add(1)
+		go func(i int, step Step) {
+			defer waitGroup.Done()
+
+			log := logger.WithField("Stage", step.Name())
+			log.Infof("Starting processing")
+			result, err := step.Run(cluster, operation, log)
+			results[i] = stageRun{result: result, err: err}
+		}(i, stage.Step)
+	}
+	waitGroup.Wait()
+
+	var waiting bool
+	var delay time.Duration
+	var runErr error
+	for i, stage := range stages {
+		name := stage.Step.Name()
+		log := logger.WithField("Stage", name)
+		run := results[i]
+
+		if run.err != nil {
+			log.Warnf("error while processing operation, stage failed: %s", run.err.Error())
+			if runErr == nil || errors.As(run.err, &NonRecoverableError{}) {
+				runErr = run.err
+			}
+			continue
+		}
+
+		// Steps report completion by returning any stage other than their own
+		if run.result.Stage == name {
+			if !waiting || run.result.Delay < delay {
+				delay = run.result.Delay
+			}
+			waiting = true
+			continue
+		}
+
+		dberr := e.dbSession.FinishOperationStage(operation.ID, name, model.StageSucceeded, time.Now())
+		if dberr != nil {
+			return false, 0, dberr
+		}
+		log.Infof("Stage completed")
+	}
+
+	return waiting, delay, runErr
+}
+
 func (e *Executor) timeoutReached(operation model.Operation, timeout time.Duration) bool {
 
 	lastTimestamp := operation.StartTimestamp
@@ -175,16 +430,22 @@ func (e *Executor) handleOperationFailure(operation model.Operation, cluster mod
 	}
 }
 
-func (e *Executor) updateOperationStatus(log logrus.FieldLogger, id, message string, state model.OperationState, t time.Time) {
+func (e *Executor) updateOperationStatus(log logrus.FieldLogger, operation model.Operation, message string, state model.OperationState, t time.Time) {
 	err := retry.Do(func() error {
-		return e.dbSession.UpdateOperationState(id, message, state, t)
+		return e.dbSession.UpdateOperationState(operation.ID, message, state, t)
 	}, retry.Attempts(5))
 	if err != nil {
 		log.Infof("Cannot set operation status to %s: %s", state, err.Error())
+		return
 	}
+
+	operation.State = state
+	operation.Message = message
+	operation.EndTimestamp = &t
+	e.publishEvent(operation, t)
 }
 
-func (e *Executor) updateOperationLastError(log logrus.FieldLogger, id string, runErr error) {
+func (e *Executor) updateOperationLastError(log logrus.FieldLogger, id string, runErr error, action classification.Action) model.LastError {
 	var lastErr model.LastError
 
 	if runErr != nil {
@@ -193,19 +454,93 @@ func (e *Executor) updateOperationLastError(log logrus.FieldLogger, id string, r
 			ErrMessage: runErr.Error(),
 			Reason:     string(appErr.Reason()),
 			Component:  string(appErr.Component()),
+			Action:     string(action),
 		}
 	}
 
 	err := retry.Do(func() error {
-		return e.dbSession.UpdateOperationLastError(id, lastErr.ErrMessage, lastErr.Reason, lastErr.Component)
+		return e.dbSession.UpdateOperationLastError(id, lastErr.ErrMessage, lastErr.Reason, lastErr.Component, lastErr.Action)
 	}, retry.Attempts(5))
 
 	if err != nil {
 		log.Infof("Cannot set operation last error to %v: %s", lastErr, err.Error())
 	}
+
+	return lastErr
+}
+
+// captureDiagnostics captures the diagnostics when the operation fails or on the first failure of its stage,
+// so that the Shoot and its events are not fetched on every retry
+func (e *Executor) captureDiagnostics(log logrus.FieldLogger, operation model.Operation, cluster model.Cluster, failed bool) {
+	if e.diagnosticsCollector == nil {
+		return
+	}
+
+	// The operation read at the start of processing may be behind the stage which failed
+	if current, dberr := e.dbSession.GetOperation(operation.ID); dberr == nil {
+		operation = current
+	}
+
+	if !e.markStageDiagnosed(operation.ID, operation.Stage) && !failed {
+		return
+	}
+
+	diagnostics, err := e.diagnosticsCollector.Collect(cluster, operation)
+	if err != nil {
+		log.Warnf("Cannot capture operation diagnostics: %s", err.Error())
+		return
+	}
+
+	dberr := e.dbSession.UpdateOperationDiagnostics(operation.ID, diagnostics)
+	if dberr != nil {
+		log.Warnf("Cannot store operation diagnostics: %s", dberr.Error())
+	}
+}
+
+// markStageDiagnosed returns false when the diagnostics of the stage were already captured
+func (e *Executor) markStageDiagnosed(operationID string, stage model.OperationStage) bool {
+	e.diagnosedStagesMutex.Lock()
+	defer e.diagnosedStagesMutex.Unlock()
+
+	if e.diagnosedStages[operationID] == stage {
+		return false
+	}
+	e.diagnosedStages[operationID] = stage
+
+	return true
+}
+
+func (e *Executor) forgetDiagnosedStage(operationID string) {
+	e.diagnosedStagesMutex.Lock()
+	defer e.diagnosedStagesMutex.Unlock()
+
+	delete(e.diagnosedStages, operationID)
+}
+
+// nextRetry returns the number of previous consecutive retries of the operation
+func (e *Executor) nextRetry(operationID string) int {
+	e.retriesMutex.Lock()
+	defer e.retriesMutex.Unlock()
+
+	retries := e.retries[operationID]
+	e.retries[operationID] = retries + 1
+
+	return retries
+}
+
+func (e *Executor) resetRetries(operationID string) {
+	e.retriesMutex.Lock()
+	defer e.retriesMutex.Unlock()
+
+	delete(e.retries, operationID)
 }
 
 func (e *Executor) setRuntimeStatusCondition(log logrus.FieldLogger, id, tenant string) {
+	// Without Compass the Runtimes are not registered in Director
+	if e.directorClient == nil {
+		return
+	}
+
 	err := retry.Do(func() error {
 		return e.directorClient.SetRuntimeStatusCondition(id, graphql.RuntimeStatusConditionFailed, tenant)
 	}, retry.Attempts(5), retry.Delay(backOffDirectorDelay), retry.DelayType(retry.BackOffDelay))
@@ -214,11 +549,25 @@ func (e *Executor) setRuntimeStatusCondition(log logrus.FieldLogger, id, tenant
 	}
 }
 
-func (e *Executor) updateOperationStage(log logrus.FieldLogger, id, message string, stage model.OperationStage, t time.Time) {
+func (e *Executor) updateOperationStage(log logrus.FieldLogger, operation model.Operation, message string, stage model.OperationStage, t time.Time) {
 	err := retry.Do(func() error {
-		return e.dbSession.TransitionOperation(id, message, stage, t)
+		return e.dbSession.TransitionOperation(operation.ID, message, stage, t)
 	}, retry.Attempts(5))
 	if err != nil {
 		log.Infof("Cannot modify operation stage to %s: %s", stage, err.Error())
+		return
 	}
+
+	operation.Stage = stage
+	operation.Message = message
+	operation.LastTransition = &t
+	e.publishEvent(operation, t)
+}
+
+func (e *Executor) publishEvent(operation model.Operation, t time.Time) {
+	if e.eventPublisher == nil {
+		return
+	}
+
+	e.eventPublisher.Publish(events.OperationEvent{Operation: operation, Timestamp: t})
 }
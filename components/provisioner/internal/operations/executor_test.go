@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -10,8 +11,11 @@ import (
 	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
 
 	directorMocks "github.com/kyma-project/control-plane/components/provisioner/internal/director/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/classification"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
+	operationsMocks "github.com/kyma-project/control-plane/components/provisioner/internal/operations/mocks"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
@@ -19,6 +23,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -52,7 +57,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 			Return(nil)
 		dbSession.On("UpdateOperationState", operationId, "Operation succeeded", model.Succeeded, mock.AnythingOfType("time.Time")).
 			Return(nil)
-		dbSession.On("UpdateOperationLastError", operationId, "", "", "").Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
 
 		mockStage := NewMockStep(model.WaitingForInstallation, model.FinishedStage, 10*time.Second, 10*time.Second)
 
@@ -62,7 +67,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		directorClient := &directorMocks.DirectorClient{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient, ExecutorOptions{EventPublisher: events.NewBroker()})
 
 		// when
 		result := executor.Execute(operationId)
@@ -72,13 +77,58 @@ func TestStagesExecutor_Execute(t *testing.T) {
 		assert.True(t, mockStage.called)
 	})
 
+	t.Run("should publish events on stage transition and operation success", func(t *testing.T) {
+		// given
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("TransitionOperation", operationId, "Operation in progress. Stage ConnectRuntimeAgent", model.ConnectRuntimeAgent, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("TransitionOperation", operationId, "Provisioning steps finished", model.FinishedStage, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "Operation succeeded", model.Succeeded, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
+
+		installationStages := map[model.OperationStage]Step{
+			model.WaitingForInstallation: NewMockStep(model.WaitingForInstallation, model.ConnectRuntimeAgent, 0, 10*time.Second),
+			model.ConnectRuntimeAgent:    NewMockStep(model.ConnectRuntimeAgent, model.FinishedStage, 0, 10*time.Second),
+		}
+
+		broker := events.NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		operationEvents := broker.SubscribeOperation(ctx, operationId)
+		runtimeEvents := broker.SubscribeRuntime(ctx, clusterId)
+
+		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{EventPublisher: broker})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		for _, ch := range []<-chan events.OperationEvent{operationEvents, runtimeEvents} {
+			stageEvent := <-ch
+			assert.Equal(t, model.ConnectRuntimeAgent, stageEvent.Operation.Stage)
+			assert.Equal(t, model.InProgress, stageEvent.Operation.State)
+
+			finishedEvent := <-ch
+			assert.Equal(t, model.FinishedStage, finishedEvent.Operation.Stage)
+
+			succeededEvent := <-ch
+			assert.Equal(t, model.Succeeded, succeededEvent.Operation.State)
+			assert.Equal(t, "Operation succeeded", succeededEvent.Operation.Message)
+		}
+	})
+
 	t.Run("should requeue operation if error occurred", func(t *testing.T) {
 		// given
 		runErr := fmt.Errorf("error")
 		dbSession := &mocks.ReadWriteSession{}
 		dbSession.On("GetOperation", operationId).Return(operation, nil)
 		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
-		dbSession.On("UpdateOperationLastError", operationId, runErr.Error(), string(apperrors.ErrProvisionerInternal), string(apperrors.ErrProvisioner)).Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, runErr.Error(), string(apperrors.ErrProvisionerInternal), string(apperrors.ErrProvisioner), string(classification.Retry)).Return(nil)
 
 		mockStage := NewErrorStep(model.WaitingForClusterCreation, runErr, time.Second*10)
 
@@ -88,7 +138,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		directorClient := &directorMocks.DirectorClient{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient, ExecutorOptions{EventPublisher: events.NewBroker()})
 
 		// when
 		result := executor.Execute(operationId)
@@ -106,7 +156,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
 		dbSession.On("UpdateOperationState", operationId, "something, gardener error", model.Failed, mock.AnythingOfType("time.Time")).
 			Return(nil)
-		dbSession.On("UpdateOperationLastError", operationId, "something, gardener error", "ERR_INFRA_QUOTA_EXCEEDED", string(apperrors.ErrGardener)).Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "something, gardener error", "ERR_INFRA_QUOTA_EXCEEDED", string(apperrors.ErrGardener), string(classification.FailFast)).Return(nil)
 
 		mockStage := NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second)
 
@@ -119,7 +169,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		failureHandler := MockFailureHandler{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient, ExecutorOptions{EventPublisher: events.NewBroker()})
 
 		// when
 		result := executor.Execute(operationId)
@@ -138,7 +188,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
 		dbSession.On("UpdateOperationState", operationId, "kyma installation: error", model.Failed, mock.AnythingOfType("time.Time")).
 			Return(nil)
-		dbSession.On("UpdateOperationLastError", operationId, "kyma installation: error", "istio", string(apperrors.ErrKymaInstaller)).Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "kyma installation: error", "istio", string(apperrors.ErrKymaInstaller), string(classification.FailFast)).Return(nil)
 
 		mockStage := NewErrorStep(model.StartingInstallation, runErr, 10*time.Second)
 
@@ -151,7 +201,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		failureHandler := MockFailureHandler{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient, ExecutorOptions{EventPublisher: events.NewBroker()})
 
 		// when
 		result := executor.Execute(operationId)
@@ -171,7 +221,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 			Return(nil)
 		dbSession.On("UpdateOperationState", operationId, "error: timeout while processing operation", model.Failed, mock.AnythingOfType("time.Time")).
 			Return(nil)
-		dbSession.On("UpdateOperationLastError", operationId, "error: timeout while processing operation", string(apperrors.ErrProvisionerTimeout), string(apperrors.ErrProvisioner)).Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "error: timeout while processing operation", string(apperrors.ErrProvisionerTimeout), string(apperrors.ErrProvisioner), string(classification.FailFast)).Return(nil)
 
 		mockStage := NewMockStep(model.WaitingForInstallation, model.ConnectRuntimeAgent, 0, 0*time.Second)
 
@@ -184,7 +234,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		failureHandler := MockFailureHandler{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient, ExecutorOptions{EventPublisher: events.NewBroker()})
 
 		// when
 		result := executor.Execute(operationId)
@@ -196,6 +246,431 @@ func TestStagesExecutor_Execute(t *testing.T) {
 	})
 }
 
+func TestStagesExecutor_ExecutePipeline(t *testing.T) {
+
+	tNow := time.Now()
+
+	operation := model.Operation{
+		ID:             operationId,
+		Type:           model.Provision,
+		StartTimestamp: tNow,
+		State:          model.InProgress,
+		ClusterID:      clusterId,
+		Stage:          model.WaitingForClusterCreation,
+		LastTransition: &tNow,
+	}
+
+	cluster := model.Cluster{ID: clusterId}
+
+	succeeded := func(stage model.OperationStage) model.OperationStageStatus {
+		return model.OperationStageStatus{Stage: stage, State: model.StageSucceeded, StartTimestamp: tNow, EndTimestamp: &tNow}
+	}
+
+	t.Run("should bind new operation to current pipeline and run independent stages in parallel", func(t *testing.T) {
+		// given
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationPipelineVersion", operationId, "v2").Return(nil)
+		dbSession.On("GetOperationStages", operationId).Return([]model.OperationStageStatus{}, nil).Once()
+		dbSession.On("GetOperationStages", operationId).Return([]model.OperationStageStatus{
+			succeeded(model.WaitingForClusterCreation),
+		}, nil).Once()
+		dbSession.On("GetOperationStages", operationId).Return([]model.OperationStageStatus{
+			succeeded(model.WaitingForClusterCreation),
+			succeeded(model.CreatingBindingsForOperators),
+			succeeded(model.ConnectRuntimeAgent),
+		}, nil).Once()
+		for _, stage := range []model.OperationStage{model.WaitingForClusterCreation, model.CreatingBindingsForOperators, model.ConnectRuntimeAgent} {
+			dbSession.On("InsertOperationStage", operationId, stage, model.StageInProgress, mock.AnythingOfType("time.Time")).Return(nil)
+			dbSession.On("FinishOperationStage", operationId, stage, model.StageSucceeded, mock.AnythingOfType("time.Time")).Return(nil)
+		}
+		dbSession.On("TransitionOperation", operationId, "Operation in progress. Stage WaitingForClusterCreation", model.WaitingForClusterCreation, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("TransitionOperation", operationId, "Operation in progress. Stage CreatingBindingsForOperators, ConnectRuntimeAgent", model.CreatingBindingsForOperators, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("TransitionOperation", operationId, "Provisioning steps finished", model.FinishedStage, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "Operation succeeded", model.Succeeded, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
+
+		clusterCreation := NewMockStep(model.WaitingForClusterCreation, model.CreatingBindingsForOperators, 0, 10*time.Second)
+		bindings := NewMockStep(model.CreatingBindingsForOperators, model.ConnectRuntimeAgent, 0, 10*time.Second)
+		agent := NewMockStep(model.ConnectRuntimeAgent, model.FinishedStage, 0, 10*time.Second)
+
+		previous, err := NewPipelineBuilder("v1").Stage(agent).Build()
+		require.NoError(t, err)
+		current, err := NewPipelineBuilder("v2").
+			Stage(clusterCreation).
+			Stage(bindings, model.WaitingForClusterCreation).
+			Stage(agent, model.WaitingForClusterCreation).
+			Build()
+		require.NoError(t, err)
+
+		executor := NewExecutor(dbSession, model.Provision, nil, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{Pipelines: Pipelines{previous, current}, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.True(t, clusterCreation.called)
+		assert.True(t, bindings.called)
+		assert.True(t, agent.called)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should process new operation started from later stage with stages", func(t *testing.T) {
+		// given
+		laterOperation := operation
+		laterOperation.Stage = model.ConnectRuntimeAgent
+
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(laterOperation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationPipelineVersion", operationId, model.LegacyPipelineVersion).Return(nil)
+		dbSession.On("TransitionOperation", operationId, "Provisioning steps finished", model.FinishedStage, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "Operation succeeded", model.Succeeded, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
+
+		clusterCreation := NewMockStep(model.WaitingForClusterCreation, model.ConnectRuntimeAgent, 0, 10*time.Second)
+		agent := NewMockStep(model.ConnectRuntimeAgent, model.FinishedStage, 0, 10*time.Second)
+
+		pipeline, err := NewPipelineBuilder("v1").
+			Stage(clusterCreation).
+			Stage(agent, model.WaitingForClusterCreation).
+			Build()
+		require.NoError(t, err)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: clusterCreation,
+			model.ConnectRuntimeAgent:       agent,
+		}
+		executor := NewExecutor(dbSession, model.Provision, stages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{Pipelines: Pipelines{pipeline}, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.False(t, clusterCreation.called)
+		assert.True(t, agent.called)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should skip stage when predicate is true", func(t *testing.T) {
+		// given
+		boundOperation := operation
+		boundOperation.PipelineVersion = "v1"
+
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(boundOperation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("GetOperationStages", operationId).Return([]model.OperationStageStatus{
+			succeeded(model.WaitingForClusterCreation),
+		}, nil).Once()
+		dbSession.On("GetOperationStages", operationId).Return([]model.OperationStageStatus{
+			succeeded(model.WaitingForClusterCreation),
+			{Stage: model.ConnectRuntimeAgent, State: model.StageSkipped, StartTimestamp: tNow, EndTimestamp: &tNow},
+		}, nil).Once()
+		dbSession.On("InsertOperationStage", operationId, model.ConnectRuntimeAgent, model.StageSkipped, mock.AnythingOfType("time.Time")).Return(nil)
+		dbSession.On("TransitionOperation", operationId, "Provisioning steps finished", model.FinishedStage, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "Operation succeeded", model.Succeeded, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
+
+		clusterCreation := NewMockStep(model.WaitingForClusterCreation, model.ConnectRuntimeAgent, 0, 10*time.Second)
+		agent := NewMockStep(model.ConnectRuntimeAgent, model.FinishedStage, 0, 10*time.Second)
+
+		pipeline, err := NewPipelineBuilder("v1").
+			Stage(clusterCreation).
+			ConditionalStage(agent, func(model.Cluster) bool { return true }, model.WaitingForClusterCreation).
+			Build()
+		require.NoError(t, err)
+
+		executor := NewExecutor(dbSession, model.Provision, nil, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{Pipelines: Pipelines{pipeline}, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.False(t, agent.called)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should requeue when parallel stage is still waiting", func(t *testing.T) {
+		// given
+		boundOperation := operation
+		boundOperation.PipelineVersion = "v1"
+
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(boundOperation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("GetOperationStages", operationId).Return([]model.OperationStageStatus{
+			succeeded(model.WaitingForClusterCreation),
+			{Stage: model.ConnectRuntimeAgent, State: model.StageInProgress, StartTimestamp: tNow},
+		}, nil)
+		dbSession.On("InsertOperationStage", operationId, model.CreatingBindingsForOperators, model.StageInProgress, mock.AnythingOfType("time.Time")).Return(nil)
+		dbSession.On("TransitionOperation", operationId, "Operation in progress. Stage CreatingBindingsForOperators", model.CreatingBindingsForOperators, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("FinishOperationStage", operationId, model.CreatingBindingsForOperators, model.StageSucceeded, mock.AnythingOfType("time.Time")).Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
+
+		clusterCreation := NewMockStep(model.WaitingForClusterCreation, model.CreatingBindingsForOperators, 0, 10*time.Second)
+		bindings := NewMockStep(model.CreatingBindingsForOperators, model.FinishedStage, 0, 10*time.Second)
+		agent := NewMockStep(model.ConnectRuntimeAgent, model.ConnectRuntimeAgent, 5*time.Second, 10*time.Second)
+
+		pipeline, err := NewPipelineBuilder("v1").
+			Stage(clusterCreation).
+			Stage(bindings, model.WaitingForClusterCreation).
+			Stage(agent, model.WaitingForClusterCreation).
+			Build()
+		require.NoError(t, err)
+
+		executor := NewExecutor(dbSession, model.Provision, nil, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{Pipelines: Pipelines{pipeline}, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.True(t, result.Requeue)
+		assert.Equal(t, 5*time.Second, result.Delay)
+		assert.False(t, clusterCreation.called)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should fail operation when stage timeout is reached", func(t *testing.T) {
+		// given
+		boundOperation := operation
+		boundOperation.PipelineVersion = "v1"
+
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(boundOperation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("GetOperationStages", operationId).Return([]model.OperationStageStatus{
+			{Stage: model.WaitingForClusterCreation, State: model.StageInProgress, StartTimestamp: tNow.Add(-time.Hour)},
+		}, nil)
+		dbSession.On("UpdateOperationState", operationId, "error: timeout while processing operation", model.Failed, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "error: timeout while processing operation", string(apperrors.ErrProvisionerTimeout), string(apperrors.ErrProvisioner), string(classification.FailFast)).Return(nil)
+
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("SetRuntimeStatusCondition", clusterId, graphql.RuntimeStatusConditionFailed, mock.Anything).Return(nil)
+
+		clusterCreation := NewMockStep(model.WaitingForClusterCreation, model.FinishedStage, 0, 10*time.Second)
+
+		pipeline, err := NewPipelineBuilder("v1").Stage(clusterCreation).Build()
+		require.NoError(t, err)
+
+		failureHandler := MockFailureHandler{}
+		executor := NewExecutor(dbSession, model.Provision, nil, &failureHandler, directorClient, ExecutorOptions{Pipelines: Pipelines{pipeline}, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.False(t, clusterCreation.called)
+		assert.True(t, failureHandler.called)
+	})
+
+	t.Run("should fail operation started on unknown pipeline", func(t *testing.T) {
+		// given
+		boundOperation := operation
+		boundOperation.PipelineVersion = "removed"
+
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(boundOperation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationState", operationId, "error: pipeline removed not found", model.Failed, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "error: pipeline removed not found", string(apperrors.ErrProvisionerPipelineNotFound), string(apperrors.ErrProvisioner), string(classification.FailFast)).Return(nil)
+
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("SetRuntimeStatusCondition", clusterId, graphql.RuntimeStatusConditionFailed, mock.Anything).Return(nil)
+
+		pipeline, err := NewPipelineBuilder("v1").Stage(NewMockStep(model.WaitingForClusterCreation, model.FinishedStage, 0, 10*time.Second)).Build()
+		require.NoError(t, err)
+
+		failureHandler := MockFailureHandler{}
+		executor := NewExecutor(dbSession, model.Provision, nil, &failureHandler, directorClient, ExecutorOptions{Pipelines: Pipelines{pipeline}, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.True(t, failureHandler.called)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should process legacy operation with linear stages", func(t *testing.T) {
+		// given
+		legacyOperation := operation
+		legacyOperation.PipelineVersion = model.LegacyPipelineVersion
+
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(legacyOperation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("TransitionOperation", operationId, "Provisioning steps finished", model.FinishedStage, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "Operation succeeded", model.Succeeded, mock.AnythingOfType("time.Time")).
+			Return(nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
+
+		clusterCreation := NewMockStep(model.WaitingForClusterCreation, model.FinishedStage, 0, 10*time.Second)
+		pipeline, err := NewPipelineBuilder("v1").Stage(clusterCreation).Build()
+		require.NoError(t, err)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: clusterCreation,
+		}
+
+		executor := NewExecutor(dbSession, model.Provision, stages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{Pipelines: Pipelines{pipeline}, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.True(t, clusterCreation.called)
+		dbSession.AssertNotCalled(t, "GetOperationStages", mock.Anything)
+	})
+}
+
+func TestStagesExecutor_ExecuteWithErrorClassification(t *testing.T) {
+	tNow := time.Now()
+
+	operation := model.Operation{
+		ID:             operationId,
+		Type:           model.Provision,
+		StartTimestamp: tNow,
+		State:          model.InProgress,
+		ClusterID:      clusterId,
+		Stage:          model.WaitingForClusterCreation,
+		LastTransition: &tNow,
+	}
+
+	cluster := model.Cluster{ID: clusterId}
+
+	classifier := classification.NewClassifier(classification.DefaultRules(), classification.Config{
+		RetryInitialDelay: time.Second,
+		RetryMaxDelay:     3 * time.Second,
+		WaitForUserDelay:  time.Minute,
+	})
+
+	t.Run("should retry with exponential backoff", func(t *testing.T) {
+		// given
+		runErr := apperrors.External("rate limits exceeded").SetComponent(apperrors.ErrGardener).SetReason("ERR_INFRA_RATE_LIMITS_EXCEEDED")
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, runErr.Error(), "ERR_INFRA_RATE_LIMITS_EXCEEDED", string(apperrors.ErrGardener), string(classification.Retry)).Return(nil)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second),
+		}
+
+		executor := NewExecutor(dbSession, model.Provision, stages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{ErrorClassifier: classifier, EventPublisher: events.NewBroker()})
+
+		// when
+		var delays []time.Duration
+		for i := 0; i < 4; i++ {
+			result := executor.Execute(operationId)
+			assert.True(t, result.Requeue)
+			delays = append(delays, result.Delay)
+		}
+
+		// then
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}, delays)
+	})
+
+	t.Run("should wait for user action instead of retrying", func(t *testing.T) {
+		// given
+		runErr := apperrors.External("gardener error").SetComponent(apperrors.ErrGardener).SetReason("ERR_INFRA_QUOTA_EXCEEDED")
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, "gardener error", "ERR_INFRA_QUOTA_EXCEEDED", string(apperrors.ErrGardener), string(classification.WaitForUser)).Return(nil)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second),
+		}
+		failureHandler := MockFailureHandler{}
+
+		executor := NewExecutor(dbSession, model.Provision, stages, &failureHandler, &directorMocks.DirectorClient{}, ExecutorOptions{ErrorClassifier: classifier, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.True(t, result.Requeue)
+		assert.Equal(t, time.Minute, result.Delay)
+		assert.False(t, failureHandler.called)
+		dbSession.AssertNotCalled(t, "UpdateOperationState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail failed Shoot creation with quota error code", func(t *testing.T) {
+		// given
+		runErr := NewNonRecoverableError(apperrors.External("cluster provisioning failed").SetComponent(apperrors.ErrGardener).SetReason("ERR_INFRA_QUOTA_EXCEEDED"))
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, "cluster provisioning failed", "ERR_INFRA_QUOTA_EXCEEDED", string(apperrors.ErrGardener), string(classification.FailFast)).Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "cluster provisioning failed", model.Failed, mock.AnythingOfType("time.Time")).Return(nil)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second),
+		}
+		failureHandler := MockFailureHandler{}
+
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("SetRuntimeStatusCondition", clusterId, graphql.RuntimeStatusConditionFailed, mock.AnythingOfType("string")).Return(nil)
+
+		executor := NewExecutor(dbSession, model.Provision, stages, &failureHandler, directorClient, ExecutorOptions{ErrorClassifier: classifier, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.True(t, failureHandler.called)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should fail fast on configuration problem", func(t *testing.T) {
+		// given
+		runErr := apperrors.External("gardener error").SetComponent(apperrors.ErrGardener).SetReason("ERR_INFRA_RATE_LIMITS_EXCEEDED, ERR_CONFIGURATION_PROBLEM")
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, "gardener error", "ERR_INFRA_RATE_LIMITS_EXCEEDED, ERR_CONFIGURATION_PROBLEM", string(apperrors.ErrGardener), string(classification.FailFast)).Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "gardener error", model.Failed, mock.AnythingOfType("time.Time")).Return(nil)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second),
+		}
+		failureHandler := MockFailureHandler{}
+
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("SetRuntimeStatusCondition", clusterId, graphql.RuntimeStatusConditionFailed, mock.AnythingOfType("string")).Return(nil)
+
+		executor := NewExecutor(dbSession, model.Provision, stages, &failureHandler, directorClient, ExecutorOptions{ErrorClassifier: classifier, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.True(t, failureHandler.called)
+	})
+}
+
 type mockStep struct {
 	name      model.OperationStage
 	next      model.OperationStage
@@ -291,3 +766,140 @@ func TestConvertToAppError(t *testing.T) {
 		assert.Equal(t, expectK8sErr, apperrK8sErr)
 	})
 }
+
+func TestStagesExecutor_ExecuteWithDiagnostics(t *testing.T) {
+	tNow := time.Now()
+
+	operation := model.Operation{
+		ID:             operationId,
+		Type:           model.Provision,
+		StartTimestamp: tNow,
+		State:          model.InProgress,
+		ClusterID:      clusterId,
+		Stage:          model.WaitingForClusterCreation,
+		LastTransition: &tNow,
+	}
+
+	cluster := model.Cluster{ID: clusterId}
+
+	diagnostics := model.OperationDiagnostics{
+		OperationID: operationId,
+		Stage:       model.WaitingForClusterCreation,
+		CapturedAt:  tNow,
+		Events:      []model.ShootEvent{{Type: "Warning", Reason: "ReconcileError", Message: "quota exceeded"}},
+	}
+
+	t.Run("should capture diagnostics when stage fails", func(t *testing.T) {
+		// given
+		runErr := fmt.Errorf("stage failed")
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, runErr.Error(), mock.Anything, mock.Anything, string(classification.Retry)).Return(nil)
+		dbSession.On("UpdateOperationDiagnostics", operationId, diagnostics).Return(nil)
+
+		collector := &operationsMocks.DiagnosticsCollector{}
+		collector.On("Collect", cluster, operation).Return(diagnostics, nil)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second),
+		}
+
+		executor := NewExecutor(dbSession, model.Provision, stages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{DiagnosticsCollector: collector, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.True(t, result.Requeue)
+		collector.AssertExpectations(t)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should capture diagnostics only on the first failure of the stage", func(t *testing.T) {
+		// given
+		runErr := fmt.Errorf("stage failed")
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, runErr.Error(), mock.Anything, mock.Anything, string(classification.Retry)).Return(nil)
+		dbSession.On("UpdateOperationDiagnostics", operationId, diagnostics).Return(nil)
+
+		collector := &operationsMocks.DiagnosticsCollector{}
+		collector.On("Collect", cluster, operation).Return(diagnostics, nil)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second),
+		}
+
+		executor := NewExecutor(dbSession, model.Provision, stages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{DiagnosticsCollector: collector, EventPublisher: events.NewBroker()})
+
+		// when
+		executor.Execute(operationId)
+		result := executor.Execute(operationId)
+
+		// then
+		assert.True(t, result.Requeue)
+		collector.AssertNumberOfCalls(t, "Collect", 1)
+		dbSession.AssertNumberOfCalls(t, "UpdateOperationDiagnostics", 1)
+	})
+
+	t.Run("should forget diagnosed stage when operation is no longer in progress", func(t *testing.T) {
+		// given
+		runErr := fmt.Errorf("stage failed")
+		canceled := operation
+		canceled.State = model.Canceled
+
+		dbSession := &mocks.ReadWriteSession{}
+		// The operation is read again when the diagnostics are captured
+		dbSession.On("GetOperation", operationId).Return(operation, nil).Twice()
+		dbSession.On("GetOperation", operationId).Return(canceled, nil).Once()
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, runErr.Error(), mock.Anything, mock.Anything, string(classification.Retry)).Return(nil)
+		dbSession.On("UpdateOperationDiagnostics", operationId, diagnostics).Return(nil)
+
+		collector := &operationsMocks.DiagnosticsCollector{}
+		collector.On("Collect", cluster, operation).Return(diagnostics, nil)
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewErrorStep(model.WaitingForClusterCreation, runErr, 10*time.Second),
+		}
+
+		executor := NewExecutor(dbSession, model.Provision, stages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{DiagnosticsCollector: collector, EventPublisher: events.NewBroker()})
+
+		// when
+		executor.Execute(operationId)
+		require.Contains(t, executor.diagnosedStages, operationId)
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.NotContains(t, executor.diagnosedStages, operationId)
+		assert.NotContains(t, executor.retries, operationId)
+	})
+
+	t.Run("should not capture diagnostics when stage succeeds", func(t *testing.T) {
+		// given
+		dbSession := &mocks.ReadWriteSession{}
+		dbSession.On("GetOperation", operationId).Return(operation, nil)
+		dbSession.On("GetCluster", clusterId).Return(cluster, nil)
+		dbSession.On("UpdateOperationLastError", operationId, "", "", "", "").Return(nil)
+		dbSession.On("TransitionOperation", operationId, "Provisioning steps finished", model.FinishedStage, mock.AnythingOfType("time.Time")).Return(nil)
+		dbSession.On("UpdateOperationState", operationId, "Operation succeeded", model.Succeeded, mock.AnythingOfType("time.Time")).Return(nil)
+
+		collector := &operationsMocks.DiagnosticsCollector{}
+
+		stages := map[model.OperationStage]Step{
+			model.WaitingForClusterCreation: NewMockStep(model.WaitingForClusterCreation, model.FinishedStage, 0, 10*time.Second),
+		}
+
+		executor := NewExecutor(dbSession, model.Provision, stages, failure.NewNoopFailureHandler(), &directorMocks.DirectorClient{}, ExecutorOptions{DiagnosticsCollector: collector, EventPublisher: events.NewBroker()})
+
+		// when
+		result := executor.Execute(operationId)
+
+		// then
+		assert.False(t, result.Requeue)
+		collector.AssertNotCalled(t, "Collect", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,20 @@
+package failure
+
+const (
+	// HandlerNoop leaves resources of the failed operation untouched
+	HandlerNoop = "noop"
+	// HandlerUnregister unregisters the Runtime which failed to be provisioned from Director
+	HandlerUnregister = "unregister"
+	// HandlerDeprovision deletes the Shoot which failed to be provisioned and unregisters the Runtime from Director
+	HandlerDeprovision = "deprovision"
+	// HandlerRestore restores the Gardener config from before the failed Shoot upgrade
+	HandlerRestore = "restore"
+)
+
+// Config selects failure handlers per operation type
+type Config struct {
+	// Provisioning is one of: noop, unregister, deprovision
+	Provisioning string `envconfig:"default=noop"`
+	// ShootUpgrade is one of: noop, restore
+	ShootUpgrade string `envconfig:"default=noop"`
+}
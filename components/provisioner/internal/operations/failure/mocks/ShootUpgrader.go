@@ -0,0 +1,46 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	apperrors "github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/kyma-project/control-plane/components/provisioner/internal/model"
+)
+
+// ShootUpgrader is an autogenerated mock type for the ShootUpgrader type
+type ShootUpgrader struct {
+	mock.Mock
+}
+
+// UpgradeCluster provides a mock function with given fields: clusterID, upgradeConfig
+func (_m *ShootUpgrader) UpgradeCluster(clusterID string, upgradeConfig model.GardenerConfig) apperrors.AppError {
+	ret := _m.Called(clusterID, upgradeConfig)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.GardenerConfig) apperrors.AppError); ok {
+		r0 = rf(clusterID, upgradeConfig)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// NewShootUpgrader creates a new instance of ShootUpgrader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewShootUpgrader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ShootUpgrader {
+	mock := &ShootUpgrader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
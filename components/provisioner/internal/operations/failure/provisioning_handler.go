@@ -0,0 +1,90 @@
+package failure
+
+import (
+	"context"
+	"fmt"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//go:generate mockery --name=ShootClient
+type ShootClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*gardener_types.Shoot, error)
+	Update(ctx context.Context, shoot *gardener_types.Shoot, opts metav1.UpdateOptions) (*gardener_types.Shoot, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+// ProvisioningRollbackHandler removes leftovers of failed provisioning, the Runtime itself stays in the database until it is deprovisioned
+type ProvisioningRollbackHandler struct {
+	shootClient      ShootClient
+	runtimeRegistry  director.RuntimeRegistry
+	deprovisionShoot bool
+	log              logrus.FieldLogger
+}
+
+func NewProvisioningRollbackHandler(shootClient ShootClient, runtimeRegistry director.RuntimeRegistry, deprovisionShoot bool) *ProvisioningRollbackHandler {
+	return &ProvisioningRollbackHandler{
+		shootClient:      shootClient,
+		runtimeRegistry:  runtimeRegistry,
+		deprovisionShoot: deprovisionShoot,
+		log:              logrus.WithField("Component", "ProvisioningRollbackHandler"),
+	}
+}
+
+func (h ProvisioningRollbackHandler) HandleFailure(operation model.Operation, cluster model.Cluster) error {
+	if h.deprovisionShoot {
+		err := h.deleteShoot(cluster.ClusterConfig.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	exists, err := h.runtimeRegistry.RuntimeExists(cluster.ID, cluster.Tenant)
+	if err != nil {
+		return fmt.Errorf("failed to check if Runtime %s exists in Director: %s", cluster.ID, err.Error())
+	}
+
+	if exists {
+		err = h.runtimeRegistry.DeleteRuntime(cluster.ID, cluster.Tenant)
+		if err != nil {
+			return fmt.Errorf("failed to unregister Runtime %s from Director: %s", cluster.ID, err.Error())
+		}
+		h.log.Infof("Runtime %s unregistered from Director after failed operation %s", cluster.ID, operation.ID)
+	}
+
+	return nil
+}
+
+func (h ProvisioningRollbackHandler) deleteShoot(name string) error {
+	shoot, err := h.shootClient.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get Shoot %s: %s", name, err.Error())
+	}
+
+	if shoot.DeletionTimestamp != nil {
+		return nil
+	}
+
+	gardener.AnnotateWithConfirmDeletion(shoot)
+	_, err = h.shootClient.Update(context.Background(), shoot, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to confirm deletion of Shoot %s: %s", name, err.Error())
+	}
+
+	err = h.shootClient.Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Shoot %s: %s", name, err.Error())
+	}
+
+	h.log.Infof("Shoot %s deleted after failed provisioning", name)
+	return nil
+}
@@ -0,0 +1,102 @@
+package failure
+
+import (
+	"context"
+	"testing"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gardener/gardener/pkg/client/core/clientset/versioned/fake"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	directorMocks "github.com/kyma-project/control-plane/components/provisioner/internal/director/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	runtimeID   = "runtime-id"
+	tenant      = "tenant"
+	shootName   = "shoot"
+	namespace   = "garden-project"
+	operationID = "operation-id"
+)
+
+func TestProvisioningRollbackHandler_HandleFailure(t *testing.T) {
+	cluster := model.Cluster{ID: runtimeID, Tenant: tenant, ClusterConfig: model.GardenerConfig{Name: shootName}}
+	operation := model.Operation{ID: operationID, Type: model.Provision, ClusterID: runtimeID}
+
+	t.Run("should delete Shoot and unregister Runtime from Director", func(t *testing.T) {
+		// given
+		shootClient := fake.NewSimpleClientset(&gardener_types.Shoot{ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: namespace}}).CoreV1beta1().Shoots(namespace)
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("RuntimeExists", runtimeID, tenant).Return(true, nil)
+		directorClient.On("DeleteRuntime", runtimeID, tenant).Return(nil)
+
+		handler := NewProvisioningRollbackHandler(shootClient, directorClient, true)
+
+		// when
+		err := handler.HandleFailure(operation, cluster)
+
+		// then
+		require.NoError(t, err)
+		_, err = shootClient.Get(context.Background(), shootName, metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+		directorClient.AssertExpectations(t)
+	})
+
+	t.Run("should only unregister Runtime from Director", func(t *testing.T) {
+		// given
+		shootClient := fake.NewSimpleClientset(&gardener_types.Shoot{ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: namespace}}).CoreV1beta1().Shoots(namespace)
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("RuntimeExists", runtimeID, tenant).Return(true, nil)
+		directorClient.On("DeleteRuntime", runtimeID, tenant).Return(nil)
+
+		handler := NewProvisioningRollbackHandler(shootClient, directorClient, false)
+
+		// when
+		err := handler.HandleFailure(operation, cluster)
+
+		// then
+		require.NoError(t, err)
+		shoot, err := shootClient.Get(context.Background(), shootName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, shoot.Annotations)
+		directorClient.AssertExpectations(t)
+	})
+
+	t.Run("should skip missing Shoot and Runtime", func(t *testing.T) {
+		// given
+		shootClient := fake.NewSimpleClientset().CoreV1beta1().Shoots(namespace)
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("RuntimeExists", runtimeID, tenant).Return(false, nil)
+
+		handler := NewProvisioningRollbackHandler(shootClient, directorClient, true)
+
+		// when
+		err := handler.HandleFailure(operation, cluster)
+
+		// then
+		require.NoError(t, err)
+		directorClient.AssertExpectations(t)
+		directorClient.AssertNotCalled(t, "DeleteRuntime", runtimeID, tenant)
+	})
+
+	t.Run("should return error when failed to unregister Runtime", func(t *testing.T) {
+		// given
+		shootClient := fake.NewSimpleClientset().CoreV1beta1().Shoots(namespace)
+		directorClient := &directorMocks.DirectorClient{}
+		directorClient.On("RuntimeExists", runtimeID, tenant).Return(true, nil)
+		directorClient.On("DeleteRuntime", runtimeID, tenant).Return(apperrors.Internal("director error"))
+
+		handler := NewProvisioningRollbackHandler(shootClient, directorClient, false)
+
+		// when
+		err := handler.HandleFailure(operation, cluster)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "director error")
+	})
+}
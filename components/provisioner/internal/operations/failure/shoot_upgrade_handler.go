@@ -0,0 +1,60 @@
+package failure
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockery --name=ShootUpgrader
+type ShootUpgrader interface {
+	UpgradeCluster(clusterID string, upgradeConfig model.GardenerConfig) apperrors.AppError
+}
+
+// ShootUpgradeRestoreHandler applies the Gardener config stored before the failed upgrade to the Shoot and the database
+type ShootUpgradeRestoreHandler struct {
+	dbSessionFactory dbsession.Factory
+	shootUpgrader    ShootUpgrader
+	log              logrus.FieldLogger
+}
+
+func NewShootUpgradeRestoreHandler(dbSessionFactory dbsession.Factory, shootUpgrader ShootUpgrader) *ShootUpgradeRestoreHandler {
+	return &ShootUpgradeRestoreHandler{
+		dbSessionFactory: dbSessionFactory,
+		shootUpgrader:    shootUpgrader,
+		log:              logrus.WithField("Component", "ShootUpgradeRestoreHandler"),
+	}
+}
+
+func (h ShootUpgradeRestoreHandler) HandleFailure(operation model.Operation, cluster model.Cluster) error {
+	previousConfig, dberr := h.dbSessionFactory.NewReadSession().GetPreUpgradeGardenerConfig(operation.ID)
+	if dberr != nil {
+		if dberr.Code() == dberrors.CodeNotFound {
+			h.log.Warnf("Gardener config from before upgrade operation %s not found, skipping restore", operation.ID)
+			return nil
+		}
+		return fmt.Errorf("failed to get Gardener config from before upgrade: %s", dberr.Error())
+	}
+
+	// Gardener does not allow for downgrading versions
+	previousConfig.KubernetesVersion = cluster.ClusterConfig.KubernetesVersion
+	previousConfig.MachineImage = cluster.ClusterConfig.MachineImage
+	previousConfig.MachineImageVersion = cluster.ClusterConfig.MachineImageVersion
+
+	appErr := h.shootUpgrader.UpgradeCluster(cluster.ID, previousConfig)
+	if appErr != nil {
+		return fmt.Errorf("failed to restore Shoot configuration: %s", appErr.Error())
+	}
+
+	dberr = h.dbSessionFactory.NewWriteSession().UpdateGardenerClusterConfig(previousConfig)
+	if dberr != nil {
+		return fmt.Errorf("failed to restore Gardener config in database: %s", dberr.Error())
+	}
+
+	h.log.Infof("Gardener config of Runtime %s restored after failed upgrade operation %s", cluster.ID, operation.ID)
+	return nil
+}
@@ -0,0 +1,106 @@
+package failure
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShootUpgradeRestoreHandler_HandleFailure(t *testing.T) {
+	operation := model.Operation{ID: operationID, Type: model.UpgradeShoot, ClusterID: runtimeID}
+	previousConfig := model.GardenerConfig{
+		ClusterID:           runtimeID,
+		Name:                shootName,
+		KubernetesVersion:   "1.25.1",
+		MachineType:         "m5.xlarge",
+		MachineImage:        util.StringPtr("gardenlinux"),
+		MachineImageVersion: util.StringPtr("1.0.0"),
+		AutoScalerMax:       3,
+	}
+	cluster := model.Cluster{
+		ID: runtimeID,
+		ClusterConfig: model.GardenerConfig{
+			ClusterID:           runtimeID,
+			Name:                shootName,
+			KubernetesVersion:   "1.26.2",
+			MachineType:         "m5.2xlarge",
+			MachineImage:        util.StringPtr("gardenlinux"),
+			MachineImageVersion: util.StringPtr("2.0.0"),
+			AutoScalerMax:       10,
+		},
+	}
+
+	t.Run("should restore previous config keeping versions", func(t *testing.T) {
+		// given
+		expectedConfig := previousConfig
+		expectedConfig.KubernetesVersion = "1.26.2"
+		expectedConfig.MachineImageVersion = util.StringPtr("2.0.0")
+
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSession{}
+		shootUpgrader := &mocks.ShootUpgrader{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		sessionFactory.On("NewWriteSession").Return(writeSession)
+		readSession.On("GetPreUpgradeGardenerConfig", operationID).Return(previousConfig, nil)
+		shootUpgrader.On("UpgradeCluster", runtimeID, expectedConfig).Return(nil)
+		writeSession.On("UpdateGardenerClusterConfig", expectedConfig).Return(nil)
+
+		handler := NewShootUpgradeRestoreHandler(sessionFactory, shootUpgrader)
+
+		// when
+		err := handler.HandleFailure(operation, cluster)
+
+		// then
+		require.NoError(t, err)
+		shootUpgrader.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should skip restore when previous config is not stored", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		shootUpgrader := &mocks.ShootUpgrader{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetPreUpgradeGardenerConfig", operationID).Return(model.GardenerConfig{}, dberrors.NotFound("not found"))
+
+		handler := NewShootUpgradeRestoreHandler(sessionFactory, shootUpgrader)
+
+		// when
+		err := handler.HandleFailure(operation, cluster)
+
+		// then
+		require.NoError(t, err)
+		shootUpgrader.AssertNotCalled(t, "UpgradeCluster", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not update database when Shoot restore failed", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		shootUpgrader := &mocks.ShootUpgrader{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetPreUpgradeGardenerConfig", operationID).Return(previousConfig, nil)
+		shootUpgrader.On("UpgradeCluster", runtimeID, mock.Anything).Return(apperrors.External("conflict"))
+
+		handler := NewShootUpgradeRestoreHandler(sessionFactory, shootUpgrader)
+
+		// when
+		err := handler.HandleFailure(operation, cluster)
+
+		// then
+		require.Error(t, err)
+		sessionFactory.AssertNotCalled(t, "NewWriteSession")
+	})
+}
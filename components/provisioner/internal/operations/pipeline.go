@@ -0,0 +1,195 @@
+package operations
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+)
+
+// Predicate decides whether a pipeline stage should be skipped for the cluster
+type Predicate func(cluster model.Cluster) bool
+
+type PipelineStage struct {
+	Step      Step
+	DependsOn []model.OperationStage
+	SkipWhen  Predicate
+}
+
+// Pipeline is a versioned set of stages with dependencies, stages whose dependencies are completed run in parallel
+type Pipeline struct {
+	version string
+	stages  []PipelineStage
+}
+
+func (p *Pipeline) Version() string {
+	return p.version
+}
+
+func (p *Pipeline) Stages() []PipelineStage {
+	return p.stages
+}
+
+// FirstStage is the stage from which operations processed with the pipeline start
+func (p *Pipeline) FirstStage() model.OperationStage {
+	return p.stages[0].Step.Name()
+}
+
+// Next returns stages which can be run and stages which should be skipped given the state of already processed stages
+func (p *Pipeline) Next(cluster model.Cluster, statuses map[model.OperationStage]model.OperationStageStatus) (run []PipelineStage, skip []PipelineStage, finished bool) {
+	finished = true
+
+	for _, stage := range p.stages {
+		if isStageCompleted(statuses, stage.Step.Name()) {
+			continue
+		}
+		finished = false
+
+		if !p.dependenciesCompleted(stage, statuses) {
+			continue
+		}
+
+		_, started := statuses[stage.Step.Name()]
+		if !started && stage.SkipWhen != nil && stage.SkipWhen(cluster) {
+			skip = append(skip, stage)
+			continue
+		}
+
+		run = append(run, stage)
+	}
+
+	return run, skip, finished
+}
+
+func (p *Pipeline) dependenciesCompleted(stage PipelineStage, statuses map[model.OperationStage]model.OperationStageStatus) bool {
+	for _, dependency := range stage.DependsOn {
+		if !isStageCompleted(statuses, dependency) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isStageCompleted(statuses map[model.OperationStage]model.OperationStageStatus, stage model.OperationStage) bool {
+	status, found := statuses[stage]
+	return found && (status.State == model.StageSucceeded || status.State == model.StageSkipped)
+}
+
+// Pipelines holds all known versions of a pipeline, the last one is used for new operations
+type Pipelines []*Pipeline
+
+func (p Pipelines) Current() (*Pipeline, bool) {
+	if len(p) == 0 {
+		return nil, false
+	}
+
+	return p[len(p)-1], true
+}
+
+func (p Pipelines) Find(version string) (*Pipeline, bool) {
+	for _, pipeline := range p {
+		if pipeline.version == version {
+			return pipeline, true
+		}
+	}
+
+	return nil, false
+}
+
+type PipelineBuilder struct {
+	version string
+	stages  []PipelineStage
+}
+
+func NewPipelineBuilder(version string) *PipelineBuilder {
+	return &PipelineBuilder{
+		version: version,
+	}
+}
+
+// Stage adds the step to the pipeline, dependencies have to be added before the stage depending on them
+func (b *PipelineBuilder) Stage(step Step, dependsOn ...model.OperationStage) *PipelineBuilder {
+	return b.ConditionalStage(step, nil, dependsOn...)
+}
+
+// ConditionalStage adds the step which is skipped when the predicate is true for the cluster
+func (b *PipelineBuilder) ConditionalStage(step Step, skipWhen Predicate, dependsOn ...model.OperationStage) *PipelineBuilder {
+	b.stages = append(b.stages, PipelineStage{
+		Step:      step,
+		DependsOn: dependsOn,
+		SkipWhen:  skipWhen,
+	})
+
+	return b
+}
+
+func (b *PipelineBuilder) Build() (*Pipeline, error) {
+	if b.version == "" || b.version == model.LegacyPipelineVersion {
+		return nil, fmt.Errorf("invalid pipeline version %q", b.version)
+	}
+
+	if len(b.stages) == 0 {
+		return nil, fmt.Errorf("pipeline %s has no stages", b.version)
+	}
+
+	added := map[model.OperationStage]bool{}
+	for _, stage := range b.stages {
+		if stage.Step == nil {
+			return nil, fmt.Errorf("pipeline %s contains stage without step", b.version)
+		}
+
+		name := stage.Step.Name()
+		if added[name] {
+			return nil, fmt.Errorf("stage %s is defined more than once in pipeline %s", name, b.version)
+		}
+
+		for _, dependency := range stage.DependsOn {
+			if !added[dependency] {
+				return nil, fmt.Errorf("stage %s in pipeline %s depends on %s which is not defined before it", name, b.version, dependency)
+			}
+		}
+
+		added[name] = true
+	}
+
+	return &Pipeline{
+		version: b.version,
+		stages:  b.stages,
+	}, nil
+}
+
+// PipelineDefinition describes the pipeline in configuration file
+type PipelineDefinition struct {
+	Version string            `json:"version"`
+	Stages  []StageDefinition `json:"stages"`
+}
+
+type StageDefinition struct {
+	Name      model.OperationStage   `json:"name"`
+	DependsOn []model.OperationStage `json:"dependsOn"`
+	SkipWhen  string                 `json:"skipWhen"`
+}
+
+// Build creates the pipeline using available steps and predicates referenced by name
+func (d PipelineDefinition) Build(steps map[model.OperationStage]Step, predicates map[string]Predicate) (*Pipeline, error) {
+	builder := NewPipelineBuilder(d.Version)
+
+	for _, stage := range d.Stages {
+		step, found := steps[stage.Name]
+		if !found {
+			return nil, fmt.Errorf("unknown stage %s in pipeline %s", stage.Name, d.Version)
+		}
+
+		var skipWhen Predicate
+		if stage.SkipWhen != "" {
+			skipWhen, found = predicates[stage.SkipWhen]
+			if !found {
+				return nil, fmt.Errorf("unknown predicate %s for stage %s in pipeline %s", stage.SkipWhen, stage.Name, d.Version)
+			}
+		}
+
+		builder.ConditionalStage(step, skipWhen, stage.DependsOn...)
+	}
+
+	return builder.Build()
+}
@@ -0,0 +1,220 @@
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineBuilder_Build(t *testing.T) {
+	domain := NewMockStep(model.WaitingForClusterDomain, model.WaitingForClusterCreation, 0, time.Minute)
+	creation := NewMockStep(model.WaitingForClusterCreation, model.FinishedStage, 0, time.Minute)
+
+	t.Run("should build pipeline", func(t *testing.T) {
+		// when
+		pipeline, err := NewPipelineBuilder("v1").
+			Stage(domain).
+			Stage(creation, model.WaitingForClusterDomain).
+			Build()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "v1", pipeline.Version())
+		assert.Len(t, pipeline.Stages(), 2)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		builder     *PipelineBuilder
+	}{
+		{
+			description: "empty version",
+			builder:     NewPipelineBuilder("").Stage(domain),
+		},
+		{
+			description: "legacy version",
+			builder:     NewPipelineBuilder(model.LegacyPipelineVersion).Stage(domain),
+		},
+		{
+			description: "no stages",
+			builder:     NewPipelineBuilder("v1"),
+		},
+		{
+			description: "duplicated stage",
+			builder:     NewPipelineBuilder("v1").Stage(domain).Stage(domain),
+		},
+		{
+			description: "dependency defined after the stage",
+			builder:     NewPipelineBuilder("v1").Stage(creation, model.WaitingForClusterDomain).Stage(domain),
+		},
+	} {
+		t.Run("should fail to build pipeline with "+testCase.description, func(t *testing.T) {
+			// when
+			_, err := testCase.builder.Build()
+
+			// then
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPipeline_Next(t *testing.T) {
+	creation := NewMockStep(model.WaitingForClusterCreation, model.FinishedStage, 0, time.Minute)
+	bindings := NewMockStep(model.CreatingBindingsForOperators, model.FinishedStage, 0, time.Minute)
+	agent := NewMockStep(model.ConnectRuntimeAgent, model.FinishedStage, 0, time.Minute)
+
+	compassDisabled := func(cluster model.Cluster) bool {
+		return cluster.KymaConfig == nil
+	}
+
+	pipeline, err := NewPipelineBuilder("v1").
+		Stage(creation).
+		Stage(bindings, model.WaitingForClusterCreation).
+		ConditionalStage(agent, compassDisabled, model.WaitingForClusterCreation).
+		Build()
+	require.NoError(t, err)
+
+	withKyma := model.Cluster{KymaConfig: &model.KymaConfig{}}
+
+	t.Run("should run root stages first", func(t *testing.T) {
+		// when
+		run, skip, finished := pipeline.Next(withKyma, nil)
+
+		// then
+		assert.False(t, finished)
+		assert.Empty(t, skip)
+		assert.Equal(t, []model.OperationStage{model.WaitingForClusterCreation}, stageNames(run))
+	})
+
+	t.Run("should run independent stages together", func(t *testing.T) {
+		// given
+		statuses := map[model.OperationStage]model.OperationStageStatus{
+			model.WaitingForClusterCreation: {State: model.StageSucceeded},
+		}
+
+		// when
+		run, skip, finished := pipeline.Next(withKyma, statuses)
+
+		// then
+		assert.False(t, finished)
+		assert.Empty(t, skip)
+		assert.Equal(t, []model.OperationStage{model.CreatingBindingsForOperators, model.ConnectRuntimeAgent}, stageNames(run))
+	})
+
+	t.Run("should skip stage when predicate is true", func(t *testing.T) {
+		// given
+		statuses := map[model.OperationStage]model.OperationStageStatus{
+			model.WaitingForClusterCreation: {State: model.StageSucceeded},
+		}
+
+		// when
+		run, skip, _ := pipeline.Next(model.Cluster{}, statuses)
+
+		// then
+		assert.Equal(t, []model.OperationStage{model.CreatingBindingsForOperators}, stageNames(run))
+		assert.Equal(t, []model.OperationStage{model.ConnectRuntimeAgent}, stageNames(skip))
+	})
+
+	t.Run("should not skip stage already in progress", func(t *testing.T) {
+		// given
+		statuses := map[model.OperationStage]model.OperationStageStatus{
+			model.WaitingForClusterCreation:    {State: model.StageSucceeded},
+			model.CreatingBindingsForOperators: {State: model.StageSucceeded},
+			model.ConnectRuntimeAgent:          {State: model.StageInProgress},
+		}
+
+		// when
+		run, skip, _ := pipeline.Next(model.Cluster{}, statuses)
+
+		// then
+		assert.Equal(t, []model.OperationStage{model.ConnectRuntimeAgent}, stageNames(run))
+		assert.Empty(t, skip)
+	})
+
+	t.Run("should finish when all stages are completed", func(t *testing.T) {
+		// given
+		statuses := map[model.OperationStage]model.OperationStageStatus{
+			model.WaitingForClusterCreation:    {State: model.StageSucceeded},
+			model.CreatingBindingsForOperators: {State: model.StageSucceeded},
+			model.ConnectRuntimeAgent:          {State: model.StageSkipped},
+		}
+
+		// when
+		run, skip, finished := pipeline.Next(model.Cluster{}, statuses)
+
+		// then
+		assert.True(t, finished)
+		assert.Empty(t, run)
+		assert.Empty(t, skip)
+	})
+}
+
+func TestPipelineDefinition_Build(t *testing.T) {
+	steps := map[model.OperationStage]Step{
+		model.WaitingForClusterCreation: NewMockStep(model.WaitingForClusterCreation, model.ConnectRuntimeAgent, 0, time.Minute),
+		model.ConnectRuntimeAgent:       NewMockStep(model.ConnectRuntimeAgent, model.FinishedStage, 0, time.Minute),
+	}
+	predicates := map[string]Predicate{
+		"always": func(model.Cluster) bool { return true },
+	}
+
+	t.Run("should build pipeline from definition", func(t *testing.T) {
+		// given
+		definition := PipelineDefinition{
+			Version: "v2",
+			Stages: []StageDefinition{
+				{Name: model.WaitingForClusterCreation},
+				{Name: model.ConnectRuntimeAgent, DependsOn: []model.OperationStage{model.WaitingForClusterCreation}, SkipWhen: "always"},
+			},
+		}
+
+		// when
+		pipeline, err := definition.Build(steps, predicates)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "v2", pipeline.Version())
+		require.Len(t, pipeline.Stages(), 2)
+		assert.NotNil(t, pipeline.Stages()[1].SkipWhen)
+	})
+
+	t.Run("should fail for unknown stage", func(t *testing.T) {
+		// given
+		definition := PipelineDefinition{
+			Version: "v2",
+			Stages:  []StageDefinition{{Name: model.WaitingForInstallation}},
+		}
+
+		// when
+		_, err := definition.Build(steps, predicates)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should fail for unknown predicate", func(t *testing.T) {
+		// given
+		definition := PipelineDefinition{
+			Version: "v2",
+			Stages:  []StageDefinition{{Name: model.WaitingForClusterCreation, SkipWhen: "never"}},
+		}
+
+		// when
+		_, err := definition.Build(steps, predicates)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func stageNames(stages []PipelineStage) []model.OperationStage {
+	var names []model.OperationStage
+	for _, stage := range stages {
+		names = append(names, stage.Step.Name())
+	}
+
+	return names
+}
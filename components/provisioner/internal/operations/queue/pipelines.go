@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// DefaultPipelineVersion is the version of pipelines built into the Provisioner
+const DefaultPipelineVersion = "v1"
+
+type PipelinesConfig struct {
+	Definitions PipelineDefinitions
+	// CompassEnabled is false on landscapes without Compass, where stages which only matter with Compass are skipped
+	CompassEnabled bool
+}
+
+// PipelineDefinitions contain additional pipeline versions per operation type, the last version is used for new operations
+type PipelineDefinitions struct {
+	Provisioning        []operations.PipelineDefinition `json:"provisioning"`
+	Deprovisioning      []operations.PipelineDefinition `json:"deprovisioning"`
+	ShootUpgrade        []operations.PipelineDefinition `json:"shootUpgrade"`
+	CredentialsRotation []operations.PipelineDefinition `json:"credentialsRotation"`
+}
+
+func LoadPipelineDefinitions(path string) (PipelineDefinitions, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return PipelineDefinitions{}, fmt.Errorf("failed to read pipeline definitions file: %s", err.Error())
+	}
+
+	var definitions PipelineDefinitions
+	if err := yaml.Unmarshal(file, &definitions); err != nil {
+		return PipelineDefinitions{}, fmt.Errorf("failed to decode pipeline definitions file: %s", err.Error())
+	}
+
+	return definitions, nil
+}
+
+// CompassDisabled is true for all Runtimes when Compass is not used, regardless of their Kyma configuration
+func (c PipelinesConfig) CompassDisabled(_ model.Cluster) bool {
+	return !c.CompassEnabled
+}
+
+func (c PipelinesConfig) predicates() map[string]operations.Predicate {
+	return map[string]operations.Predicate{
+		"compassDisabled": c.CompassDisabled,
+	}
+}
+
+func (c PipelinesConfig) newPipelines(defaultPipeline *operations.PipelineBuilder, definitions []operations.PipelineDefinition, steps map[model.OperationStage]operations.Step) (operations.Pipelines, error) {
+	pipeline, err := defaultPipeline.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build default pipeline: %s", err.Error())
+	}
+
+	pipelines := operations.Pipelines{pipeline}
+	for _, definition := range definitions {
+		if _, found := pipelines.Find(definition.Version); found {
+			return nil, fmt.Errorf("pipeline version %s is defined more than once", definition.Version)
+		}
+
+		pipeline, err := definition.Build(steps, c.predicates())
+		if err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, pipeline)
+	}
+
+	return pipelines, nil
+}
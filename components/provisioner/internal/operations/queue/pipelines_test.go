@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/provisioning"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPipelineDefinitions(t *testing.T) {
+	// when
+	definitions, err := LoadPipelineDefinitions("testdata/pipelines.yaml")
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, definitions.Provisioning, 1)
+	assert.Equal(t, "v2", definitions.Provisioning[0].Version)
+	assert.Equal(t, operations.StageDefinition{
+		Name:      model.ConnectRuntimeAgent,
+		DependsOn: []model.OperationStage{model.WaitingForClusterCreation},
+		SkipWhen:  "compassDisabled",
+	}, definitions.Provisioning[0].Stages[3])
+	require.Len(t, definitions.Deprovisioning, 1)
+	assert.Empty(t, definitions.ShootUpgrade)
+}
+
+func TestLoadPipelineDefinitions_BuildWithQueueSteps(t *testing.T) {
+	// given
+	definitions, err := LoadPipelineDefinitions("testdata/pipelines.yaml")
+	require.NoError(t, err)
+
+	factory := &sessionMocks.Factory{}
+	factory.On("NewReadWriteSession").Return(&sessionMocks.ReadWriteSession{})
+	options := QueueOptions{Pipelines: PipelinesConfig{Definitions: definitions}}
+
+	// when
+	_, provisioningErr := CreateProvisioningQueue(ProvisioningTimeouts{}, factory, nil, nil, provisioning.OperatorRoleBinding{}, nil, nil, nil, options)
+	_, deprovisioningErr := CreateDeprovisioningQueue(DeprovisioningTimeouts{}, factory, nil, nil, nil, nil, nil, options)
+	_, shootUpgradeErr := CreateShootUpgradeQueue(ProvisioningTimeouts{}, factory, nil, nil, provisioning.OperatorRoleBinding{}, nil, nil, options)
+	_, credentialsRotationErr := CreateCredentialsRotationQueue(ProvisioningTimeouts{}, factory, nil, nil, nil, options)
+
+	// then
+	assert.NoError(t, provisioningErr)
+	assert.NoError(t, deprovisioningErr)
+	assert.NoError(t, shootUpgradeErr)
+	assert.NoError(t, credentialsRotationErr)
+}
+
+func TestPipelinesConfig_CompassDisabled(t *testing.T) {
+	withoutRuntimeAgent := model.Cluster{KymaConfig: &model.KymaConfig{
+		Components: []model.KymaComponentConfig{{Component: "istio"}},
+	}}
+
+	for _, testCase := range []struct {
+		description    string
+		compassEnabled bool
+		cluster        model.Cluster
+		expected       bool
+	}{
+		{
+			description:    "with Compass disabled",
+			compassEnabled: false,
+			cluster:        model.Cluster{},
+			expected:       true,
+		},
+		{
+			description:    "with Compass enabled",
+			compassEnabled: true,
+			cluster:        model.Cluster{},
+			expected:       false,
+		},
+		{
+			description:    "with Compass enabled for Runtime without Runtime Agent",
+			compassEnabled: true,
+			cluster:        withoutRuntimeAgent,
+			expected:       false,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			config := PipelinesConfig{CompassEnabled: testCase.compassEnabled}
+			assert.Equal(t, testCase.expected, config.CompassDisabled(testCase.cluster))
+		})
+	}
+}
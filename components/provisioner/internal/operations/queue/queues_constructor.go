@@ -5,9 +5,12 @@ import (
 
 	gardener_apis "github.com/gardener/gardener/pkg/client/core/clientset/versioned/typed/core/v1beta1"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/classification"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/credentialsrotation"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/deprovisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/provisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/shootupgrade"
@@ -16,6 +19,26 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util/k8s"
 )
 
+// QueueOptions groups the dependencies of the operation queues which are not used by their stages
+type QueueOptions struct {
+	Pipelines PipelinesConfig
+	// FailureHandler is called when an operation fails, without it failures are only recorded
+	FailureHandler operations.FailureHandler
+	// ErrorClassifier chooses the action for errors returned by stages, without it recoverable errors are retried with a constant delay
+	ErrorClassifier *classification.Classifier
+	// DiagnosticsCollector captures the Shoot state when a stage fails, without it no diagnostics are stored
+	DiagnosticsCollector operations.DiagnosticsCollector
+	// EventPublisher is notified about operation changes, without it no events are published
+	EventPublisher events.Publisher
+}
+
+func (o QueueOptions) failureHandler() operations.FailureHandler {
+	if o.FailureHandler == nil {
+		return failure.NewNoopFailureHandler()
+	}
+	return o.FailureHandler
+}
+
 type ProvisioningTimeouts struct {
 	ClusterCreation        time.Duration `envconfig:"default=60m"`
 	ClusterDomains         time.Duration `envconfig:"default=10m"`
@@ -28,9 +51,11 @@ type ProvisioningTimeouts struct {
 	ShootRefresh           time.Duration `envconfig:"default=5m"`
 	AgentConfiguration     time.Duration `envconfig:"default=15m"`
 	AgentConnection        time.Duration `envconfig:"default=15m"`
+	CredentialsRotation    time.Duration `envconfig:"default=60m"`
 }
 
 type DeprovisioningTimeouts struct {
+	ClusterCleanup            time.Duration `envconfig:"default=20m"`
 	ClusterDeletion           time.Duration `envconfig:"default=30m"`
 	WaitingForClusterDeletion time.Duration `envconfig:"default=60m"`
 }
@@ -53,7 +78,8 @@ func CreateProvisioningQueue(
 	operatorRoleBindingConfig provisioning.OperatorRoleBinding,
 	k8sClientProvider k8s.K8sClientProvider,
 	configurator runtime.Configurator,
-	kubeconfigProvider KubeconfigProvider) OperationQueue {
+	kubeconfigProvider KubeconfigProvider,
+	options QueueOptions) (OperationQueue, error) {
 
 	configureAgentStep := provisioning.NewConnectAgentStep(configurator, kubeconfigProvider, model.FinishedStage, timeouts.AgentConfiguration)
 	createBindingsForOperatorsStep := provisioning.NewCreateBindingsForOperatorsStep(k8sClientProvider, operatorRoleBindingConfig, kubeconfigProvider, configureAgentStep.Name(), timeouts.BindingsCreation)
@@ -67,41 +93,84 @@ func CreateProvisioningQueue(
 		model.WaitingForClusterCreation:    waitForClusterCreationStep,
 	}
 
+	// Cluster domain is only propagated to Director, operator bindings and Runtime Agent connection run in parallel once the cluster is created
+	defaultPipeline := operations.NewPipelineBuilder(DefaultPipelineVersion).
+		ConditionalStage(waitForClusterDomainStep, options.Pipelines.CompassDisabled).
+		Stage(waitForClusterCreationStep, model.WaitingForClusterDomain).
+		Stage(createBindingsForOperatorsStep, model.WaitingForClusterCreation).
+		ConditionalStage(configureAgentStep, options.Pipelines.CompassDisabled, model.WaitingForClusterCreation)
+
+	provisionPipelines, err := options.Pipelines.newPipelines(defaultPipeline, options.Pipelines.Definitions.Provisioning, provisionSteps)
+	if err != nil {
+		return nil, err
+	}
+
 	provisioningExecutor := operations.NewExecutor(
 		factory.NewReadWriteSession(),
 		model.Provision,
 		provisionSteps,
-		failure.NewNoopFailureHandler(),
+		options.failureHandler(),
 		directorClient,
+		operations.ExecutorOptions{
+			Pipelines:            provisionPipelines,
+			ErrorClassifier:      options.ErrorClassifier,
+			DiagnosticsCollector: options.DiagnosticsCollector,
+			EventPublisher:       options.EventPublisher,
+		},
 	)
 
-	return NewQueue(provisioningExecutor)
+	return NewQueue(provisioningExecutor), nil
 }
 
 func CreateDeprovisioningQueue(
 	timeouts DeprovisioningTimeouts,
 	factory dbsession.Factory,
-	directorClient director.DirectorClient,
+	runtimeRegistry director.RuntimeRegistry,
 	shootClient gardener_apis.ShootInterface,
-) OperationQueue {
+	kubeconfigProvider KubeconfigProvider,
+	dynamicClientProvider k8s.DynamicClientProvider,
+	cleanupSelectors []deprovisioning.ResourceSelector,
+	options QueueOptions,
+) (OperationQueue, error) {
 
-	waitForClusterDeletion := deprovisioning.NewWaitForClusterDeletionStep(shootClient, factory, directorClient, model.FinishedStage, timeouts.WaitingForClusterDeletion)
+	waitForClusterDeletion := deprovisioning.NewWaitForClusterDeletionStep(shootClient, factory, runtimeRegistry, model.FinishedStage, timeouts.WaitingForClusterDeletion)
 	deleteCluster := deprovisioning.NewDeleteClusterStep(shootClient, waitForClusterDeletion.Name(), timeouts.ClusterDeletion)
+	cleanupCluster := deprovisioning.NewCleanupClusterStep(shootClient, kubeconfigProvider, dynamicClientProvider, cleanupSelectors, deleteCluster.Name(), timeouts.ClusterCleanup)
 
 	deprovisioningSteps := map[model.OperationStage]operations.Step{
+		model.CleanupCluster:         cleanupCluster,
 		model.DeleteCluster:          deleteCluster,
 		model.WaitForClusterDeletion: waitForClusterDeletion,
 	}
 
+	defaultPipeline := operations.NewPipelineBuilder(DefaultPipelineVersion).
+		Stage(cleanupCluster).
+		Stage(deleteCluster, model.CleanupCluster).
+		Stage(waitForClusterDeletion, model.DeleteCluster)
+
+	deprovisioningPipelines, err := options.Pipelines.newPipelines(defaultPipeline, options.Pipelines.Definitions.Deprovisioning, deprovisioningSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Runtime status conditions are only kept in Director, the local registry has none
+	directorClient, _ := runtimeRegistry.(director.DirectorClient)
+
 	deprovisioningExecutor := operations.NewExecutor(
 		factory.NewReadWriteSession(),
 		model.DeprovisionNoInstall,
 		deprovisioningSteps,
-		failure.NewNoopFailureHandler(),
+		options.failureHandler(),
 		directorClient,
+		operations.ExecutorOptions{
+			Pipelines:            deprovisioningPipelines,
+			ErrorClassifier:      options.ErrorClassifier,
+			DiagnosticsCollector: options.DiagnosticsCollector,
+			EventPublisher:       options.EventPublisher,
+		},
 	)
 
-	return NewQueue(deprovisioningExecutor)
+	return NewQueue(deprovisioningExecutor), nil
 }
 
 func CreateShootUpgradeQueue(
@@ -112,7 +181,8 @@ func CreateShootUpgradeQueue(
 	operatorRoleBindingConfig provisioning.OperatorRoleBinding,
 	k8sClientProvider k8s.K8sClientProvider,
 	kubeconfigProvider KubeconfigProvider,
-) OperationQueue {
+	options QueueOptions,
+) (OperationQueue, error) {
 
 	createBindingsForOperatorsStep := provisioning.NewCreateBindingsForOperatorsStep(k8sClientProvider, operatorRoleBindingConfig, kubeconfigProvider, model.FinishedStage, timeouts.BindingsCreation)
 	waitForShootUpgrade := shootupgrade.NewWaitForShootUpgradeStep(shootClient, factory.NewReadWriteSession(), kubeconfigProvider, createBindingsForOperatorsStep.Name(), timeouts.ShootUpgrade)
@@ -124,13 +194,72 @@ func CreateShootUpgradeQueue(
 		model.WaitingForShootNewVersion:    waitForShootNewVersion,
 	}
 
+	defaultPipeline := operations.NewPipelineBuilder(DefaultPipelineVersion).
+		Stage(waitForShootNewVersion).
+		Stage(waitForShootUpgrade, model.WaitingForShootNewVersion).
+		Stage(createBindingsForOperatorsStep, model.WaitingForShootUpgrade)
+
+	upgradePipelines, err := options.Pipelines.newPipelines(defaultPipeline, options.Pipelines.Definitions.ShootUpgrade, upgradeSteps)
+	if err != nil {
+		return nil, err
+	}
+
 	upgradeClusterExecutor := operations.NewExecutor(
 		factory.NewReadWriteSession(),
 		model.UpgradeShoot,
 		upgradeSteps,
-		failure.NewNoopFailureHandler(),
+		options.failureHandler(),
+		directorClient,
+		operations.ExecutorOptions{
+			Pipelines:            upgradePipelines,
+			ErrorClassifier:      options.ErrorClassifier,
+			DiagnosticsCollector: options.DiagnosticsCollector,
+			EventPublisher:       options.EventPublisher,
+		},
+	)
+
+	return NewQueue(upgradeClusterExecutor), nil
+}
+
+func CreateCredentialsRotationQueue(
+	timeouts ProvisioningTimeouts,
+	factory dbsession.Factory,
+	directorClient director.DirectorClient,
+	shootClient gardener_apis.ShootInterface,
+	kubeconfigProvider KubeconfigProvider,
+	options QueueOptions,
+) (OperationQueue, error) {
+
+	refreshKubeconfig := credentialsrotation.NewRefreshKubeconfigStep(factory.NewReadWriteSession(), kubeconfigProvider, model.FinishedStage, timeouts.ShootRefresh)
+	rotateCredentials := credentialsrotation.NewRotateCredentialsStep(shootClient, factory.NewReadWriteSession(), refreshKubeconfig.Name(), timeouts.CredentialsRotation)
+
+	rotationSteps := map[model.OperationStage]operations.Step{
+		model.RotatingCredentials:  rotateCredentials,
+		model.RefreshingKubeconfig: refreshKubeconfig,
+	}
+
+	defaultPipeline := operations.NewPipelineBuilder(DefaultPipelineVersion).
+		Stage(rotateCredentials).
+		Stage(refreshKubeconfig, model.RotatingCredentials)
+
+	rotationPipelines, err := options.Pipelines.newPipelines(defaultPipeline, options.Pipelines.Definitions.CredentialsRotation, rotationSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	rotationExecutor := operations.NewExecutor(
+		factory.NewReadWriteSession(),
+		model.RotateCredentials,
+		rotationSteps,
+		options.failureHandler(),
 		directorClient,
+		operations.ExecutorOptions{
+			Pipelines:            rotationPipelines,
+			ErrorClassifier:      options.ErrorClassifier,
+			DiagnosticsCollector: options.DiagnosticsCollector,
+			EventPublisher:       options.EventPublisher,
+		},
 	)
 
-	return NewQueue(upgradeClusterExecutor)
+	return NewQueue(rotationExecutor), nil
 }
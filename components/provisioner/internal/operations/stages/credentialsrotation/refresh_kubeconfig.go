@@ -0,0 +1,64 @@
+package credentialsrotation
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockery --name=KubeconfigProvider
+type KubeconfigProvider interface {
+	FetchFromShoot(shootName string) ([]byte, error)
+}
+
+// RefreshKubeconfigStep stores the new kubeconfig of the Runtime after rotation of certificate authorities
+type RefreshKubeconfigStep struct {
+	dbSession          dbsession.ReadWriteSession
+	kubeconfigProvider KubeconfigProvider
+	nextStep           model.OperationStage
+	timeLimit          time.Duration
+}
+
+func NewRefreshKubeconfigStep(dbSession dbsession.ReadWriteSession, kubeconfigProvider KubeconfigProvider, nextStep model.OperationStage, timeLimit time.Duration) *RefreshKubeconfigStep {
+	return &RefreshKubeconfigStep{
+		dbSession:          dbSession,
+		kubeconfigProvider: kubeconfigProvider,
+		nextStep:           nextStep,
+		timeLimit:          timeLimit,
+	}
+}
+
+func (s *RefreshKubeconfigStep) Name() model.OperationStage {
+	return model.RefreshingKubeconfig
+}
+
+func (s *RefreshKubeconfigStep) TimeLimit() time.Duration {
+	return s.timeLimit
+}
+
+func (s *RefreshKubeconfigStep) Run(cluster model.Cluster, operation model.Operation, logger logrus.FieldLogger) (operations.StageResult, error) {
+	rotation, dberr := s.dbSession.GetCredentialsRotation(operation.ID)
+	if dberr != nil {
+		return operations.StageResult{}, dberr
+	}
+
+	if !rotation.Includes(model.CertificateAuthorities) {
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	kubeconfig, err := s.kubeconfigProvider.FetchFromShoot(cluster.ClusterConfig.Name)
+	if err != nil {
+		return operations.StageResult{}, err
+	}
+
+	if dberr := s.dbSession.UpdateKubeconfig(cluster.ID, string(kubeconfig)); dberr != nil {
+		return operations.StageResult{}, dberr
+	}
+
+	logger.Info("Kubeconfig refreshed after certificate authorities rotation")
+
+	return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+}
@@ -0,0 +1,67 @@
+package credentialsrotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/credentialsrotation/mocks"
+	dbMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshKubeconfigStep_Run(t *testing.T) {
+	cluster := model.Cluster{
+		ID:            runtimeID,
+		ClusterConfig: model.GardenerConfig{Name: clusterName},
+	}
+	operation := model.Operation{ID: operationID}
+
+	t.Run("should store new kubeconfig after certificate authorities rotation", func(t *testing.T) {
+		// given
+		dbSession := &dbMocks.ReadWriteSession{}
+		dbSession.On("GetCredentialsRotation", operationID).Return(model.CredentialsRotation{
+			OperationID: operationID,
+			Kinds:       []model.CredentialsRotationKind{model.CertificateAuthorities},
+			Phase:       model.RotationComplete,
+		}, nil)
+		dbSession.On("UpdateKubeconfig", runtimeID, "kubeconfig").Return(nil)
+
+		kubeconfigProvider := &mocks.KubeconfigProvider{}
+		kubeconfigProvider.On("FetchFromShoot", clusterName).Return([]byte("kubeconfig"), nil)
+
+		step := NewRefreshKubeconfigStep(dbSession, kubeconfigProvider, model.FinishedStage, time.Minute)
+
+		// when
+		result, err := step.Run(cluster, operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.FinishedStage, result.Stage)
+		dbSession.AssertExpectations(t)
+	})
+
+	t.Run("should skip refresh when certificate authorities were not rotated", func(t *testing.T) {
+		// given
+		dbSession := &dbMocks.ReadWriteSession{}
+		dbSession.On("GetCredentialsRotation", operationID).Return(model.CredentialsRotation{
+			OperationID: operationID,
+			Kinds:       []model.CredentialsRotationKind{model.SSHKeypair},
+			Phase:       model.RotationPrepare,
+		}, nil)
+
+		kubeconfigProvider := &mocks.KubeconfigProvider{}
+
+		step := NewRefreshKubeconfigStep(dbSession, kubeconfigProvider, model.FinishedStage, time.Minute)
+
+		// when
+		result, err := step.Run(cluster, operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.FinishedStage, result.Stage)
+		kubeconfigProvider.AssertNotCalled(t, "FetchFromShoot", clusterName)
+	})
+}
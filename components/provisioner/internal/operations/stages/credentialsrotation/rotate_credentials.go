@@ -0,0 +1,115 @@
+package credentialsrotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/apis/core/v1beta1/helper"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//go:generate mockery --name=GardenerClient
+type GardenerClient interface {
+	Get(ctx context.Context, name string, options v1.GetOptions) (*gardener_types.Shoot, error)
+	Update(ctx context.Context, shoot *gardener_types.Shoot, options v1.UpdateOptions) (*gardener_types.Shoot, error)
+}
+
+// RotateCredentialsStep triggers rotation of requested credentials one by one, as Gardener handles a single Shoot operation at a time
+type RotateCredentialsStep struct {
+	gardenerClient GardenerClient
+	dbSession      dbsession.ReadWriteSession
+	nextStep       model.OperationStage
+	timeLimit      time.Duration
+}
+
+func NewRotateCredentialsStep(gardenerClient GardenerClient, dbSession dbsession.ReadWriteSession, nextStep model.OperationStage, timeLimit time.Duration) *RotateCredentialsStep {
+	return &RotateCredentialsStep{
+		gardenerClient: gardenerClient,
+		dbSession:      dbSession,
+		nextStep:       nextStep,
+		timeLimit:      timeLimit,
+	}
+}
+
+func (s *RotateCredentialsStep) Name() model.OperationStage {
+	return model.RotatingCredentials
+}
+
+func (s *RotateCredentialsStep) TimeLimit() time.Duration {
+	return s.timeLimit
+}
+
+func (s *RotateCredentialsStep) Run(cluster model.Cluster, operation model.Operation, logger logrus.FieldLogger) (operations.StageResult, error) {
+	rotation, dberr := s.dbSession.GetCredentialsRotation(operation.ID)
+	if dberr != nil {
+		return operations.StageResult{}, dberr
+	}
+
+	shoot, err := s.gardenerClient.Get(context.Background(), cluster.ClusterConfig.Name, v1.GetOptions{})
+	if err != nil {
+		return operations.StageResult{}, util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient)
+	}
+
+	pending := make([]model.CredentialsRotationKind, 0, len(rotation.Kinds))
+	for _, kind := range rotation.Kinds {
+		status := gardener.CredentialsRotationStatus(*shoot, kind)
+		if dberr := s.dbSession.UpsertCredentialsRotationStatus(cluster.ID, status); dberr != nil {
+			return operations.StageResult{}, dberr
+		}
+
+		if !gardener.CredentialsRotationFinished(status, rotation.Phase, operation.StartTimestamp) {
+			pending = append(pending, kind)
+		}
+	}
+
+	if len(pending) == 0 {
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	if shoot.Annotations[v1beta1constants.GardenerOperation] != "" {
+		logger.Infof("Waiting for Gardener to pick up %s operation", shoot.Annotations[v1beta1constants.GardenerOperation])
+		return operations.StageResult{Stage: s.Name(), Delay: 20 * time.Second}, nil
+	}
+
+	lastOperation := shoot.Status.LastOperation
+	if lastOperation != nil {
+		if lastOperation.State == gardener_types.LastOperationStateProcessing || lastOperation.State == gardener_types.LastOperationStatePending {
+			return operations.StageResult{Stage: s.Name(), Delay: 20 * time.Second}, nil
+		}
+
+		if lastOperation.State == gardener_types.LastOperationStateFailed && !lastOperation.LastUpdateTime.Time.Before(operation.StartTimestamp.Truncate(time.Second)) {
+			if gardencorev1beta1helper.HasErrorCode(shoot.Status.LastErrors, gardener_types.ErrorInfraRateLimitsExceeded) {
+				return operations.StageResult{}, errors.New("error during credentials rotation: rate limits exceeded")
+			}
+
+			err := fmt.Errorf("Gardener Shoot reconciliation failed during credentials rotation. Last Shoot state: %s, Shoot description: %s", lastOperation.State, lastOperation.Description)
+			return operations.StageResult{}, operations.NewNonRecoverableError(err)
+		}
+	}
+
+	kind := pending[0]
+	if shoot.Annotations == nil {
+		shoot.Annotations = map[string]string{}
+	}
+	shoot.Annotations[v1beta1constants.GardenerOperation] = gardener.CredentialsRotationOperation(kind, rotation.Phase)
+
+	_, err = s.gardenerClient.Update(context.Background(), shoot, v1.UpdateOptions{})
+	if err != nil {
+		return operations.StageResult{}, util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient).Append("failed to trigger %s rotation", kind)
+	}
+
+	logger.Infof("Triggered %s phase of %s rotation", rotation.Phase, kind)
+
+	return operations.StageResult{Stage: s.Name(), Delay: 20 * time.Second}, nil
+}
@@ -0,0 +1,159 @@
+package credentialsrotation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/credentialsrotation/mocks"
+	dbMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util/testkit"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	clusterName = "shootName"
+	runtimeID   = "runtimeID"
+	operationID = "operationID"
+)
+
+func TestRotateCredentialsStep_Run(t *testing.T) {
+	startTime := time.Now().Add(-time.Minute)
+	rotatedTime := metav1.NewTime(startTime.Add(30 * time.Second))
+	earlierTime := metav1.NewTime(startTime.Add(-time.Hour))
+
+	cluster := model.Cluster{
+		ID:            runtimeID,
+		ClusterConfig: model.GardenerConfig{Name: clusterName},
+	}
+	operation := model.Operation{ID: operationID, StartTimestamp: startTime}
+	rotation := model.CredentialsRotation{
+		OperationID: operationID,
+		Kinds:       []model.CredentialsRotationKind{model.CertificateAuthorities, model.SSHKeypair},
+		Phase:       model.RotationPrepare,
+	}
+
+	t.Run("should trigger rotation of first pending credentials", func(t *testing.T) {
+		// given
+		shoot := testkit.NewTestShoot(clusterName).WithOperationSucceeded().ToShoot()
+
+		gardenerClient, dbSession := newMocks(rotation)
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(shoot, nil)
+		gardenerClient.On("Update", context.Background(), mock.MatchedBy(func(shoot *gardener_types.Shoot) bool {
+			return shoot.Annotations[v1beta1constants.GardenerOperation] == v1beta1constants.OperationRotateCAStart
+		}), mock.Anything).Return(shoot, nil)
+
+		step := NewRotateCredentialsStep(gardenerClient, dbSession, model.RefreshingKubeconfig, time.Hour)
+
+		// when
+		result, err := step.Run(cluster, operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.RotatingCredentials, result.Stage)
+		gardenerClient.AssertExpectations(t)
+	})
+
+	t.Run("should trigger next credentials when previous rotation finished", func(t *testing.T) {
+		// given
+		shoot := testkit.NewTestShoot(clusterName).WithOperationSucceeded().ToShoot()
+		shoot.Status.Credentials = &gardener_types.ShootCredentials{Rotation: &gardener_types.ShootCredentialsRotation{
+			CertificateAuthorities: &gardener_types.CARotation{Phase: gardener_types.RotationPrepared, LastInitiationTime: &rotatedTime},
+			SSHKeypair:             &gardener_types.ShootSSHKeypairRotation{LastInitiationTime: &earlierTime, LastCompletionTime: &earlierTime},
+		}}
+
+		gardenerClient, dbSession := newMocks(rotation)
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(shoot, nil)
+		gardenerClient.On("Update", context.Background(), mock.MatchedBy(func(shoot *gardener_types.Shoot) bool {
+			return shoot.Annotations[v1beta1constants.GardenerOperation] == v1beta1constants.ShootOperationRotateSSHKeypair
+		}), mock.Anything).Return(shoot, nil)
+
+		step := NewRotateCredentialsStep(gardenerClient, dbSession, model.RefreshingKubeconfig, time.Hour)
+
+		// when
+		result, err := step.Run(cluster, operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.RotatingCredentials, result.Stage)
+		gardenerClient.AssertExpectations(t)
+		dbSession.AssertCalled(t, "UpsertCredentialsRotationStatus", runtimeID, mock.MatchedBy(func(status model.CredentialsRotationStatus) bool {
+			return status.Kind == model.CertificateAuthorities && status.Phase == string(gardener_types.RotationPrepared)
+		}))
+	})
+
+	t.Run("should wait while Shoot is reconciled", func(t *testing.T) {
+		// given
+		shoot := testkit.NewTestShoot(clusterName).WithOperationProcessing().ToShoot()
+
+		gardenerClient, dbSession := newMocks(rotation)
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(shoot, nil)
+
+		step := NewRotateCredentialsStep(gardenerClient, dbSession, model.RefreshingKubeconfig, time.Hour)
+
+		// when
+		result, err := step.Run(cluster, operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.RotatingCredentials, result.Stage)
+		assert.Equal(t, 20*time.Second, result.Delay)
+		gardenerClient.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should proceed when all credentials are rotated", func(t *testing.T) {
+		// given
+		shoot := testkit.NewTestShoot(clusterName).WithOperationSucceeded().ToShoot()
+		shoot.Status.Credentials = &gardener_types.ShootCredentials{Rotation: &gardener_types.ShootCredentialsRotation{
+			CertificateAuthorities: &gardener_types.CARotation{Phase: gardener_types.RotationPrepared, LastInitiationTime: &rotatedTime},
+			SSHKeypair:             &gardener_types.ShootSSHKeypairRotation{LastInitiationTime: &rotatedTime, LastCompletionTime: &rotatedTime},
+		}}
+
+		gardenerClient, dbSession := newMocks(rotation)
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(shoot, nil)
+
+		step := NewRotateCredentialsStep(gardenerClient, dbSession, model.RefreshingKubeconfig, time.Hour)
+
+		// when
+		result, err := step.Run(cluster, operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.RefreshingKubeconfig, result.Stage)
+	})
+
+	t.Run("should fail when Shoot reconciliation failed", func(t *testing.T) {
+		// given
+		shoot := testkit.NewTestShoot(clusterName).WithOperationFailed().ToShoot()
+		shoot.Status.LastOperation.LastUpdateTime = rotatedTime
+
+		gardenerClient, dbSession := newMocks(rotation)
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(shoot, nil)
+
+		step := NewRotateCredentialsStep(gardenerClient, dbSession, model.RefreshingKubeconfig, time.Hour)
+
+		// when
+		_, err := step.Run(cluster, operation, logrus.New())
+
+		// then
+		require.Error(t, err)
+		nonRecoverable := operations.NonRecoverableError{}
+		require.ErrorAs(t, err, &nonRecoverable)
+	})
+}
+
+func newMocks(rotation model.CredentialsRotation) (*mocks.GardenerClient, *dbMocks.ReadWriteSession) {
+	dbSession := &dbMocks.ReadWriteSession{}
+	dbSession.On("GetCredentialsRotation", rotation.OperationID).Return(rotation, nil)
+	dbSession.On("UpsertCredentialsRotationStatus", runtimeID, mock.AnythingOfType("model.CredentialsRotationStatus")).Return(nil)
+
+	return &mocks.GardenerClient{}, dbSession
+}
@@ -0,0 +1,246 @@
+package deprovisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util/k8s"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	cleanupRetryDelay = 20 * time.Second
+	// cleanupTimeLimitMargin lets the step give up the cleanup before the operation times out
+	cleanupTimeLimitMargin = 5 * time.Minute
+)
+
+//go:generate mockery --name=DynamicKubeconfigProvider
+type DynamicKubeconfigProvider interface {
+	FetchFromRequest(shootName string) ([]byte, error)
+}
+
+// ResourceSelector selects resources removed from the cluster before the Shoot is deleted
+type ResourceSelector struct {
+	Group         string `json:"group"`
+	Version       string `json:"version"`
+	Resource      string `json:"resource"`
+	LabelSelector string `json:"labelSelector"`
+	// FieldsMatch limits deletion to resources with given field values, for example {"spec.type": "LoadBalancer"}
+	FieldsMatch map[string]string `json:"fieldsMatch"`
+	// IgnoredFinalizers are not awaited, for example finalizers removed only after workloads are deleted together with the Shoot
+	IgnoredFinalizers []string `json:"ignoredFinalizers"`
+}
+
+// DefaultCleanupSelectors removes Service Manager bindings before instances, then LoadBalancer Services and PVCs
+func DefaultCleanupSelectors() []ResourceSelector {
+	return []ResourceSelector{
+		{Group: "services.cloud.sap.com", Version: "v1", Resource: "servicebindings"},
+		{Group: "services.cloud.sap.com", Version: "v1", Resource: "serviceinstances"},
+		{Version: "v1", Resource: "services", FieldsMatch: map[string]string{"spec.type": "LoadBalancer"}},
+		{Version: "v1", Resource: "persistentvolumeclaims", IgnoredFinalizers: []string{"kubernetes.io/pvc-protection"}},
+	}
+}
+
+// LoadCleanupSelectors reads selectors from the JSON file in format: {"resources": [{"group": "...", "version": "...", "resource": "..."}]}
+func LoadCleanupSelectors(path string) ([]ResourceSelector, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cleanup config file: %s", err.Error())
+	}
+
+	defer file.Close()
+
+	var config struct {
+		Resources []ResourceSelector `json:"resources"`
+	}
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode cleanup config file: %s", err.Error())
+	}
+
+	for _, selector := range config.Resources {
+		if selector.Version == "" || selector.Resource == "" {
+			return nil, fmt.Errorf("version and resource must be specified for cleanup resource selector")
+		}
+	}
+
+	return config.Resources, nil
+}
+
+type CleanupClusterStep struct {
+	gardenerClient            GardenerClient
+	dynamicKubeconfigProvider DynamicKubeconfigProvider
+	dynamicClientProvider     k8s.DynamicClientProvider
+	selectors                 []ResourceSelector
+	nextStep                  model.OperationStage
+	timeLimit                 time.Duration
+}
+
+func NewCleanupClusterStep(
+	gardenerClient GardenerClient,
+	dynamicKubeconfigProvider DynamicKubeconfigProvider,
+	dynamicClientProvider k8s.DynamicClientProvider,
+	selectors []ResourceSelector,
+	nextStep model.OperationStage,
+	timeLimit time.Duration) *CleanupClusterStep {
+
+	return &CleanupClusterStep{
+		gardenerClient:            gardenerClient,
+		dynamicKubeconfigProvider: dynamicKubeconfigProvider,
+		dynamicClientProvider:     dynamicClientProvider,
+		selectors:                 selectors,
+		nextStep:                  nextStep,
+		timeLimit:                 timeLimit,
+	}
+}
+
+func (s *CleanupClusterStep) Name() model.OperationStage {
+	return model.CleanupCluster
+}
+
+// TimeLimit is longer than the cleanup time limit, so that the Shoot is deleted when the cleanup does not finish in time
+func (s *CleanupClusterStep) TimeLimit() time.Duration {
+	return s.timeLimit + cleanupTimeLimitMargin
+}
+
+// Run deletes the selected resources. The cleanup is best effort: when the cluster cannot be reached or the resources
+// are not deleted within the time limit, the Shoot is deleted anyway, so that it does not keep running.
+func (s *CleanupClusterStep) Run(cluster model.Cluster, operation model.Operation, logger logrus.FieldLogger) (operations.StageResult, error) {
+	if len(s.selectors) == 0 {
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	if s.cleanupTimeLimitReached(operation) {
+		logger.Warnf("Cluster cleanup did not finish within %s, continuing with deprovisioning", s.timeLimit)
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	shoot, err := s.gardenerClient.Get(context.Background(), cluster.ClusterConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logger.Info("Shoot does not exist, skipping cluster cleanup")
+			return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+		}
+		return operations.StageResult{}, util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient)
+	}
+
+	if shoot.DeletionTimestamp != nil {
+		logger.Info("Shoot is already being deleted, skipping cluster cleanup")
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	if shoot.Status.IsHibernated {
+		logger.Info("Shoot is hibernated, skipping cluster cleanup")
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	kubeconfig, err := s.dynamicKubeconfigProvider.FetchFromRequest(cluster.ClusterConfig.Name)
+	if err != nil {
+		logger.Warnf("Failed to fetch dynamic kubeconfig, skipping cluster cleanup: %s", err.Error())
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	dynamicClient, appErr := s.dynamicClientProvider.CreateDynamicClient(kubeconfig)
+	if appErr != nil {
+		logger.Warnf("Failed to create dynamic client, skipping cluster cleanup: %s", appErr.Error())
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	// Selectors are processed in order, so that for example bindings are gone before their instances are deleted
+	for _, selector := range s.selectors {
+		remaining, err := s.cleanup(dynamicClient, selector, logger)
+		if err != nil {
+			logger.Warnf("Failed to clean up cluster, skipping cluster cleanup: %s", err.Error())
+			return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+		}
+
+		if remaining > 0 {
+			logger.Infof("Waiting for deletion of %d %s", remaining, selector.Resource)
+			return operations.StageResult{Stage: s.Name(), Delay: cleanupRetryDelay}, nil
+		}
+	}
+
+	return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+}
+
+// cleanupTimeLimitReached checks the time since the operation entered the stage, as the executor does for TimeLimit
+func (s *CleanupClusterStep) cleanupTimeLimitReached(operation model.Operation) bool {
+	lastTimestamp := operation.StartTimestamp
+	if operation.LastTransition != nil {
+		lastTimestamp = *operation.LastTransition
+	}
+	return !lastTimestamp.IsZero() && time.Since(lastTimestamp) > s.timeLimit
+}
+
+// cleanup deletes resources matching the selector and returns the number of resources still awaiting deletion
+func (s *CleanupClusterStep) cleanup(dynamicClient dynamic.Interface, selector ResourceSelector, logger logrus.FieldLogger) (int, error) {
+	gvr := schema.GroupVersionResource{Group: selector.Group, Version: selector.Version, Resource: selector.Resource}
+
+	list, err := dynamicClient.Resource(gvr).List(context.Background(), metav1.ListOptions{LabelSelector: selector.LabelSelector})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logger.Debugf("Resource %s is not served by the cluster, skipping", gvr.String())
+			return 0, nil
+		}
+		return 0, util.K8SErrorToAppError(err).SetComponent(apperrors.ErrClusterK8SClient).Append("failed to list %s", gvr.String())
+	}
+
+	remaining := 0
+	for _, item := range list.Items {
+		if !fieldsMatch(item, selector.FieldsMatch) {
+			continue
+		}
+
+		if item.GetDeletionTimestamp() == nil {
+			logger.Infof("Deleting %s %s/%s", selector.Resource, item.GetNamespace(), item.GetName())
+			err := dynamicClient.Resource(gvr).Namespace(item.GetNamespace()).Delete(context.Background(), item.GetName(), metav1.DeleteOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return 0, util.K8SErrorToAppError(err).SetComponent(apperrors.ErrClusterK8SClient).Append("failed to delete %s %s/%s", selector.Resource, item.GetNamespace(), item.GetName())
+			}
+			remaining++
+			continue
+		}
+
+		if hasAwaitedFinalizers(item, selector.IgnoredFinalizers) {
+			remaining++
+		}
+	}
+
+	return remaining, nil
+}
+
+func fieldsMatch(item unstructured.Unstructured, fields map[string]string) bool {
+	for path, expected := range fields {
+		value, found, err := unstructured.NestedString(item.Object, strings.Split(path, ".")...)
+		if err != nil || !found || value != expected {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAwaitedFinalizers(item unstructured.Unstructured, ignoredFinalizers []string) bool {
+	ignored := map[string]bool{}
+	for _, finalizer := range ignoredFinalizers {
+		ignored[finalizer] = true
+	}
+
+	for _, finalizer := range item.GetFinalizers() {
+		if !ignored[finalizer] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package deprovisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	gardener_mocks "github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/deprovisioning/mocks"
+	k8sMocks "github.com/kyma-project/control-plane/components/provisioner/internal/util/k8s/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	serviceInstancesGVR = schema.GroupVersionResource{Group: "services.cloud.sap.com", Version: "v1", Resource: "serviceinstances"}
+	servicesGVR         = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+)
+
+func TestCleanupClusterStep_Run(t *testing.T) {
+	cluster := model.Cluster{
+		ID:            runtimeID,
+		ClusterConfig: model.GardenerConfig{Name: clusterName},
+	}
+	selectors := []ResourceSelector{
+		{Group: "services.cloud.sap.com", Version: "v1", Resource: "serviceinstances"},
+		{Version: "v1", Resource: "services", FieldsMatch: map[string]string{"spec.type": "LoadBalancer"}},
+	}
+
+	t.Run("should delete resources and wait for their deletion", func(t *testing.T) {
+		// given
+		dynamicClient := newFakeDynamicClient(
+			fixUnstructured("ServiceInstance", "services.cloud.sap.com/v1", "instance", nil),
+			fixUnstructured("Service", "v1", "load-balancer", map[string]interface{}{"type": "LoadBalancer"}),
+		)
+		step := newTestCleanupClusterStep(t, fixShoot(), dynamicClient, selectors)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.CleanupCluster, result.Stage)
+		assert.Equal(t, cleanupRetryDelay, result.Delay)
+
+		_, err = dynamicClient.Resource(serviceInstancesGVR).Namespace("default").Get(context.Background(), "instance", metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+		_, err = dynamicClient.Resource(servicesGVR).Namespace("default").Get(context.Background(), "load-balancer", metav1.GetOptions{})
+		assert.NoError(t, err, "services should be deleted after service instances are gone")
+	})
+
+	t.Run("should go to the next step when matching resources are gone", func(t *testing.T) {
+		// given
+		dynamicClient := newFakeDynamicClient(
+			fixUnstructured("Service", "v1", "cluster-ip", map[string]interface{}{"type": "ClusterIP"}),
+		)
+		step := newTestCleanupClusterStep(t, fixShoot(), dynamicClient, selectors)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+		assert.Equal(t, time.Duration(0), result.Delay)
+
+		_, err = dynamicClient.Resource(servicesGVR).Namespace("default").Get(context.Background(), "cluster-ip", metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should wait for resources with finalizers", func(t *testing.T) {
+		// given
+		deletionTimestamp := metav1.Now()
+		instance := fixUnstructured("ServiceInstance", "services.cloud.sap.com/v1", "instance", nil)
+		instance.SetDeletionTimestamp(&deletionTimestamp)
+		instance.SetFinalizers([]string{"services.cloud.sap.com/sap-btp-finalizer"})
+
+		step := newTestCleanupClusterStep(t, fixShoot(), newFakeDynamicClient(instance), selectors)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.CleanupCluster, result.Stage)
+	})
+
+	t.Run("should not wait for ignored finalizers", func(t *testing.T) {
+		// given
+		deletionTimestamp := metav1.Now()
+		instance := fixUnstructured("ServiceInstance", "services.cloud.sap.com/v1", "instance", nil)
+		instance.SetDeletionTimestamp(&deletionTimestamp)
+		instance.SetFinalizers([]string{"services.cloud.sap.com/sap-btp-finalizer"})
+
+		ignoringSelectors := []ResourceSelector{
+			{Group: "services.cloud.sap.com", Version: "v1", Resource: "serviceinstances", IgnoredFinalizers: []string{"services.cloud.sap.com/sap-btp-finalizer"}},
+		}
+		step := newTestCleanupClusterStep(t, fixShoot(), newFakeDynamicClient(instance), ignoringSelectors)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+	})
+
+	t.Run("should skip cleanup when Shoot is hibernated", func(t *testing.T) {
+		// given
+		shoot := fixShoot()
+		shoot.Status.IsHibernated = true
+
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", mock.Anything, clusterName, mock.Anything).Return(shoot, nil)
+		step := NewCleanupClusterStep(gardenerClient, nil, nil, selectors, nextStageName, 10*time.Minute)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+	})
+
+	t.Run("should continue with deprovisioning when cleanup time limit is reached", func(t *testing.T) {
+		// given
+		deletionTimestamp := metav1.Now()
+		instance := fixUnstructured("ServiceInstance", "services.cloud.sap.com/v1", "instance", nil)
+		instance.SetDeletionTimestamp(&deletionTimestamp)
+		instance.SetFinalizers([]string{"services.cloud.sap.com/sap-btp-finalizer"})
+
+		step := NewCleanupClusterStep(nil, nil, nil, selectors, nextStageName, 10*time.Minute)
+		lastTransition := time.Now().Add(-11 * time.Minute)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{StartTimestamp: time.Now().Add(-time.Hour), LastTransition: &lastTransition}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+		assert.Greater(t, step.TimeLimit(), 10*time.Minute)
+	})
+
+	t.Run("should continue with deprovisioning when kubeconfig cannot be fetched", func(t *testing.T) {
+		// given
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", mock.Anything, clusterName, mock.Anything).Return(fixShoot(), nil)
+		kubeconfigProvider := &gardener_mocks.DynamicKubeconfigProvider{}
+		kubeconfigProvider.On("FetchFromRequest", clusterName).Return(nil, errors.New("forbidden"))
+		step := NewCleanupClusterStep(gardenerClient, kubeconfigProvider, nil, selectors, nextStageName, 10*time.Minute)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{StartTimestamp: time.Now()}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+	})
+
+	t.Run("should continue with deprovisioning when cluster is not reachable", func(t *testing.T) {
+		// given
+		dynamicClient := newFakeDynamicClient()
+		dynamicClient.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("connection refused")
+		})
+		step := newTestCleanupClusterStep(t, fixShoot(), dynamicClient, selectors)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{StartTimestamp: time.Now()}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+	})
+
+	t.Run("should skip cleanup when no selectors are configured", func(t *testing.T) {
+		// given
+		step := NewCleanupClusterStep(nil, nil, nil, nil, nextStageName, 10*time.Minute)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+	})
+}
+
+func TestLoadCleanupSelectors(t *testing.T) {
+	// when
+	selectors, err := LoadCleanupSelectors("testdata/cleanup.json")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []ResourceSelector{
+		{Group: "services.cloud.sap.com", Version: "v1", Resource: "serviceinstances", LabelSelector: "app=test"},
+		{Version: "v1", Resource: "services", FieldsMatch: map[string]string{"spec.type": "LoadBalancer"}},
+	}, selectors)
+}
+
+func newTestCleanupClusterStep(t *testing.T, shoot *gardener_types.Shoot, dynamicClient *dynamicfake.FakeDynamicClient, selectors []ResourceSelector) *CleanupClusterStep {
+	gardenerClient := &gardener_mocks.GardenerClient{}
+	gardenerClient.On("Get", mock.Anything, clusterName, mock.Anything).Return(shoot, nil)
+
+	kubeconfigProvider := &gardener_mocks.DynamicKubeconfigProvider{}
+	kubeconfigProvider.On("FetchFromRequest", clusterName).Return([]byte("kubeconfig"), nil)
+
+	dynamicClientProvider := &k8sMocks.DynamicClientProvider{}
+	dynamicClientProvider.On("CreateDynamicClient", []byte("kubeconfig")).Return(dynamicClient, nil)
+
+	t.Cleanup(func() {
+		kubeconfigProvider.AssertExpectations(t)
+	})
+
+	return NewCleanupClusterStep(gardenerClient, kubeconfigProvider, dynamicClientProvider, selectors, nextStageName, 10*time.Minute)
+}
+
+func newFakeDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		serviceInstancesGVR: "ServiceInstanceList",
+		servicesGVR:         "ServiceList",
+	}, objects...)
+}
+
+func fixUnstructured(kind, apiVersion, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	object.SetKind(kind)
+	object.SetAPIVersion(apiVersion)
+	object.SetName(name)
+	object.SetNamespace("default")
+	return object
+}
+
+func fixShoot() *gardener_types.Shoot {
+	return &gardener_types.Shoot{ObjectMeta: metav1.ObjectMeta{Name: clusterName}}
+}
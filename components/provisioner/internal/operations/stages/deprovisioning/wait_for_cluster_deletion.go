@@ -19,20 +19,20 @@ import (
 )
 
 type WaitForClusterDeletionStep struct {
-	gardenerClient GardenerClient
-	dbsFactory     dbsession.Factory
-	directorClient director.DirectorClient
-	nextStep       model.OperationStage
-	timeLimit      time.Duration
+	gardenerClient  GardenerClient
+	dbsFactory      dbsession.Factory
+	runtimeRegistry director.RuntimeRegistry
+	nextStep        model.OperationStage
+	timeLimit       time.Duration
 }
 
-func NewWaitForClusterDeletionStep(gardenerClient GardenerClient, dbsFactory dbsession.Factory, directorClient director.DirectorClient, nextStep model.OperationStage, timeLimit time.Duration) *WaitForClusterDeletionStep {
+func NewWaitForClusterDeletionStep(gardenerClient GardenerClient, dbsFactory dbsession.Factory, runtimeRegistry director.RuntimeRegistry, nextStep model.OperationStage, timeLimit time.Duration) *WaitForClusterDeletionStep {
 	return &WaitForClusterDeletionStep{
-		gardenerClient: gardenerClient,
-		dbsFactory:     dbsFactory,
-		directorClient: directorClient,
-		nextStep:       nextStep,
-		timeLimit:      timeLimit,
+		gardenerClient:  gardenerClient,
+		dbsFactory:      dbsFactory,
+		runtimeRegistry: runtimeRegistry,
+		nextStep:        nextStep,
+		timeLimit:       timeLimit,
 	}
 }
 
@@ -103,7 +103,7 @@ func (s *WaitForClusterDeletionStep) setDeprovisioningFinished(cluster model.Clu
 func (s *WaitForClusterDeletionStep) deleteRuntime(cluster model.Cluster) error {
 	var exists bool
 	err := util.RetryOnError(5*time.Second, 3, "Error while checking if runtime exists in Director: %s", func() (err apperrors.AppError) {
-		exists, err = s.directorClient.RuntimeExists(cluster.ID, cluster.Tenant)
+		exists, err = s.runtimeRegistry.RuntimeExists(cluster.ID, cluster.Tenant)
 		return
 	})
 
@@ -116,7 +116,7 @@ func (s *WaitForClusterDeletionStep) deleteRuntime(cluster model.Cluster) error
 	}
 
 	err = util.RetryOnError(5*time.Second, 3, "Error while unregistering runtime in Director: %s", func() (err apperrors.AppError) {
-		err = s.directorClient.DeleteRuntime(cluster.ID, cluster.Tenant)
+		err = s.runtimeRegistry.DeleteRuntime(cluster.ID, cluster.Tenant)
 		return
 	})
 
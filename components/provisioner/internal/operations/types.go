@@ -41,6 +41,11 @@ type FailureHandler interface {
 	HandleFailure(operation model.Operation, cluster model.Cluster) error
 }
 
+//go:generate mockery --name=DiagnosticsCollector
+type DiagnosticsCollector interface {
+	Collect(cluster model.Cluster, operation model.Operation) (model.OperationDiagnostics, error)
+}
+
 func ConvertToAppError(err error) apperrors.AppError {
 	if nonRecoverErr := (NonRecoverableError{}); errors.As(err, &nonRecoverErr) {
 		err = nonRecoverErr.error
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	apperrors "github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/kyma-project/control-plane/components/provisioner/internal/model"
+)
+
+// Scanner is an autogenerated mock type for the Scanner type
+type Scanner struct {
+	mock.Mock
+}
+
+// Run provides a mock function with given fields: stop
+func (_m *Scanner) Run(stop <-chan struct{}) {
+	_m.Called(stop)
+}
+
+// Scan provides a mock function with given fields: remediate
+func (_m *Scanner) Scan(remediate bool) (model.OrphansReport, apperrors.AppError) {
+	ret := _m.Called(remediate)
+
+	var r0 model.OrphansReport
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(bool) (model.OrphansReport, apperrors.AppError)); ok {
+		return rf(remediate)
+	}
+	if rf, ok := ret.Get(0).(func(bool) model.OrphansReport); ok {
+		r0 = rf(remediate)
+	} else {
+		r0 = ret.Get(0).(model.OrphansReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(bool) apperrors.AppError); ok {
+		r1 = rf(remediate)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewScanner creates a new instance of Scanner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScanner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Scanner {
+	mock := &Scanner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
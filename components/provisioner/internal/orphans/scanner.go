@@ -0,0 +1,304 @@
+package orphans
+
+import (
+	"context"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type Config struct {
+	Enabled  bool          `envconfig:"default=false"`
+	Interval time.Duration `envconfig:"default=1h"`
+	// Remediate enables removal of orphans found by the periodic scan, otherwise they are only reported
+	Remediate bool `envconfig:"default=false"`
+	// MaxRemediationsPerScan limits the number of orphans removed in a single scan
+	MaxRemediationsPerScan int `envconfig:"default=5"`
+	// AbortRemediationThreshold disables remediation if more orphans are found, which most likely indicates misconfiguration
+	AbortRemediationThreshold int `envconfig:"default=20"`
+	// MinShootAge protects Shoots which are being created and not yet stored in the database
+	MinShootAge time.Duration `envconfig:"default=1h"`
+	// DirectorCheckWindow limits the Director Runtime checks of deleted clusters to the ones deleted within the window,
+	// so that the number of Director calls does not grow with every deleted cluster. All deleted clusters are checked if it is 0.
+	DirectorCheckWindow time.Duration `envconfig:"default=168h"`
+}
+
+//go:generate mockery --name=ShootClient
+type ShootClient interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*gardener_types.ShootList, error)
+	Update(ctx context.Context, shoot *gardener_types.Shoot, opts metav1.UpdateOptions) (*gardener_types.Shoot, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+//go:generate mockery --name=Scanner
+type Scanner interface {
+	Scan(remediate bool) (model.OrphansReport, apperrors.AppError)
+	Run(stop <-chan struct{})
+}
+
+type scanner struct {
+	config           Config
+	dbSessionFactory dbsession.Factory
+	runtimeRegistry  director.RuntimeRegistry
+	shootClient      ShootClient
+	log              logrus.FieldLogger
+	timeNow          func() time.Time
+}
+
+func NewScanner(config Config, dbSessionFactory dbsession.Factory, runtimeRegistry director.RuntimeRegistry, shootClient ShootClient) Scanner {
+	return &scanner{
+		config:           config,
+		dbSessionFactory: dbSessionFactory,
+		runtimeRegistry:  runtimeRegistry,
+		shootClient:      shootClient,
+		log:              logrus.WithField("Component", "OrphanScanner"),
+		timeNow:          time.Now,
+	}
+}
+
+// Run periodically scans for orphans until stop channel is closed
+// disabledScanner is used when no scanner is configured, it reports that scanning is not available
+type disabledScanner struct{}
+
+func NewDisabledScanner() Scanner {
+	return disabledScanner{}
+}
+
+func (disabledScanner) Scan(_ bool) (model.OrphansReport, apperrors.AppError) {
+	return model.OrphansReport{}, apperrors.BadRequest("orphan scanning is disabled")
+}
+
+func (disabledScanner) Run(_ <-chan struct{}) {}
+
+func (s *scanner) Run(stop <-chan struct{}) {
+	go wait.Until(func() {
+		_, err := s.Scan(s.config.Remediate)
+		if err != nil {
+			s.log.Errorf("Failed to scan for orphans: %s", err.Error())
+		}
+	}, s.config.Interval, stop)
+}
+
+func (s *scanner) Scan(remediate bool) (model.OrphansReport, apperrors.AppError) {
+	report := model.OrphansReport{ScanTime: s.timeNow(), Orphans: []model.Orphan{}}
+
+	clusters, shoots, err := s.listClustersAndShoots()
+	if err != nil {
+		return model.OrphansReport{}, err
+	}
+
+	activeClusters := map[string]model.ClusterReference{}
+	for _, cluster := range clusters {
+		if !cluster.Deleted {
+			activeClusters[cluster.ID] = cluster
+		}
+	}
+
+	shootsByName := map[string]gardener_types.Shoot{}
+	managedShoots := map[string]bool{}
+	for _, shoot := range shoots {
+		shootsByName[shoot.Name] = shoot
+		if runtimeID := gardener.RuntimeID(shoot); runtimeID != "" {
+			managedShoots[runtimeID] = true
+		}
+	}
+
+	clustersInProgress, err := s.listClustersWithOperationsInProgress()
+	if err != nil {
+		return model.OrphansReport{}, err
+	}
+
+	for _, cluster := range activeClusters {
+		if _, found := shootsByName[cluster.ShootName]; !found && !clustersInProgress[cluster.ID] {
+			report.Orphans = append(report.Orphans, model.Orphan{
+				Category:  model.ClusterWithoutShoot,
+				RuntimeID: cluster.ID,
+				Tenant:    cluster.Tenant,
+				ShootName: cluster.ShootName,
+			})
+		}
+	}
+
+	for _, shoot := range shoots {
+		runtimeID := gardener.RuntimeID(shoot)
+		if runtimeID == "" || shoot.DeletionTimestamp != nil || s.timeNow().Sub(shoot.CreationTimestamp.Time) < s.config.MinShootAge {
+			continue
+		}
+		if _, found := activeClusters[runtimeID]; found {
+			continue
+		}
+
+		tenant := shoot.Labels[model.AccountLabel]
+		report.Orphans = append(report.Orphans, model.Orphan{
+			Category:  model.ShootWithoutCluster,
+			RuntimeID: runtimeID,
+			Tenant:    tenant,
+			ShootName: shoot.Name,
+		})
+
+		orphan, err := s.checkDirectorRuntime(runtimeID, tenant, shoot.Name)
+		if err != nil {
+			return model.OrphansReport{}, err
+		}
+		if orphan != nil {
+			report.Orphans = append(report.Orphans, *orphan)
+		}
+	}
+
+	for _, cluster := range clusters {
+		if !cluster.Deleted || managedShoots[cluster.ID] || !s.deletedWithinDirectorCheckWindow(cluster) {
+			continue
+		}
+		if _, found := activeClusters[cluster.ID]; found {
+			continue
+		}
+
+		orphan, err := s.checkDirectorRuntime(cluster.ID, cluster.Tenant, "")
+		if err != nil {
+			return model.OrphansReport{}, err
+		}
+		if orphan != nil {
+			report.Orphans = append(report.Orphans, *orphan)
+		}
+	}
+
+	s.log.Infof("Found %d orphans", len(report.Orphans))
+	for _, orphan := range report.Orphans {
+		s.log.Infof("Orphan %s: Runtime %s, tenant %s, Shoot %s", orphan.Category, orphan.RuntimeID, orphan.Tenant, orphan.ShootName)
+	}
+
+	if remediate {
+		s.remediate(report.Orphans, shootsByName)
+	}
+
+	return report, nil
+}
+
+func (s *scanner) listClustersAndShoots() ([]model.ClusterReference, []gardener_types.Shoot, apperrors.AppError) {
+	clusters, dberr := s.dbSessionFactory.NewReadSession().ListClusterReferences()
+	if dberr != nil {
+		return nil, nil, dberr.Append("failed to list clusters")
+	}
+
+	shoots, err := s.shootClient.List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient).Append("failed to list Shoots")
+	}
+
+	return clusters, shoots.Items, nil
+}
+
+func (s *scanner) listClustersWithOperationsInProgress() (map[string]bool, apperrors.AppError) {
+	operations, dberr := s.dbSessionFactory.NewReadSession().ListInProgressOperations()
+	if dberr != nil {
+		return nil, dberr.Append("failed to list operations in progress")
+	}
+
+	clusters := map[string]bool{}
+	for _, operation := range operations {
+		clusters[operation.ClusterID] = true
+	}
+
+	return clusters, nil
+}
+
+func (s *scanner) deletedWithinDirectorCheckWindow(cluster model.ClusterReference) bool {
+	if s.config.DirectorCheckWindow == 0 {
+		return true
+	}
+	return cluster.DeletionTimestamp != nil && s.timeNow().Sub(*cluster.DeletionTimestamp) <= s.config.DirectorCheckWindow
+}
+
+func (s *scanner) checkDirectorRuntime(runtimeID, tenant, shootName string) (*model.Orphan, apperrors.AppError) {
+	if tenant == "" {
+		return nil, nil
+	}
+
+	exists, err := s.runtimeRegistry.RuntimeExists(runtimeID, tenant)
+	if err != nil {
+		return nil, err.Append("failed to check if Runtime %s exists in Director", runtimeID)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	return &model.Orphan{
+		Category:  model.DirectorRuntimeWithoutCluster,
+		RuntimeID: runtimeID,
+		Tenant:    tenant,
+		ShootName: shootName,
+	}, nil
+}
+
+func (s *scanner) remediate(orphans []model.Orphan, shoots map[string]gardener_types.Shoot) {
+	if len(orphans) > s.config.AbortRemediationThreshold {
+		s.log.Warnf("Found %d orphans which exceeds the threshold of %d, skipping remediation", len(orphans), s.config.AbortRemediationThreshold)
+		return
+	}
+
+	remediations := 0
+	for i := range orphans {
+		if remediations >= s.config.MaxRemediationsPerScan {
+			s.log.Infof("Limit of %d remediations per scan reached, remaining orphans will be removed in the next scans", s.config.MaxRemediationsPerScan)
+			return
+		}
+		remediations++
+
+		orphan := &orphans[i]
+
+		var err error
+		switch orphan.Category {
+		case model.DirectorRuntimeWithoutCluster:
+			err = s.runtimeRegistry.DeleteRuntime(orphan.RuntimeID, orphan.Tenant)
+		case model.ShootWithoutCluster:
+			err = s.deleteShoot(shoots[orphan.ShootName])
+		case model.ClusterWithoutShoot:
+			err = s.markClusterAsDeleted(orphan.RuntimeID)
+		}
+
+		if err != nil {
+			s.log.Errorf("Failed to remediate orphan %s of Runtime %s: %s", orphan.Category, orphan.RuntimeID, err.Error())
+			orphan.RemediationError = err.Error()
+			continue
+		}
+
+		s.log.Infof("Remediated orphan %s of Runtime %s", orphan.Category, orphan.RuntimeID)
+		orphan.Remediated = true
+	}
+}
+
+func (s *scanner) deleteShoot(shoot gardener_types.Shoot) error {
+	gardener.AnnotateWithConfirmDeletion(&shoot)
+
+	_, err := s.shootClient.Update(context.Background(), &shoot, metav1.UpdateOptions{})
+	if err != nil {
+		return util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient).Append("failed to annotate Shoot with deletion confirmation")
+	}
+
+	err = s.shootClient.Delete(context.Background(), shoot.Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return util.K8SErrorToAppError(err).SetComponent(apperrors.ErrGardenerClient).Append("failed to delete Shoot")
+	}
+
+	return nil
+}
+
+// Director Runtime of the cluster marked as deleted is reported in the next scan
+func (s *scanner) markClusterAsDeleted(runtimeID string) error {
+	dberr := s.dbSessionFactory.NewWriteSession().MarkClusterAsDeleted(runtimeID)
+	if dberr != nil {
+		return dberr.Append("failed to mark cluster as deleted")
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package orphans
+
+import (
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	directorMocks "github.com/kyma-project/control-plane/components/provisioner/internal/director/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/orphans/mocks"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	tenant = "tenant"
+
+	activeRuntimeID         = "active"
+	clusterWithoutShootID   = "cluster-without-shoot"
+	deprovisioningRuntimeID = "deprovisioning"
+	shootWithoutClusterID   = "shoot-without-cluster"
+	deletedRuntimeID        = "deleted"
+	directorOrphanID        = "director-orphan"
+	youngShootRuntimeID     = "young-shoot"
+	longDeletedRuntimeID    = "long-deleted"
+	// deletedWithoutTimestampID is a cluster deleted before the deletion timestamp was stored
+	deletedWithoutTimestampID = "deleted-without-timestamp"
+)
+
+const directorCheckWindow = 7 * 24 * time.Hour
+
+var (
+	now            = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	recentDeletion = now.Add(-24 * time.Hour)
+	oldDeletion    = now.Add(-30 * 24 * time.Hour)
+)
+
+func TestScanner_Scan(t *testing.T) {
+	t.Run("should report orphans of each category", func(t *testing.T) {
+		// given
+		scanner, directorClient, shootClient, _ := newTestScanner(Config{MinShootAge: time.Hour, DirectorCheckWindow: directorCheckWindow})
+
+		// when
+		report, err := scanner.Scan(false)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, now, report.ScanTime)
+		directorClient.AssertNotCalled(t, "RuntimeExists", longDeletedRuntimeID, mock.Anything)
+		directorClient.AssertNotCalled(t, "RuntimeExists", deletedWithoutTimestampID, mock.Anything)
+		assert.ElementsMatch(t, []model.Orphan{
+			{Category: model.ClusterWithoutShoot, RuntimeID: clusterWithoutShootID, Tenant: tenant, ShootName: "shoot-2"},
+			{Category: model.ShootWithoutCluster, RuntimeID: shootWithoutClusterID, Tenant: tenant, ShootName: "shoot-4"},
+			{Category: model.DirectorRuntimeWithoutCluster, RuntimeID: shootWithoutClusterID, Tenant: tenant, ShootName: "shoot-4"},
+			{Category: model.DirectorRuntimeWithoutCluster, RuntimeID: directorOrphanID, Tenant: tenant},
+		}, report.Orphans)
+		directorClient.AssertNotCalled(t, "DeleteRuntime", mock.Anything, mock.Anything)
+		shootClient.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should check Director Runtimes of all deleted clusters without window", func(t *testing.T) {
+		// given
+		scanner, directorClient, _, _ := newTestScanner(Config{MinShootAge: time.Hour})
+		directorClient.On("RuntimeExists", longDeletedRuntimeID, tenant).Return(true, nil)
+		directorClient.On("RuntimeExists", deletedWithoutTimestampID, tenant).Return(false, nil)
+
+		// when
+		report, err := scanner.Scan(false)
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, report.Orphans, model.Orphan{Category: model.DirectorRuntimeWithoutCluster, RuntimeID: longDeletedRuntimeID, Tenant: tenant})
+		directorClient.AssertCalled(t, "RuntimeExists", deletedWithoutTimestampID, tenant)
+	})
+
+	t.Run("should remediate orphans", func(t *testing.T) {
+		// given
+		scanner, directorClient, shootClient, writeSession := newTestScanner(Config{MinShootAge: time.Hour, DirectorCheckWindow: directorCheckWindow, MaxRemediationsPerScan: 10, AbortRemediationThreshold: 10})
+		directorClient.On("DeleteRuntime", mock.Anything, tenant).Return(nil)
+		shootClient.On("Update", mock.Anything, mock.MatchedBy(func(shoot *gardener_types.Shoot) bool {
+			return shoot.Name == "shoot-4" && shoot.Annotations["confirmation.gardener.cloud/deletion"] == "true"
+		}), mock.Anything).Return(&gardener_types.Shoot{}, nil)
+		shootClient.On("Delete", mock.Anything, "shoot-4", mock.Anything).Return(nil)
+		writeSession.On("MarkClusterAsDeleted", clusterWithoutShootID).Return(nil)
+
+		// when
+		report, err := scanner.Scan(true)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, report.Orphans, 4)
+		for _, orphan := range report.Orphans {
+			assert.True(t, orphan.Remediated)
+		}
+		directorClient.AssertCalled(t, "DeleteRuntime", shootWithoutClusterID, tenant)
+		directorClient.AssertCalled(t, "DeleteRuntime", directorOrphanID, tenant)
+		shootClient.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should limit the number of remediations per scan", func(t *testing.T) {
+		// given
+		scanner, directorClient, _, writeSession := newTestScanner(Config{MinShootAge: time.Hour, DirectorCheckWindow: directorCheckWindow, MaxRemediationsPerScan: 1, AbortRemediationThreshold: 10})
+		writeSession.On("MarkClusterAsDeleted", clusterWithoutShootID).Return(nil)
+		directorClient.On("DeleteRuntime", mock.Anything, tenant).Return(nil)
+
+		// when
+		report, err := scanner.Scan(true)
+
+		// then
+		require.NoError(t, err)
+		remediated := 0
+		for _, orphan := range report.Orphans {
+			if orphan.Remediated {
+				remediated++
+			}
+		}
+		assert.Equal(t, 1, remediated)
+	})
+
+	t.Run("should not remediate when the number of orphans exceeds threshold", func(t *testing.T) {
+		// given
+		scanner, directorClient, shootClient, writeSession := newTestScanner(Config{MinShootAge: time.Hour, DirectorCheckWindow: directorCheckWindow, MaxRemediationsPerScan: 10, AbortRemediationThreshold: 3})
+
+		// when
+		report, err := scanner.Scan(true)
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, report.Orphans, 4)
+		directorClient.AssertNotCalled(t, "DeleteRuntime", mock.Anything, mock.Anything)
+		shootClient.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		writeSession.AssertNotCalled(t, "MarkClusterAsDeleted", mock.Anything)
+	})
+}
+
+func newTestScanner(config Config) (*scanner, *directorMocks.DirectorClient, *mocks.ShootClient, *sessionMocks.WriteSession) {
+	readSession := &sessionMocks.ReadSession{}
+	writeSession := &sessionMocks.WriteSession{}
+	sessionFactory := &sessionMocks.Factory{}
+	sessionFactory.On("NewReadSession").Return(readSession)
+	sessionFactory.On("NewWriteSession").Return(writeSession)
+
+	readSession.On("ListClusterReferences").Return([]model.ClusterReference{
+		{ID: activeRuntimeID, Tenant: tenant, ShootName: "shoot-1"},
+		{ID: clusterWithoutShootID, Tenant: tenant, ShootName: "shoot-2"},
+		{ID: deprovisioningRuntimeID, Tenant: tenant, ShootName: "shoot-3"},
+		{ID: deletedRuntimeID, Tenant: tenant, ShootName: "shoot-5", Deleted: true, DeletionTimestamp: &recentDeletion},
+		{ID: directorOrphanID, Tenant: tenant, ShootName: "shoot-6", Deleted: true, DeletionTimestamp: &recentDeletion},
+		{ID: longDeletedRuntimeID, Tenant: tenant, ShootName: "shoot-8", Deleted: true, DeletionTimestamp: &oldDeletion},
+		{ID: deletedWithoutTimestampID, Tenant: tenant, ShootName: "shoot-9", Deleted: true},
+	}, nil)
+	readSession.On("ListInProgressOperations").Return([]model.Operation{
+		{ID: "operation", ClusterID: deprovisioningRuntimeID, State: model.InProgress},
+	}, nil)
+
+	shootClient := &mocks.ShootClient{}
+	shootClient.On("List", mock.Anything, mock.Anything).Return(&gardener_types.ShootList{
+		Items: []gardener_types.Shoot{
+			newShoot("shoot-1", activeRuntimeID, now.Add(-24*time.Hour)),
+			newShoot("shoot-4", shootWithoutClusterID, now.Add(-24*time.Hour)),
+			newShoot("shoot-7", youngShootRuntimeID, now.Add(-time.Minute)),
+			{ObjectMeta: metav1.ObjectMeta{Name: "not-managed"}},
+		},
+	}, nil)
+
+	directorClient := &directorMocks.DirectorClient{}
+	directorClient.On("RuntimeExists", shootWithoutClusterID, tenant).Return(true, nil)
+	directorClient.On("RuntimeExists", deletedRuntimeID, tenant).Return(false, nil)
+	directorClient.On("RuntimeExists", directorOrphanID, tenant).Return(true, nil)
+
+	scanner := NewScanner(config, sessionFactory, directorClient, shootClient).(*scanner)
+	scanner.timeNow = func() time.Time {
+		return now
+	}
+
+	return scanner, directorClient, shootClient, writeSession
+}
+
+func newShoot(name, runtimeID string, creationTime time.Time) gardener_types.Shoot {
+	return gardener_types.Shoot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(creationTime),
+			Labels:            map[string]string{model.AccountLabel: tenant},
+			Annotations:       map[string]string{"kcp.provisioner.kyma-project.io/runtime-id": runtimeID},
+		},
+	}
+}
@@ -3,8 +3,12 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/testutils"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -66,6 +70,56 @@ func TestSchemaInitializer(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("Should insert operations of every type", func(t *testing.T) {
+		//given
+		containerCleanupFunc, connString, err := testutils.InitTestDBContainer(t, ctx, "test_DB_5")
+		require.NoError(t, err)
+
+		defer containerCleanupFunc()
+
+		connection, err := InitializeDatabaseConnection(connString, 4)
+		require.NoError(t, err)
+		require.NotNil(t, connection)
+
+		defer testutils.CloseDatabase(t, connection)
+
+		err = SetupSchema(connection, testutils.SchemaFilePath)
+		require.NoError(t, err)
+
+		dbsFactory, err := dbsession.NewFactory(connection, "qbl92bqtl6zshtjb4bvbwwc2qk7vtw2d")
+		require.NoError(t, err)
+		writeSession := dbsFactory.NewWriteSession()
+
+		clusterID := uuid.New().String()
+		dbErr := writeSession.InsertCluster(model.Cluster{ID: clusterID, Tenant: "tenant", CreationTimestamp: time.Now()})
+		require.NoError(t, dbErr)
+
+		for _, operationType := range []model.OperationType{
+			model.Provision,
+			model.ProvisionNoInstall,
+			model.Upgrade,
+			model.UpgradeShoot,
+			model.Deprovision,
+			model.DeprovisionNoInstall,
+			model.ReconnectRuntime,
+			model.Hibernate,
+			model.RotateCredentials,
+		} {
+			// when
+			dbErr = writeSession.InsertOperation(model.Operation{
+				ID:             uuid.New().String(),
+				Type:           operationType,
+				StartTimestamp: time.Now(),
+				State:          model.Succeeded,
+				ClusterID:      clusterID,
+				Stage:          model.FinishedStage,
+			})
+
+			// then
+			assert.NoError(t, dbErr, "operation type: %s", operationType)
+		}
+	})
+
 	t.Run("Should return error when failed to connect to the database", func(t *testing.T) {
 
 		containerCleanupFunc, _, err := testutils.InitTestDBContainer(t, ctx, "test_DB_3")
@@ -0,0 +1,284 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type BulkOperationsConfig struct {
+	CheckInterval time.Duration `envconfig:"default=30s"`
+}
+
+//go:generate mockery --name=ShootUpgrader
+type ShootUpgrader interface {
+	UpgradeGardenerShoot(runtimeID string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, apperrors.AppError)
+}
+
+// BulkOperationScheduler starts child operations of bulk operations within the limits of their strategy and tracks their results
+type BulkOperationScheduler struct {
+	config           BulkOperationsConfig
+	dbSessionFactory dbsession.Factory
+	shootUpgrader    ShootUpgrader
+	log              logrus.FieldLogger
+	timeNow          func() time.Time
+}
+
+func NewBulkOperationScheduler(config BulkOperationsConfig, dbSessionFactory dbsession.Factory, shootUpgrader ShootUpgrader) *BulkOperationScheduler {
+	return &BulkOperationScheduler{
+		config:           config,
+		dbSessionFactory: dbSessionFactory,
+		shootUpgrader:    shootUpgrader,
+		log:              logrus.WithField("Component", "BulkOperationScheduler"),
+		timeNow:          time.Now,
+	}
+}
+
+// Run periodically processes unfinished bulk operations until stop channel is closed
+func (s *BulkOperationScheduler) Run(stop <-chan struct{}) {
+	go wait.Until(s.ProcessOperations, s.config.CheckInterval, stop)
+}
+
+func (s *BulkOperationScheduler) ProcessOperations() {
+	operations, dberr := s.dbSessionFactory.NewReadSession().ListUnfinishedBulkOperations()
+	if dberr != nil {
+		s.log.Errorf("Failed to list unfinished bulk operations: %s", dberr.Error())
+		return
+	}
+
+	for _, operation := range operations {
+		err := s.process(operation)
+		if err != nil {
+			s.log.WithField("BulkOperationId", operation.ID).Errorf("Failed to process bulk operation: %s", err.Error())
+		}
+	}
+}
+
+func (s *BulkOperationScheduler) process(operation model.BulkOperation) error {
+	log := s.log.WithField("BulkOperationId", operation.ID)
+	session := s.dbSessionFactory.NewReadWriteSession()
+
+	children, dberr := session.ListBulkOperationChildren(operation.ID)
+	if dberr != nil {
+		return dberr
+	}
+
+	dberr = s.refreshChildren(session, children)
+	if dberr != nil {
+		return dberr
+	}
+
+	if operation.State == model.BulkInProgress {
+		if operation.FailureRatioExceeded(children) {
+			log.Warnf("Maximum failure ratio %v exceeded, canceling pending child operations", operation.Strategy.MaxFailureRatio)
+
+			operation.State = model.BulkFailed
+			operation.Message = "Maximum failure ratio exceeded"
+			dberr = session.UpdateUnfinishedBulkOperationState(operation.ID, operation.State, operation.Message, nil)
+			if dberr != nil {
+				return stateChangedMeanwhile(dberr, log)
+			}
+
+			dberr = cancelPendingChildren(session, operation.ID, children, "Canceled after the maximum failure ratio was exceeded")
+			if dberr != nil {
+				return dberr
+			}
+		} else {
+			err := s.startChildren(session, operation, children, log)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.finishIfDone(session, operation, children, log)
+}
+
+// refreshChildren updates the state of children in progress with the state of their operations
+func (s *BulkOperationScheduler) refreshChildren(session dbsession.ReadWriteSession, children []model.BulkOperationChild) dberrors.Error {
+	for i, child := range children {
+		if child.State != model.InProgress {
+			continue
+		}
+
+		operation, dberr := session.GetOperation(child.OperationID)
+		if dberr != nil {
+			return dberr
+		}
+
+		if operation.State == model.InProgress || operation.State == model.Pending {
+			continue
+		}
+
+		child.State = operation.State
+		child.Message = operation.Message
+		dberr = session.UpdateBulkOperationChild(child)
+		if dberr != nil {
+			return dberr
+		}
+		children[i] = child
+	}
+
+	return nil
+}
+
+func (s *BulkOperationScheduler) startChildren(session dbsession.ReadWriteSession, operation model.BulkOperation, children []model.BulkOperationChild, log logrus.FieldLogger) error {
+	available := operation.Strategy.MaxParallel - countChildren(children, model.InProgress)
+	if available <= 0 || countChildren(children, model.Pending) == 0 {
+		return nil
+	}
+
+	var input gqlschema.UpgradeShootInput
+	err := json.Unmarshal([]byte(operation.Input), &input)
+	if err != nil {
+		return fmt.Errorf("failed to decode input: %s", err.Error())
+	}
+
+	for i, child := range children {
+		if available == 0 {
+			break
+		}
+		if child.State != model.Pending {
+			continue
+		}
+
+		child, started, dberr := s.startChild(operation.ID, child, input, log)
+		if dberr != nil {
+			return dberr
+		}
+		if !started {
+			return nil
+		}
+		if child.State == model.InProgress {
+			available--
+		}
+		children[i] = child
+
+		// Failures to start count towards the failure ratio as well
+		if operation.FailureRatioExceeded(children) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// startChild starts the operation of the child while the bulk operation is locked, so that the bulk operation paused
+// or canceled in the meantime does not start any more children. It returns false if the bulk operation is no longer in progress.
+func (s *BulkOperationScheduler) startChild(operationID string, child model.BulkOperationChild, input gqlschema.UpgradeShootInput, log logrus.FieldLogger) (model.BulkOperationChild, bool, dberrors.Error) {
+	txSession, dberr := s.dbSessionFactory.NewSessionWithinTransaction()
+	if dberr != nil {
+		return child, false, dberr
+	}
+	defer txSession.RollbackUnlessCommitted()
+
+	state, dberr := txSession.GetBulkOperationStateForUpdate(operationID)
+	if dberr != nil {
+		return child, false, dberr
+	}
+	if state != model.BulkInProgress {
+		log.Infof("Bulk operation is %s, no more child operations are started", state)
+		return child, false, nil
+	}
+
+	status, appErr := s.shootUpgrader.UpgradeGardenerShoot(child.RuntimeID, input)
+	if appErr != nil {
+		log.Warnf("Failed to start Shoot upgrade of Runtime %s: %s", child.RuntimeID, appErr.Error())
+		child.State = model.Failed
+		child.Message = fmt.Sprintf("Failed to start operation: %s", appErr.Error())
+	} else {
+		child.OperationID = *status.ID
+		child.State = model.InProgress
+		child.Message = ""
+	}
+
+	dberr = txSession.UpdateBulkOperationChild(child)
+	if dberr != nil {
+		return child, false, dberr
+	}
+
+	dberr = txSession.Commit()
+	if dberr != nil {
+		return child, false, dberr
+	}
+
+	return child, true, nil
+}
+
+// finishIfDone sets the end of the bulk operation once none of its children is pending or in progress
+func (s *BulkOperationScheduler) finishIfDone(session dbsession.ReadWriteSession, operation model.BulkOperation, children []model.BulkOperationChild, log logrus.FieldLogger) error {
+	if countChildren(children, model.Pending) > 0 || countChildren(children, model.InProgress) > 0 {
+		return nil
+	}
+
+	endTimestamp := s.timeNow()
+
+	// Canceled and failed bulk operations keep their state
+	if operation.State != model.BulkInProgress && operation.State != model.BulkPaused {
+		log.Infof("Bulk operation finished with state %s", operation.State)
+		return session.UpdateBulkOperationState(operation.ID, operation.State, operation.Message, &endTimestamp)
+	}
+
+	operation.State = model.BulkSucceeded
+	operation.Message = "Bulk operation succeeded"
+	if failed := countChildren(children, model.Failed); failed > 0 {
+		operation.State = model.BulkFailed
+		operation.Message = fmt.Sprintf("%d of %d child operations failed", failed, len(children))
+	}
+
+	dberr := session.UpdateUnfinishedBulkOperationState(operation.ID, operation.State, operation.Message, &endTimestamp)
+	if dberr != nil {
+		return stateChangedMeanwhile(dberr, log)
+	}
+
+	log.Infof("Bulk operation finished with state %s", operation.State)
+	return nil
+}
+
+// stateChangedMeanwhile ignores the error of the bulk operation which was canceled while it was processed,
+// it is finished with its new state in the next run
+func stateChangedMeanwhile(dberr dberrors.Error, log logrus.FieldLogger) error {
+	if dberr.Code() == dberrors.CodeNotFound {
+		log.Infof("Bulk operation state was changed meanwhile")
+		return nil
+	}
+
+	return dberr
+}
+
+// cancelPendingChildren cancels the children which are still pending in the database, so that children started
+// in the meantime are not overwritten
+func cancelPendingChildren(session dbsession.WriteSession, operationID string, children []model.BulkOperationChild, message string) dberrors.Error {
+	dberr := session.CancelPendingBulkOperationChildren(operationID, message)
+	if dberr != nil {
+		return dberr
+	}
+
+	for i, child := range children {
+		if child.State == model.Pending {
+			children[i].State = model.Canceled
+			children[i].Message = message
+		}
+	}
+
+	return nil
+}
+
+func countChildren(children []model.BulkOperationChild, state model.OperationState) int {
+	count := 0
+	for _, child := range children {
+		if child.State == state {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,229 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	mocks2 "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/mocks"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const bulkOperationID = "bulk-operation"
+
+func TestBulkOperationScheduler_ProcessOperations(t *testing.T) {
+	now := time.Now()
+
+	bulkOperation := model.BulkOperation{
+		ID:             bulkOperationID,
+		Type:           model.UpgradeShoot,
+		State:          model.BulkInProgress,
+		Input:          `{"gardenerConfig":{"kubernetesVersion":"1.27.1"}}`,
+		Strategy:       model.BulkOperationStrategy{MaxParallel: 2, MaxFailureRatio: 0.5},
+		StartTimestamp: now,
+	}
+	expectedInput := gqlschema.UpgradeShootInput{GardenerConfig: &gqlschema.GardenerUpgradeInput{KubernetesVersion: util.StringPtr("1.27.1")}}
+
+	child := func(runtimeID, operationID string, state model.OperationState) model.BulkOperationChild {
+		return model.BulkOperationChild{BulkOperationID: bulkOperationID, RuntimeID: runtimeID, OperationID: operationID, State: state}
+	}
+
+	newMocks := func(operation model.BulkOperation, children []model.BulkOperationChild) (*sessionMocks.Factory, *sessionMocks.ReadWriteSession, *mocks2.ShootUpgrader) {
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		sessionFactory.On("NewReadWriteSession").Return(readWriteSession)
+		readSession.On("ListUnfinishedBulkOperations").Return([]model.BulkOperation{operation}, nil)
+		readWriteSession.On("ListBulkOperationChildren", bulkOperationID).Return(children, nil)
+
+		return sessionFactory, readWriteSession, &mocks2.ShootUpgrader{}
+	}
+
+	// newTxSession mocks the transaction in which the children are started while the bulk operation has the given state
+	newTxSession := func(sessionFactory *sessionMocks.Factory, state model.BulkOperationState) *sessionMocks.WriteSessionWithinTransaction {
+		txSession := &sessionMocks.WriteSessionWithinTransaction{}
+		sessionFactory.On("NewSessionWithinTransaction").Return(txSession, nil)
+		txSession.On("GetBulkOperationStateForUpdate", bulkOperationID).Return(state, nil)
+		txSession.On("RollbackUnlessCommitted").Return()
+
+		return txSession
+	}
+
+	t.Run("Should start pending children up to max parallel", func(t *testing.T) {
+		// given
+		children := []model.BulkOperationChild{
+			child("runtime-1", "operation-1", model.InProgress),
+			child("runtime-2", "", model.Pending),
+			child("runtime-3", "", model.Pending),
+		}
+		sessionFactory, session, shootUpgrader := newMocks(bulkOperation, children)
+
+		session.On("GetOperation", "operation-1").Return(model.Operation{ID: "operation-1", State: model.InProgress}, nil)
+		txSession := newTxSession(sessionFactory, model.BulkInProgress)
+		shootUpgrader.On("UpgradeGardenerShoot", "runtime-2", expectedInput).Return(&gqlschema.OperationStatus{ID: util.StringPtr("operation-2")}, nil)
+		txSession.On("UpdateBulkOperationChild", child("runtime-2", "operation-2", model.InProgress)).Return(nil)
+		txSession.On("Commit").Return(nil)
+
+		scheduler := NewBulkOperationScheduler(BulkOperationsConfig{}, sessionFactory, shootUpgrader)
+
+		// when
+		scheduler.ProcessOperations()
+
+		// then
+		session.AssertExpectations(t)
+		txSession.AssertExpectations(t)
+		shootUpgrader.AssertExpectations(t)
+		shootUpgrader.AssertNotCalled(t, "UpgradeGardenerShoot", "runtime-3", mock.Anything)
+	})
+
+	t.Run("Should not start children of paused bulk operation", func(t *testing.T) {
+		// given
+		paused := bulkOperation
+		paused.State = model.BulkPaused
+		children := []model.BulkOperationChild{
+			child("runtime-1", "operation-1", model.InProgress),
+			child("runtime-2", "", model.Pending),
+		}
+		sessionFactory, session, shootUpgrader := newMocks(paused, children)
+
+		session.On("GetOperation", "operation-1").Return(model.Operation{ID: "operation-1", State: model.Succeeded, Message: "Operation succeeded"}, nil)
+		session.On("UpdateBulkOperationChild", model.BulkOperationChild{BulkOperationID: bulkOperationID, RuntimeID: "runtime-1", OperationID: "operation-1", State: model.Succeeded, Message: "Operation succeeded"}).Return(nil)
+
+		scheduler := NewBulkOperationScheduler(BulkOperationsConfig{}, sessionFactory, shootUpgrader)
+
+		// when
+		scheduler.ProcessOperations()
+
+		// then
+		session.AssertExpectations(t)
+		shootUpgrader.AssertNotCalled(t, "UpgradeGardenerShoot", mock.Anything, mock.Anything)
+		session.AssertNotCalled(t, "UpdateBulkOperationState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		session.AssertNotCalled(t, "UpdateUnfinishedBulkOperationState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should not start children of bulk operation paused meanwhile", func(t *testing.T) {
+		// given
+		children := []model.BulkOperationChild{
+			child("runtime-1", "", model.Pending),
+			child("runtime-2", "", model.Pending),
+		}
+		sessionFactory, session, shootUpgrader := newMocks(bulkOperation, children)
+		txSession := newTxSession(sessionFactory, model.BulkPaused)
+
+		scheduler := NewBulkOperationScheduler(BulkOperationsConfig{}, sessionFactory, shootUpgrader)
+
+		// when
+		scheduler.ProcessOperations()
+
+		// then
+		txSession.AssertExpectations(t)
+		shootUpgrader.AssertNotCalled(t, "UpgradeGardenerShoot", mock.Anything, mock.Anything)
+		txSession.AssertNotCalled(t, "UpdateBulkOperationChild", mock.Anything)
+		session.AssertNotCalled(t, "UpdateUnfinishedBulkOperationState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should cancel pending children when max failure ratio is exceeded", func(t *testing.T) {
+		// given
+		children := []model.BulkOperationChild{
+			child("runtime-1", "operation-1", model.InProgress),
+			child("runtime-2", "operation-2", model.Failed),
+			child("runtime-3", "", model.Pending),
+		}
+		sessionFactory, session, shootUpgrader := newMocks(bulkOperation, children)
+
+		session.On("GetOperation", "operation-1").Return(model.Operation{ID: "operation-1", State: model.Failed, Message: "Operation failed"}, nil)
+		session.On("UpdateBulkOperationChild", mock.MatchedBy(func(c model.BulkOperationChild) bool { return c.RuntimeID == "runtime-1" && c.State == model.Failed })).Return(nil)
+		session.On("UpdateUnfinishedBulkOperationState", bulkOperationID, model.BulkFailed, "Maximum failure ratio exceeded", (*time.Time)(nil)).Return(nil)
+		session.On("CancelPendingBulkOperationChildren", bulkOperationID, "Canceled after the maximum failure ratio was exceeded").Return(nil)
+		session.On("UpdateBulkOperationState", bulkOperationID, model.BulkFailed, "Maximum failure ratio exceeded", mock.AnythingOfType("*time.Time")).Return(nil)
+
+		scheduler := NewBulkOperationScheduler(BulkOperationsConfig{}, sessionFactory, shootUpgrader)
+
+		// when
+		scheduler.ProcessOperations()
+
+		// then
+		session.AssertExpectations(t)
+		shootUpgrader.AssertNotCalled(t, "UpgradeGardenerShoot", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should mark child failed when operation cannot be started", func(t *testing.T) {
+		// given
+		tolerant := bulkOperation
+		tolerant.Strategy.MaxFailureRatio = 1
+		children := []model.BulkOperationChild{
+			child("runtime-1", "", model.Pending),
+		}
+		sessionFactory, session, shootUpgrader := newMocks(tolerant, children)
+
+		txSession := newTxSession(sessionFactory, model.BulkInProgress)
+		shootUpgrader.On("UpgradeGardenerShoot", "runtime-1", expectedInput).Return(nil, apperrors.BadRequest("operation in progress"))
+		txSession.On("UpdateBulkOperationChild", mock.MatchedBy(func(c model.BulkOperationChild) bool {
+			return c.RuntimeID == "runtime-1" && c.State == model.Failed && c.OperationID == ""
+		})).Return(nil)
+		txSession.On("Commit").Return(nil)
+		session.On("UpdateUnfinishedBulkOperationState", bulkOperationID, model.BulkFailed, "1 of 1 child operations failed", mock.AnythingOfType("*time.Time")).Return(nil)
+
+		scheduler := NewBulkOperationScheduler(BulkOperationsConfig{}, sessionFactory, shootUpgrader)
+
+		// when
+		scheduler.ProcessOperations()
+
+		// then
+		session.AssertExpectations(t)
+		txSession.AssertExpectations(t)
+		shootUpgrader.AssertExpectations(t)
+	})
+
+	t.Run("Should finish bulk operation when all children succeeded", func(t *testing.T) {
+		// given
+		children := []model.BulkOperationChild{
+			child("runtime-1", "operation-1", model.Succeeded),
+			child("runtime-2", "operation-2", model.Succeeded),
+		}
+		sessionFactory, session, shootUpgrader := newMocks(bulkOperation, children)
+
+		var endTimestamp *time.Time
+		session.On("UpdateUnfinishedBulkOperationState", bulkOperationID, model.BulkSucceeded, "Bulk operation succeeded", mock.AnythingOfType("*time.Time")).
+			Run(func(args mock.Arguments) { endTimestamp = args.Get(3).(*time.Time) }).
+			Return(nil)
+
+		scheduler := NewBulkOperationScheduler(BulkOperationsConfig{}, sessionFactory, shootUpgrader)
+		scheduler.timeNow = func() time.Time { return now }
+
+		// when
+		scheduler.ProcessOperations()
+
+		// then
+		session.AssertExpectations(t)
+		assert.Equal(t, now, *endTimestamp)
+	})
+
+	t.Run("Should keep state of bulk operation canceled meanwhile", func(t *testing.T) {
+		// given
+		children := []model.BulkOperationChild{
+			child("runtime-1", "operation-1", model.Succeeded),
+		}
+		sessionFactory, session, shootUpgrader := newMocks(bulkOperation, children)
+
+		session.On("UpdateUnfinishedBulkOperationState", bulkOperationID, model.BulkSucceeded, "Bulk operation succeeded", mock.AnythingOfType("*time.Time")).
+			Return(dberrors.NotFound("Unfinished bulk operation %s not found", bulkOperationID))
+
+		scheduler := NewBulkOperationScheduler(BulkOperationsConfig{}, sessionFactory, shootUpgrader)
+
+		// when
+		scheduler.ProcessOperations()
+
+		// then
+		session.AssertExpectations(t)
+		session.AssertNotCalled(t, "UpdateBulkOperationState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
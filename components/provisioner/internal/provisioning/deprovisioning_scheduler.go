@@ -0,0 +1,141 @@
+package provisioning
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/classification"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type DeprovisioningConfig struct {
+	// GracePeriod delays deprovisioning, during which the pending operation can be canceled
+	GracePeriod   time.Duration `envconfig:"default=0s"`
+	CheckInterval time.Duration `envconfig:"default=1m"`
+}
+
+// DeprovisioningScheduler starts pending deprovisioning operations once their grace period passes
+type DeprovisioningScheduler struct {
+	config              DeprovisioningConfig
+	dbSessionFactory    dbsession.Factory
+	provisioner         Provisioner
+	deprovisioningQueue queue.OperationQueue
+	log                 logrus.FieldLogger
+	timeNow             func() time.Time
+}
+
+func NewDeprovisioningScheduler(config DeprovisioningConfig, dbSessionFactory dbsession.Factory, provisioner Provisioner, deprovisioningQueue queue.OperationQueue) *DeprovisioningScheduler {
+	return &DeprovisioningScheduler{
+		config:              config,
+		dbSessionFactory:    dbSessionFactory,
+		provisioner:         provisioner,
+		deprovisioningQueue: deprovisioningQueue,
+		log:                 logrus.WithField("Component", "DeprovisioningScheduler"),
+		timeNow:             time.Now,
+	}
+}
+
+// Run periodically starts due deprovisioning operations until stop channel is closed
+func (s *DeprovisioningScheduler) Run(stop <-chan struct{}) {
+	go wait.Until(s.StartDueOperations, s.config.CheckInterval, stop)
+}
+
+func (s *DeprovisioningScheduler) StartDueOperations() {
+	operations, dberr := s.dbSessionFactory.NewReadSession().ListPendingOperations()
+	if dberr != nil {
+		s.log.Errorf("Failed to list pending operations: %s", dberr.Error())
+		return
+	}
+
+	for _, operation := range operations {
+		if operation.Type != model.DeprovisionNoInstall || s.timeNow().Before(operation.StartTimestamp.Add(s.config.GracePeriod)) {
+			continue
+		}
+
+		err := s.start(operation)
+		if err != nil {
+			s.log.WithField("OperationId", operation.ID).Errorf("Failed to start deprovisioning of runtime %s: %s", operation.ClusterID, err.Error())
+		}
+	}
+}
+
+func (s *DeprovisioningScheduler) start(pending model.Operation) error {
+	session := s.dbSessionFactory.NewReadWriteSession()
+
+	cluster, dberr := session.GetCluster(pending.ClusterID)
+	if dberr != nil {
+		return dberr
+	}
+
+	started, dberr := s.claim(pending)
+	if dberr != nil || !started {
+		return dberr
+	}
+
+	operation, appErr := s.provisioner.DeprovisionCluster(cluster, pending.ID)
+	if appErr != nil {
+		message := fmt.Sprintf("Failed to start deprovisioning: %s", appErr.Error())
+		if dberr := session.UpdateOperationLastError(pending.ID, message, string(appErr.Reason()), string(appErr.Component()), string(classification.FailFast)); dberr != nil {
+			s.log.Errorf("Failed to update last error of operation %s: %s", pending.ID, dberr.Error())
+		}
+		if dberr := session.UpdateOperationState(pending.ID, message, model.Failed, s.timeNow()); dberr != nil {
+			return dberr
+		}
+		return appErr
+	}
+
+	dberr = session.TransitionOperation(operation.ID, operation.Message, operation.Stage, operation.StartTimestamp)
+	if dberr != nil {
+		return dberr
+	}
+
+	s.log.Infof("Starting deprovisioning steps for runtime %s after grace period", cluster.ID)
+	s.deprovisioningQueue.Add(operation.ID)
+
+	return nil
+}
+
+// claim starts the pending operation before the Shoot is touched, so that it cannot be canceled afterwards.
+// The cluster is locked meanwhile, so that deletion protection enabled during the grace period cancels the operation.
+func (s *DeprovisioningScheduler) claim(pending model.Operation) (bool, dberrors.Error) {
+	txSession, dberr := s.dbSessionFactory.NewSessionWithinTransaction()
+	if dberr != nil {
+		return false, dberr
+	}
+	defer txSession.RollbackUnlessCommitted()
+
+	deletionProtection, dberr := txSession.GetDeletionProtectionForUpdate(pending.ClusterID)
+	if dberr != nil {
+		return false, dberr
+	}
+
+	if deletionProtection {
+		dberr = txSession.CancelPendingOperation(pending.ID, "Deprovisioning canceled as deletion protection was enabled", s.timeNow())
+	} else {
+		dberr = txSession.StartPendingOperation(pending.ID, "Deprovisioning started", s.timeNow())
+	}
+	if dberr != nil {
+		if dberr.Code() == dberrors.CodeNotFound {
+			s.log.Infof("Deprovisioning of runtime %s is no longer pending", pending.ClusterID)
+			return false, nil
+		}
+		return false, dberr
+	}
+
+	dberr = txSession.Commit()
+	if dberr != nil {
+		return false, dberr
+	}
+
+	if deletionProtection {
+		s.log.Infof("Deprovisioning of runtime %s canceled as deletion protection was enabled", pending.ClusterID)
+		return false, nil
+	}
+
+	return true, nil
+}
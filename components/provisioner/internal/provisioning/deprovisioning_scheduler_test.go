@@ -0,0 +1,171 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/classification"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	mocks2 "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/mocks"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeprovisioningScheduler_StartDueOperations(t *testing.T) {
+	now := time.Now()
+	config := DeprovisioningConfig{GracePeriod: time.Hour}
+	cluster := model.Cluster{ID: runtimeID}
+
+	dueOperation := model.Operation{
+		ID:             operationID,
+		Type:           model.DeprovisionNoInstall,
+		State:          model.Pending,
+		Stage:          model.WaitingForDeprovisioningGracePeriod,
+		StartTimestamp: now.Add(-2 * time.Hour),
+		ClusterID:      runtimeID,
+	}
+	notDueOperation := model.Operation{
+		ID:             "not-due",
+		Type:           model.DeprovisionNoInstall,
+		State:          model.Pending,
+		Stage:          model.WaitingForDeprovisioningGracePeriod,
+		StartTimestamp: now.Add(-time.Minute),
+		ClusterID:      "other-runtime",
+	}
+
+	t.Run("Should start deprovisioning after grace period", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+		txSession := &sessionMocks.WriteSessionWithinTransaction{}
+		provisioner := &mocks2.Provisioner{}
+		deprovisioningQueue := &mocks.OperationQueue{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		sessionFactory.On("NewReadWriteSession").Return(readWriteSession)
+		sessionFactory.On("NewSessionWithinTransaction").Return(txSession, nil)
+		txSession.On("GetDeletionProtectionForUpdate", runtimeID).Return(false, nil)
+		txSession.On("RollbackUnlessCommitted").Return()
+		readSession.On("ListPendingOperations").Return([]model.Operation{dueOperation, notDueOperation}, nil)
+		readWriteSession.On("GetCluster", runtimeID).Return(cluster, nil)
+		txSession.On("StartPendingOperation", operationID, "Deprovisioning started", now).Return(nil)
+		txSession.On("Commit").Return(nil)
+		provisioner.On("DeprovisionCluster", cluster, operationID).Return(model.Operation{
+			ID:             operationID,
+			Stage:          model.CleanupCluster,
+			Message:        "Deprovisioning started",
+			StartTimestamp: now,
+		}, nil)
+		readWriteSession.On("TransitionOperation", operationID, "Deprovisioning started", model.CleanupCluster, now).Return(nil)
+		deprovisioningQueue.On("Add", operationID).Return()
+
+		scheduler := NewDeprovisioningScheduler(config, sessionFactory, provisioner, deprovisioningQueue)
+		scheduler.timeNow = func() time.Time { return now }
+
+		// when
+		scheduler.StartDueOperations()
+
+		// then
+		readWriteSession.AssertExpectations(t)
+		provisioner.AssertExpectations(t)
+		deprovisioningQueue.AssertExpectations(t)
+	})
+
+	t.Run("Should not start deprovisioning canceled in the meantime", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+		txSession := &sessionMocks.WriteSessionWithinTransaction{}
+		provisioner := &mocks2.Provisioner{}
+		deprovisioningQueue := &mocks.OperationQueue{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		sessionFactory.On("NewReadWriteSession").Return(readWriteSession)
+		sessionFactory.On("NewSessionWithinTransaction").Return(txSession, nil)
+		txSession.On("GetDeletionProtectionForUpdate", runtimeID).Return(false, nil)
+		txSession.On("RollbackUnlessCommitted").Return()
+		readSession.On("ListPendingOperations").Return([]model.Operation{dueOperation}, nil)
+		readWriteSession.On("GetCluster", runtimeID).Return(cluster, nil)
+		txSession.On("StartPendingOperation", operationID, "Deprovisioning started", now).Return(dberrors.NotFound("not found"))
+
+		scheduler := NewDeprovisioningScheduler(config, sessionFactory, provisioner, deprovisioningQueue)
+		scheduler.timeNow = func() time.Time { return now }
+
+		// when
+		scheduler.StartDueOperations()
+
+		// then
+		provisioner.AssertNotCalled(t, "DeprovisionCluster", mock.Anything, mock.Anything)
+		deprovisioningQueue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+
+	t.Run("Should cancel deprovisioning when deletion protection was enabled in the meantime", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+		txSession := &sessionMocks.WriteSessionWithinTransaction{}
+		provisioner := &mocks2.Provisioner{}
+		deprovisioningQueue := &mocks.OperationQueue{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		sessionFactory.On("NewReadWriteSession").Return(readWriteSession)
+		sessionFactory.On("NewSessionWithinTransaction").Return(txSession, nil)
+		readSession.On("ListPendingOperations").Return([]model.Operation{dueOperation}, nil)
+		readWriteSession.On("GetCluster", runtimeID).Return(cluster, nil)
+		txSession.On("GetDeletionProtectionForUpdate", runtimeID).Return(true, nil)
+		txSession.On("CancelPendingOperation", operationID, "Deprovisioning canceled as deletion protection was enabled", now).Return(nil)
+		txSession.On("Commit").Return(nil)
+		txSession.On("RollbackUnlessCommitted").Return()
+
+		scheduler := NewDeprovisioningScheduler(config, sessionFactory, provisioner, deprovisioningQueue)
+		scheduler.timeNow = func() time.Time { return now }
+
+		// when
+		scheduler.StartDueOperations()
+
+		// then
+		txSession.AssertExpectations(t)
+		txSession.AssertNotCalled(t, "StartPendingOperation", mock.Anything, mock.Anything, mock.Anything)
+		provisioner.AssertNotCalled(t, "DeprovisionCluster", mock.Anything, mock.Anything)
+		deprovisioningQueue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+
+	t.Run("Should fail operation when deprovisioning could not be started", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+		txSession := &sessionMocks.WriteSessionWithinTransaction{}
+		provisioner := &mocks2.Provisioner{}
+		deprovisioningQueue := &mocks.OperationQueue{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		sessionFactory.On("NewReadWriteSession").Return(readWriteSession)
+		sessionFactory.On("NewSessionWithinTransaction").Return(txSession, nil)
+		txSession.On("GetDeletionProtectionForUpdate", runtimeID).Return(false, nil)
+		txSession.On("RollbackUnlessCommitted").Return()
+		readSession.On("ListPendingOperations").Return([]model.Operation{dueOperation}, nil)
+		readWriteSession.On("GetCluster", runtimeID).Return(cluster, nil)
+		txSession.On("StartPendingOperation", operationID, "Deprovisioning started", now).Return(nil)
+		txSession.On("Commit").Return(nil)
+		provisioner.On("DeprovisionCluster", cluster, operationID).Return(model.Operation{}, apperrors.External("gardener error"))
+		readWriteSession.On("UpdateOperationLastError", operationID, "Failed to start deprovisioning: gardener error", mock.Anything, mock.Anything, string(classification.FailFast)).Return(nil)
+		readWriteSession.On("UpdateOperationState", operationID, "Failed to start deprovisioning: gardener error", model.Failed, now).Return(nil)
+
+		scheduler := NewDeprovisioningScheduler(config, sessionFactory, provisioner, deprovisioningQueue)
+		scheduler.timeNow = func() time.Time { return now }
+
+		// when
+		scheduler.StartDueOperations()
+
+		// then
+		readWriteSession.AssertExpectations(t)
+		deprovisioningQueue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+}
@@ -1,13 +1,23 @@
 package provisioning
 
 import (
+	"sort"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 )
 
 type GraphQLConverter interface {
 	RuntimeStatusToGraphQLStatus(status model.RuntimeStatus) *gqlschema.RuntimeStatus
 	OperationStatusToGQLOperationStatus(operation model.Operation) *gqlschema.OperationStatus
+	OperationEventToGQLRuntimeEvent(event events.OperationEvent) *gqlschema.RuntimeEvent
+	TenantQuotaToGraphQLTenantQuota(quota model.TenantQuota) *gqlschema.TenantQuota
+	TenantUsageToGraphQLTenantUsage(usage model.TenantUsage, quota model.TenantQuota) *gqlschema.TenantUsage
+	OrphansReportToGraphQLOrphansReport(report model.OrphansReport) *gqlschema.OrphansReport
+	OperationDiagnosticsToGraphQLDiagnostics(runtimeID string, diagnostics model.OperationDiagnostics) *gqlschema.OperationDiagnostics
+	BulkOperationToGraphQLStatus(operation model.BulkOperation, children []model.BulkOperationChild) *gqlschema.BulkOperationStatus
 }
 
 func NewGraphQLConverter() GraphQLConverter {
@@ -21,6 +31,7 @@ func (c graphQLConverter) RuntimeStatusToGraphQLStatus(status model.RuntimeStatu
 		LastOperationStatus:     c.OperationStatusToGQLOperationStatus(status.LastOperationStatus),
 		RuntimeConnectionStatus: c.runtimeConnectionStatusToGraphQLStatus(status.RuntimeConnectionStatus),
 		RuntimeConfiguration:    c.clusterToToGraphQLRuntimeConfiguration(status.RuntimeConfiguration),
+		CredentialsRotation:     c.credentialsRotationToGraphQLStatus(status.CredentialsRotation),
 	}
 }
 
@@ -35,7 +46,28 @@ func (c graphQLConverter) OperationStatusToGQLOperationStatus(operation model.Op
 			ErrMessage: operation.ErrMessage,
 			Reason:     operation.Reason,
 			Component:  operation.Component,
+			Action:     operation.Action,
+		},
+	}
+}
+
+func (c graphQLConverter) OperationEventToGQLRuntimeEvent(event events.OperationEvent) *gqlschema.RuntimeEvent {
+	operation := event.Operation
+
+	return &gqlschema.RuntimeEvent{
+		RuntimeID:   operation.ClusterID,
+		OperationID: operation.ID,
+		Operation:   c.operationTypeToGraphQLType(operation.Type),
+		State:       c.operationStateToGraphQLState(operation.State),
+		Stage:       string(operation.Stage),
+		Message:     &operation.Message,
+		LastError: &gqlschema.LastError{
+			ErrMessage: operation.ErrMessage,
+			Reason:     operation.Reason,
+			Component:  operation.Component,
+			Action:     operation.Action,
 		},
+		Timestamp: event.Timestamp,
 	}
 }
 
@@ -58,8 +90,9 @@ func (c graphQLConverter) runtimeAgentConnectionStatusToGraphQLStatus(status mod
 
 func (c graphQLConverter) clusterToToGraphQLRuntimeConfiguration(config model.Cluster) *gqlschema.RuntimeConfig {
 	runtimeConfig := &gqlschema.RuntimeConfig{
-		ClusterConfig: c.gardenerConfigToGraphQLConfig(config.ClusterConfig),
-		Kubeconfig:    config.Kubeconfig,
+		ClusterConfig:      c.gardenerConfigToGraphQLConfig(config.ClusterConfig),
+		Kubeconfig:         config.Kubeconfig,
+		DeletionProtection: &config.DeletionProtection,
 	}
 	if config.KymaConfig != nil {
 		runtimeConfig.KymaConfig = c.kymaConfigToGraphQLConfig(*config.KymaConfig)
@@ -176,6 +209,43 @@ func (c graphQLConverter) operationTypeToGraphQLType(operationType model.Operati
 		return gqlschema.OperationTypeReconnectRuntime
 	case model.Hibernate:
 		return gqlschema.OperationTypeHibernate
+	case model.RotateCredentials:
+		return gqlschema.OperationTypeRotateCredentials
+	default:
+		return ""
+	}
+}
+
+func (c graphQLConverter) credentialsRotationToGraphQLStatus(statuses []model.CredentialsRotationStatus) []*gqlschema.CredentialsRotationStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	converted := make([]*gqlschema.CredentialsRotationStatus, 0, len(statuses))
+	for _, status := range statuses {
+		converted = append(converted, &gqlschema.CredentialsRotationStatus{
+			Kind:               c.credentialsRotationKindToGraphQLKind(status.Kind),
+			Phase:              optionalString(status.Phase),
+			LastInitiationTime: status.LastInitiationTime,
+			LastCompletionTime: status.LastCompletionTime,
+		})
+	}
+
+	return converted
+}
+
+func (c graphQLConverter) credentialsRotationKindToGraphQLKind(kind model.CredentialsRotationKind) gqlschema.CredentialsRotationKind {
+	switch kind {
+	case model.CertificateAuthorities:
+		return gqlschema.CredentialsRotationKindCertificateAuthorities
+	case model.ServiceAccountKey:
+		return gqlschema.CredentialsRotationKindServiceAccountKey
+	case model.ETCDEncryptionKey:
+		return gqlschema.CredentialsRotationKindETCDEncryptionKey
+	case model.ObservabilityCredentials:
+		return gqlschema.CredentialsRotationKindObservability
+	case model.SSHKeypair:
+		return gqlschema.CredentialsRotationKindSSHKeypair
 	default:
 		return ""
 	}
@@ -183,12 +253,16 @@ func (c graphQLConverter) operationTypeToGraphQLType(operationType model.Operati
 
 func (c graphQLConverter) operationStateToGraphQLState(state model.OperationState) gqlschema.OperationState {
 	switch state {
+	case model.Pending:
+		return gqlschema.OperationStatePending
 	case model.InProgress:
 		return gqlschema.OperationStateInProgress
 	case model.Succeeded:
 		return gqlschema.OperationStateSucceeded
 	case model.Failed:
 		return gqlschema.OperationStateFailed
+	case model.Canceled:
+		return gqlschema.OperationStateCanceled
 	default:
 		return ""
 	}
@@ -236,3 +310,179 @@ func (c graphQLConverter) dnsConfigToGraphQLConfig(config *model.DNSConfig) *gql
 
 	return &gqlConfig
 }
+
+func (c graphQLConverter) TenantQuotaToGraphQLTenantQuota(quota model.TenantQuota) *gqlschema.TenantQuota {
+	return &gqlschema.TenantQuota{
+		Tenant:                     quota.Tenant,
+		MaxRuntimes:                quota.MaxRuntimes,
+		MaxOperationsInProgress:    quota.MaxOperationsInProgress,
+		MaxNodesPerProvider:        c.providerNodesToGraphQLProviderNodes(quota.MaxNodesPerProvider),
+		DefaultMaxNodesPerProvider: quota.DefaultMaxNodesPerProvider,
+	}
+}
+
+func (c graphQLConverter) TenantUsageToGraphQLTenantUsage(usage model.TenantUsage, quota model.TenantQuota) *gqlschema.TenantUsage {
+	return &gqlschema.TenantUsage{
+		Tenant:               usage.Tenant,
+		Runtimes:             usage.Runtimes,
+		OperationsInProgress: usage.OperationsInProgress,
+		NodesPerProvider:     c.providerNodesToGraphQLProviderNodes(usage.NodesPerProvider),
+		Quota:                c.TenantQuotaToGraphQLTenantQuota(quota),
+	}
+}
+
+func (c graphQLConverter) OrphansReportToGraphQLOrphansReport(report model.OrphansReport) *gqlschema.OrphansReport {
+	orphans := make([]*gqlschema.Orphan, 0, len(report.Orphans))
+	for _, orphan := range report.Orphans {
+		orphans = append(orphans, &gqlschema.Orphan{
+			Category:         gqlschema.OrphanCategory(orphan.Category),
+			RuntimeID:        orphan.RuntimeID,
+			Tenant:           optionalString(orphan.Tenant),
+			ShootName:        optionalString(orphan.ShootName),
+			Remediated:       orphan.Remediated,
+			RemediationError: optionalString(orphan.RemediationError),
+		})
+	}
+
+	return &gqlschema.OrphansReport{
+		ScanTime: report.ScanTime,
+		Orphans:  orphans,
+	}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func (c graphQLConverter) providerNodesToGraphQLProviderNodes(nodesPerProvider map[string]int) []*gqlschema.ProviderNodes {
+	providerNodes := make([]*gqlschema.ProviderNodes, 0, len(nodesPerProvider))
+	for provider, nodes := range nodesPerProvider {
+		providerNodes = append(providerNodes, &gqlschema.ProviderNodes{Provider: provider, Nodes: nodes})
+	}
+
+	sort.Slice(providerNodes, func(i, j int) bool {
+		return providerNodes[i].Provider < providerNodes[j].Provider
+	})
+
+	return providerNodes
+}
+
+func (c graphQLConverter) OperationDiagnosticsToGraphQLDiagnostics(runtimeID string, diagnostics model.OperationDiagnostics) *gqlschema.OperationDiagnostics {
+	lastErrors := make([]*gqlschema.ShootLastError, 0, len(diagnostics.LastErrors))
+	for _, lastError := range diagnostics.LastErrors {
+		lastErrors = append(lastErrors, &gqlschema.ShootLastError{
+			Description:    lastError.Description,
+			TaskID:         lastError.TaskID,
+			Codes:          nonNilCodes(lastError.Codes),
+			LastUpdateTime: lastError.LastUpdateTime,
+		})
+	}
+
+	events := make([]*gqlschema.ShootEvent, 0, len(diagnostics.Events))
+	for _, event := range diagnostics.Events {
+		events = append(events, &gqlschema.ShootEvent{
+			Type:           event.Type,
+			Reason:         event.Reason,
+			Message:        event.Message,
+			Count:          int(event.Count),
+			FirstTimestamp: event.FirstTimestamp,
+			LastTimestamp:  event.LastTimestamp,
+		})
+	}
+
+	return &gqlschema.OperationDiagnostics{
+		OperationID: diagnostics.OperationID,
+		RuntimeID:   runtimeID,
+		Stage:       string(diagnostics.Stage),
+		CapturedAt:  diagnostics.CapturedAt,
+		Conditions:  c.shootConditionsToGraphQLConditions(diagnostics.Conditions),
+		Constraints: c.shootConditionsToGraphQLConditions(diagnostics.Constraints),
+		LastErrors:  lastErrors,
+		Events:      events,
+		Truncated:   diagnostics.Truncated,
+	}
+}
+
+func (c graphQLConverter) shootConditionsToGraphQLConditions(conditions []model.ShootCondition) []*gqlschema.ShootCondition {
+	converted := make([]*gqlschema.ShootCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		converted = append(converted, &gqlschema.ShootCondition{
+			Type:               condition.Type,
+			Status:             condition.Status,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			Codes:              nonNilCodes(condition.Codes),
+			LastTransitionTime: condition.LastTransitionTime,
+		})
+	}
+
+	return converted
+}
+
+func nonNilCodes(codes []string) []string {
+	if codes == nil {
+		return []string{}
+	}
+
+	return codes
+}
+
+func (c graphQLConverter) BulkOperationToGraphQLStatus(operation model.BulkOperation, children []model.BulkOperationChild) *gqlschema.BulkOperationStatus {
+	status := &gqlschema.BulkOperationStatus{
+		ID:              operation.ID,
+		Operation:       c.operationTypeToGraphQLType(operation.Type),
+		State:           c.bulkOperationStateToGraphQLState(operation.State),
+		Message:         &operation.Message,
+		MaxParallel:     operation.Strategy.MaxParallel,
+		MaxFailureRatio: operation.Strategy.MaxFailureRatio,
+		StartTimestamp:  operation.StartTimestamp,
+		EndTimestamp:    operation.EndTimestamp,
+		Total:           len(children),
+		Children:        make([]*gqlschema.BulkOperationChild, 0, len(children)),
+	}
+
+	for _, child := range children {
+		switch child.State {
+		case model.Pending:
+			status.Pending++
+		case model.InProgress:
+			status.InProgress++
+		case model.Succeeded:
+			status.Succeeded++
+		case model.Failed:
+			status.Failed++
+		case model.Canceled:
+			status.Canceled++
+		}
+
+		converted := &gqlschema.BulkOperationChild{
+			RuntimeID: child.RuntimeID,
+			State:     c.operationStateToGraphQLState(child.State),
+			Message:   util.StringPtr(child.Message),
+		}
+		if child.OperationID != "" {
+			converted.OperationID = util.StringPtr(child.OperationID)
+		}
+		status.Children = append(status.Children, converted)
+	}
+
+	return status
+}
+
+func (c graphQLConverter) bulkOperationStateToGraphQLState(state model.BulkOperationState) gqlschema.BulkOperationState {
+	switch state {
+	case model.BulkPaused:
+		return gqlschema.BulkOperationStatePaused
+	case model.BulkCanceled:
+		return gqlschema.BulkOperationStateCanceled
+	case model.BulkSucceeded:
+		return gqlschema.BulkOperationStateSucceeded
+	case model.BulkFailed:
+		return gqlschema.BulkOperationStateFailed
+	default:
+		return gqlschema.BulkOperationStateInProgress
+	}
+}
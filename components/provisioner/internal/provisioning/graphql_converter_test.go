@@ -213,8 +213,9 @@ func TestRuntimeStatusToGraphQLStatus(t *testing.T) {
 					ControlPlaneFailureTolerance:  &controlPlaneFailureTolerance,
 					EuAccess:                      &euAccess,
 				},
-				KymaConfig: fixKymaGraphQLConfig(nil),
-				Kubeconfig: &kubeconfig,
+				KymaConfig:         fixKymaGraphQLConfig(nil),
+				Kubeconfig:         &kubeconfig,
+				DeletionProtection: util.BoolPtr(false),
 			},
 		}
 
@@ -362,7 +363,8 @@ func TestRuntimeStatusToGraphQLStatus(t *testing.T) {
 					ControlPlaneFailureTolerance:  &controlPlaneFailureTolerance,
 					EuAccess:                      &euAccess,
 				},
-				Kubeconfig: &kubeconfig,
+				Kubeconfig:         &kubeconfig,
+				DeletionProtection: util.BoolPtr(false),
 			},
 		}
 
@@ -502,8 +504,9 @@ func TestRuntimeStatusToGraphQLStatus(t *testing.T) {
 						Zones:    nil, // Expected empty when no zones specified in input.
 					},
 				},
-				KymaConfig: fixKymaGraphQLConfig(&gqlProductionProfile),
-				Kubeconfig: &kubeconfig,
+				KymaConfig:         fixKymaGraphQLConfig(&gqlProductionProfile),
+				Kubeconfig:         &kubeconfig,
+				DeletionProtection: util.BoolPtr(false),
 			},
 		}
 
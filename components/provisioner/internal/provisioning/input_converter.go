@@ -14,6 +14,7 @@ type InputConverter interface {
 	ProvisioningInputToCluster(runtimeID string, input gqlschema.ProvisionRuntimeInput, tenant, subAccountId string) (model.Cluster, apperrors.AppError)
 	KymaConfigFromInput(runtimeID string, input gqlschema.KymaConfigInput) (model.KymaConfig, apperrors.AppError)
 	UpgradeShootInputToGardenerConfig(input gqlschema.GardenerUpgradeInput, existing model.GardenerConfig) (model.GardenerConfig, apperrors.AppError)
+	TenantQuotaInputToTenantQuota(tenant string, input gqlschema.TenantQuotaInput) model.TenantQuota
 }
 
 func NewInputConverter(
@@ -294,3 +295,63 @@ func (c converter) configurationFromInput(input []*gqlschema.ConfigEntryInput, c
 func configEntryFromInput(entry *gqlschema.ConfigEntryInput) model.ConfigEntry {
 	return model.NewConfigEntry(entry.Key, entry.Value, util.UnwrapBoolOrDefault(entry.Secret, false))
 }
+
+func (c converter) TenantQuotaInputToTenantQuota(tenant string, input gqlschema.TenantQuotaInput) model.TenantQuota {
+	maxNodesPerProvider := make(map[string]int, len(input.MaxNodesPerProvider))
+	for _, providerNodes := range input.MaxNodesPerProvider {
+		if providerNodes != nil {
+			maxNodesPerProvider[providerNodes.Provider] = providerNodes.Nodes
+		}
+	}
+
+	return model.TenantQuota{
+		Tenant:                  tenant,
+		MaxRuntimes:             input.MaxRuntimes,
+		MaxOperationsInProgress: input.MaxOperationsInProgress,
+		MaxNodesPerProvider:     maxNodesPerProvider,
+	}
+}
+
+func credentialsRotationKindsFromInput(kinds []gqlschema.CredentialsRotationKind) []model.CredentialsRotationKind {
+	converted := make([]model.CredentialsRotationKind, 0, len(kinds))
+	for _, kind := range kinds {
+		switch kind {
+		case gqlschema.CredentialsRotationKindCertificateAuthorities:
+			converted = append(converted, model.CertificateAuthorities)
+		case gqlschema.CredentialsRotationKindServiceAccountKey:
+			converted = append(converted, model.ServiceAccountKey)
+		case gqlschema.CredentialsRotationKindETCDEncryptionKey:
+			converted = append(converted, model.ETCDEncryptionKey)
+		case gqlschema.CredentialsRotationKindObservability:
+			converted = append(converted, model.ObservabilityCredentials)
+		case gqlschema.CredentialsRotationKindSSHKeypair:
+			converted = append(converted, model.SSHKeypair)
+		}
+	}
+
+	return converted
+}
+
+func credentialsRotationPhaseFromInput(phase gqlschema.CredentialsRotationPhase) model.CredentialsRotationPhase {
+	if phase == gqlschema.CredentialsRotationPhaseComplete {
+		return model.RotationComplete
+	}
+
+	return model.RotationPrepare
+}
+
+func runtimeSelectorFromInput(selector gqlschema.RuntimeSelectorInput) model.RuntimeSelector {
+	return model.RuntimeSelector{
+		Tenants:            selector.Tenants,
+		Providers:          selector.Providers,
+		Regions:            selector.Regions,
+		KubernetesVersions: selector.KubernetesVersions,
+	}
+}
+
+func bulkOperationStrategyFromInput(strategy gqlschema.BulkOperationStrategyInput) model.BulkOperationStrategy {
+	return model.BulkOperationStrategy{
+		MaxParallel:     strategy.MaxParallel,
+		MaxFailureRatio: strategy.MaxFailureRatio,
+	}
+}
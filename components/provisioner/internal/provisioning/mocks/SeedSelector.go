@@ -0,0 +1,62 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	apperrors "github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/kyma-project/control-plane/components/provisioner/internal/model"
+)
+
+// SeedSelector is an autogenerated mock type for the SeedSelector type
+type SeedSelector struct {
+	mock.Mock
+}
+
+// SelectSeed provides a mock function with given fields: config
+func (_m *SeedSelector) SelectSeed(config model.GardenerConfig) (string, string, apperrors.AppError) {
+	ret := _m.Called(config)
+
+	var r0 string
+	var r1 string
+	var r2 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.GardenerConfig) (string, string, apperrors.AppError)); ok {
+		return rf(config)
+	}
+	if rf, ok := ret.Get(0).(func(model.GardenerConfig) string); ok {
+		r0 = rf(config)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(model.GardenerConfig) string); ok {
+		r1 = rf(config)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(model.GardenerConfig) apperrors.AppError); ok {
+		r2 = rf(config)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// NewSeedSelector creates a new instance of SeedSelector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSeedSelector(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SeedSelector {
+	mock := &SeedSelector{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	context "context"
+
 	apperrors "github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+
 	gqlschema "github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 
 	mock "github.com/stretchr/testify/mock"
@@ -14,21 +17,189 @@ type Service struct {
 	mock.Mock
 }
 
-// DeprovisionRuntime provides a mock function with given fields: id
-func (_m *Service) DeprovisionRuntime(id string) (string, apperrors.AppError) {
+// BulkOperationStatus provides a mock function with given fields: id
+func (_m *Service) BulkOperationStatus(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
 	ret := _m.Called(id)
 
-	var r0 string
+	var r0 *gqlschema.BulkOperationStatus
 	var r1 apperrors.AppError
-	if rf, ok := ret.Get(0).(func(string) (string, apperrors.AppError)); ok {
+	if rf, ok := ret.Get(0).(func(string) (*gqlschema.BulkOperationStatus, apperrors.AppError)); ok {
 		return rf(id)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.BulkOperationStatus); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.BulkOperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// BulkUpgradeShoot provides a mock function with given fields: selector, input, strategy
+func (_m *Service) BulkUpgradeShoot(selector gqlschema.RuntimeSelectorInput, input gqlschema.UpgradeShootInput, strategy gqlschema.BulkOperationStrategyInput) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	ret := _m.Called(selector, input, strategy)
+
+	var r0 *gqlschema.BulkOperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(gqlschema.RuntimeSelectorInput, gqlschema.UpgradeShootInput, gqlschema.BulkOperationStrategyInput) (*gqlschema.BulkOperationStatus, apperrors.AppError)); ok {
+		return rf(selector, input, strategy)
+	}
+	if rf, ok := ret.Get(0).(func(gqlschema.RuntimeSelectorInput, gqlschema.UpgradeShootInput, gqlschema.BulkOperationStrategyInput) *gqlschema.BulkOperationStatus); ok {
+		r0 = rf(selector, input, strategy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.BulkOperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(gqlschema.RuntimeSelectorInput, gqlschema.UpgradeShootInput, gqlschema.BulkOperationStrategyInput) apperrors.AppError); ok {
+		r1 = rf(selector, input, strategy)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CancelBulkOperation provides a mock function with given fields: id
+func (_m *Service) CancelBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *gqlschema.BulkOperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (*gqlschema.BulkOperationStatus, apperrors.AppError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.BulkOperationStatus); ok {
 		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.BulkOperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CancelDeprovisioning provides a mock function with given fields: id
+func (_m *Service) CancelDeprovisioning(id string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *gqlschema.OperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (*gqlschema.OperationStatus, apperrors.AppError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.OperationStatus); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeprovisionRuntime provides a mock function with given fields: id, force
+func (_m *Service) DeprovisionRuntime(id string, force bool) (string, apperrors.AppError) {
+	ret := _m.Called(id, force)
+
+	var r0 string
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, bool) (string, apperrors.AppError)); ok {
+		return rf(id, force)
+	}
+	if rf, ok := ret.Get(0).(func(string, bool) string); ok {
+		r0 = rf(id, force)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
+	if rf, ok := ret.Get(1).(func(string, bool) apperrors.AppError); ok {
+		r1 = rf(id, force)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FindOrphans provides a mock function with given fields:
+func (_m *Service) FindOrphans() (*gqlschema.OrphansReport, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 *gqlschema.OrphansReport
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() (*gqlschema.OrphansReport, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *gqlschema.OrphansReport); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OrphansReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PauseBulkOperation provides a mock function with given fields: id
+func (_m *Service) PauseBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *gqlschema.BulkOperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (*gqlschema.BulkOperationStatus, apperrors.AppError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.BulkOperationStatus); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.BulkOperationStatus)
+		}
+	}
+
 	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
 		r1 = rf(id)
 	} else {
@@ -94,6 +265,90 @@ func (_m *Service) ReconnectRuntimeAgent(id string) (string, apperrors.AppError)
 	return r0, r1
 }
 
+// ResumeBulkOperation provides a mock function with given fields: id
+func (_m *Service) ResumeBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *gqlschema.BulkOperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (*gqlschema.BulkOperationStatus, apperrors.AppError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.BulkOperationStatus); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.BulkOperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RotateCredentials provides a mock function with given fields: id, kinds, phase
+func (_m *Service) RotateCredentials(id string, kinds []gqlschema.CredentialsRotationKind, phase gqlschema.CredentialsRotationPhase) (*gqlschema.OperationStatus, apperrors.AppError) {
+	ret := _m.Called(id, kinds, phase)
+
+	var r0 *gqlschema.OperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, []gqlschema.CredentialsRotationKind, gqlschema.CredentialsRotationPhase) (*gqlschema.OperationStatus, apperrors.AppError)); ok {
+		return rf(id, kinds, phase)
+	}
+	if rf, ok := ret.Get(0).(func(string, []gqlschema.CredentialsRotationKind, gqlschema.CredentialsRotationPhase) *gqlschema.OperationStatus); ok {
+		r0 = rf(id, kinds, phase)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []gqlschema.CredentialsRotationKind, gqlschema.CredentialsRotationPhase) apperrors.AppError); ok {
+		r1 = rf(id, kinds, phase)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RuntimeOperationDiagnostics provides a mock function with given fields: id
+func (_m *Service) RuntimeOperationDiagnostics(id string) (*gqlschema.OperationDiagnostics, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *gqlschema.OperationDiagnostics
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (*gqlschema.OperationDiagnostics, apperrors.AppError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.OperationDiagnostics); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationDiagnostics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // RuntimeOperationStatus provides a mock function with given fields: id
 func (_m *Service) RuntimeOperationStatus(id string) (*gqlschema.OperationStatus, apperrors.AppError) {
 	ret := _m.Called(id)
@@ -150,6 +405,174 @@ func (_m *Service) RuntimeStatus(id string) (*gqlschema.RuntimeStatus, apperrors
 	return r0, r1
 }
 
+// SetDeletionProtection provides a mock function with given fields: id, enabled
+func (_m *Service) SetDeletionProtection(id string, enabled bool) (*gqlschema.RuntimeStatus, apperrors.AppError) {
+	ret := _m.Called(id, enabled)
+
+	var r0 *gqlschema.RuntimeStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, bool) (*gqlschema.RuntimeStatus, apperrors.AppError)); ok {
+		return rf(id, enabled)
+	}
+	if rf, ok := ret.Get(0).(func(string, bool) *gqlschema.RuntimeStatus); ok {
+		r0 = rf(id, enabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.RuntimeStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, bool) apperrors.AppError); ok {
+		r1 = rf(id, enabled)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SetTenantQuota provides a mock function with given fields: tenant, input
+func (_m *Service) SetTenantQuota(tenant string, input gqlschema.TenantQuotaInput) (*gqlschema.TenantQuota, apperrors.AppError) {
+	ret := _m.Called(tenant, input)
+
+	var r0 *gqlschema.TenantQuota
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, gqlschema.TenantQuotaInput) (*gqlschema.TenantQuota, apperrors.AppError)); ok {
+		return rf(tenant, input)
+	}
+	if rf, ok := ret.Get(0).(func(string, gqlschema.TenantQuotaInput) *gqlschema.TenantQuota); ok {
+		r0 = rf(tenant, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.TenantQuota)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, gqlschema.TenantQuotaInput) apperrors.AppError); ok {
+		r1 = rf(tenant, input)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SubscribeOperationStatus provides a mock function with given fields: ctx, id
+func (_m *Service) SubscribeOperationStatus(ctx context.Context, id string) (<-chan *gqlschema.OperationStatus, apperrors.AppError) {
+	ret := _m.Called(ctx, id)
+
+	var r0 <-chan *gqlschema.OperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan *gqlschema.OperationStatus, apperrors.AppError)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan *gqlschema.OperationStatus); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *gqlschema.OperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) apperrors.AppError); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SubscribeRuntimeEvents provides a mock function with given fields: ctx, runtimeID
+func (_m *Service) SubscribeRuntimeEvents(ctx context.Context, runtimeID string) (<-chan *gqlschema.RuntimeEvent, apperrors.AppError) {
+	ret := _m.Called(ctx, runtimeID)
+
+	var r0 <-chan *gqlschema.RuntimeEvent
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan *gqlschema.RuntimeEvent, apperrors.AppError)); ok {
+		return rf(ctx, runtimeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan *gqlschema.RuntimeEvent); ok {
+		r0 = rf(ctx, runtimeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *gqlschema.RuntimeEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) apperrors.AppError); ok {
+		r1 = rf(ctx, runtimeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// TenantUsage provides a mock function with given fields: tenant
+func (_m *Service) TenantUsage(tenant string) (*gqlschema.TenantUsage, apperrors.AppError) {
+	ret := _m.Called(tenant)
+
+	var r0 *gqlschema.TenantUsage
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (*gqlschema.TenantUsage, apperrors.AppError)); ok {
+		return rf(tenant)
+	}
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.TenantUsage); ok {
+		r0 = rf(tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.TenantUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(tenant)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// TenantsUsage provides a mock function with given fields:
+func (_m *Service) TenantsUsage() ([]*gqlschema.TenantUsage, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []*gqlschema.TenantUsage
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]*gqlschema.TenantUsage, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*gqlschema.TenantUsage); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*gqlschema.TenantUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // UpgradeGardenerShoot provides a mock function with given fields: id, input
 func (_m *Service) UpgradeGardenerShoot(id string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, apperrors.AppError) {
 	ret := _m.Called(id, input)
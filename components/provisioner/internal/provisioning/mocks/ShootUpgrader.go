@@ -0,0 +1,57 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	apperrors "github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	gqlschema "github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ShootUpgrader is an autogenerated mock type for the ShootUpgrader type
+type ShootUpgrader struct {
+	mock.Mock
+}
+
+// UpgradeGardenerShoot provides a mock function with given fields: runtimeID, input
+func (_m *ShootUpgrader) UpgradeGardenerShoot(runtimeID string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, apperrors.AppError) {
+	ret := _m.Called(runtimeID, input)
+
+	var r0 *gqlschema.OperationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, apperrors.AppError)); ok {
+		return rf(runtimeID, input)
+	}
+	if rf, ok := ret.Get(0).(func(string, gqlschema.UpgradeShootInput) *gqlschema.OperationStatus); ok {
+		r0 = rf(runtimeID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, gqlschema.UpgradeShootInput) apperrors.AppError); ok {
+		r1 = rf(runtimeID, input)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewShootUpgrader creates a new instance of ShootUpgrader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewShootUpgrader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ShootUpgrader {
+	mock := &ShootUpgrader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
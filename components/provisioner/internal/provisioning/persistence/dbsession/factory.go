@@ -26,9 +26,23 @@ type ReadSession interface {
 	GetGardenerClusterByName(name string) (model.Cluster, dberrors.Error)
 	GetTenant(runtimeID string) (string, dberrors.Error)
 	ListInProgressOperations() ([]model.Operation, dberrors.Error)
+	ListPendingOperations() ([]model.Operation, dberrors.Error)
+	ListClusterReferences() ([]model.ClusterReference, dberrors.Error)
 	GetRuntimeUpgrade(operationId string) (model.RuntimeUpgrade, dberrors.Error)
+	GetOperationStages(operationID string) ([]model.OperationStageStatus, dberrors.Error)
+	GetPreUpgradeGardenerConfig(operationID string) (model.GardenerConfig, dberrors.Error)
+	GetOperationDiagnostics(operationID string) (model.OperationDiagnostics, dberrors.Error)
+	ListRuntimeIDs(selector model.RuntimeSelector) ([]string, dberrors.Error)
+	GetBulkOperation(id string) (model.BulkOperation, dberrors.Error)
+	ListUnfinishedBulkOperations() ([]model.BulkOperation, dberrors.Error)
+	ListBulkOperationChildren(bulkOperationID string) ([]model.BulkOperationChild, dberrors.Error)
+	GetCredentialsRotation(operationID string) (model.CredentialsRotation, dberrors.Error)
+	GetCredentialsRotationStatus(runtimeID string) ([]model.CredentialsRotationStatus, dberrors.Error)
 	GetTenantForOperation(operationID string) (string, dberrors.Error)
 	InProgressOperationsCount() (model.OperationsCount, dberrors.Error)
+	GetTenantQuota(tenant string) (model.TenantQuota, dberrors.Error)
+	GetTenantUsage(tenant string) (model.TenantUsage, dberrors.Error)
+	ListTenantUsages() ([]model.TenantUsage, dberrors.Error)
 }
 
 //go:generate mockery --name=WriteSession
@@ -38,15 +52,31 @@ type WriteSession interface {
 	UpdateGardenerClusterConfig(config model.GardenerConfig) dberrors.Error
 	InsertAdministrators(clusterId string, administrators []string) dberrors.Error
 	InsertOperation(operation model.Operation) dberrors.Error
+	InsertPreUpgradeGardenerConfig(operationID string, config model.GardenerConfig) dberrors.Error
+	InsertCredentialsRotation(rotation model.CredentialsRotation) dberrors.Error
+	UpsertCredentialsRotationStatus(runtimeID string, status model.CredentialsRotationStatus) dberrors.Error
 	UpdateOperationState(operationID string, message string, state model.OperationState, endTime time.Time) dberrors.Error
-	UpdateOperationLastError(operationID, msg, reason, component string) dberrors.Error
+	StartPendingOperation(operationID string, message string, startTime time.Time) dberrors.Error
+	CancelPendingOperation(operationID string, message string, endTime time.Time) dberrors.Error
+	UpdateOperationLastError(operationID, msg, reason, component, action string) dberrors.Error
 	TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) dberrors.Error
+	UpdateOperationPipelineVersion(operationID string, version string) dberrors.Error
+	UpdateOperationDiagnostics(operationID string, diagnostics model.OperationDiagnostics) dberrors.Error
+	InsertBulkOperation(operation model.BulkOperation) dberrors.Error
+	InsertBulkOperationChild(child model.BulkOperationChild) dberrors.Error
+	UpdateBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) dberrors.Error
+	UpdateUnfinishedBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) dberrors.Error
+	UpdateBulkOperationChild(child model.BulkOperationChild) dberrors.Error
+	CancelPendingBulkOperationChildren(bulkOperationID string, message string) dberrors.Error
+	InsertOperationStage(operationID string, stage model.OperationStage, state model.StageState, startTime time.Time) dberrors.Error
+	FinishOperationStage(operationID string, stage model.OperationStage, state model.StageState, endTime time.Time) dberrors.Error
 	UpdateKubeconfig(runtimeID string, kubeconfig string) dberrors.Error
 	DeleteCluster(runtimeID string) dberrors.Error
 	MarkClusterAsDeleted(runtimeID string) dberrors.Error
-	UpdateTenant(runtimeID string, tenant string) dberrors.Error
+	UpdateDeletionProtection(runtimeID string, enabled bool) dberrors.Error
 	UpdateKubernetesVersion(runtimeID string, version string) dberrors.Error
 	UpdateShootNetworkingFilterDisabled(runtimeID string, shootNetworkingFilterDisabled *bool) dberrors.Error
+	UpsertTenantQuota(quota model.TenantQuota) dberrors.Error
 }
 
 //go:generate mockery --name=ReadWriteSession
@@ -64,6 +94,12 @@ type Transaction interface {
 type WriteSessionWithinTransaction interface {
 	WriteSession
 	Transaction
+	// LockTenantQuota waits until other transactions checking the quota of the tenant are finished
+	LockTenantQuota(tenant string) dberrors.Error
+	// GetDeletionProtectionForUpdate locks the cluster, so that its deletion protection is not changed until the transaction ends
+	GetDeletionProtectionForUpdate(runtimeID string) (bool, dberrors.Error)
+	// GetBulkOperationStateForUpdate locks the bulk operation, so that it is not paused or canceled until the transaction ends
+	GetBulkOperationStateForUpdate(id string) (model.BulkOperationState, dberrors.Error)
 }
 
 type factory struct {
@@ -15,6 +15,32 @@ type ReadSession struct {
 	mock.Mock
 }
 
+// GetBulkOperation provides a mock function with given fields: id
+func (_m *ReadSession) GetBulkOperation(id string) (model.BulkOperation, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 model.BulkOperation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.BulkOperation, apperrors.AppError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.BulkOperation); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(model.BulkOperation)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetCluster provides a mock function with given fields: runtimeID
 func (_m *ReadSession) GetCluster(runtimeID string) (model.Cluster, apperrors.AppError) {
 	ret := _m.Called(runtimeID)
@@ -41,6 +67,60 @@ func (_m *ReadSession) GetCluster(runtimeID string) (model.Cluster, apperrors.Ap
 	return r0, r1
 }
 
+// GetCredentialsRotation provides a mock function with given fields: operationID
+func (_m *ReadSession) GetCredentialsRotation(operationID string) (model.CredentialsRotation, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 model.CredentialsRotation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.CredentialsRotation, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.CredentialsRotation); ok {
+		r0 = rf(operationID)
+	} else {
+		r0 = ret.Get(0).(model.CredentialsRotation)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetCredentialsRotationStatus provides a mock function with given fields: runtimeID
+func (_m *ReadSession) GetCredentialsRotationStatus(runtimeID string) ([]model.CredentialsRotationStatus, apperrors.AppError) {
+	ret := _m.Called(runtimeID)
+
+	var r0 []model.CredentialsRotationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) ([]model.CredentialsRotationStatus, apperrors.AppError)); ok {
+		return rf(runtimeID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.CredentialsRotationStatus); ok {
+		r0 = rf(runtimeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.CredentialsRotationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(runtimeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetGardenerClusterByName provides a mock function with given fields: name
 func (_m *ReadSession) GetGardenerClusterByName(name string) (model.Cluster, apperrors.AppError) {
 	ret := _m.Called(name)
@@ -119,6 +199,86 @@ func (_m *ReadSession) GetOperation(operationID string) (model.Operation, apperr
 	return r0, r1
 }
 
+// GetOperationDiagnostics provides a mock function with given fields: operationID
+func (_m *ReadSession) GetOperationDiagnostics(operationID string) (model.OperationDiagnostics, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 model.OperationDiagnostics
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.OperationDiagnostics, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.OperationDiagnostics); ok {
+		r0 = rf(operationID)
+	} else {
+		r0 = ret.Get(0).(model.OperationDiagnostics)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetOperationStages provides a mock function with given fields: operationID
+func (_m *ReadSession) GetOperationStages(operationID string) ([]model.OperationStageStatus, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 []model.OperationStageStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) ([]model.OperationStageStatus, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.OperationStageStatus); ok {
+		r0 = rf(operationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.OperationStageStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetPreUpgradeGardenerConfig provides a mock function with given fields: operationID
+func (_m *ReadSession) GetPreUpgradeGardenerConfig(operationID string) (model.GardenerConfig, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 model.GardenerConfig
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.GardenerConfig, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.GardenerConfig); ok {
+		r0 = rf(operationID)
+	} else {
+		r0 = ret.Get(0).(model.GardenerConfig)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetRuntimeUpgrade provides a mock function with given fields: operationId
 func (_m *ReadSession) GetRuntimeUpgrade(operationId string) (model.RuntimeUpgrade, apperrors.AppError) {
 	ret := _m.Called(operationId)
@@ -197,6 +357,58 @@ func (_m *ReadSession) GetTenantForOperation(operationID string) (string, apperr
 	return r0, r1
 }
 
+// GetTenantQuota provides a mock function with given fields: tenant
+func (_m *ReadSession) GetTenantQuota(tenant string) (model.TenantQuota, apperrors.AppError) {
+	ret := _m.Called(tenant)
+
+	var r0 model.TenantQuota
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.TenantQuota, apperrors.AppError)); ok {
+		return rf(tenant)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.TenantQuota); ok {
+		r0 = rf(tenant)
+	} else {
+		r0 = ret.Get(0).(model.TenantQuota)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(tenant)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTenantUsage provides a mock function with given fields: tenant
+func (_m *ReadSession) GetTenantUsage(tenant string) (model.TenantUsage, apperrors.AppError) {
+	ret := _m.Called(tenant)
+
+	var r0 model.TenantUsage
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.TenantUsage, apperrors.AppError)); ok {
+		return rf(tenant)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.TenantUsage); ok {
+		r0 = rf(tenant)
+	} else {
+		r0 = ret.Get(0).(model.TenantUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(tenant)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // InProgressOperationsCount provides a mock function with given fields:
 func (_m *ReadSession) InProgressOperationsCount() (model.OperationsCount, apperrors.AppError) {
 	ret := _m.Called()
@@ -223,6 +435,62 @@ func (_m *ReadSession) InProgressOperationsCount() (model.OperationsCount, apper
 	return r0, r1
 }
 
+// ListBulkOperationChildren provides a mock function with given fields: bulkOperationID
+func (_m *ReadSession) ListBulkOperationChildren(bulkOperationID string) ([]model.BulkOperationChild, apperrors.AppError) {
+	ret := _m.Called(bulkOperationID)
+
+	var r0 []model.BulkOperationChild
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) ([]model.BulkOperationChild, apperrors.AppError)); ok {
+		return rf(bulkOperationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.BulkOperationChild); ok {
+		r0 = rf(bulkOperationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BulkOperationChild)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(bulkOperationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListClusterReferences provides a mock function with given fields:
+func (_m *ReadSession) ListClusterReferences() ([]model.ClusterReference, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.ClusterReference
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.ClusterReference, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.ClusterReference); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ClusterReference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // ListInProgressOperations provides a mock function with given fields:
 func (_m *ReadSession) ListInProgressOperations() ([]model.Operation, apperrors.AppError) {
 	ret := _m.Called()
@@ -251,6 +519,118 @@ func (_m *ReadSession) ListInProgressOperations() ([]model.Operation, apperrors.
 	return r0, r1
 }
 
+// ListPendingOperations provides a mock function with given fields:
+func (_m *ReadSession) ListPendingOperations() ([]model.Operation, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.Operation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.Operation, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.Operation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListRuntimeIDs provides a mock function with given fields: selector
+func (_m *ReadSession) ListRuntimeIDs(selector model.RuntimeSelector) ([]string, apperrors.AppError) {
+	ret := _m.Called(selector)
+
+	var r0 []string
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.RuntimeSelector) ([]string, apperrors.AppError)); ok {
+		return rf(selector)
+	}
+	if rf, ok := ret.Get(0).(func(model.RuntimeSelector) []string); ok {
+		r0 = rf(selector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(model.RuntimeSelector) apperrors.AppError); ok {
+		r1 = rf(selector)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListTenantUsages provides a mock function with given fields:
+func (_m *ReadSession) ListTenantUsages() ([]model.TenantUsage, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.TenantUsage
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.TenantUsage, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.TenantUsage); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TenantUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListUnfinishedBulkOperations provides a mock function with given fields:
+func (_m *ReadSession) ListUnfinishedBulkOperations() ([]model.BulkOperation, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.BulkOperation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.BulkOperation, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.BulkOperation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BulkOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // NewReadSession creates a new instance of ReadSession. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReadSession(t interface {
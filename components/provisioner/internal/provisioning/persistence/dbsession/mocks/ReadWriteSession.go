@@ -17,6 +17,38 @@ type ReadWriteSession struct {
 	mock.Mock
 }
 
+// CancelPendingBulkOperationChildren provides a mock function with given fields: bulkOperationID, message
+func (_m *ReadWriteSession) CancelPendingBulkOperationChildren(bulkOperationID string, message string) apperrors.AppError {
+	ret := _m.Called(bulkOperationID, message)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
+		r0 = rf(bulkOperationID, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// CancelPendingOperation provides a mock function with given fields: operationID, message, endTime
+func (_m *ReadWriteSession) CancelPendingOperation(operationID string, message string, endTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, message, endTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, message, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // DeleteCluster provides a mock function with given fields: runtimeID
 func (_m *ReadWriteSession) DeleteCluster(runtimeID string) apperrors.AppError {
 	ret := _m.Called(runtimeID)
@@ -33,6 +65,48 @@ func (_m *ReadWriteSession) DeleteCluster(runtimeID string) apperrors.AppError {
 	return r0
 }
 
+// FinishOperationStage provides a mock function with given fields: operationID, stage, state, endTime
+func (_m *ReadWriteSession) FinishOperationStage(operationID string, stage model.OperationStage, state model.StageState, endTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, stage, state, endTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.OperationStage, model.StageState, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, stage, state, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// GetBulkOperation provides a mock function with given fields: id
+func (_m *ReadWriteSession) GetBulkOperation(id string) (model.BulkOperation, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 model.BulkOperation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.BulkOperation, apperrors.AppError)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.BulkOperation); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(model.BulkOperation)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetCluster provides a mock function with given fields: runtimeID
 func (_m *ReadWriteSession) GetCluster(runtimeID string) (model.Cluster, apperrors.AppError) {
 	ret := _m.Called(runtimeID)
@@ -59,6 +133,60 @@ func (_m *ReadWriteSession) GetCluster(runtimeID string) (model.Cluster, apperro
 	return r0, r1
 }
 
+// GetCredentialsRotation provides a mock function with given fields: operationID
+func (_m *ReadWriteSession) GetCredentialsRotation(operationID string) (model.CredentialsRotation, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 model.CredentialsRotation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.CredentialsRotation, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.CredentialsRotation); ok {
+		r0 = rf(operationID)
+	} else {
+		r0 = ret.Get(0).(model.CredentialsRotation)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetCredentialsRotationStatus provides a mock function with given fields: runtimeID
+func (_m *ReadWriteSession) GetCredentialsRotationStatus(runtimeID string) ([]model.CredentialsRotationStatus, apperrors.AppError) {
+	ret := _m.Called(runtimeID)
+
+	var r0 []model.CredentialsRotationStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) ([]model.CredentialsRotationStatus, apperrors.AppError)); ok {
+		return rf(runtimeID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.CredentialsRotationStatus); ok {
+		r0 = rf(runtimeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.CredentialsRotationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(runtimeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetGardenerClusterByName provides a mock function with given fields: name
 func (_m *ReadWriteSession) GetGardenerClusterByName(name string) (model.Cluster, apperrors.AppError) {
 	ret := _m.Called(name)
@@ -137,6 +265,86 @@ func (_m *ReadWriteSession) GetOperation(operationID string) (model.Operation, a
 	return r0, r1
 }
 
+// GetOperationDiagnostics provides a mock function with given fields: operationID
+func (_m *ReadWriteSession) GetOperationDiagnostics(operationID string) (model.OperationDiagnostics, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 model.OperationDiagnostics
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.OperationDiagnostics, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.OperationDiagnostics); ok {
+		r0 = rf(operationID)
+	} else {
+		r0 = ret.Get(0).(model.OperationDiagnostics)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetOperationStages provides a mock function with given fields: operationID
+func (_m *ReadWriteSession) GetOperationStages(operationID string) ([]model.OperationStageStatus, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 []model.OperationStageStatus
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) ([]model.OperationStageStatus, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.OperationStageStatus); ok {
+		r0 = rf(operationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.OperationStageStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetPreUpgradeGardenerConfig provides a mock function with given fields: operationID
+func (_m *ReadWriteSession) GetPreUpgradeGardenerConfig(operationID string) (model.GardenerConfig, apperrors.AppError) {
+	ret := _m.Called(operationID)
+
+	var r0 model.GardenerConfig
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.GardenerConfig, apperrors.AppError)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.GardenerConfig); ok {
+		r0 = rf(operationID)
+	} else {
+		r0 = ret.Get(0).(model.GardenerConfig)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(operationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetRuntimeUpgrade provides a mock function with given fields: operationId
 func (_m *ReadWriteSession) GetRuntimeUpgrade(operationId string) (model.RuntimeUpgrade, apperrors.AppError) {
 	ret := _m.Called(operationId)
@@ -215,6 +423,58 @@ func (_m *ReadWriteSession) GetTenantForOperation(operationID string) (string, a
 	return r0, r1
 }
 
+// GetTenantQuota provides a mock function with given fields: tenant
+func (_m *ReadWriteSession) GetTenantQuota(tenant string) (model.TenantQuota, apperrors.AppError) {
+	ret := _m.Called(tenant)
+
+	var r0 model.TenantQuota
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.TenantQuota, apperrors.AppError)); ok {
+		return rf(tenant)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.TenantQuota); ok {
+		r0 = rf(tenant)
+	} else {
+		r0 = ret.Get(0).(model.TenantQuota)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(tenant)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTenantUsage provides a mock function with given fields: tenant
+func (_m *ReadWriteSession) GetTenantUsage(tenant string) (model.TenantUsage, apperrors.AppError) {
+	ret := _m.Called(tenant)
+
+	var r0 model.TenantUsage
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) (model.TenantUsage, apperrors.AppError)); ok {
+		return rf(tenant)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.TenantUsage); ok {
+		r0 = rf(tenant)
+	} else {
+		r0 = ret.Get(0).(model.TenantUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(tenant)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // InProgressOperationsCount provides a mock function with given fields:
 func (_m *ReadWriteSession) InProgressOperationsCount() (model.OperationsCount, apperrors.AppError) {
 	ret := _m.Called()
@@ -257,6 +517,38 @@ func (_m *ReadWriteSession) InsertAdministrators(clusterId string, administrator
 	return r0
 }
 
+// InsertBulkOperation provides a mock function with given fields: operation
+func (_m *ReadWriteSession) InsertBulkOperation(operation model.BulkOperation) apperrors.AppError {
+	ret := _m.Called(operation)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperation) apperrors.AppError); ok {
+		r0 = rf(operation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// InsertBulkOperationChild provides a mock function with given fields: child
+func (_m *ReadWriteSession) InsertBulkOperationChild(child model.BulkOperationChild) apperrors.AppError {
+	ret := _m.Called(child)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperationChild) apperrors.AppError); ok {
+		r0 = rf(child)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // InsertCluster provides a mock function with given fields: cluster
 func (_m *ReadWriteSession) InsertCluster(cluster model.Cluster) apperrors.AppError {
 	ret := _m.Called(cluster)
@@ -273,6 +565,22 @@ func (_m *ReadWriteSession) InsertCluster(cluster model.Cluster) apperrors.AppEr
 	return r0
 }
 
+// InsertCredentialsRotation provides a mock function with given fields: rotation
+func (_m *ReadWriteSession) InsertCredentialsRotation(rotation model.CredentialsRotation) apperrors.AppError {
+	ret := _m.Called(rotation)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.CredentialsRotation) apperrors.AppError); ok {
+		r0 = rf(rotation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // InsertGardenerConfig provides a mock function with given fields: config
 func (_m *ReadWriteSession) InsertGardenerConfig(config model.GardenerConfig) apperrors.AppError {
 	ret := _m.Called(config)
@@ -305,6 +613,94 @@ func (_m *ReadWriteSession) InsertOperation(operation model.Operation) apperrors
 	return r0
 }
 
+// InsertOperationStage provides a mock function with given fields: operationID, stage, state, startTime
+func (_m *ReadWriteSession) InsertOperationStage(operationID string, stage model.OperationStage, state model.StageState, startTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, stage, state, startTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.OperationStage, model.StageState, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, stage, state, startTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// InsertPreUpgradeGardenerConfig provides a mock function with given fields: operationID, config
+func (_m *ReadWriteSession) InsertPreUpgradeGardenerConfig(operationID string, config model.GardenerConfig) apperrors.AppError {
+	ret := _m.Called(operationID, config)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.GardenerConfig) apperrors.AppError); ok {
+		r0 = rf(operationID, config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// ListBulkOperationChildren provides a mock function with given fields: bulkOperationID
+func (_m *ReadWriteSession) ListBulkOperationChildren(bulkOperationID string) ([]model.BulkOperationChild, apperrors.AppError) {
+	ret := _m.Called(bulkOperationID)
+
+	var r0 []model.BulkOperationChild
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) ([]model.BulkOperationChild, apperrors.AppError)); ok {
+		return rf(bulkOperationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.BulkOperationChild); ok {
+		r0 = rf(bulkOperationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BulkOperationChild)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(bulkOperationID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListClusterReferences provides a mock function with given fields:
+func (_m *ReadWriteSession) ListClusterReferences() ([]model.ClusterReference, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.ClusterReference
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.ClusterReference, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.ClusterReference); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ClusterReference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // ListInProgressOperations provides a mock function with given fields:
 func (_m *ReadWriteSession) ListInProgressOperations() ([]model.Operation, apperrors.AppError) {
 	ret := _m.Called()
@@ -333,6 +729,118 @@ func (_m *ReadWriteSession) ListInProgressOperations() ([]model.Operation, apper
 	return r0, r1
 }
 
+// ListPendingOperations provides a mock function with given fields:
+func (_m *ReadWriteSession) ListPendingOperations() ([]model.Operation, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.Operation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.Operation, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.Operation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListRuntimeIDs provides a mock function with given fields: selector
+func (_m *ReadWriteSession) ListRuntimeIDs(selector model.RuntimeSelector) ([]string, apperrors.AppError) {
+	ret := _m.Called(selector)
+
+	var r0 []string
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.RuntimeSelector) ([]string, apperrors.AppError)); ok {
+		return rf(selector)
+	}
+	if rf, ok := ret.Get(0).(func(model.RuntimeSelector) []string); ok {
+		r0 = rf(selector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(model.RuntimeSelector) apperrors.AppError); ok {
+		r1 = rf(selector)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListTenantUsages provides a mock function with given fields:
+func (_m *ReadWriteSession) ListTenantUsages() ([]model.TenantUsage, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.TenantUsage
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.TenantUsage, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.TenantUsage); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TenantUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListUnfinishedBulkOperations provides a mock function with given fields:
+func (_m *ReadWriteSession) ListUnfinishedBulkOperations() ([]model.BulkOperation, apperrors.AppError) {
+	ret := _m.Called()
+
+	var r0 []model.BulkOperation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() ([]model.BulkOperation, apperrors.AppError)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.BulkOperation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BulkOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() apperrors.AppError); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // MarkClusterAsDeleted provides a mock function with given fields: runtimeID
 func (_m *ReadWriteSession) MarkClusterAsDeleted(runtimeID string) apperrors.AppError {
 	ret := _m.Called(runtimeID)
@@ -349,6 +857,22 @@ func (_m *ReadWriteSession) MarkClusterAsDeleted(runtimeID string) apperrors.App
 	return r0
 }
 
+// StartPendingOperation provides a mock function with given fields: operationID, message, startTime
+func (_m *ReadWriteSession) StartPendingOperation(operationID string, message string, startTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, message, startTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, message, startTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // TransitionOperation provides a mock function with given fields: operationID, message, stage, transitionTime
 func (_m *ReadWriteSession) TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) apperrors.AppError {
 	ret := _m.Called(operationID, message, stage, transitionTime)
@@ -365,6 +889,54 @@ func (_m *ReadWriteSession) TransitionOperation(operationID string, message stri
 	return r0
 }
 
+// UpdateBulkOperationChild provides a mock function with given fields: child
+func (_m *ReadWriteSession) UpdateBulkOperationChild(child model.BulkOperationChild) apperrors.AppError {
+	ret := _m.Called(child)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperationChild) apperrors.AppError); ok {
+		r0 = rf(child)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateBulkOperationState provides a mock function with given fields: id, state, message, endTimestamp
+func (_m *ReadWriteSession) UpdateBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) apperrors.AppError {
+	ret := _m.Called(id, state, message, endTimestamp)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.BulkOperationState, string, *time.Time) apperrors.AppError); ok {
+		r0 = rf(id, state, message, endTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateDeletionProtection provides a mock function with given fields: runtimeID, enabled
+func (_m *ReadWriteSession) UpdateDeletionProtection(runtimeID string, enabled bool) apperrors.AppError {
+	ret := _m.Called(runtimeID, enabled)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, bool) apperrors.AppError); ok {
+		r0 = rf(runtimeID, enabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // UpdateGardenerClusterConfig provides a mock function with given fields: config
 func (_m *ReadWriteSession) UpdateGardenerClusterConfig(config model.GardenerConfig) apperrors.AppError {
 	ret := _m.Called(config)
@@ -413,13 +985,45 @@ func (_m *ReadWriteSession) UpdateKubernetesVersion(runtimeID string, version st
 	return r0
 }
 
-// UpdateOperationLastError provides a mock function with given fields: operationID, msg, reason, component
-func (_m *ReadWriteSession) UpdateOperationLastError(operationID string, msg string, reason string, component string) apperrors.AppError {
-	ret := _m.Called(operationID, msg, reason, component)
+// UpdateOperationDiagnostics provides a mock function with given fields: operationID, diagnostics
+func (_m *ReadWriteSession) UpdateOperationDiagnostics(operationID string, diagnostics model.OperationDiagnostics) apperrors.AppError {
+	ret := _m.Called(operationID, diagnostics)
 
 	var r0 apperrors.AppError
-	if rf, ok := ret.Get(0).(func(string, string, string, string) apperrors.AppError); ok {
-		r0 = rf(operationID, msg, reason, component)
+	if rf, ok := ret.Get(0).(func(string, model.OperationDiagnostics) apperrors.AppError); ok {
+		r0 = rf(operationID, diagnostics)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOperationLastError provides a mock function with given fields: operationID, msg, reason, component, action
+func (_m *ReadWriteSession) UpdateOperationLastError(operationID string, msg string, reason string, component string, action string) apperrors.AppError {
+	ret := _m.Called(operationID, msg, reason, component, action)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) apperrors.AppError); ok {
+		r0 = rf(operationID, msg, reason, component, action)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOperationPipelineVersion provides a mock function with given fields: operationID, version
+func (_m *ReadWriteSession) UpdateOperationPipelineVersion(operationID string, version string) apperrors.AppError {
+	ret := _m.Called(operationID, version)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
+		r0 = rf(operationID, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(apperrors.AppError)
@@ -461,13 +1065,45 @@ func (_m *ReadWriteSession) UpdateShootNetworkingFilterDisabled(runtimeID string
 	return r0
 }
 
-// UpdateTenant provides a mock function with given fields: runtimeID, tenant
-func (_m *ReadWriteSession) UpdateTenant(runtimeID string, tenant string) apperrors.AppError {
-	ret := _m.Called(runtimeID, tenant)
+// UpdateUnfinishedBulkOperationState provides a mock function with given fields: id, state, message, endTimestamp
+func (_m *ReadWriteSession) UpdateUnfinishedBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) apperrors.AppError {
+	ret := _m.Called(id, state, message, endTimestamp)
 
 	var r0 apperrors.AppError
-	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
-		r0 = rf(runtimeID, tenant)
+	if rf, ok := ret.Get(0).(func(string, model.BulkOperationState, string, *time.Time) apperrors.AppError); ok {
+		r0 = rf(id, state, message, endTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpsertCredentialsRotationStatus provides a mock function with given fields: runtimeID, status
+func (_m *ReadWriteSession) UpsertCredentialsRotationStatus(runtimeID string, status model.CredentialsRotationStatus) apperrors.AppError {
+	ret := _m.Called(runtimeID, status)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.CredentialsRotationStatus) apperrors.AppError); ok {
+		r0 = rf(runtimeID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpsertTenantQuota provides a mock function with given fields: quota
+func (_m *ReadWriteSession) UpsertTenantQuota(quota model.TenantQuota) apperrors.AppError {
+	ret := _m.Called(quota)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.TenantQuota) apperrors.AppError); ok {
+		r0 = rf(quota)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(apperrors.AppError)
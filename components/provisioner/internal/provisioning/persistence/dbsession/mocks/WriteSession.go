@@ -17,6 +17,38 @@ type WriteSession struct {
 	mock.Mock
 }
 
+// CancelPendingBulkOperationChildren provides a mock function with given fields: bulkOperationID, message
+func (_m *WriteSession) CancelPendingBulkOperationChildren(bulkOperationID string, message string) apperrors.AppError {
+	ret := _m.Called(bulkOperationID, message)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
+		r0 = rf(bulkOperationID, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// CancelPendingOperation provides a mock function with given fields: operationID, message, endTime
+func (_m *WriteSession) CancelPendingOperation(operationID string, message string, endTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, message, endTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, message, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // DeleteCluster provides a mock function with given fields: runtimeID
 func (_m *WriteSession) DeleteCluster(runtimeID string) apperrors.AppError {
 	ret := _m.Called(runtimeID)
@@ -33,6 +65,22 @@ func (_m *WriteSession) DeleteCluster(runtimeID string) apperrors.AppError {
 	return r0
 }
 
+// FinishOperationStage provides a mock function with given fields: operationID, stage, state, endTime
+func (_m *WriteSession) FinishOperationStage(operationID string, stage model.OperationStage, state model.StageState, endTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, stage, state, endTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.OperationStage, model.StageState, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, stage, state, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // InsertAdministrators provides a mock function with given fields: clusterId, administrators
 func (_m *WriteSession) InsertAdministrators(clusterId string, administrators []string) apperrors.AppError {
 	ret := _m.Called(clusterId, administrators)
@@ -49,6 +97,38 @@ func (_m *WriteSession) InsertAdministrators(clusterId string, administrators []
 	return r0
 }
 
+// InsertBulkOperation provides a mock function with given fields: operation
+func (_m *WriteSession) InsertBulkOperation(operation model.BulkOperation) apperrors.AppError {
+	ret := _m.Called(operation)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperation) apperrors.AppError); ok {
+		r0 = rf(operation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// InsertBulkOperationChild provides a mock function with given fields: child
+func (_m *WriteSession) InsertBulkOperationChild(child model.BulkOperationChild) apperrors.AppError {
+	ret := _m.Called(child)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperationChild) apperrors.AppError); ok {
+		r0 = rf(child)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // InsertCluster provides a mock function with given fields: cluster
 func (_m *WriteSession) InsertCluster(cluster model.Cluster) apperrors.AppError {
 	ret := _m.Called(cluster)
@@ -65,6 +145,22 @@ func (_m *WriteSession) InsertCluster(cluster model.Cluster) apperrors.AppError
 	return r0
 }
 
+// InsertCredentialsRotation provides a mock function with given fields: rotation
+func (_m *WriteSession) InsertCredentialsRotation(rotation model.CredentialsRotation) apperrors.AppError {
+	ret := _m.Called(rotation)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.CredentialsRotation) apperrors.AppError); ok {
+		r0 = rf(rotation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // InsertGardenerConfig provides a mock function with given fields: config
 func (_m *WriteSession) InsertGardenerConfig(config model.GardenerConfig) apperrors.AppError {
 	ret := _m.Called(config)
@@ -97,6 +193,38 @@ func (_m *WriteSession) InsertOperation(operation model.Operation) apperrors.App
 	return r0
 }
 
+// InsertOperationStage provides a mock function with given fields: operationID, stage, state, startTime
+func (_m *WriteSession) InsertOperationStage(operationID string, stage model.OperationStage, state model.StageState, startTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, stage, state, startTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.OperationStage, model.StageState, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, stage, state, startTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// InsertPreUpgradeGardenerConfig provides a mock function with given fields: operationID, config
+func (_m *WriteSession) InsertPreUpgradeGardenerConfig(operationID string, config model.GardenerConfig) apperrors.AppError {
+	ret := _m.Called(operationID, config)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.GardenerConfig) apperrors.AppError); ok {
+		r0 = rf(operationID, config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // MarkClusterAsDeleted provides a mock function with given fields: runtimeID
 func (_m *WriteSession) MarkClusterAsDeleted(runtimeID string) apperrors.AppError {
 	ret := _m.Called(runtimeID)
@@ -113,6 +241,22 @@ func (_m *WriteSession) MarkClusterAsDeleted(runtimeID string) apperrors.AppErro
 	return r0
 }
 
+// StartPendingOperation provides a mock function with given fields: operationID, message, startTime
+func (_m *WriteSession) StartPendingOperation(operationID string, message string, startTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, message, startTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, message, startTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // TransitionOperation provides a mock function with given fields: operationID, message, stage, transitionTime
 func (_m *WriteSession) TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) apperrors.AppError {
 	ret := _m.Called(operationID, message, stage, transitionTime)
@@ -129,6 +273,54 @@ func (_m *WriteSession) TransitionOperation(operationID string, message string,
 	return r0
 }
 
+// UpdateBulkOperationChild provides a mock function with given fields: child
+func (_m *WriteSession) UpdateBulkOperationChild(child model.BulkOperationChild) apperrors.AppError {
+	ret := _m.Called(child)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperationChild) apperrors.AppError); ok {
+		r0 = rf(child)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateBulkOperationState provides a mock function with given fields: id, state, message, endTimestamp
+func (_m *WriteSession) UpdateBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) apperrors.AppError {
+	ret := _m.Called(id, state, message, endTimestamp)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.BulkOperationState, string, *time.Time) apperrors.AppError); ok {
+		r0 = rf(id, state, message, endTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateDeletionProtection provides a mock function with given fields: runtimeID, enabled
+func (_m *WriteSession) UpdateDeletionProtection(runtimeID string, enabled bool) apperrors.AppError {
+	ret := _m.Called(runtimeID, enabled)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, bool) apperrors.AppError); ok {
+		r0 = rf(runtimeID, enabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // UpdateGardenerClusterConfig provides a mock function with given fields: config
 func (_m *WriteSession) UpdateGardenerClusterConfig(config model.GardenerConfig) apperrors.AppError {
 	ret := _m.Called(config)
@@ -177,13 +369,45 @@ func (_m *WriteSession) UpdateKubernetesVersion(runtimeID string, version string
 	return r0
 }
 
-// UpdateOperationLastError provides a mock function with given fields: operationID, msg, reason, component
-func (_m *WriteSession) UpdateOperationLastError(operationID string, msg string, reason string, component string) apperrors.AppError {
-	ret := _m.Called(operationID, msg, reason, component)
+// UpdateOperationDiagnostics provides a mock function with given fields: operationID, diagnostics
+func (_m *WriteSession) UpdateOperationDiagnostics(operationID string, diagnostics model.OperationDiagnostics) apperrors.AppError {
+	ret := _m.Called(operationID, diagnostics)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.OperationDiagnostics) apperrors.AppError); ok {
+		r0 = rf(operationID, diagnostics)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOperationLastError provides a mock function with given fields: operationID, msg, reason, component, action
+func (_m *WriteSession) UpdateOperationLastError(operationID string, msg string, reason string, component string, action string) apperrors.AppError {
+	ret := _m.Called(operationID, msg, reason, component, action)
 
 	var r0 apperrors.AppError
-	if rf, ok := ret.Get(0).(func(string, string, string, string) apperrors.AppError); ok {
-		r0 = rf(operationID, msg, reason, component)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) apperrors.AppError); ok {
+		r0 = rf(operationID, msg, reason, component, action)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOperationPipelineVersion provides a mock function with given fields: operationID, version
+func (_m *WriteSession) UpdateOperationPipelineVersion(operationID string, version string) apperrors.AppError {
+	ret := _m.Called(operationID, version)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
+		r0 = rf(operationID, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(apperrors.AppError)
@@ -225,13 +449,45 @@ func (_m *WriteSession) UpdateShootNetworkingFilterDisabled(runtimeID string, sh
 	return r0
 }
 
-// UpdateTenant provides a mock function with given fields: runtimeID, tenant
-func (_m *WriteSession) UpdateTenant(runtimeID string, tenant string) apperrors.AppError {
-	ret := _m.Called(runtimeID, tenant)
+// UpdateUnfinishedBulkOperationState provides a mock function with given fields: id, state, message, endTimestamp
+func (_m *WriteSession) UpdateUnfinishedBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) apperrors.AppError {
+	ret := _m.Called(id, state, message, endTimestamp)
 
 	var r0 apperrors.AppError
-	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
-		r0 = rf(runtimeID, tenant)
+	if rf, ok := ret.Get(0).(func(string, model.BulkOperationState, string, *time.Time) apperrors.AppError); ok {
+		r0 = rf(id, state, message, endTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpsertCredentialsRotationStatus provides a mock function with given fields: runtimeID, status
+func (_m *WriteSession) UpsertCredentialsRotationStatus(runtimeID string, status model.CredentialsRotationStatus) apperrors.AppError {
+	ret := _m.Called(runtimeID, status)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.CredentialsRotationStatus) apperrors.AppError); ok {
+		r0 = rf(runtimeID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpsertTenantQuota provides a mock function with given fields: quota
+func (_m *WriteSession) UpsertTenantQuota(quota model.TenantQuota) apperrors.AppError {
+	ret := _m.Called(quota)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.TenantQuota) apperrors.AppError); ok {
+		r0 = rf(quota)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(apperrors.AppError)
@@ -17,6 +17,38 @@ type WriteSessionWithinTransaction struct {
 	mock.Mock
 }
 
+// CancelPendingBulkOperationChildren provides a mock function with given fields: bulkOperationID, message
+func (_m *WriteSessionWithinTransaction) CancelPendingBulkOperationChildren(bulkOperationID string, message string) apperrors.AppError {
+	ret := _m.Called(bulkOperationID, message)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
+		r0 = rf(bulkOperationID, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// CancelPendingOperation provides a mock function with given fields: operationID, message, endTime
+func (_m *WriteSessionWithinTransaction) CancelPendingOperation(operationID string, message string, endTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, message, endTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, message, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // Commit provides a mock function with given fields:
 func (_m *WriteSessionWithinTransaction) Commit() apperrors.AppError {
 	ret := _m.Called()
@@ -49,6 +81,68 @@ func (_m *WriteSessionWithinTransaction) DeleteCluster(runtimeID string) apperro
 	return r0
 }
 
+// FinishOperationStage provides a mock function with given fields: operationID, stage, state, endTime
+func (_m *WriteSessionWithinTransaction) FinishOperationStage(operationID string, stage model.OperationStage, state model.StageState, endTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, stage, state, endTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.OperationStage, model.StageState, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, stage, state, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// GetBulkOperationStateForUpdate provides a mock function with given fields: id
+func (_m *WriteSessionWithinTransaction) GetBulkOperationStateForUpdate(id string) (model.BulkOperationState, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 model.BulkOperationState
+	if rf, ok := ret.Get(0).(func(string) model.BulkOperationState); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(model.BulkOperationState)
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetDeletionProtectionForUpdate provides a mock function with given fields: runtimeID
+func (_m *WriteSessionWithinTransaction) GetDeletionProtectionForUpdate(runtimeID string) (bool, apperrors.AppError) {
+	ret := _m.Called(runtimeID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(runtimeID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(runtimeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // InsertAdministrators provides a mock function with given fields: clusterId, administrators
 func (_m *WriteSessionWithinTransaction) InsertAdministrators(clusterId string, administrators []string) apperrors.AppError {
 	ret := _m.Called(clusterId, administrators)
@@ -65,6 +159,38 @@ func (_m *WriteSessionWithinTransaction) InsertAdministrators(clusterId string,
 	return r0
 }
 
+// InsertBulkOperation provides a mock function with given fields: operation
+func (_m *WriteSessionWithinTransaction) InsertBulkOperation(operation model.BulkOperation) apperrors.AppError {
+	ret := _m.Called(operation)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperation) apperrors.AppError); ok {
+		r0 = rf(operation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// InsertBulkOperationChild provides a mock function with given fields: child
+func (_m *WriteSessionWithinTransaction) InsertBulkOperationChild(child model.BulkOperationChild) apperrors.AppError {
+	ret := _m.Called(child)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperationChild) apperrors.AppError); ok {
+		r0 = rf(child)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // InsertCluster provides a mock function with given fields: cluster
 func (_m *WriteSessionWithinTransaction) InsertCluster(cluster model.Cluster) apperrors.AppError {
 	ret := _m.Called(cluster)
@@ -81,6 +207,22 @@ func (_m *WriteSessionWithinTransaction) InsertCluster(cluster model.Cluster) ap
 	return r0
 }
 
+// InsertCredentialsRotation provides a mock function with given fields: rotation
+func (_m *WriteSessionWithinTransaction) InsertCredentialsRotation(rotation model.CredentialsRotation) apperrors.AppError {
+	ret := _m.Called(rotation)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.CredentialsRotation) apperrors.AppError); ok {
+		r0 = rf(rotation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // InsertGardenerConfig provides a mock function with given fields: config
 func (_m *WriteSessionWithinTransaction) InsertGardenerConfig(config model.GardenerConfig) apperrors.AppError {
 	ret := _m.Called(config)
@@ -113,6 +255,54 @@ func (_m *WriteSessionWithinTransaction) InsertOperation(operation model.Operati
 	return r0
 }
 
+// InsertOperationStage provides a mock function with given fields: operationID, stage, state, startTime
+func (_m *WriteSessionWithinTransaction) InsertOperationStage(operationID string, stage model.OperationStage, state model.StageState, startTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, stage, state, startTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.OperationStage, model.StageState, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, stage, state, startTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// InsertPreUpgradeGardenerConfig provides a mock function with given fields: operationID, config
+func (_m *WriteSessionWithinTransaction) InsertPreUpgradeGardenerConfig(operationID string, config model.GardenerConfig) apperrors.AppError {
+	ret := _m.Called(operationID, config)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.GardenerConfig) apperrors.AppError); ok {
+		r0 = rf(operationID, config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// LockTenantQuota provides a mock function with given fields: tenant
+func (_m *WriteSessionWithinTransaction) LockTenantQuota(tenant string) apperrors.AppError {
+	ret := _m.Called(tenant)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string) apperrors.AppError); ok {
+		r0 = rf(tenant)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // MarkClusterAsDeleted provides a mock function with given fields: runtimeID
 func (_m *WriteSessionWithinTransaction) MarkClusterAsDeleted(runtimeID string) apperrors.AppError {
 	ret := _m.Called(runtimeID)
@@ -134,6 +324,22 @@ func (_m *WriteSessionWithinTransaction) RollbackUnlessCommitted() {
 	_m.Called()
 }
 
+// StartPendingOperation provides a mock function with given fields: operationID, message, startTime
+func (_m *WriteSessionWithinTransaction) StartPendingOperation(operationID string, message string, startTime time.Time) apperrors.AppError {
+	ret := _m.Called(operationID, message, startTime)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) apperrors.AppError); ok {
+		r0 = rf(operationID, message, startTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // TransitionOperation provides a mock function with given fields: operationID, message, stage, transitionTime
 func (_m *WriteSessionWithinTransaction) TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) apperrors.AppError {
 	ret := _m.Called(operationID, message, stage, transitionTime)
@@ -150,6 +356,54 @@ func (_m *WriteSessionWithinTransaction) TransitionOperation(operationID string,
 	return r0
 }
 
+// UpdateBulkOperationChild provides a mock function with given fields: child
+func (_m *WriteSessionWithinTransaction) UpdateBulkOperationChild(child model.BulkOperationChild) apperrors.AppError {
+	ret := _m.Called(child)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.BulkOperationChild) apperrors.AppError); ok {
+		r0 = rf(child)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateBulkOperationState provides a mock function with given fields: id, state, message, endTimestamp
+func (_m *WriteSessionWithinTransaction) UpdateBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) apperrors.AppError {
+	ret := _m.Called(id, state, message, endTimestamp)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.BulkOperationState, string, *time.Time) apperrors.AppError); ok {
+		r0 = rf(id, state, message, endTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateDeletionProtection provides a mock function with given fields: runtimeID, enabled
+func (_m *WriteSessionWithinTransaction) UpdateDeletionProtection(runtimeID string, enabled bool) apperrors.AppError {
+	ret := _m.Called(runtimeID, enabled)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, bool) apperrors.AppError); ok {
+		r0 = rf(runtimeID, enabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // UpdateGardenerClusterConfig provides a mock function with given fields: config
 func (_m *WriteSessionWithinTransaction) UpdateGardenerClusterConfig(config model.GardenerConfig) apperrors.AppError {
 	ret := _m.Called(config)
@@ -198,13 +452,45 @@ func (_m *WriteSessionWithinTransaction) UpdateKubernetesVersion(runtimeID strin
 	return r0
 }
 
-// UpdateOperationLastError provides a mock function with given fields: operationID, msg, reason, component
-func (_m *WriteSessionWithinTransaction) UpdateOperationLastError(operationID string, msg string, reason string, component string) apperrors.AppError {
-	ret := _m.Called(operationID, msg, reason, component)
+// UpdateOperationDiagnostics provides a mock function with given fields: operationID, diagnostics
+func (_m *WriteSessionWithinTransaction) UpdateOperationDiagnostics(operationID string, diagnostics model.OperationDiagnostics) apperrors.AppError {
+	ret := _m.Called(operationID, diagnostics)
 
 	var r0 apperrors.AppError
-	if rf, ok := ret.Get(0).(func(string, string, string, string) apperrors.AppError); ok {
-		r0 = rf(operationID, msg, reason, component)
+	if rf, ok := ret.Get(0).(func(string, model.OperationDiagnostics) apperrors.AppError); ok {
+		r0 = rf(operationID, diagnostics)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOperationLastError provides a mock function with given fields: operationID, msg, reason, component, action
+func (_m *WriteSessionWithinTransaction) UpdateOperationLastError(operationID string, msg string, reason string, component string, action string) apperrors.AppError {
+	ret := _m.Called(operationID, msg, reason, component, action)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) apperrors.AppError); ok {
+		r0 = rf(operationID, msg, reason, component, action)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateOperationPipelineVersion provides a mock function with given fields: operationID, version
+func (_m *WriteSessionWithinTransaction) UpdateOperationPipelineVersion(operationID string, version string) apperrors.AppError {
+	ret := _m.Called(operationID, version)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
+		r0 = rf(operationID, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(apperrors.AppError)
@@ -246,13 +532,45 @@ func (_m *WriteSessionWithinTransaction) UpdateShootNetworkingFilterDisabled(run
 	return r0
 }
 
-// UpdateTenant provides a mock function with given fields: runtimeID, tenant
-func (_m *WriteSessionWithinTransaction) UpdateTenant(runtimeID string, tenant string) apperrors.AppError {
-	ret := _m.Called(runtimeID, tenant)
+// UpdateUnfinishedBulkOperationState provides a mock function with given fields: id, state, message, endTimestamp
+func (_m *WriteSessionWithinTransaction) UpdateUnfinishedBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) apperrors.AppError {
+	ret := _m.Called(id, state, message, endTimestamp)
 
 	var r0 apperrors.AppError
-	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
-		r0 = rf(runtimeID, tenant)
+	if rf, ok := ret.Get(0).(func(string, model.BulkOperationState, string, *time.Time) apperrors.AppError); ok {
+		r0 = rf(id, state, message, endTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpsertCredentialsRotationStatus provides a mock function with given fields: runtimeID, status
+func (_m *WriteSessionWithinTransaction) UpsertCredentialsRotationStatus(runtimeID string, status model.CredentialsRotationStatus) apperrors.AppError {
+	ret := _m.Called(runtimeID, status)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, model.CredentialsRotationStatus) apperrors.AppError); ok {
+		r0 = rf(runtimeID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// UpsertTenantQuota provides a mock function with given fields: quota
+func (_m *WriteSessionWithinTransaction) UpsertTenantQuota(quota model.TenantQuota) apperrors.AppError {
+	ret := _m.Called(quota)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(model.TenantQuota) apperrors.AppError); ok {
+		r0 = rf(quota)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(apperrors.AppError)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/gocraft/dbr/v2"
 
@@ -65,7 +66,7 @@ func (r readSession) GetCluster(runtimeID string) (model.Cluster, dberrors.Error
 		Select(
 			"id", "kubeconfig", "tenant",
 			"creation_timestamp", "deleted", "sub_account_id",
-			"active_kyma_config_id", "is_kubeconfig_encrypted").
+			"active_kyma_config_id", "is_kubeconfig_encrypted", "deletion_protection").
 		From("cluster").
 		Where(dbr.Eq("cluster.id", runtimeID)).
 		LoadOne(&cluster)
@@ -292,6 +293,12 @@ func (r readSession) getClusterAdministrators(runtimeID string) ([]model.Cluster
 	return decryptedClusterAdministrators, nil
 }
 
+// gardenerConfigSnapshot stores provider config as raw JSON, as the interface cannot be decoded directly
+type gardenerConfigSnapshot struct {
+	model.GardenerConfig
+	GardenerProviderConfig string
+}
+
 type gardenerConfigRead struct {
 	model.GardenerConfig
 	ProviderSpecificConfig string `db:"provider_specific_config"`
@@ -341,7 +348,7 @@ func (r readSession) getGardenerConfig(runtimeID string) (model.GardenerConfig,
 
 var (
 	operationColumns = []string{
-		"id", "type", "start_timestamp", "stage", "end_timestamp", "state", "message", "cluster_id", "last_transition", "err_message", "reason", "component",
+		"id", "type", "start_timestamp", "stage", "end_timestamp", "state", "message", "cluster_id", "last_transition", "err_message", "reason", "component", "action", "pipeline_version",
 	}
 )
 
@@ -407,6 +414,41 @@ func (r readSession) ListInProgressOperations() ([]model.Operation, dberrors.Err
 	return operations, nil
 }
 
+func (r readSession) ListPendingOperations() ([]model.Operation, dberrors.Error) {
+	var operations []model.Operation
+
+	_, err := r.session.
+		Select(operationColumns...).
+		From("operation").
+		Where(dbr.Eq("state", model.Pending)).
+		Load(&operations)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return []model.Operation{}, nil
+		}
+		return nil, dberrors.Internal("Failed to list Pending operations: %s", err)
+	}
+
+	return operations, nil
+}
+
+func (r readSession) ListClusterReferences() ([]model.ClusterReference, dberrors.Error) {
+	var clusters []model.ClusterReference
+
+	_, err := r.session.
+		Select("cluster.id", "cluster.tenant", "gardener_config.name AS shoot_name", "cluster.deleted", "cluster.deletion_timestamp").
+		From("cluster").
+		Join("gardener_config", "cluster.id=gardener_config.cluster_id").
+		Load(&clusters)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to list clusters: %s", err)
+	}
+
+	return clusters, nil
+}
+
 func (r readSession) GetRuntimeUpgrade(operationId string) (model.RuntimeUpgrade, dberrors.Error) {
 	var runtimeUpgrade model.RuntimeUpgrade
 
@@ -426,6 +468,129 @@ func (r readSession) GetRuntimeUpgrade(operationId string) (model.RuntimeUpgrade
 	return runtimeUpgrade, nil
 }
 
+func (r readSession) GetOperationStages(operationID string) ([]model.OperationStageStatus, dberrors.Error) {
+	var stages []model.OperationStageStatus
+
+	_, err := r.session.
+		Select("stage", "state", "start_timestamp", "end_timestamp").
+		From("operation_stage").
+		Where(dbr.Eq("operation_id", operationID)).
+		Load(&stages)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to get stages of operation %s: %s", operationID, err)
+	}
+
+	return stages, nil
+}
+
+func (r readSession) GetCredentialsRotation(operationID string) (model.CredentialsRotation, dberrors.Error) {
+	var row struct {
+		Kinds string
+		Phase model.CredentialsRotationPhase
+	}
+
+	err := r.session.
+		Select("kinds", "phase").
+		From("credentials_rotation").
+		Where(dbr.Eq("operation_id", operationID)).
+		LoadOne(&row)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return model.CredentialsRotation{}, dberrors.NotFound("Credentials rotation not found for operation with %s id", operationID)
+		}
+		return model.CredentialsRotation{}, dberrors.Internal("Failed to get credentials rotation for operation %s: %s", operationID, err)
+	}
+
+	rotation := model.CredentialsRotation{
+		OperationID: operationID,
+		Phase:       row.Phase,
+	}
+	for _, kind := range strings.Split(row.Kinds, ",") {
+		rotation.Kinds = append(rotation.Kinds, model.CredentialsRotationKind(kind))
+	}
+
+	return rotation, nil
+}
+
+func (r readSession) GetCredentialsRotationStatus(runtimeID string) ([]model.CredentialsRotationStatus, dberrors.Error) {
+	var statuses []model.CredentialsRotationStatus
+
+	_, err := r.session.
+		Select("kind", "phase", "last_initiation_time", "last_completion_time").
+		From("credentials_rotation_status").
+		Where(dbr.Eq("cluster_id", runtimeID)).
+		OrderBy("kind").
+		Load(&statuses)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to get credentials rotation status of Runtime %s: %s", runtimeID, err)
+	}
+
+	return statuses, nil
+}
+
+func (r readSession) GetOperationDiagnostics(operationID string) (model.OperationDiagnostics, dberrors.Error) {
+	var rawDiagnostics dbr.NullString
+
+	err := r.session.
+		Select("diagnostics").
+		From("operation").
+		Where(dbr.Eq("id", operationID)).
+		LoadOne(&rawDiagnostics)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return model.OperationDiagnostics{}, dberrors.NotFound("Operation with %s id not found", operationID)
+		}
+		return model.OperationDiagnostics{}, dberrors.Internal("Failed to get diagnostics of operation %s: %s", operationID, err)
+	}
+
+	if !rawDiagnostics.Valid {
+		return model.OperationDiagnostics{}, dberrors.NotFound("Diagnostics not captured for operation with %s id", operationID)
+	}
+
+	var diagnostics model.OperationDiagnostics
+	err = json.Unmarshal([]byte(rawDiagnostics.String), &diagnostics)
+	if err != nil {
+		return model.OperationDiagnostics{}, dberrors.Internal("Failed to decode diagnostics of operation %s: %s", operationID, err)
+	}
+
+	return diagnostics, nil
+}
+
+func (r readSession) GetPreUpgradeGardenerConfig(operationID string) (model.GardenerConfig, dberrors.Error) {
+	var rawConfig string
+
+	err := r.session.
+		Select("pre_upgrade_gardener_config").
+		From("shoot_upgrade").
+		Where(dbr.Eq("operation_id", operationID)).
+		LoadOne(&rawConfig)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return model.GardenerConfig{}, dberrors.NotFound("Shoot upgrade not found for operation with %s id", operationID)
+		}
+		return model.GardenerConfig{}, dberrors.Internal("Failed to get Shoot upgrade for operation %s: %s", operationID, err)
+	}
+
+	var snapshot gardenerConfigSnapshot
+	err = json.Unmarshal([]byte(rawConfig), &snapshot)
+	if err != nil {
+		return model.GardenerConfig{}, dberrors.Internal("Failed to decode pre-upgrade Gardener config for operation %s: %s", operationID, err)
+	}
+
+	providerConfig, appErr := model.NewGardenerProviderConfigFromJSON(snapshot.GardenerProviderConfig)
+	if appErr != nil {
+		return model.GardenerConfig{}, dberrors.Internal("Failed to decode pre-upgrade Gardener provider config for operation %s: %s", operationID, appErr)
+	}
+
+	snapshot.GardenerConfig.GardenerProviderConfig = providerConfig
+	return snapshot.GardenerConfig, nil
+}
+
 func (r readSession) InProgressOperationsCount() (model.OperationsCount, dberrors.Error) {
 	var opsCount []struct {
 		Type  model.OperationType
@@ -455,6 +620,141 @@ func (r readSession) InProgressOperationsCount() (model.OperationsCount, dberror
 	return operationsCount, nil
 }
 
+func (r readSession) GetTenantQuota(tenant string) (model.TenantQuota, dberrors.Error) {
+	quota := model.TenantQuota{
+		Tenant:              tenant,
+		MaxNodesPerProvider: map[string]int{},
+	}
+
+	err := r.session.
+		Select("max_runtimes", "max_operations_in_progress").
+		From("tenant_quota").
+		Where(dbr.Eq("tenant", tenant)).
+		LoadOne(&quota)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return model.TenantQuota{}, dberrors.NotFound("Quota not found for tenant: %s", tenant)
+		}
+		return model.TenantQuota{}, dberrors.Internal("Failed to get quota for tenant %s: %s", tenant, err)
+	}
+
+	var providerQuotas []struct {
+		Provider string
+		MaxNodes int
+	}
+
+	_, err = r.session.
+		Select("provider", "max_nodes").
+		From("tenant_provider_quota").
+		Where(dbr.Eq("tenant", tenant)).
+		Load(&providerQuotas)
+
+	if err != nil {
+		return model.TenantQuota{}, dberrors.Internal("Failed to get provider quotas for tenant %s: %s", tenant, err)
+	}
+
+	for _, providerQuota := range providerQuotas {
+		quota.MaxNodesPerProvider[providerQuota.Provider] = providerQuota.MaxNodes
+	}
+
+	return quota, nil
+}
+
+func (r readSession) GetTenantUsage(tenant string) (model.TenantUsage, dberrors.Error) {
+	usages, dberr := r.getTenantUsages(dbr.Eq("cluster.tenant", tenant))
+	if dberr != nil {
+		return model.TenantUsage{}, dberr
+	}
+
+	usage, found := usages[tenant]
+	if !found {
+		return model.TenantUsage{Tenant: tenant, NodesPerProvider: map[string]int{}}, nil
+	}
+
+	return *usage, nil
+}
+
+func (r readSession) ListTenantUsages() ([]model.TenantUsage, dberrors.Error) {
+	usages, dberr := r.getTenantUsages(nil)
+	if dberr != nil {
+		return nil, dberr
+	}
+
+	result := make([]model.TenantUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, *usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Tenant < result[j].Tenant
+	})
+
+	return result, nil
+}
+
+func (r readSession) getTenantUsages(tenantCondition dbr.Builder) (map[string]*model.TenantUsage, dberrors.Error) {
+	conditions := []dbr.Builder{dbr.Eq("cluster.deleted", false)}
+	if tenantCondition != nil {
+		conditions = append(conditions, tenantCondition)
+	}
+
+	var nodes []struct {
+		Tenant   string
+		Provider string
+		Count    int
+		Nodes    int
+	}
+
+	_, err := r.session.
+		Select("cluster.tenant", "gardener_config.provider", "count(*)", "sum(gardener_config.auto_scaler_max) AS nodes").
+		From("cluster").
+		Join("gardener_config", "gardener_config.cluster_id=cluster.id").
+		Where(dbr.And(conditions...)).
+		GroupBy("cluster.tenant", "gardener_config.provider").
+		Load(&nodes)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to count tenant nodes: %s", err)
+	}
+
+	var operations []struct {
+		Tenant string
+		Count  int
+	}
+
+	_, err = r.session.
+		Select("cluster.tenant", "count(*)").
+		From("operation").
+		Join("cluster", "operation.cluster_id=cluster.id").
+		Where(dbr.And(append(conditions, dbr.Eq("operation.state", model.InProgress))...)).
+		GroupBy("cluster.tenant").
+		Load(&operations)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to count tenant operations in progress: %s", err)
+	}
+
+	usages := map[string]*model.TenantUsage{}
+	getUsage := func(tenant string) *model.TenantUsage {
+		if _, found := usages[tenant]; !found {
+			usages[tenant] = &model.TenantUsage{Tenant: tenant, NodesPerProvider: map[string]int{}}
+		}
+		return usages[tenant]
+	}
+
+	for _, n := range nodes {
+		usage := getUsage(n.Tenant)
+		usage.Runtimes += n.Count
+		usage.NodesPerProvider[n.Provider] = n.Nodes
+	}
+	for _, op := range operations {
+		getUsage(op.Tenant).OperationsInProgress = op.Count
+	}
+
+	return usages, nil
+}
+
 func (r readSession) getOidcConfig(gardenerConfigID string) (model.OIDCConfig, dberrors.Error) {
 	var oidc model.OIDCConfig
 	var algorithms []string
@@ -562,3 +862,158 @@ func (r readSession) decryptClusterAdministrators(
 	}
 	return decryptedClusterAdministrators, nil
 }
+
+func (r readSession) ListRuntimeIDs(selector model.RuntimeSelector) ([]string, dberrors.Error) {
+	if selector.IsEmpty() {
+		return nil, dberrors.Internal("Failed to list Runtimes: selector without criteria would select all Runtimes")
+	}
+
+	conditions := []dbr.Builder{dbr.Eq("cluster.deleted", false)}
+	if len(selector.Tenants) > 0 {
+		conditions = append(conditions, dbr.Eq("cluster.tenant", selector.Tenants))
+	}
+	if len(selector.Providers) > 0 {
+		conditions = append(conditions, dbr.Eq("gardener_config.provider", selector.Providers))
+	}
+	if len(selector.Regions) > 0 {
+		conditions = append(conditions, dbr.Eq("gardener_config.region", selector.Regions))
+	}
+	if len(selector.KubernetesVersions) > 0 {
+		conditions = append(conditions, dbr.Eq("gardener_config.kubernetes_version", selector.KubernetesVersions))
+	}
+
+	var runtimeIDs []string
+
+	_, err := r.session.
+		Select("cluster.id").
+		From("cluster").
+		Join("gardener_config", "gardener_config.cluster_id=cluster.id").
+		Where(dbr.And(conditions...)).
+		OrderBy("cluster.id").
+		Load(&runtimeIDs)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to list Runtimes: %s", err)
+	}
+
+	return runtimeIDs, nil
+}
+
+type bulkOperationRow struct {
+	ID              string
+	Type            model.OperationType
+	State           model.BulkOperationState
+	Message         string
+	Selector        string
+	Input           string
+	MaxParallel     int
+	MaxFailureRatio float64
+	StartTimestamp  time.Time
+	EndTimestamp    *time.Time
+}
+
+var bulkOperationColumns = []string{
+	"id", "type", "state", "message", "selector", "input", "max_parallel", "max_failure_ratio", "start_timestamp", "end_timestamp",
+}
+
+func (r readSession) GetBulkOperation(id string) (model.BulkOperation, dberrors.Error) {
+	var row bulkOperationRow
+
+	err := r.session.
+		Select(bulkOperationColumns...).
+		From("bulk_operation").
+		Where(dbr.Eq("id", id)).
+		LoadOne(&row)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return model.BulkOperation{}, dberrors.NotFound("Bulk operation with %s id not found", id)
+		}
+		return model.BulkOperation{}, dberrors.Internal("Failed to get bulk operation %s: %s", id, err)
+	}
+
+	return row.toBulkOperation()
+}
+
+func (r readSession) ListUnfinishedBulkOperations() ([]model.BulkOperation, dberrors.Error) {
+	var rows []bulkOperationRow
+
+	_, err := r.session.
+		Select(bulkOperationColumns...).
+		From("bulk_operation").
+		Where(dbr.Eq("end_timestamp", nil)).
+		OrderBy("start_timestamp").
+		Load(&rows)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to list unfinished bulk operations: %s", err)
+	}
+
+	operations := make([]model.BulkOperation, 0, len(rows))
+	for _, row := range rows {
+		operation, dberr := row.toBulkOperation()
+		if dberr != nil {
+			return nil, dberr
+		}
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
+func (row bulkOperationRow) toBulkOperation() (model.BulkOperation, dberrors.Error) {
+	var selector model.RuntimeSelector
+	err := json.Unmarshal([]byte(row.Selector), &selector)
+	if err != nil {
+		return model.BulkOperation{}, dberrors.Internal("Failed to decode selector of bulk operation %s: %s", row.ID, err)
+	}
+
+	return model.BulkOperation{
+		ID:       row.ID,
+		Type:     row.Type,
+		State:    row.State,
+		Message:  row.Message,
+		Selector: selector,
+		Input:    row.Input,
+		Strategy: model.BulkOperationStrategy{
+			MaxParallel:     row.MaxParallel,
+			MaxFailureRatio: row.MaxFailureRatio,
+		},
+		StartTimestamp: row.StartTimestamp,
+		EndTimestamp:   row.EndTimestamp,
+	}, nil
+}
+
+func (r readSession) ListBulkOperationChildren(bulkOperationID string) ([]model.BulkOperationChild, dberrors.Error) {
+	var rows []struct {
+		BulkOperationID string
+		RuntimeID       string
+		OperationID     dbr.NullString
+		State           model.OperationState
+		Message         string
+	}
+
+	_, err := r.session.
+		Select("bulk_operation_id", "runtime_id", "operation_id", "state", "message").
+		From("bulk_operation_child").
+		Where(dbr.Eq("bulk_operation_id", bulkOperationID)).
+		OrderBy("runtime_id").
+		Load(&rows)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to list children of bulk operation %s: %s", bulkOperationID, err)
+	}
+
+	children := make([]model.BulkOperationChild, 0, len(rows))
+	for _, row := range rows {
+		children = append(children, model.BulkOperationChild{
+			BulkOperationID: row.BulkOperationID,
+			RuntimeID:       row.RuntimeID,
+			OperationID:     row.OperationID.String,
+			State:           row.State,
+			Message:         row.Message,
+		})
+	}
+
+	return children, nil
+}
@@ -14,6 +14,9 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
 )
 
+// tenantQuotaLockClass separates the advisory locks of the tenant quotas from other advisory locks
+const tenantQuotaLockClass = 1
+
 type writeSession struct {
 	session     *dbr.Session
 	transaction *dbr.Tx
@@ -33,6 +36,7 @@ func (ws writeSession) InsertCluster(cluster model.Cluster) dberrors.Error {
 		Pair("sub_account_id", cluster.SubAccountId).
 		Pair("active_kyma_config_id", kymaConfigId). // Possible due to deferred constrain
 		Pair("is_kubeconfig_encrypted", false).
+		Pair("deletion_protection", cluster.DeletionProtection).
 		Exec()
 
 	if err != nil {
@@ -310,6 +314,29 @@ func (ws writeSession) InsertOperation(operation model.Operation) dberrors.Error
 	return nil
 }
 
+func (ws writeSession) InsertPreUpgradeGardenerConfig(operationID string, config model.GardenerConfig) dberrors.Error {
+	snapshot := gardenerConfigSnapshot{GardenerConfig: config}
+	if config.GardenerProviderConfig != nil {
+		snapshot.GardenerProviderConfig = config.GardenerProviderConfig.RawJSON()
+	}
+
+	rawConfig, err := json.Marshal(snapshot)
+	if err != nil {
+		return dberrors.Internal("Failed to encode pre-upgrade Gardener config: %s", err)
+	}
+
+	_, err = ws.insertInto("shoot_upgrade").
+		Pair("operation_id", operationID).
+		Pair("pre_upgrade_gardener_config", string(rawConfig)).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to insert record to Shoot upgrade table: %s", err)
+	}
+
+	return nil
+}
+
 func (ws writeSession) DeleteCluster(runtimeID string) dberrors.Error {
 	result, err := ws.deleteFrom("cluster").
 		Where(dbr.Eq("id", runtimeID)).
@@ -332,6 +359,49 @@ func (ws writeSession) DeleteCluster(runtimeID string) dberrors.Error {
 	return nil
 }
 
+func (ws writeSession) InsertCredentialsRotation(rotation model.CredentialsRotation) dberrors.Error {
+	kinds := make([]string, 0, len(rotation.Kinds))
+	for _, kind := range rotation.Kinds {
+		kinds = append(kinds, string(kind))
+	}
+
+	_, err := ws.insertInto("credentials_rotation").
+		Pair("operation_id", rotation.OperationID).
+		Pair("kinds", strings.Join(kinds, ",")).
+		Pair("phase", rotation.Phase).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to insert credentials rotation of operation %s: %s", rotation.OperationID, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpsertCredentialsRotationStatus(runtimeID string, status model.CredentialsRotationStatus) dberrors.Error {
+	_, err := ws.deleteFrom("credentials_rotation_status").
+		Where(dbr.And(dbr.Eq("cluster_id", runtimeID), dbr.Eq("kind", status.Kind))).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to delete %s rotation status of Runtime %s: %s", status.Kind, runtimeID, err)
+	}
+
+	_, err = ws.insertInto("credentials_rotation_status").
+		Pair("cluster_id", runtimeID).
+		Pair("kind", status.Kind).
+		Pair("phase", status.Phase).
+		Pair("last_initiation_time", status.LastInitiationTime).
+		Pair("last_completion_time", status.LastCompletionTime).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to insert %s rotation status of Runtime %s: %s", status.Kind, runtimeID, err)
+	}
+
+	return nil
+}
+
 func (ws writeSession) UpdateOperationState(operationID string, message string, state model.OperationState, endTime time.Time) dberrors.Error {
 	res, err := ws.update("operation").
 		Where(dbr.Eq("id", operationID)).
@@ -347,12 +417,45 @@ func (ws writeSession) UpdateOperationState(operationID string, message string,
 	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update operation %s state: %s", operationID, err))
 }
 
-func (ws writeSession) UpdateOperationLastError(operationID, msg, reason, component string) dberrors.Error {
+// StartPendingOperation fails with NotFound error if the operation is no longer pending, for example was canceled
+func (ws writeSession) StartPendingOperation(operationID string, message string, startTime time.Time) dberrors.Error {
+	res, err := ws.update("operation").
+		Where(dbr.And(dbr.Eq("id", operationID), dbr.Eq("state", model.Pending))).
+		Set("state", model.InProgress).
+		Set("message", message).
+		Set("start_timestamp", startTime).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to start pending operation %s: %s", operationID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Pending operation %s not found", operationID))
+}
+
+// CancelPendingOperation fails with NotFound error if the operation is no longer pending, for example was already started
+func (ws writeSession) CancelPendingOperation(operationID string, message string, endTime time.Time) dberrors.Error {
+	res, err := ws.update("operation").
+		Where(dbr.And(dbr.Eq("id", operationID), dbr.Eq("state", model.Pending))).
+		Set("state", model.Canceled).
+		Set("message", message).
+		Set("end_timestamp", endTime).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to cancel pending operation %s: %s", operationID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Pending operation %s not found", operationID))
+}
+
+func (ws writeSession) UpdateOperationLastError(operationID, msg, reason, component, action string) dberrors.Error {
 	res, err := ws.update("operation").
 		Where(dbr.Eq("id", operationID)).
 		Set("err_message", msg).
 		Set("reason", reason).
 		Set("component", component).
+		Set("action", action).
 		Exec()
 
 	if err != nil {
@@ -362,6 +465,24 @@ func (ws writeSession) UpdateOperationLastError(operationID, msg, reason, compon
 	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update operation %s last error: %s", operationID, err))
 }
 
+func (ws writeSession) UpdateOperationDiagnostics(operationID string, diagnostics model.OperationDiagnostics) dberrors.Error {
+	rawDiagnostics, err := json.Marshal(diagnostics)
+	if err != nil {
+		return dberrors.Internal("Failed to encode diagnostics of operation %s: %s", operationID, err)
+	}
+
+	res, err := ws.update("operation").
+		Where(dbr.Eq("id", operationID)).
+		Set("diagnostics", string(rawDiagnostics)).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to update operation %s diagnostics: %s", operationID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update operation %s diagnostics: %s", operationID, err))
+}
+
 func (ws writeSession) TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) dberrors.Error {
 	res, err := ws.update("operation").
 		Where(dbr.Eq("id", operationID)).
@@ -377,6 +498,67 @@ func (ws writeSession) TransitionOperation(operationID string, message string, s
 	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update operation %s state: %s", operationID, err))
 }
 
+func (ws writeSession) UpdateOperationPipelineVersion(operationID string, version string) dberrors.Error {
+	res, err := ws.update("operation").
+		Where(dbr.Eq("id", operationID)).
+		Set("pipeline_version", version).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to update operation %s pipeline version: %s", operationID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update operation %s pipeline version: %s", operationID, err))
+}
+
+// CancelPendingBulkOperationChildren cancels only the children which are still pending, the other ones are left unchanged
+func (ws writeSession) CancelPendingBulkOperationChildren(bulkOperationID string, message string) dberrors.Error {
+	_, err := ws.update("bulk_operation_child").
+		Where(dbr.And(dbr.Eq("bulk_operation_id", bulkOperationID), dbr.Eq("state", model.Pending))).
+		Set("state", model.Canceled).
+		Set("message", message).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to cancel pending children of bulk operation %s: %s", bulkOperationID, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertOperationStage(operationID string, stage model.OperationStage, state model.StageState, startTime time.Time) dberrors.Error {
+	insert := ws.insertInto("operation_stage").
+		Pair("operation_id", operationID).
+		Pair("stage", stage).
+		Pair("state", state).
+		Pair("start_timestamp", startTime)
+
+	if state != model.StageInProgress {
+		insert = insert.Pair("end_timestamp", startTime)
+	}
+
+	_, err := insert.Exec()
+	if err != nil {
+		return dberrors.Internal("Failed to insert stage %s of operation %s: %s", stage, operationID, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) FinishOperationStage(operationID string, stage model.OperationStage, state model.StageState, endTime time.Time) dberrors.Error {
+	res, err := ws.update("operation_stage").
+		Where(dbr.And(dbr.Eq("operation_id", operationID), dbr.Eq("stage", stage))).
+		Set("state", state).
+		Set("end_timestamp", endTime).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to finish stage %s of operation %s: %s", stage, operationID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Stage %s of operation %s not found", stage, operationID))
+}
+
 func (ws writeSession) UpdateKubeconfig(runtimeID string, kubeconfig string) dberrors.Error {
 	encryptedKubeconfig, dberr := ws.encryptString(kubeconfig)
 	if dberr != nil {
@@ -426,6 +608,7 @@ func (ws writeSession) MarkClusterAsDeleted(runtimeID string) dberrors.Error {
 	res, err := ws.update("cluster").
 		Where(dbr.Eq("id", runtimeID)).
 		Set("deleted", true).
+		Set("deletion_timestamp", time.Now()).
 		Exec()
 
 	if err != nil {
@@ -435,16 +618,111 @@ func (ws writeSession) MarkClusterAsDeleted(runtimeID string) dberrors.Error {
 	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update cluster %s data: %s", runtimeID, err))
 }
 
-func (ws writeSession) UpdateTenant(runtimeID, tenant string) dberrors.Error {
+func (ws writeSession) UpdateDeletionProtection(runtimeID string, enabled bool) dberrors.Error {
 	res, err := ws.update("cluster").
 		Where(dbr.Eq("id", runtimeID)).
-		Set("tenant", tenant).
+		Set("deletion_protection", enabled).
 		Exec()
 
 	if err != nil {
-		return dberrors.Internal("Failed to update cluster %s state: %s", runtimeID, err)
+		return dberrors.Internal("Failed to update cluster %s deletion protection: %s", runtimeID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update cluster %s deletion protection: %s", runtimeID, err))
+}
+
+func (ws writeSession) GetDeletionProtectionForUpdate(runtimeID string) (bool, dberrors.Error) {
+	if ws.transaction == nil {
+		return false, dberrors.Internal("Failed to lock cluster %s: no transaction", runtimeID)
+	}
+
+	var deletionProtection bool
+	err := ws.transaction.
+		Select("deletion_protection").
+		From("cluster").
+		Where(dbr.Eq("id", runtimeID)).
+		Suffix("FOR UPDATE").
+		LoadOne(&deletionProtection)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return false, dberrors.NotFound("Cannot find cluster %s", runtimeID)
+		}
+		return false, dberrors.Internal("Failed to lock cluster %s: %s", runtimeID, err)
+	}
+
+	return deletionProtection, nil
+}
+
+func (ws writeSession) GetBulkOperationStateForUpdate(id string) (model.BulkOperationState, dberrors.Error) {
+	if ws.transaction == nil {
+		return "", dberrors.Internal("Failed to lock bulk operation %s: no transaction", id)
+	}
+
+	var state model.BulkOperationState
+	err := ws.transaction.
+		Select("state").
+		From("bulk_operation").
+		Where(dbr.Eq("id", id)).
+		Suffix("FOR UPDATE").
+		LoadOne(&state)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return "", dberrors.NotFound("Bulk operation with %s id not found", id)
+		}
+		return "", dberrors.Internal("Failed to lock bulk operation %s: %s", id, err)
 	}
-	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update tenant %s: %s", tenant, err))
+
+	return state, nil
+}
+
+func (ws writeSession) UpsertTenantQuota(quota model.TenantQuota) dberrors.Error {
+	_, err := ws.deleteFrom("tenant_quota").
+		Where(dbr.Eq("tenant", quota.Tenant)).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to delete quota for tenant %s: %s", quota.Tenant, err)
+	}
+
+	_, err = ws.insertInto("tenant_quota").
+		Pair("tenant", quota.Tenant).
+		Pair("max_runtimes", quota.MaxRuntimes).
+		Pair("max_operations_in_progress", quota.MaxOperationsInProgress).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to insert quota for tenant %s: %s", quota.Tenant, err)
+	}
+
+	for provider, maxNodes := range quota.MaxNodesPerProvider {
+		_, err = ws.insertInto("tenant_provider_quota").
+			Pair("tenant", quota.Tenant).
+			Pair("provider", provider).
+			Pair("max_nodes", maxNodes).
+			Exec()
+
+		if err != nil {
+			return dberrors.Internal("Failed to insert %s provider quota for tenant %s: %s", provider, quota.Tenant, err)
+		}
+	}
+
+	return nil
+}
+
+// LockTenantQuota takes the advisory lock of the tenant, which is released when the transaction ends
+func (ws writeSession) LockTenantQuota(tenant string) dberrors.Error {
+	if ws.transaction == nil {
+		return dberrors.Internal("Failed to lock quota for tenant %s: no transaction", tenant)
+	}
+
+	_, err := ws.transaction.Exec("SELECT pg_advisory_xact_lock($1, hashtext($2))", tenantQuotaLockClass, tenant)
+	if err != nil {
+		return dberrors.Internal("Failed to lock quota for tenant %s: %s", tenant, err)
+	}
+
+	return nil
 }
 
 func (ws writeSession) updateSucceeded(result sql.Result, errorMsg string) dberrors.Error {
@@ -504,3 +782,100 @@ func (ws writeSession) encryptString(s string) (string, dberrors.Error) {
 	}
 	return string(encrypted), nil
 }
+
+func (ws writeSession) InsertBulkOperation(operation model.BulkOperation) dberrors.Error {
+	selector, err := json.Marshal(operation.Selector)
+	if err != nil {
+		return dberrors.Internal("Failed to encode selector of bulk operation %s: %s", operation.ID, err)
+	}
+
+	_, err = ws.insertInto("bulk_operation").
+		Pair("id", operation.ID).
+		Pair("type", operation.Type).
+		Pair("state", operation.State).
+		Pair("message", operation.Message).
+		Pair("selector", string(selector)).
+		Pair("input", operation.Input).
+		Pair("max_parallel", operation.Strategy.MaxParallel).
+		Pair("max_failure_ratio", operation.Strategy.MaxFailureRatio).
+		Pair("start_timestamp", operation.StartTimestamp).
+		Pair("end_timestamp", operation.EndTimestamp).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to insert bulk operation %s: %s", operation.ID, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertBulkOperationChild(child model.BulkOperationChild) dberrors.Error {
+	_, err := ws.insertInto("bulk_operation_child").
+		Pair("bulk_operation_id", child.BulkOperationID).
+		Pair("runtime_id", child.RuntimeID).
+		Pair("operation_id", nullableString(child.OperationID)).
+		Pair("state", child.State).
+		Pair("message", child.Message).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to insert child of bulk operation %s for Runtime %s: %s", child.BulkOperationID, child.RuntimeID, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpdateBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) dberrors.Error {
+	res, err := ws.update("bulk_operation").
+		Where(dbr.Eq("id", id)).
+		Set("state", state).
+		Set("message", message).
+		Set("end_timestamp", endTimestamp).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to update bulk operation %s state: %s", id, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update bulk operation %s state: %s", id, err))
+}
+
+// UpdateUnfinishedBulkOperationState fails with NotFound error if the bulk operation is no longer in progress or paused,
+// for example was canceled
+func (ws writeSession) UpdateUnfinishedBulkOperationState(id string, state model.BulkOperationState, message string, endTimestamp *time.Time) dberrors.Error {
+	res, err := ws.update("bulk_operation").
+		Where(dbr.And(dbr.Eq("id", id), dbr.Eq("state", []model.BulkOperationState{model.BulkInProgress, model.BulkPaused}))).
+		Set("state", state).
+		Set("message", message).
+		Set("end_timestamp", endTimestamp).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to update bulk operation %s state: %s", id, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Unfinished bulk operation %s not found", id))
+}
+
+func (ws writeSession) UpdateBulkOperationChild(child model.BulkOperationChild) dberrors.Error {
+	res, err := ws.update("bulk_operation_child").
+		Where(dbr.And(dbr.Eq("bulk_operation_id", child.BulkOperationID), dbr.Eq("runtime_id", child.RuntimeID))).
+		Set("operation_id", nullableString(child.OperationID)).
+		Set("state", child.State).
+		Set("message", child.Message).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to update child of bulk operation %s for Runtime %s: %s", child.BulkOperationID, child.RuntimeID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update child of bulk operation %s for Runtime %s: %s", child.BulkOperationID, child.RuntimeID, err))
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -1,30 +1,54 @@
 package provisioning
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	gardener_Types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/hashicorp/go-version"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/director"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/gardener"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/orphans"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/quota"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 	uuid "github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 	log "github.com/sirupsen/logrus"
 )
 
+const productionPurpose = "production"
+
 //go:generate mockery --name=Service
 type Service interface {
 	ProvisionRuntime(config gqlschema.ProvisionRuntimeInput, tenant, subAccount string) (*gqlschema.OperationStatus, apperrors.AppError)
-	DeprovisionRuntime(id string) (string, apperrors.AppError)
+	DeprovisionRuntime(id string, force bool) (string, apperrors.AppError)
+	CancelDeprovisioning(id string) (*gqlschema.OperationStatus, apperrors.AppError)
+	SetDeletionProtection(id string, enabled bool) (*gqlschema.RuntimeStatus, apperrors.AppError)
 	UpgradeGardenerShoot(id string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, apperrors.AppError)
+	RotateCredentials(id string, kinds []gqlschema.CredentialsRotationKind, phase gqlschema.CredentialsRotationPhase) (*gqlschema.OperationStatus, apperrors.AppError)
 	ReconnectRuntimeAgent(id string) (string, apperrors.AppError)
 	RuntimeStatus(id string) (*gqlschema.RuntimeStatus, apperrors.AppError)
 	RuntimeOperationStatus(id string) (*gqlschema.OperationStatus, apperrors.AppError)
+	RuntimeOperationDiagnostics(id string) (*gqlschema.OperationDiagnostics, apperrors.AppError)
+	SubscribeOperationStatus(ctx context.Context, id string) (<-chan *gqlschema.OperationStatus, apperrors.AppError)
+	SubscribeRuntimeEvents(ctx context.Context, runtimeID string) (<-chan *gqlschema.RuntimeEvent, apperrors.AppError)
+	TenantUsage(tenant string) (*gqlschema.TenantUsage, apperrors.AppError)
+	TenantsUsage() ([]*gqlschema.TenantUsage, apperrors.AppError)
+	SetTenantQuota(tenant string, input gqlschema.TenantQuotaInput) (*gqlschema.TenantQuota, apperrors.AppError)
+	FindOrphans() (*gqlschema.OrphansReport, apperrors.AppError)
+	BulkUpgradeShoot(selector gqlschema.RuntimeSelectorInput, input gqlschema.UpgradeShootInput, strategy gqlschema.BulkOperationStrategyInput) (*gqlschema.BulkOperationStatus, apperrors.AppError)
+	BulkOperationStatus(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError)
+	PauseBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError)
+	ResumeBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError)
+	CancelBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError)
 }
 
 //go:generate mockery --name=Provisioner
@@ -34,6 +58,18 @@ type Provisioner interface {
 	UpgradeCluster(clusterID string, upgradeConfig model.GardenerConfig) apperrors.AppError
 }
 
+//go:generate mockery --name=SeedSelector
+type SeedSelector interface {
+	SelectSeed(config model.GardenerConfig) (string, string, apperrors.AppError)
+}
+
+// gardenerSeedSelector leaves the seed selection to Gardener
+type gardenerSeedSelector struct{}
+
+func (gardenerSeedSelector) SelectSeed(_ model.GardenerConfig) (string, string, apperrors.AppError) {
+	return "", "", nil
+}
+
 //go:generate mockery --name=ShootProvider
 type ShootProvider interface {
 	Get(runtimeID string, tenant string) (gardener_Types.Shoot, apperrors.AppError)
@@ -42,7 +78,7 @@ type ShootProvider interface {
 type service struct {
 	inputConverter   InputConverter
 	graphQLConverter GraphQLConverter
-	directorService  director.DirectorClient
+	runtimeRegistry  director.RuntimeRegistry
 	shootProvider    ShootProvider
 
 	dbSessionFactory dbsession.Factory
@@ -54,12 +90,35 @@ type service struct {
 	upgradeQueue        queue.OperationQueue
 	shootUpgradeQueue   queue.OperationQueue
 	hibernationQueue    queue.OperationQueue
+
+	credentialsRotationQueue queue.OperationQueue
+
+	eventSubscriber events.Subscriber
+	quotaManager    quota.Manager
+	orphanScanner   orphans.Scanner
+	seedSelector    SeedSelector
+
+	deprovisioningGracePeriod time.Duration
+}
+
+// ServiceOptions groups the dependencies of the service which are only needed by some of its operations
+type ServiceOptions struct {
+	CredentialsRotationQueue queue.OperationQueue
+	EventSubscriber          events.Subscriber
+	// QuotaManager checks tenant quotas, without it only quotas stored for tenants are applied
+	QuotaManager quota.Manager
+	// OrphanScanner finds leftovers of Runtimes, without it orphan scanning is disabled
+	OrphanScanner orphans.Scanner
+	// SeedSelector chooses seeds for new Shoots, without it the seed is chosen by Gardener
+	SeedSelector SeedSelector
+	// DeprovisioningGracePeriod delays deprovisioning so that it can be canceled, zero deprovisions immediately
+	DeprovisioningGracePeriod time.Duration
 }
 
 func NewProvisioningService(
 	inputConverter InputConverter,
 	graphQLConverter GraphQLConverter,
-	directorService director.DirectorClient,
+	runtimeRegistry director.RuntimeRegistry,
 	factory dbsession.Factory,
 	provisioner Provisioner,
 	generator uuid.UUIDGenerator,
@@ -67,12 +126,22 @@ func NewProvisioningService(
 	provisioningQueue queue.OperationQueue,
 	deprovisioningQueue queue.OperationQueue,
 	shootUpgradeQueue queue.OperationQueue,
-
+	options ServiceOptions,
 ) Service {
+	if options.QuotaManager == nil {
+		options.QuotaManager = quota.NewManager(quota.Config{}, factory)
+	}
+	if options.OrphanScanner == nil {
+		options.OrphanScanner = orphans.NewDisabledScanner()
+	}
+	if options.SeedSelector == nil {
+		options.SeedSelector = gardenerSeedSelector{}
+	}
+
 	return &service{
 		inputConverter:      inputConverter,
 		graphQLConverter:    graphQLConverter,
-		directorService:     directorService,
+		runtimeRegistry:     runtimeRegistry,
 		dbSessionFactory:    factory,
 		provisioner:         provisioner,
 		uuidGenerator:       generator,
@@ -80,16 +149,33 @@ func NewProvisioningService(
 		deprovisioningQueue: deprovisioningQueue,
 		shootUpgradeQueue:   shootUpgradeQueue,
 		shootProvider:       shootProvider,
+		eventSubscriber:     options.EventSubscriber,
+		quotaManager:        options.QuotaManager,
+		orphanScanner:       options.OrphanScanner,
+		seedSelector:        options.SeedSelector,
+
+		credentialsRotationQueue:  options.CredentialsRotationQueue,
+		deprovisioningGracePeriod: options.DeprovisioningGracePeriod,
 	}
 }
 
 func (r *service) ProvisionRuntime(config gqlschema.ProvisionRuntimeInput, tenant, subAccount string) (*gqlschema.OperationStatus, apperrors.AppError) {
 	runtimeInput := config.RuntimeInput
 
+	if config.ClusterConfig != nil && config.ClusterConfig.GardenerConfig != nil {
+		gardenerConfig := config.ClusterConfig.GardenerConfig
+
+		// Rejects the request before the Runtime is registered in Director
+		err := r.quotaManager.CheckProvisioning(tenant, gardenerConfig.Provider, gardenerConfig.AutoScalerMax)
+		if err != nil {
+			return nil, err.Append("Failed to provision Runtime")
+		}
+	}
+
 	var runtimeID string
 
 	err := util.RetryOnError(5*time.Second, 3, "Error while registering runtime in Director: %s", func() (err apperrors.AppError) {
-		runtimeID, err = r.directorService.CreateRuntime(runtimeInput, tenant)
+		runtimeID, err = r.runtimeRegistry.CreateRuntime(runtimeInput, tenant)
 		return
 	})
 
@@ -103,14 +189,36 @@ func (r *service) ProvisionRuntime(config gqlschema.ProvisionRuntimeInput, tenan
 		return nil, err
 	}
 
+	message := "Provisioning started"
+	if cluster.ClusterConfig.Seed == "" {
+		seed, reason, err := r.seedSelector.SelectSeed(cluster.ClusterConfig)
+		if err != nil {
+			r.unregisterFailedRuntime(runtimeID, tenant)
+			return nil, err.Append("Failed to select seed")
+		}
+		if seed != "" {
+			cluster.ClusterConfig.Seed = seed
+			message = fmt.Sprintf("Provisioning started. %s", reason)
+		}
+	}
+
 	dbSession, dberr := r.dbSessionFactory.NewSessionWithinTransaction()
 	if dberr != nil {
 		return nil, dberr
 	}
 	defer dbSession.RollbackUnlessCommitted()
 
+	// The quota is checked again under the tenant lock, so that concurrent requests of the tenant cannot exceed it together
+	if config.ClusterConfig != nil && config.ClusterConfig.GardenerConfig != nil {
+		err = r.checkProvisioningQuota(dbSession, tenant, config.ClusterConfig.GardenerConfig)
+		if err != nil {
+			r.unregisterFailedRuntime(runtimeID, tenant)
+			return nil, err
+		}
+	}
+
 	// Try to set provisioning started before triggering it (which is hard to interrupt) to verify all unique constraints
-	operation, dberr := r.setProvisioningStarted(dbSession, runtimeID, cluster)
+	operation, dberr := r.setProvisioningStarted(dbSession, runtimeID, cluster, message)
 	if dberr != nil {
 		r.unregisterFailedRuntime(runtimeID, tenant)
 		return nil, dberr
@@ -134,10 +242,65 @@ func (r *service) ProvisionRuntime(config gqlschema.ProvisionRuntimeInput, tenan
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
 
+func (r *service) TenantUsage(tenant string) (*gqlschema.TenantUsage, apperrors.AppError) {
+	usage, err := r.quotaManager.GetUsage(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantQuota, err := r.quotaManager.GetQuota(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.graphQLConverter.TenantUsageToGraphQLTenantUsage(usage, tenantQuota), nil
+}
+
+func (r *service) TenantsUsage() ([]*gqlschema.TenantUsage, apperrors.AppError) {
+	usages, err := r.quotaManager.ListUsages()
+	if err != nil {
+		return nil, err
+	}
+
+	tenantsUsage := make([]*gqlschema.TenantUsage, 0, len(usages))
+	for _, usage := range usages {
+		tenantQuota, err := r.quotaManager.GetQuota(usage.Tenant)
+		if err != nil {
+			return nil, err
+		}
+		tenantsUsage = append(tenantsUsage, r.graphQLConverter.TenantUsageToGraphQLTenantUsage(usage, tenantQuota))
+	}
+
+	return tenantsUsage, nil
+}
+
+func (r *service) SetTenantQuota(tenant string, input gqlschema.TenantQuotaInput) (*gqlschema.TenantQuota, apperrors.AppError) {
+	err := r.quotaManager.SetQuota(r.inputConverter.TenantQuotaInputToTenantQuota(tenant, input))
+	if err != nil {
+		return nil, err
+	}
+
+	tenantQuota, err := r.quotaManager.GetQuota(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.graphQLConverter.TenantQuotaToGraphQLTenantQuota(tenantQuota), nil
+}
+
+func (r *service) FindOrphans() (*gqlschema.OrphansReport, apperrors.AppError) {
+	report, err := r.orphanScanner.Scan(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.graphQLConverter.OrphansReportToGraphQLOrphansReport(report), nil
+}
+
 func (r *service) unregisterFailedRuntime(id, tenant string) {
 	log.Infof("Starting provisioning failed. Unregistering Runtime %s...", id)
 	err := util.RetryOnError(10*time.Second, 3, "Error while unregistering runtime in Director: %s", func() (err apperrors.AppError) {
-		err = r.directorService.DeleteRuntime(id, tenant)
+		err = r.runtimeRegistry.DeleteRuntime(id, tenant)
 		return
 	})
 	if err != nil {
@@ -145,7 +308,7 @@ func (r *service) unregisterFailedRuntime(id, tenant string) {
 	}
 }
 
-func (r *service) DeprovisionRuntime(id string) (string, apperrors.AppError) {
+func (r *service) DeprovisionRuntime(id string, force bool) (string, apperrors.AppError) {
 	session := r.dbSessionFactory.NewReadWriteSession()
 
 	appErr := r.verifyLastOperationFinished(session, id)
@@ -158,6 +321,18 @@ func (r *service) DeprovisionRuntime(id string) (string, apperrors.AppError) {
 		return "", dberr
 	}
 
+	if cluster.DeletionProtection {
+		return "", apperrors.BadRequest("Runtime %s has deletion protection enabled, disable it before deprovisioning", id)
+	}
+
+	if isProduction(cluster) && !force {
+		return "", apperrors.BadRequest("Runtime %s is a production Runtime, deprovisioning requires the force flag", id)
+	}
+
+	if r.deprovisioningGracePeriod > 0 {
+		return r.scheduleDeprovisioning(session, cluster)
+	}
+
 	operation, appErr := r.provisioner.DeprovisionCluster(cluster, r.uuidGenerator.New())
 	if appErr != nil {
 		return "", apperrors.Internal("Failed to start deprovisioning: %s", appErr.Error()).SetComponent(appErr.Component()).SetReason(appErr.Reason())
@@ -174,6 +349,69 @@ func (r *service) DeprovisionRuntime(id string) (string, apperrors.AppError) {
 	return operation.ID, nil
 }
 
+func (r *service) scheduleDeprovisioning(session dbsession.WriteSession, cluster model.Cluster) (string, apperrors.AppError) {
+	now := time.Now()
+	operation := model.Operation{
+		ID:             r.uuidGenerator.New(),
+		Type:           model.DeprovisionNoInstall,
+		StartTimestamp: now,
+		State:          model.Pending,
+		Stage:          model.WaitingForDeprovisioningGracePeriod,
+		Message:        fmt.Sprintf("Deprovisioning scheduled at %s, it can be canceled until then", now.Add(r.deprovisioningGracePeriod).UTC().Format(time.RFC3339)),
+		ClusterID:      cluster.ID,
+	}
+
+	dberr := session.InsertOperation(operation)
+	if dberr != nil {
+		return "", dberr
+	}
+
+	log.Infof("Deprovisioning of runtime %s scheduled after grace period of %s", cluster.ID, r.deprovisioningGracePeriod)
+
+	return operation.ID, nil
+}
+
+func (r *service) CancelDeprovisioning(id string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	session := r.dbSessionFactory.NewReadWriteSession()
+
+	operation, dberr := session.GetLastOperation(id)
+	if dberr != nil {
+		return nil, dberr.Append("failed to get last operation")
+	}
+
+	if operation.Type != model.DeprovisionNoInstall || operation.State != model.Pending {
+		return nil, apperrors.BadRequest("Runtime %s has no pending deprovisioning", id)
+	}
+
+	operation.State = model.Canceled
+	operation.Message = "Deprovisioning canceled"
+
+	dberr = session.CancelPendingOperation(operation.ID, operation.Message, time.Now())
+	if dberr != nil {
+		if dberr.Code() == dberrors.CodeNotFound {
+			return nil, apperrors.BadRequest("deprovisioning of Runtime %s has already started", id)
+		}
+		return nil, dberr.Append("failed to cancel deprovisioning")
+	}
+
+	log.Infof("Deprovisioning of runtime %s canceled", id)
+
+	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
+}
+
+func (r *service) SetDeletionProtection(id string, enabled bool) (*gqlschema.RuntimeStatus, apperrors.AppError) {
+	dberr := r.dbSessionFactory.NewWriteSession().UpdateDeletionProtection(id, enabled)
+	if dberr != nil {
+		return nil, dberr.Append("failed to set deletion protection")
+	}
+
+	return r.RuntimeStatus(id)
+}
+
+func isProduction(cluster model.Cluster) bool {
+	return cluster.ClusterConfig.Purpose != nil && *cluster.ClusterConfig.Purpose == productionPurpose
+}
+
 func (r *service) UpgradeGardenerShoot(runtimeID string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, apperrors.AppError) {
 	log.Infof("Starting Upgrade of Gardener Shoot for Runtime '%s'...", runtimeID)
 
@@ -225,12 +463,25 @@ func (r *service) UpgradeGardenerShoot(runtimeID string, input gqlschema.Upgrade
 	if err != nil {
 		return &gqlschema.OperationStatus{}, err.Append("Invalid gardener provider config change")
 	}
+
 	txSession, dbErr := r.dbSessionFactory.NewSessionWithinTransaction()
 	if dbErr != nil {
 		return &gqlschema.OperationStatus{}, apperrors.Internal("Failed to start database transaction: %s", dbErr.Error())
 	}
 	defer txSession.RollbackUnlessCommitted()
 
+	// The quota is checked under the tenant lock, so that concurrent requests of the tenant cannot exceed it together
+	dbErr = txSession.LockTenantQuota(cluster.Tenant)
+	if dbErr != nil {
+		return &gqlschema.OperationStatus{}, apperrors.Internal("Failed to lock tenant quota: %s", dbErr.Error())
+	}
+
+	additionalNodes := gardenerConfig.AutoScalerMax - cluster.ClusterConfig.AutoScalerMax
+	err = r.quotaManager.CheckShootUpgrade(cluster.Tenant, cluster.ClusterConfig.Provider, additionalNodes)
+	if err != nil {
+		return &gqlschema.OperationStatus{}, err.Append("Failed to upgrade Shoot")
+	}
+
 	operation, gardError := r.setGardenerShootUpgradeStarted(txSession, cluster, gardenerConfig, input.Administrators)
 	if gardError != nil {
 		return &gqlschema.OperationStatus{}, apperrors.Internal("Failed to set shoot upgrade started: %s", gardError.Error())
@@ -251,6 +502,230 @@ func (r *service) UpgradeGardenerShoot(runtimeID string, input gqlschema.Upgrade
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
 
+func (r *service) RotateCredentials(runtimeID string, input []gqlschema.CredentialsRotationKind, inputPhase gqlschema.CredentialsRotationPhase) (*gqlschema.OperationStatus, apperrors.AppError) {
+	log.Infof("Starting credentials rotation for Runtime '%s'...", runtimeID)
+
+	kinds := credentialsRotationKindsFromInput(input)
+	phase := credentialsRotationPhaseFromInput(inputPhase)
+
+	if len(kinds) == 0 {
+		return nil, apperrors.BadRequest("at least one kind of credentials has to be rotated")
+	}
+
+	session := r.dbSessionFactory.NewReadSession()
+
+	err := r.verifyLastOperationFinished(session, runtimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, dberr := session.GetCluster(runtimeID)
+	if dberr != nil {
+		return nil, dberr.Append("failed to get cluster")
+	}
+
+	if phase == model.RotationComplete {
+		shoot, err := r.shootProvider.Get(runtimeID, cluster.Tenant)
+		if err != nil {
+			return nil, err.Append("Failed to get shoot")
+		}
+
+		for _, kind := range kinds {
+			if !kind.TwoPhase() {
+				return nil, apperrors.BadRequest("rotation of %s credentials cannot be completed, it is done in a single phase", kind)
+			}
+
+			status := gardener.CredentialsRotationStatus(shoot, kind)
+			if status.Phase != string(gardener_Types.RotationPrepared) {
+				return nil, apperrors.BadRequest("rotation of %s credentials has to be prepared before completion", kind)
+			}
+		}
+	}
+
+	txSession, dbErr := r.dbSessionFactory.NewSessionWithinTransaction()
+	if dbErr != nil {
+		return nil, apperrors.Internal("Failed to start database transaction: %s", dbErr.Error())
+	}
+	defer txSession.RollbackUnlessCommitted()
+
+	operation, dbErr := r.setOperationStarted(txSession, runtimeID, model.RotateCredentials, model.RotatingCredentials, time.Now(), "Starting credentials rotation")
+	if dbErr != nil {
+		return nil, dbErr.Append("Failed to set credentials rotation started")
+	}
+
+	dbErr = txSession.InsertCredentialsRotation(model.CredentialsRotation{
+		OperationID: operation.ID,
+		Kinds:       kinds,
+		Phase:       phase,
+	})
+	if dbErr != nil {
+		return nil, dbErr.Append("Failed to set credentials rotation started")
+	}
+
+	dbErr = txSession.Commit()
+	if dbErr != nil {
+		return nil, apperrors.Internal("Failed to commit credentials rotation transaction: %s", dbErr.Error())
+	}
+
+	r.credentialsRotationQueue.Add(operation.ID)
+
+	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
+}
+
+// BulkUpgradeShoot only records the bulk operation, the child operations are started by the bulk operation scheduler
+func (r *service) BulkUpgradeShoot(inputSelector gqlschema.RuntimeSelectorInput, input gqlschema.UpgradeShootInput, inputStrategy gqlschema.BulkOperationStrategyInput) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	selector := runtimeSelectorFromInput(inputSelector)
+	strategy := bulkOperationStrategyFromInput(inputStrategy)
+
+	if selector.IsEmpty() {
+		return nil, apperrors.BadRequest("at least one Runtime selector criterion is required")
+	}
+	if input.GardenerConfig == nil {
+		return nil, apperrors.BadRequest("Gardener config is required")
+	}
+	if strategy.MaxParallel < 1 {
+		return nil, apperrors.BadRequest("max parallel has to be at least 1")
+	}
+	if strategy.MaxFailureRatio < 0 || strategy.MaxFailureRatio > 1 {
+		return nil, apperrors.BadRequest("max failure ratio has to be between 0 and 1")
+	}
+
+	rawInput, err := json.Marshal(input)
+	if err != nil {
+		return nil, apperrors.Internal("Failed to encode Shoot upgrade input: %s", err.Error())
+	}
+
+	runtimeIDs, dberr := r.dbSessionFactory.NewReadSession().ListRuntimeIDs(selector)
+	if dberr != nil {
+		return nil, dberr.Append("Failed to select Runtimes")
+	}
+	if len(runtimeIDs) == 0 {
+		return nil, apperrors.BadRequest("no Runtimes match the selector")
+	}
+
+	log.Infof("Starting bulk Shoot upgrade of %d Runtimes...", len(runtimeIDs))
+
+	operation := model.BulkOperation{
+		ID:             r.uuidGenerator.New(),
+		Type:           model.UpgradeShoot,
+		State:          model.BulkInProgress,
+		Message:        "Bulk operation in progress",
+		Selector:       selector,
+		Input:          string(rawInput),
+		Strategy:       strategy,
+		StartTimestamp: time.Now(),
+	}
+
+	txSession, dberr := r.dbSessionFactory.NewSessionWithinTransaction()
+	if dberr != nil {
+		return nil, apperrors.Internal("Failed to start database transaction: %s", dberr.Error())
+	}
+	defer txSession.RollbackUnlessCommitted()
+
+	dberr = txSession.InsertBulkOperation(operation)
+	if dberr != nil {
+		return nil, dberr.Append("Failed to set bulk operation started")
+	}
+
+	children := make([]model.BulkOperationChild, 0, len(runtimeIDs))
+	for _, runtimeID := range runtimeIDs {
+		child := model.BulkOperationChild{BulkOperationID: operation.ID, RuntimeID: runtimeID, State: model.Pending}
+
+		dberr = txSession.InsertBulkOperationChild(child)
+		if dberr != nil {
+			return nil, dberr.Append("Failed to set bulk operation started")
+		}
+		children = append(children, child)
+	}
+
+	dberr = txSession.Commit()
+	if dberr != nil {
+		return nil, apperrors.Internal("Failed to commit bulk operation transaction: %s", dberr.Error())
+	}
+
+	return r.graphQLConverter.BulkOperationToGraphQLStatus(operation, children), nil
+}
+
+func (r *service) BulkOperationStatus(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	session := r.dbSessionFactory.NewReadSession()
+
+	operation, dberr := session.GetBulkOperation(id)
+	if dberr != nil {
+		return nil, dberr.Append("failed to get bulk operation status")
+	}
+
+	children, dberr := session.ListBulkOperationChildren(id)
+	if dberr != nil {
+		return nil, dberr.Append("failed to get bulk operation status")
+	}
+
+	return r.graphQLConverter.BulkOperationToGraphQLStatus(operation, children), nil
+}
+
+func (r *service) PauseBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	return r.changeBulkOperationState(id, model.BulkInProgress, model.BulkPaused, "Bulk operation paused")
+}
+
+func (r *service) ResumeBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	return r.changeBulkOperationState(id, model.BulkPaused, model.BulkInProgress, "Bulk operation in progress")
+}
+
+func (r *service) changeBulkOperationState(id string, from, to model.BulkOperationState, message string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	session := r.dbSessionFactory.NewReadWriteSession()
+
+	operation, dberr := session.GetBulkOperation(id)
+	if dberr != nil {
+		return nil, dberr.Append("failed to get bulk operation")
+	}
+
+	if operation.State != from {
+		return nil, apperrors.BadRequest("bulk operation %s is %s, expected %s", id, operation.State, from)
+	}
+
+	dberr = session.UpdateBulkOperationState(id, to, message, nil)
+	if dberr != nil {
+		return nil, dberr.Append("failed to update bulk operation state")
+	}
+
+	return r.BulkOperationStatus(id)
+}
+
+func (r *service) CancelBulkOperation(id string) (*gqlschema.BulkOperationStatus, apperrors.AppError) {
+	readSession := r.dbSessionFactory.NewReadSession()
+
+	operation, dberr := readSession.GetBulkOperation(id)
+	if dberr != nil {
+		return nil, dberr.Append("failed to get bulk operation")
+	}
+
+	if operation.State != model.BulkInProgress && operation.State != model.BulkPaused {
+		return nil, apperrors.BadRequest("bulk operation %s is %s and cannot be canceled", id, operation.State)
+	}
+
+	txSession, dberr := r.dbSessionFactory.NewSessionWithinTransaction()
+	if dberr != nil {
+		return nil, apperrors.Internal("Failed to start database transaction: %s", dberr.Error())
+	}
+	defer txSession.RollbackUnlessCommitted()
+
+	dberr = txSession.UpdateBulkOperationState(id, model.BulkCanceled, "Bulk operation canceled", nil)
+	if dberr != nil {
+		return nil, dberr.Append("failed to cancel bulk operation")
+	}
+
+	dberr = txSession.CancelPendingBulkOperationChildren(id, "Canceled with the bulk operation")
+	if dberr != nil {
+		return nil, dberr.Append("failed to cancel bulk operation")
+	}
+
+	dberr = txSession.Commit()
+	if dberr != nil {
+		return nil, apperrors.Internal("Failed to commit bulk operation transaction: %s", dberr.Error())
+	}
+
+	return r.BulkOperationStatus(id)
+}
+
 func (r *service) verifyLastOperationFinished(session dbsession.ReadSession, runtimeId string) apperrors.AppError {
 	lastOperation, dberr := session.GetLastOperation(runtimeId)
 	if dberr != nil {
@@ -261,6 +736,10 @@ func (r *service) verifyLastOperationFinished(session dbsession.ReadSession, run
 		return apperrors.BadRequest("cannot start new operation for %s Runtime while previous one is in progress", runtimeId)
 	}
 
+	if lastOperation.State == model.Pending {
+		return apperrors.BadRequest("cannot start new operation for %s Runtime while deprovisioning is pending", runtimeId)
+	}
+
 	return nil
 }
 
@@ -288,6 +767,91 @@ func (r *service) RuntimeOperationStatus(operationID string) (*gqlschema.Operati
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
 
+// RuntimeOperationDiagnostics returns nil when no stage of the operation failed yet
+func (r *service) RuntimeOperationDiagnostics(operationID string) (*gqlschema.OperationDiagnostics, apperrors.AppError) {
+	readSession := r.dbSessionFactory.NewReadSession()
+
+	operation, dberr := readSession.GetOperation(operationID)
+	if dberr != nil {
+		return nil, dberr.Append("failed to get Runtime Operation Diagnostics")
+	}
+
+	diagnostics, dberr := readSession.GetOperationDiagnostics(operationID)
+	if dberr != nil {
+		if dberr.Code() == dberrors.CodeNotFound {
+			return nil, nil
+		}
+		return nil, dberr.Append("failed to get Runtime Operation Diagnostics")
+	}
+
+	return r.graphQLConverter.OperationDiagnosticsToGraphQLDiagnostics(operation.ClusterID, diagnostics), nil
+}
+
+func (r *service) SubscribeOperationStatus(ctx context.Context, operationID string) (<-chan *gqlschema.OperationStatus, apperrors.AppError) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Subscribe before reading the operation so that no transition is missed in between
+	operationEvents := r.eventSubscriber.SubscribeOperation(ctx, operationID)
+
+	operation, dberr := r.dbSessionFactory.NewReadSession().GetOperation(operationID)
+	if dberr != nil {
+		cancel()
+		return nil, dberr.Append("failed to subscribe to Runtime Operation Status")
+	}
+
+	statuses := make(chan *gqlschema.OperationStatus, 1)
+	statuses <- r.graphQLConverter.OperationStatusToGQLOperationStatus(operation)
+
+	if operation.State != model.InProgress {
+		cancel()
+		close(statuses)
+		return statuses, nil
+	}
+
+	go func() {
+		defer cancel()
+		defer close(statuses)
+
+		for event := range operationEvents {
+			select {
+			case statuses <- r.graphQLConverter.OperationStatusToGQLOperationStatus(event.Operation):
+			case <-ctx.Done():
+				return
+			}
+
+			if event.Operation.State != model.InProgress {
+				return
+			}
+		}
+	}()
+
+	return statuses, nil
+}
+
+func (r *service) SubscribeRuntimeEvents(ctx context.Context, runtimeID string) (<-chan *gqlschema.RuntimeEvent, apperrors.AppError) {
+	_, dberr := r.dbSessionFactory.NewReadSession().GetTenant(runtimeID)
+	if dberr != nil {
+		return nil, dberr.Append("failed to subscribe to Runtime events")
+	}
+
+	operationEvents := r.eventSubscriber.SubscribeRuntime(ctx, runtimeID)
+	runtimeEvents := make(chan *gqlschema.RuntimeEvent, 1)
+
+	go func() {
+		defer close(runtimeEvents)
+
+		for event := range operationEvents {
+			select {
+			case runtimeEvents <- r.graphQLConverter.OperationEventToGQLRuntimeEvent(event):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return runtimeEvents, nil
+}
+
 func (r *service) getRuntimeStatus(runtimeID string) (model.RuntimeStatus, apperrors.AppError) {
 	session := r.dbSessionFactory.NewReadSession()
 
@@ -301,13 +865,34 @@ func (r *service) getRuntimeStatus(runtimeID string) (model.RuntimeStatus, apper
 		return model.RuntimeStatus{}, err
 	}
 
+	credentialsRotation, err := session.GetCredentialsRotationStatus(runtimeID)
+	if err != nil {
+		return model.RuntimeStatus{}, err
+	}
+
 	return model.RuntimeStatus{
 		LastOperationStatus:  operation,
 		RuntimeConfiguration: cluster,
+		CredentialsRotation:  credentialsRotation,
 	}, nil
 }
 
-func (r *service) setProvisioningStarted(dbSession dbsession.WriteSession, runtimeID string, cluster model.Cluster) (model.Operation, dberrors.Error) {
+// checkProvisioningQuota locks the quota of the tenant until the transaction ends and checks it
+func (r *service) checkProvisioningQuota(dbSession dbsession.WriteSessionWithinTransaction, tenant string, gardenerConfig *gqlschema.GardenerConfigInput) apperrors.AppError {
+	dberr := dbSession.LockTenantQuota(tenant)
+	if dberr != nil {
+		return dberr.Append("Failed to lock tenant quota")
+	}
+
+	err := r.quotaManager.CheckProvisioning(tenant, gardenerConfig.Provider, gardenerConfig.AutoScalerMax)
+	if err != nil {
+		return err.Append("Failed to provision Runtime")
+	}
+
+	return nil
+}
+
+func (r *service) setProvisioningStarted(dbSession dbsession.WriteSession, runtimeID string, cluster model.Cluster, message string) (model.Operation, dberrors.Error) {
 	timestamp := time.Now()
 	cluster.CreationTimestamp = timestamp
 
@@ -321,7 +906,7 @@ func (r *service) setProvisioningStarted(dbSession dbsession.WriteSession, runti
 
 	provisioningMode := model.Provision
 
-	operation, err := r.setOperationStarted(dbSession, runtimeID, provisioningMode, model.WaitingForClusterDomain, timestamp, "Provisioning started")
+	operation, err := r.setOperationStarted(dbSession, runtimeID, provisioningMode, model.WaitingForClusterDomain, timestamp, message)
 	if err != nil {
 		return model.Operation{}, err.Append("Failed to set provisioning started: %s")
 	}
@@ -348,6 +933,11 @@ func (r *service) setGardenerShootUpgradeStarted(txSession dbsession.WriteSessio
 		return model.Operation{}, dbError.Append("Failed to start operation of Gardener Shoot upgrade %s", dbError.Error())
 	}
 
+	dberr = txSession.InsertPreUpgradeGardenerConfig(operation.ID, currentCluster.ClusterConfig)
+	if dberr != nil {
+		return model.Operation{}, dberrors.Internal("Failed to set Shoot Upgrade started: %s", dberr.Error())
+	}
+
 	return operation, nil
 }
 
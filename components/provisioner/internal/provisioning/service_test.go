@@ -1,6 +1,8 @@
 package provisioning
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -12,11 +14,13 @@ import (
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
 	directormock "github.com/kyma-project/control-plane/components/provisioner/internal/director/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/events"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/mocks"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
 	mocks2 "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/mocks"
 	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	quotaMocks "github.com/kyma-project/control-plane/components/provisioner/internal/quota/mocks"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
 	uuidMocks "github.com/kyma-project/control-plane/components/provisioner/internal/uuid/mocks"
@@ -54,6 +58,11 @@ users:
 )
 
 func TestService_ProvisionRuntime(t *testing.T) {
+	quotaManager := &quotaMocks.Manager{}
+	quotaManager.On("CheckProvisioning", tenant, mock.Anything, mock.Anything).Return(nil)
+	seedSelector := &mocks2.SeedSelector{}
+	seedSelector.On("SelectSeed", mock.Anything).Return("", "", nil)
+
 	inputConverter := NewInputConverter(uuid.NewUUIDGenerator(), gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
 	graphQLConverter := NewGraphQLConverter()
 	uuidGenerator := uuid.NewUUIDGenerator()
@@ -111,6 +120,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 
 		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return(runtimeID, nil)
 		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("LockTenantQuota", tenant).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertCluster", mock.MatchedBy(clusterMatcher)).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertGardenerConfig", mock.AnythingOfType("model.GardenerConfig")).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
@@ -120,7 +130,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 
 		provisioningQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, provisioningQueue, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, provisioningQueue, nil, nil, ServiceOptions{QuotaManager: quotaManager, SeedSelector: seedSelector})
 
 		// when
 		operationStatus, err := service.ProvisionRuntime(provisionRuntimeInputNoKymaConfig, tenant, subAccountId)
@@ -136,6 +146,85 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		provisioner.AssertExpectations(t)
 	})
 
+	t.Run("Should provision Runtime without optional dependencies", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSessionWithinTransactionMock := &sessionMocks.WriteSessionWithinTransaction{}
+		directorServiceMock := &directormock.DirectorClient{}
+		provisioner := &mocks2.Provisioner{}
+		provisioningQueue := &mocks.OperationQueue{}
+
+		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return(runtimeID, nil)
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetTenantQuota", tenant).Return(model.TenantQuota{}, dberrors.NotFound("quota not found"))
+		readSession.On("GetTenantUsage", tenant).Return(model.TenantUsage{Tenant: tenant}, nil)
+		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("LockTenantQuota", tenant).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertCluster", mock.MatchedBy(clusterMatcher)).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertGardenerConfig", mock.MatchedBy(func(config model.GardenerConfig) bool {
+			return config.Seed == ""
+		})).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
+		writeSessionWithinTransactionMock.On("Commit").Return(nil)
+		writeSessionWithinTransactionMock.On("RollbackUnlessCommitted").Return()
+		provisioner.On("ProvisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(nil)
+		provisioningQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, provisioningQueue, nil, nil, ServiceOptions{})
+
+		// when
+		operationStatus, err := service.ProvisionRuntime(provisionRuntimeInputNoKymaConfig, tenant, subAccountId)
+		require.NoError(t, err)
+
+		_, orphansErr := service.FindOrphans()
+
+		// then
+		assert.Equal(t, runtimeID, *operationStatus.RuntimeID)
+		require.Error(t, orphansErr)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+		writeSessionWithinTransactionMock.AssertExpectations(t)
+		provisioner.AssertExpectations(t)
+	})
+
+	t.Run("Should select seed and record the reason in operation message", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		writeSessionWithinTransactionMock := &sessionMocks.WriteSessionWithinTransaction{}
+		directorServiceMock := &directormock.DirectorClient{}
+		provisioner := &mocks2.Provisioner{}
+		provisioningQueue := &mocks.OperationQueue{}
+
+		selectingSeedSelector := &mocks2.SeedSelector{}
+		selectingSeedSelector.On("SelectSeed", mock.Anything).Return("gcp-eu1", "Seed gcp-eu1 selected by preference out of 2 eligible seeds", nil)
+
+		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return(runtimeID, nil)
+		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("LockTenantQuota", tenant).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertCluster", mock.MatchedBy(clusterMatcher)).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertGardenerConfig", mock.MatchedBy(func(config model.GardenerConfig) bool {
+			return config.Seed == "gcp-eu1"
+		})).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertOperation", mock.MatchedBy(func(operation model.Operation) bool {
+			return operation.Message == "Provisioning started. Seed gcp-eu1 selected by preference out of 2 eligible seeds"
+		})).Return(nil)
+		writeSessionWithinTransactionMock.On("Commit").Return(nil)
+		writeSessionWithinTransactionMock.On("RollbackUnlessCommitted").Return()
+		provisioner.On("ProvisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(nil)
+		provisioningQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, provisioningQueue, nil, nil, ServiceOptions{QuotaManager: quotaManager, SeedSelector: selectingSeedSelector})
+
+		// when
+		_, err := service.ProvisionRuntime(provisionRuntimeInputNoKymaConfig, tenant, subAccountId)
+
+		// then
+		require.NoError(t, err)
+		writeSessionWithinTransactionMock.AssertExpectations(t)
+		provisioner.AssertExpectations(t)
+	})
+
 	t.Run("Should return error and unregister Runtime when failed to commit transaction", func(t *testing.T) {
 		// given
 		sessionFactoryMock := &sessionMocks.Factory{}
@@ -146,6 +235,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		expectErr := dberrors.Internal("Failed to commit transaction: error")
 		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return(runtimeID, nil)
 		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("LockTenantQuota", tenant).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertCluster", mock.MatchedBy(clusterMatcher)).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertGardenerConfig", mock.AnythingOfType("model.GardenerConfig")).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
@@ -154,7 +244,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		provisioner.On("ProvisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(nil)
 		directorServiceMock.On("DeleteRuntime", runtimeID, tenant).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil, ServiceOptions{QuotaManager: quotaManager, SeedSelector: seedSelector})
 
 		// when
 		_, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -178,6 +268,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 
 		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return(runtimeID, nil)
 		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("LockTenantQuota", tenant).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertCluster", mock.MatchedBy(clusterMatcher)).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertGardenerConfig", mock.AnythingOfType("model.GardenerConfig")).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
@@ -185,7 +276,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		provisioner.On("ProvisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(apperrors.Internal("error"))
 		directorServiceMock.On("DeleteRuntime", runtimeID, tenant).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil, ServiceOptions{QuotaManager: quotaManager, SeedSelector: seedSelector})
 
 		// when
 		_, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -200,13 +291,42 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		provisioner.AssertExpectations(t)
 	})
 
+	t.Run("Should return error and unregister Runtime when quota is exceeded by a concurrent request", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		writeSessionWithinTransactionMock := &sessionMocks.WriteSessionWithinTransaction{}
+		directorServiceMock := &directormock.DirectorClient{}
+		exceededQuotaManager := &quotaMocks.Manager{}
+
+		exceededQuotaManager.On("CheckProvisioning", tenant, mock.Anything, mock.Anything).Once().Return(nil)
+		exceededQuotaManager.On("CheckProvisioning", tenant, mock.Anything, mock.Anything).Once().Return(apperrors.Forbidden("runtimes quota exceeded"))
+		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return(runtimeID, nil)
+		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("LockTenantQuota", tenant).Return(nil)
+		writeSessionWithinTransactionMock.On("RollbackUnlessCommitted").Return()
+		directorServiceMock.On("DeleteRuntime", runtimeID, tenant).Return(nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{QuotaManager: exceededQuotaManager, SeedSelector: seedSelector})
+
+		// when
+		_, err := service.ProvisionRuntime(provisionRuntimeInputNoKymaConfig, tenant, subAccountId)
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+
+		// then
+		sessionFactoryMock.AssertExpectations(t)
+		writeSessionWithinTransactionMock.AssertExpectations(t)
+		directorServiceMock.AssertExpectations(t)
+		exceededQuotaManager.AssertExpectations(t)
+	})
+
 	t.Run("Should return error when failed to register Runtime", func(t *testing.T) {
 		// given
 		directorServiceMock := &directormock.DirectorClient{}
 
 		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return("", apperrors.Internal("registering error"))
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, nil, nil, uuidGenerator, nil, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, nil, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{QuotaManager: quotaManager, SeedSelector: seedSelector})
 
 		// when
 		_, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -231,6 +351,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Once().Return("", apperrors.Internal("registering error"))
 		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Once().Return(runtimeID, nil)
 		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("LockTenantQuota", tenant).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertCluster", mock.MatchedBy(clusterMatcher)).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertGardenerConfig", mock.AnythingOfType("model.GardenerConfig")).Return(nil)
 		writeSessionWithinTransactionMock.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
@@ -240,7 +361,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 
 		provisioningQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, provisioningQueue, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, provisioningQueue, nil, nil, ServiceOptions{QuotaManager: quotaManager, SeedSelector: seedSelector})
 
 		// when
 		operationStatus, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -299,10 +420,10 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		provisioner.On("DeprovisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(operation, nil)
 		readWriteSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, deprovisioningQueue, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, deprovisioningQueue, nil, ServiceOptions{})
 
 		// when
-		opID, err := resolver.DeprovisionRuntime(runtimeID)
+		opID, err := resolver.DeprovisionRuntime(runtimeID, false)
 		require.NoError(t, err)
 
 		// then
@@ -345,10 +466,10 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		provisioner.On("DeprovisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(operation, nil)
 		readWriteSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, deprovisioningQueue, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, deprovisioningQueue, nil, ServiceOptions{})
 
 		// when
-		opID, err := resolver.DeprovisionRuntime(runtimeID)
+		opID, err := resolver.DeprovisionRuntime(runtimeID, false)
 		require.NoError(t, err)
 
 		// then
@@ -370,10 +491,10 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		readWriteSession.On("GetCluster", runtimeID).Return(cluster, nil)
 		provisioner.On("DeprovisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(model.Operation{}, apperrors.Internal("some error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, nil, nil, ServiceOptions{})
 
 		// when
-		_, err := resolver.DeprovisionRuntime(runtimeID)
+		_, err := resolver.DeprovisionRuntime(runtimeID, false)
 		require.Error(t, err)
 		util.CheckErrorType(t, err, apperrors.CodeInternal)
 
@@ -395,10 +516,10 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		readWriteSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
 		readWriteSession.On("GetCluster", runtimeID).Return(model.Cluster{}, dberrors.Internal("some error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, ServiceOptions{})
 
 		// when
-		_, err := resolver.DeprovisionRuntime(runtimeID)
+		_, err := resolver.DeprovisionRuntime(runtimeID, false)
 		require.Error(t, err)
 
 		// then
@@ -419,10 +540,10 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
 		readWriteSession.On("GetLastOperation", runtimeID).Return(operation, nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, ServiceOptions{})
 
 		// when
-		_, err := resolver.DeprovisionRuntime(runtimeID)
+		_, err := resolver.DeprovisionRuntime(runtimeID, false)
 		require.Error(t, err)
 
 		// then
@@ -442,10 +563,10 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
 		readWriteSession.On("GetLastOperation", runtimeID).Return(model.Operation{}, dberrors.Internal("some error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, ServiceOptions{})
 
 		// when
-		_, err := resolver.DeprovisionRuntime(runtimeID)
+		_, err := resolver.DeprovisionRuntime(runtimeID, false)
 		require.Error(t, err)
 
 		// then
@@ -455,6 +576,182 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		sessionFactoryMock.AssertExpectations(t)
 		readWriteSession.AssertExpectations(t)
 	})
+
+	t.Run("Should return error when Runtime has deletion protection enabled", func(t *testing.T) {
+		// given
+		protectedCluster := cluster
+		protectedCluster.DeletionProtection = true
+
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+
+		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
+		readWriteSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readWriteSession.On("GetCluster", runtimeID).Return(protectedCluster, nil)
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := resolver.DeprovisionRuntime(runtimeID, true)
+		require.Error(t, err)
+
+		// then
+		assert.Contains(t, err.Error(), "deletion protection enabled")
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+		sessionFactoryMock.AssertExpectations(t)
+		readWriteSession.AssertExpectations(t)
+	})
+
+	t.Run("Should require force flag to deprovision production Runtime", func(t *testing.T) {
+		// given
+		productionCluster := cluster
+		productionCluster.ClusterConfig.Purpose = util.StringPtr("production")
+
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+
+		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
+		readWriteSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readWriteSession.On("GetCluster", runtimeID).Return(productionCluster, nil)
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := resolver.DeprovisionRuntime(runtimeID, false)
+		require.Error(t, err)
+
+		// then
+		assert.Contains(t, err.Error(), "requires the force flag")
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+		sessionFactoryMock.AssertExpectations(t)
+		readWriteSession.AssertExpectations(t)
+	})
+
+	t.Run("Should schedule pending deprovisioning when grace period is configured", func(t *testing.T) {
+		// given
+		productionCluster := cluster
+		productionCluster.ClusterConfig.Purpose = util.StringPtr("production")
+
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+		provisioner := &mocks2.Provisioner{}
+		deprovisioningQueue := &mocks.OperationQueue{}
+
+		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
+		readWriteSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readWriteSession.On("GetCluster", runtimeID).Return(productionCluster, nil)
+		readWriteSession.On("InsertOperation", mock.MatchedBy(func(operation model.Operation) bool {
+			return operation.State == model.Pending &&
+				operation.Type == model.DeprovisionNoInstall &&
+				operation.Stage == model.WaitingForDeprovisioningGracePeriod &&
+				operation.ClusterID == runtimeID
+		})).Return(nil)
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, deprovisioningQueue, nil, ServiceOptions{DeprovisioningGracePeriod: time.Hour})
+
+		// when
+		opID, err := resolver.DeprovisionRuntime(runtimeID, true)
+		require.NoError(t, err)
+
+		// then
+		assert.NotEmpty(t, opID)
+		sessionFactoryMock.AssertExpectations(t)
+		readWriteSession.AssertExpectations(t)
+		provisioner.AssertNotCalled(t, "DeprovisionCluster", mock.Anything, mock.Anything)
+		deprovisioningQueue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+
+	t.Run("Should return error while deprovisioning when deprovisioning is pending", func(t *testing.T) {
+		// given
+		operation := model.Operation{State: model.Pending}
+
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+
+		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
+		readWriteSession.On("GetLastOperation", runtimeID).Return(operation, nil)
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, ServiceOptions{DeprovisioningGracePeriod: time.Hour})
+
+		// when
+		_, err := resolver.DeprovisionRuntime(runtimeID, false)
+		require.Error(t, err)
+
+		// then
+		assert.Contains(t, err.Error(), "deprovisioning is pending")
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+	})
+}
+
+func TestService_CancelDeprovisioning(t *testing.T) {
+	graphQLConverter := NewGraphQLConverter()
+	pendingOperation := model.Operation{
+		ID:        operationID,
+		Type:      model.DeprovisionNoInstall,
+		State:     model.Pending,
+		Stage:     model.WaitingForDeprovisioningGracePeriod,
+		ClusterID: runtimeID,
+	}
+
+	t.Run("Should cancel pending deprovisioning", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+
+		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
+		readWriteSession.On("GetLastOperation", runtimeID).Return(pendingOperation, nil)
+		readWriteSession.On("CancelPendingOperation", operationID, "Deprovisioning canceled", mock.AnythingOfType("time.Time")).Return(nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactoryMock, nil, nil, nil, nil, nil, nil, ServiceOptions{DeprovisioningGracePeriod: time.Hour})
+
+		// when
+		status, err := service.CancelDeprovisioning(runtimeID)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, gqlschema.OperationStateCanceled, status.State)
+		assert.Equal(t, operationID, *status.ID)
+		readWriteSession.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when deprovisioning is not pending", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+
+		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
+		readWriteSession.On("GetLastOperation", runtimeID).Return(model.Operation{ID: operationID, Type: model.DeprovisionNoInstall, State: model.InProgress}, nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactoryMock, nil, nil, nil, nil, nil, nil, ServiceOptions{DeprovisioningGracePeriod: time.Hour})
+
+		// when
+		_, err := service.CancelDeprovisioning(runtimeID)
+		require.Error(t, err)
+
+		// then
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+		readWriteSession.AssertNotCalled(t, "CancelPendingOperation", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return error when deprovisioning started in the meantime", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readWriteSession := &sessionMocks.ReadWriteSession{}
+
+		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
+		readWriteSession.On("GetLastOperation", runtimeID).Return(pendingOperation, nil)
+		readWriteSession.On("CancelPendingOperation", operationID, "Deprovisioning canceled", mock.AnythingOfType("time.Time")).Return(dberrors.NotFound("not found"))
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactoryMock, nil, nil, nil, nil, nil, nil, ServiceOptions{DeprovisioningGracePeriod: time.Hour})
+
+		// when
+		_, err := service.CancelDeprovisioning(runtimeID)
+		require.Error(t, err)
+
+		// then
+		assert.Contains(t, err.Error(), "already started")
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+	})
 }
 
 func TestService_RuntimeOperationStatus(t *testing.T) {
@@ -478,7 +775,7 @@ func TestService_RuntimeOperationStatus(t *testing.T) {
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
 		readSession.On("GetOperation", operationID).Return(operation, nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
 
 		// when
 		status, err := resolver.RuntimeOperationStatus(operationID)
@@ -501,7 +798,7 @@ func TestService_RuntimeOperationStatus(t *testing.T) {
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
 		readSession.On("GetOperation", operationID).Return(model.Operation{}, dberrors.Internal("error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
 
 		// when
 		_, err := resolver.RuntimeOperationStatus(operationID)
@@ -513,7 +810,7 @@ func TestService_RuntimeOperationStatus(t *testing.T) {
 	})
 }
 
-func TestService_RuntimeStatus(t *testing.T) {
+func TestService_SubscribeOperationStatus(t *testing.T) {
 	uuidGenerator := &uuidMocks.UUIDGenerator{}
 	inputConverter := NewInputConverter(uuidGenerator, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
 	graphQLConverter := NewGraphQLConverter()
@@ -521,86 +818,418 @@ func TestService_RuntimeStatus(t *testing.T) {
 	operation := model.Operation{
 		ID:        operationID,
 		Type:      model.Provision,
-		State:     model.Succeeded,
-		Message:   "Message",
+		State:     model.InProgress,
+		Stage:     model.WaitingForClusterCreation,
 		ClusterID: runtimeID,
 	}
 
-	cluster := model.Cluster{
-		ID:         runtimeID,
-		Kubeconfig: util.StringPtr("kubeconfig"),
-	}
-
-	t.Run("Should return runtime status", func(t *testing.T) {
+	t.Run("Should stream statuses of the operation until it finishes", func(t *testing.T) {
 		// given
 		sessionFactoryMock := &sessionMocks.Factory{}
 		readSession := &sessionMocks.ReadSession{}
 
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
-		readSession.On("GetLastOperation", operationID).Return(operation, nil)
-		readSession.On("GetCluster", operationID).Return(cluster, nil)
+		readSession.On("GetOperation", operationID).Return(operation, nil)
 
-		provisioner := &mocks2.Provisioner{}
+		broker := events.NewBroker()
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{EventSubscriber: broker})
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
 
 		// when
-		status, err := resolver.RuntimeStatus(operationID)
+		statuses, err := resolver.SubscribeOperationStatus(ctx, operationID)
+		require.NoError(t, err)
+
+		otherOperation := operation
+		otherOperation.ID = "other-operation"
+		broker.Publish(events.OperationEvent{Operation: otherOperation})
+
+		nextStage := operation
+		nextStage.Stage = model.WaitingForClusterDomain
+		broker.Publish(events.OperationEvent{Operation: nextStage})
+
+		succeeded := operation
+		succeeded.State = model.Succeeded
+		broker.Publish(events.OperationEvent{Operation: succeeded})
 
 		// then
-		require.NoError(t, err)
-		assert.Equal(t, cluster.ID, *status.LastOperationStatus.RuntimeID)
-		assert.Equal(t, cluster.Kubeconfig, status.RuntimeConfiguration.Kubeconfig)
+		assert.Equal(t, gqlschema.OperationStateInProgress, receiveStatus(t, statuses).State)
+		assert.Equal(t, gqlschema.OperationStateInProgress, receiveStatus(t, statuses).State)
+		assert.Equal(t, gqlschema.OperationStateSucceeded, receiveStatus(t, statuses).State)
+		assertStatusesClosed(t, statuses)
 		sessionFactoryMock.AssertExpectations(t)
 		readSession.AssertExpectations(t)
 	})
 
-	t.Run("Should return error when failed to get cluster", func(t *testing.T) {
+	t.Run("Should return status of finished operation and close the stream", func(t *testing.T) {
 		// given
 		sessionFactoryMock := &sessionMocks.Factory{}
 		readSession := &sessionMocks.ReadSession{}
 
+		failed := operation
+		failed.State = model.Failed
+
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
-		readSession.On("GetLastOperation", operationID).Return(operation, nil)
-		readSession.On("GetCluster", operationID).Return(model.Cluster{}, dberrors.Internal("error"))
+		readSession.On("GetOperation", operationID).Return(failed, nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{EventSubscriber: events.NewBroker()})
 
 		// when
-		_, err := resolver.RuntimeStatus(operationID)
+		statuses, err := resolver.SubscribeOperationStatus(context.Background(), operationID)
 
 		// then
-		require.Error(t, err)
-		sessionFactoryMock.AssertExpectations(t)
-		readSession.AssertExpectations(t)
+		require.NoError(t, err)
+		assert.Equal(t, gqlschema.OperationStateFailed, receiveStatus(t, statuses).State)
+		assertStatusesClosed(t, statuses)
 	})
 
-	t.Run("Should return error when failed to get operation status", func(t *testing.T) {
+	t.Run("Should close the stream when the context is cancelled", func(t *testing.T) {
 		// given
 		sessionFactoryMock := &sessionMocks.Factory{}
 		readSession := &sessionMocks.ReadSession{}
 
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
-		readSession.On("GetLastOperation", operationID).Return(model.Operation{}, dberrors.Internal("error"))
+		readSession.On("GetOperation", operationID).Return(operation, nil)
+
+		broker := events.NewBroker()
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{EventSubscriber: broker})
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		statuses, err := resolver.SubscribeOperationStatus(ctx, operationID)
+		require.NoError(t, err)
+		receiveStatus(t, statuses)
 
 		// when
-		_, err := resolver.RuntimeStatus(operationID)
+		cancel()
+
+		// then
+		assertStatusesClosed(t, statuses)
+	})
+
+	t.Run("Should return error when failed to get operation", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetOperation", operationID).Return(model.Operation{}, dberrors.NotFound("error"))
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{EventSubscriber: events.NewBroker()})
+
+		// when
+		_, err := resolver.SubscribeOperationStatus(context.Background(), operationID)
 
 		// then
 		require.Error(t, err)
-		sessionFactoryMock.AssertExpectations(t)
-		readSession.AssertExpectations(t)
 	})
 }
 
-func TestService_UpgradeGardenerShoot(t *testing.T) {
-	inputConverter := NewInputConverter(uuid.NewUUIDGenerator(), gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
+func TestService_SubscribeRuntimeEvents(t *testing.T) {
+	uuidGenerator := &uuidMocks.UUIDGenerator{}
+	inputConverter := NewInputConverter(uuidGenerator, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
 	graphQLConverter := NewGraphQLConverter()
-	uuidGenerator := uuid.NewUUIDGenerator()
-
-	lastOperation := model.Operation{State: model.Succeeded}
+
+	operation := model.Operation{
+		ID:        operationID,
+		Type:      model.Deprovision,
+		State:     model.InProgress,
+		Stage:     model.DeleteCluster,
+		ClusterID: runtimeID,
+	}
+
+	t.Run("Should stream events of the Runtime only", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetTenant", runtimeID).Return(tenant, nil)
+
+		broker := events.NewBroker()
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{EventSubscriber: broker})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// when
+		runtimeEvents, err := resolver.SubscribeRuntimeEvents(ctx, runtimeID)
+		require.NoError(t, err)
+
+		otherRuntime := operation
+		otherRuntime.ID = "other-operation"
+		otherRuntime.ClusterID = "other-runtime"
+		broker.Publish(events.OperationEvent{Operation: otherRuntime})
+		broker.Publish(events.OperationEvent{Operation: operation})
+
+		// then
+		event := receiveRuntimeEvent(t, runtimeEvents)
+		assert.Equal(t, runtimeID, event.RuntimeID)
+		assert.Equal(t, operationID, event.OperationID)
+		assert.Equal(t, gqlschema.OperationTypeDeprovision, event.Operation)
+		assert.Equal(t, string(model.DeleteCluster), event.Stage)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("Should close the stream when the context is cancelled", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetTenant", runtimeID).Return(tenant, nil)
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{EventSubscriber: events.NewBroker()})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		runtimeEvents, err := resolver.SubscribeRuntimeEvents(ctx, runtimeID)
+		require.NoError(t, err)
+
+		// when
+		cancel()
+
+		// then
+		select {
+		case _, ok := <-runtimeEvents:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("Runtime events stream was not closed")
+		}
+	})
+
+	t.Run("Should return error when Runtime does not exist", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetTenant", runtimeID).Return("", dberrors.NotFound("error"))
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{EventSubscriber: events.NewBroker()})
+
+		// when
+		_, err := resolver.SubscribeRuntimeEvents(context.Background(), runtimeID)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func receiveStatus(t *testing.T, statuses <-chan *gqlschema.OperationStatus) *gqlschema.OperationStatus {
+	select {
+	case status, ok := <-statuses:
+		require.True(t, ok, "Operation status stream was closed")
+		return status
+	case <-time.After(time.Second):
+		t.Fatal("Operation status was not received")
+		return nil
+	}
+}
+
+func assertStatusesClosed(t *testing.T, statuses <-chan *gqlschema.OperationStatus) {
+	select {
+	case _, ok := <-statuses:
+		assert.False(t, ok, "Operation status stream was not closed")
+	case <-time.After(time.Second):
+		t.Fatal("Operation status stream was not closed")
+	}
+}
+
+func receiveRuntimeEvent(t *testing.T, runtimeEvents <-chan *gqlschema.RuntimeEvent) *gqlschema.RuntimeEvent {
+	select {
+	case event, ok := <-runtimeEvents:
+		require.True(t, ok, "Runtime events stream was closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Runtime event was not received")
+		return nil
+	}
+}
+
+func TestService_RuntimeOperationDiagnostics(t *testing.T) {
+	uuidGenerator := &uuidMocks.UUIDGenerator{}
+	inputConverter := NewInputConverter(uuidGenerator, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
+	graphQLConverter := NewGraphQLConverter()
+
+	operation := model.Operation{
+		ID:        operationID,
+		Type:      model.Provision,
+		State:     model.InProgress,
+		ClusterID: runtimeID,
+	}
+
+	t.Run("Should return operation diagnostics", func(t *testing.T) {
+		// given
+		diagnostics := model.OperationDiagnostics{
+			OperationID: operationID,
+			Stage:       model.WaitingForClusterCreation,
+			CapturedAt:  time.Now(),
+			Conditions:  []model.ShootCondition{{Type: "EveryNodeReady", Status: "False"}},
+			Events:      []model.ShootEvent{{Type: "Warning", Reason: "ReconcileError", Message: "quota exceeded", Count: 3}},
+		}
+
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetOperation", operationID).Return(operation, nil)
+		readSession.On("GetOperationDiagnostics", operationID).Return(diagnostics, nil)
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		result, err := resolver.RuntimeOperationDiagnostics(operationID)
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, runtimeID, result.RuntimeID)
+		assert.Equal(t, string(model.WaitingForClusterCreation), result.Stage)
+		require.Len(t, result.Conditions, 1)
+		assert.Equal(t, []string{}, result.Conditions[0].Codes)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, 3, result.Events[0].Count)
+		assert.Empty(t, result.LastErrors)
+	})
+
+	t.Run("Should return nil when diagnostics were not captured", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetOperation", operationID).Return(operation, nil)
+		readSession.On("GetOperationDiagnostics", operationID).Return(model.OperationDiagnostics{}, dberrors.NotFound("not found"))
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		result, err := resolver.RuntimeOperationDiagnostics(operationID)
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Should return error when failed to get diagnostics", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetOperation", operationID).Return(operation, nil)
+		readSession.On("GetOperationDiagnostics", operationID).Return(model.OperationDiagnostics{}, dberrors.Internal("error"))
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := resolver.RuntimeOperationDiagnostics(operationID)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestService_RuntimeStatus(t *testing.T) {
+	uuidGenerator := &uuidMocks.UUIDGenerator{}
+	inputConverter := NewInputConverter(uuidGenerator, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
+	graphQLConverter := NewGraphQLConverter()
+
+	operation := model.Operation{
+		ID:        operationID,
+		Type:      model.Provision,
+		State:     model.Succeeded,
+		Message:   "Message",
+		ClusterID: runtimeID,
+	}
+
+	cluster := model.Cluster{
+		ID:         runtimeID,
+		Kubeconfig: util.StringPtr("kubeconfig"),
+	}
+
+	t.Run("Should return runtime status", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", operationID).Return(operation, nil)
+		readSession.On("GetCluster", operationID).Return(cluster, nil)
+		readSession.On("GetCredentialsRotationStatus", operationID).Return([]model.CredentialsRotationStatus{
+			{Kind: model.CertificateAuthorities, Phase: "Prepared"},
+		}, nil)
+
+		provisioner := &mocks2.Provisioner{}
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		status, err := resolver.RuntimeStatus(operationID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, cluster.ID, *status.LastOperationStatus.RuntimeID)
+		assert.Equal(t, cluster.Kubeconfig, status.RuntimeConfiguration.Kubeconfig)
+		require.Len(t, status.CredentialsRotation, 1)
+		assert.Equal(t, gqlschema.CredentialsRotationKindCertificateAuthorities, status.CredentialsRotation[0].Kind)
+		assert.Equal(t, "Prepared", *status.CredentialsRotation[0].Phase)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when failed to get cluster", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", operationID).Return(operation, nil)
+		readSession.On("GetCluster", operationID).Return(model.Cluster{}, dberrors.Internal("error"))
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := resolver.RuntimeStatus(operationID)
+
+		// then
+		require.Error(t, err)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when failed to get operation status", func(t *testing.T) {
+		// given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", operationID).Return(model.Operation{}, dberrors.Internal("error"))
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := resolver.RuntimeStatus(operationID)
+
+		// then
+		require.Error(t, err)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+}
+
+func TestService_UpgradeGardenerShoot(t *testing.T) {
+	quotaManager := &quotaMocks.Manager{}
+	quotaManager.On("CheckShootUpgrade", tenant, mock.Anything, mock.Anything).Return(nil)
+
+	inputConverter := NewInputConverter(uuid.NewUUIDGenerator(), gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate)
+	graphQLConverter := NewGraphQLConverter()
+	uuidGenerator := uuid.NewUUIDGenerator()
+
+	lastOperation := model.Operation{State: model.Succeeded}
 
 	providerConfig, _ := model.NewGCPGardenerConfig(&gqlschema.GCPProviderConfigInput{Zones: []string{"europe-west1-a"}})
 	cluster := model.Cluster{
@@ -647,6 +1276,7 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
 				readSession.On("GetCluster", runtimeID).Return(cluster, nil)
 				sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+				writeSession.On("LockTenantQuota", tenant).Return(nil)
 
 				newUpgradedConfig := upgradedConfig
 				newUpgradedConfig.KubernetesVersion = "1.20"
@@ -656,6 +1286,7 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 				writeSession.On("InsertAdministrators", runtimeID, mock.Anything).Return(nil)
 				provisioner.On("setOperationStarted", writeSession, runtimeID, model.UpgradeShoot, model.WaitingForShootNewVersion, nil, nil).Return(mock.MatchedBy(operationMatcher), nil)
 				writeSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
+				writeSession.On("InsertPreUpgradeGardenerConfig", mock.AnythingOfType("string"), cluster.ClusterConfig).Return(nil)
 				provisioner.On("UpgradeCluster", runtimeID, newUpgradedConfig).Return(nil)
 				writeSession.On("Commit").Return(nil)
 				upgradeShootQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
@@ -669,11 +1300,13 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
 				readSession.On("GetCluster", runtimeID).Return(cluster, nil)
 				sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+				writeSession.On("LockTenantQuota", tenant).Return(nil)
 				writeSession.On("UpdateGardenerClusterConfig", upgradedConfig).Return(nil)
 				writeSession.On("RollbackUnlessCommitted").Return()
 				writeSession.On("InsertAdministrators", runtimeID, mock.Anything).Return(nil)
 				provisioner.On("setOperationStarted", writeSession, runtimeID, model.UpgradeShoot, model.WaitingForShootNewVersion, nil, nil).Return(mock.MatchedBy(operationMatcher), nil)
 				writeSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
+				writeSession.On("InsertPreUpgradeGardenerConfig", mock.AnythingOfType("string"), cluster.ClusterConfig).Return(nil)
 				provisioner.On("UpgradeCluster", runtimeID, upgradedConfig).Return(nil)
 				writeSession.On("Commit").Return(nil)
 				upgradeShootQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
@@ -694,7 +1327,7 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 
 			testCase.mockFunc(sessionFactory, readSession, writeSessionWithinTransaction, provisioner, shootProvider, upgradeShootQueue)
 
-			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, shootProvider, nil, nil, upgradeShootQueue)
+			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, shootProvider, nil, nil, upgradeShootQueue, ServiceOptions{QuotaManager: quotaManager})
 
 			// when
 			operationStatus, err := service.UpgradeGardenerShoot(runtimeID, upgradeShootInput)
@@ -721,9 +1354,11 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
 				readSession.On("GetCluster", runtimeID).Return(cluster, nil)
 				sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+				writeSession.On("LockTenantQuota", tenant).Return(nil)
 				writeSession.On("RollbackUnlessCommitted").Return()
 				writeSession.On("UpdateGardenerClusterConfig", upgradedConfig).Return(nil)
 				writeSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
+				writeSession.On("InsertPreUpgradeGardenerConfig", mock.AnythingOfType("string"), cluster.ClusterConfig).Return(nil)
 				writeSession.On("InsertAdministrators", runtimeID, mock.Anything).Return(nil)
 				provisioner.On("setOperationStarted", writeSession, runtimeID, model.UpgradeShoot, model.WaitingForShootNewVersion, nil, nil).Return(mock.MatchedBy(operationMatcher), nil)
 				provisioner.On("UpgradeCluster", runtimeID, upgradedConfig).Return(nil)
@@ -738,15 +1373,29 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
 				readSession.On("GetCluster", runtimeID).Return(cluster, nil)
 				sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+				writeSession.On("LockTenantQuota", tenant).Return(nil)
 				writeSession.On("RollbackUnlessCommitted").Return()
 				writeSession.On("UpdateGardenerClusterConfig", upgradedConfig).Return(nil)
 				writeSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
+				writeSession.On("InsertPreUpgradeGardenerConfig", mock.AnythingOfType("string"), cluster.ClusterConfig).Return(nil)
 				writeSession.On("InsertAdministrators", runtimeID, mock.Anything).Return(nil)
 				provisioner.On("setOperationStarted", writeSession, runtimeID, model.UpgradeShoot, model.WaitingForShootNewVersion, nil, nil).Return(mock.MatchedBy(operationMatcher), nil)
 				provisioner.On("UpgradeCluster", runtimeID, upgradedConfig).Return(apperrors.Internal("error"))
 				shootProvider.On("Get", runtimeID, tenant).Return(providedShoot("1.19"), nil)
 			},
 		},
+		{
+			description: "should fail to upgrade Shoot when failed to lock tenant quota",
+			mockFunc: func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession, writeSession *sessionMocks.WriteSessionWithinTransaction, provisioner *mocks2.Provisioner, shootProvider *mocks2.ShootProvider) {
+				sessionFactory.On("NewReadSession").Return(readSession)
+				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+				readSession.On("GetCluster", runtimeID).Return(cluster, nil)
+				sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+				writeSession.On("LockTenantQuota", tenant).Return(dberrors.Internal("error"))
+				writeSession.On("RollbackUnlessCommitted").Return()
+				shootProvider.On("Get", runtimeID, tenant).Return(providedShoot("1.19"), nil)
+			},
+		},
 		{
 			description: "should fail to upgrade Shoot when failed to update gardener cluster config",
 			mockFunc: func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession, writeSession *sessionMocks.WriteSessionWithinTransaction, provisioner *mocks2.Provisioner, shootProvider *mocks2.ShootProvider) {
@@ -754,6 +1403,7 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
 				readSession.On("GetCluster", runtimeID).Return(cluster, nil)
 				sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+				writeSession.On("LockTenantQuota", tenant).Return(nil)
 				writeSession.On("RollbackUnlessCommitted").Return()
 				writeSession.On("UpdateGardenerClusterConfig", upgradedConfig).Return(dberrors.Internal("error"))
 				shootProvider.On("Get", runtimeID, tenant).Return(providedShoot("1.19"), nil)
@@ -814,7 +1464,7 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 
 			testCase.mockFunc(sessionFactory, readSession, writeSessionWithinTransaction, provisioner, shootProvider)
 
-			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, shootProvider, nil, nil, upgradeShootQueue)
+			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, shootProvider, nil, nil, upgradeShootQueue, ServiceOptions{QuotaManager: quotaManager})
 
 			// when
 			_, err := service.UpgradeGardenerShoot(runtimeID, upgradeShootInput)
@@ -828,6 +1478,98 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 	}
 }
 
+func TestService_RotateCredentials(t *testing.T) {
+	graphQLConverter := NewGraphQLConverter()
+	uuidGenerator := uuid.NewUUIDGenerator()
+
+	cluster := model.Cluster{ID: runtimeID, Tenant: tenant}
+	lastOperation := model.Operation{State: model.Succeeded}
+
+	preparedShoot := gardener_Types.Shoot{Status: gardener_Types.ShootStatus{Credentials: &gardener_Types.ShootCredentials{
+		Rotation: &gardener_Types.ShootCredentialsRotation{
+			CertificateAuthorities: &gardener_Types.CARotation{Phase: gardener_Types.RotationPrepared},
+		},
+	}}}
+
+	t.Run("should start credentials rotation", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+		rotationQueue := &mocks.OperationQueue{}
+		shootProvider := &mocks2.ShootProvider{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetCluster", runtimeID).Return(cluster, nil)
+		shootProvider.On("Get", runtimeID, tenant).Return(preparedShoot, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("RollbackUnlessCommitted").Return()
+		writeSession.On("InsertOperation", mock.MatchedBy(getOperationMatcher(model.Operation{
+			Type:      model.RotateCredentials,
+			ClusterID: runtimeID,
+			State:     model.InProgress,
+			Stage:     model.RotatingCredentials,
+		}))).Return(nil)
+		writeSession.On("InsertCredentialsRotation", mock.MatchedBy(func(rotation model.CredentialsRotation) bool {
+			return rotation.Phase == model.RotationComplete && rotation.Includes(model.CertificateAuthorities)
+		})).Return(nil)
+		writeSession.On("Commit").Return(nil)
+		rotationQueue.On("Add", mock.AnythingOfType("string")).Return()
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, shootProvider, nil, nil, nil, ServiceOptions{CredentialsRotationQueue: rotationQueue})
+
+		// when
+		status, err := service.RotateCredentials(runtimeID, []gqlschema.CredentialsRotationKind{gqlschema.CredentialsRotationKindCertificateAuthorities}, gqlschema.CredentialsRotationPhaseComplete)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, gqlschema.OperationTypeRotateCredentials, status.Operation)
+		sessionFactory.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+		rotationQueue.AssertExpectations(t)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		kinds       []gqlschema.CredentialsRotationKind
+		shoot       gardener_Types.Shoot
+	}{
+		{
+			description: "should not complete rotation of single phase credentials",
+			kinds:       []gqlschema.CredentialsRotationKind{gqlschema.CredentialsRotationKindSSHKeypair},
+			shoot:       preparedShoot,
+		},
+		{
+			description: "should not complete rotation which was not prepared",
+			kinds:       []gqlschema.CredentialsRotationKind{gqlschema.CredentialsRotationKindServiceAccountKey},
+			shoot:       preparedShoot,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			// given
+			sessionFactory := &sessionMocks.Factory{}
+			readSession := &sessionMocks.ReadSession{}
+			shootProvider := &mocks2.ShootProvider{}
+
+			sessionFactory.On("NewReadSession").Return(readSession)
+			readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+			readSession.On("GetCluster", runtimeID).Return(cluster, nil)
+			shootProvider.On("Get", runtimeID, tenant).Return(testCase.shoot, nil)
+
+			service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, shootProvider, nil, nil, nil, ServiceOptions{})
+
+			// when
+			_, err := service.RotateCredentials(runtimeID, testCase.kinds, gqlschema.CredentialsRotationPhaseComplete)
+
+			// then
+			require.Error(t, err)
+			assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+			sessionFactory.AssertNotCalled(t, "NewSessionWithinTransaction")
+		})
+	}
+}
+
 func getOperationMatcher(expected model.Operation) func(model.Operation) bool {
 	return func(op model.Operation) bool {
 		return op.Type == expected.Type && op.ClusterID == expected.ClusterID &&
@@ -844,3 +1586,159 @@ func getClusterMatcher(expected model.Cluster) func(model.Cluster) bool {
 func notEmptyUUIDMatcher(id string) bool {
 	return len(id) > 0
 }
+
+func TestService_BulkUpgradeShoot(t *testing.T) {
+	graphQLConverter := NewGraphQLConverter()
+	uuidGenerator := uuid.NewUUIDGenerator()
+
+	selector := gqlschema.RuntimeSelectorInput{Providers: []string{"aws"}, Regions: []string{"eu-central-1"}}
+	input := gqlschema.UpgradeShootInput{GardenerConfig: &gqlschema.GardenerUpgradeInput{KubernetesVersion: util.StringPtr("1.27.1")}}
+	strategy := gqlschema.BulkOperationStrategyInput{MaxParallel: 10, MaxFailureRatio: 0.1}
+
+	t.Run("should create bulk operation with pending child for every selected Runtime", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("ListRuntimeIDs", model.RuntimeSelector{Providers: []string{"aws"}, Regions: []string{"eu-central-1"}}).Return([]string{"runtime-1", "runtime-2"}, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("RollbackUnlessCommitted").Return()
+		writeSession.On("InsertBulkOperation", mock.MatchedBy(func(operation model.BulkOperation) bool {
+			return operation.Type == model.UpgradeShoot && operation.State == model.BulkInProgress &&
+				operation.Strategy == model.BulkOperationStrategy{MaxParallel: 10, MaxFailureRatio: 0.1} &&
+				decodedKubernetesVersion(operation.Input) == "1.27.1"
+		})).Return(nil)
+		writeSession.On("InsertBulkOperationChild", mock.MatchedBy(func(child model.BulkOperationChild) bool {
+			return child.State == model.Pending && child.OperationID == ""
+		})).Return(nil).Twice()
+		writeSession.On("Commit").Return(nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		status, err := service.BulkUpgradeShoot(selector, input, strategy)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, gqlschema.BulkOperationStateInProgress, status.State)
+		assert.Equal(t, 2, status.Total)
+		assert.Equal(t, 2, status.Pending)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should return error when no Runtime matches the selector", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("ListRuntimeIDs", mock.Anything).Return([]string{}, nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := service.BulkUpgradeShoot(selector, input, strategy)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+	})
+
+	t.Run("should return error for selector without criteria", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := service.BulkUpgradeShoot(gqlschema.RuntimeSelectorInput{}, input, strategy)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+		sessionFactory.AssertNotCalled(t, "NewReadSession")
+	})
+
+	t.Run("should return error for invalid strategy", func(t *testing.T) {
+		// given
+		service := NewProvisioningService(nil, graphQLConverter, nil, &sessionMocks.Factory{}, nil, uuidGenerator, nil, nil, nil, nil, ServiceOptions{})
+
+		for _, invalid := range []gqlschema.BulkOperationStrategyInput{
+			{MaxParallel: 0, MaxFailureRatio: 0.1},
+			{MaxParallel: 1, MaxFailureRatio: 1.5},
+		} {
+			// when
+			_, err := service.BulkUpgradeShoot(selector, input, invalid)
+
+			// then
+			require.Error(t, err)
+			assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+		}
+	})
+}
+
+func TestService_CancelBulkOperation(t *testing.T) {
+	graphQLConverter := NewGraphQLConverter()
+
+	bulkOperation := model.BulkOperation{ID: "bulk-operation", Type: model.UpgradeShoot, State: model.BulkPaused}
+	children := []model.BulkOperationChild{
+		{BulkOperationID: "bulk-operation", RuntimeID: "runtime-1", OperationID: "operation-1", State: model.InProgress},
+		{BulkOperationID: "bulk-operation", RuntimeID: "runtime-2", State: model.Pending},
+	}
+
+	t.Run("should cancel pending children", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetBulkOperation", "bulk-operation").Return(bulkOperation, nil)
+		readSession.On("ListBulkOperationChildren", "bulk-operation").Return(children, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("RollbackUnlessCommitted").Return()
+		writeSession.On("UpdateBulkOperationState", "bulk-operation", model.BulkCanceled, "Bulk operation canceled", (*time.Time)(nil)).Return(nil)
+		writeSession.On("CancelPendingBulkOperationChildren", "bulk-operation", "Canceled with the bulk operation").Return(nil)
+		writeSession.On("Commit").Return(nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, nil, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := service.CancelBulkOperation("bulk-operation")
+
+		// then
+		require.NoError(t, err)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should not cancel finished bulk operation", func(t *testing.T) {
+		// given
+		finished := bulkOperation
+		finished.State = model.BulkSucceeded
+
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetBulkOperation", "bulk-operation").Return(finished, nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, nil, nil, nil, nil, nil, ServiceOptions{})
+
+		// when
+		_, err := service.CancelBulkOperation("bulk-operation")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+	})
+}
+
+func decodedKubernetesVersion(rawInput string) string {
+	var input gqlschema.UpgradeShootInput
+	if err := json.Unmarshal([]byte(rawInput), &input); err != nil || input.GardenerConfig == nil {
+		return ""
+	}
+
+	return util.UnwrapStr(input.GardenerConfig.KubernetesVersion)
+}
@@ -0,0 +1,178 @@
+package quota
+
+import (
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+)
+
+// Config holds limits applied to tenants without own quota. Zero means no limit.
+type Config struct {
+	DefaultMaxRuntimes             int `envconfig:"default=0"`
+	DefaultMaxNodesPerProvider     int `envconfig:"default=0"`
+	DefaultMaxOperationsInProgress int `envconfig:"default=0"`
+}
+
+//go:generate mockery --name=Manager
+type Manager interface {
+	CheckProvisioning(tenant, provider string, maxNodes int) apperrors.AppError
+	CheckShootUpgrade(tenant, provider string, additionalNodes int) apperrors.AppError
+	GetQuota(tenant string) (model.TenantQuota, apperrors.AppError)
+	SetQuota(quota model.TenantQuota) apperrors.AppError
+	GetUsage(tenant string) (model.TenantUsage, apperrors.AppError)
+	ListUsages() ([]model.TenantUsage, apperrors.AppError)
+}
+
+type manager struct {
+	config           Config
+	dbSessionFactory dbsession.Factory
+}
+
+func NewManager(config Config, dbSessionFactory dbsession.Factory) Manager {
+	return &manager{
+		config:           config,
+		dbSessionFactory: dbSessionFactory,
+	}
+}
+
+func (m *manager) CheckProvisioning(tenant, provider string, maxNodes int) apperrors.AppError {
+	quota, usage, err := m.getQuotaAndUsage(tenant)
+	if err != nil {
+		return err
+	}
+
+	if exceeded(quota.MaxRuntimes, usage.Runtimes+1) {
+		return apperrors.Forbidden("runtimes quota exceeded for tenant %s: %d runtimes allowed", tenant, *quota.MaxRuntimes)
+	}
+
+	return m.checkOperationAndNodes(quota, usage, provider, maxNodes)
+}
+
+func (m *manager) CheckShootUpgrade(tenant, provider string, additionalNodes int) apperrors.AppError {
+	quota, usage, err := m.getQuotaAndUsage(tenant)
+	if err != nil {
+		return err
+	}
+
+	return m.checkOperationAndNodes(quota, usage, provider, additionalNodes)
+}
+
+func (m *manager) checkOperationAndNodes(quota model.TenantQuota, usage model.TenantUsage, provider string, additionalNodes int) apperrors.AppError {
+	if exceeded(quota.MaxOperationsInProgress, usage.OperationsInProgress+1) {
+		return apperrors.Forbidden("operations in progress quota exceeded for tenant %s: %d operations allowed", quota.Tenant, *quota.MaxOperationsInProgress)
+	}
+
+	// Scaling down is always allowed, even if the tenant already exceeds the limit
+	if additionalNodes <= 0 {
+		return nil
+	}
+
+	maxNodes := quota.DefaultMaxNodesPerProvider
+	if providerMaxNodes, found := quota.MaxNodesPerProvider[provider]; found {
+		maxNodes = &providerMaxNodes
+	}
+
+	if exceeded(maxNodes, usage.NodesPerProvider[provider]+additionalNodes) {
+		return apperrors.Forbidden("nodes quota exceeded for tenant %s: %d nodes allowed for %s provider, %d in use, %d requested",
+			quota.Tenant, *maxNodes, provider, usage.NodesPerProvider[provider], additionalNodes)
+	}
+
+	return nil
+}
+
+// GetQuota returns quota of the tenant with defaults applied. Nil limit means that it is not restricted.
+func (m *manager) GetQuota(tenant string) (model.TenantQuota, apperrors.AppError) {
+	quota, dberr := m.dbSessionFactory.NewReadSession().GetTenantQuota(tenant)
+	if dberr != nil && dberr.Code() != dberrors.CodeNotFound {
+		return model.TenantQuota{}, dberr.Append("failed to get tenant quota")
+	}
+	if dberr != nil {
+		quota = model.TenantQuota{Tenant: tenant, MaxNodesPerProvider: map[string]int{}}
+	}
+
+	quota.MaxRuntimes = withDefault(quota.MaxRuntimes, m.config.DefaultMaxRuntimes)
+	quota.MaxOperationsInProgress = withDefault(quota.MaxOperationsInProgress, m.config.DefaultMaxOperationsInProgress)
+	quota.DefaultMaxNodesPerProvider = withDefault(nil, m.config.DefaultMaxNodesPerProvider)
+
+	return quota, nil
+}
+
+func (m *manager) SetQuota(quota model.TenantQuota) apperrors.AppError {
+	if invalid(quota.MaxRuntimes) || invalid(quota.MaxOperationsInProgress) {
+		return apperrors.BadRequest("quota limits must not be negative")
+	}
+	for provider, maxNodes := range quota.MaxNodesPerProvider {
+		if maxNodes < 0 {
+			return apperrors.BadRequest("nodes quota for %s provider must not be negative", provider)
+		}
+	}
+
+	session, dberr := m.dbSessionFactory.NewSessionWithinTransaction()
+	if dberr != nil {
+		return dberr
+	}
+	defer session.RollbackUnlessCommitted()
+
+	dberr = session.UpsertTenantQuota(quota)
+	if dberr != nil {
+		return dberr.Append("failed to set tenant quota")
+	}
+
+	dberr = session.Commit()
+	if dberr != nil {
+		return dberr.Append("failed to commit tenant quota")
+	}
+
+	return nil
+}
+
+func (m *manager) GetUsage(tenant string) (model.TenantUsage, apperrors.AppError) {
+	usage, dberr := m.dbSessionFactory.NewReadSession().GetTenantUsage(tenant)
+	if dberr != nil {
+		return model.TenantUsage{}, dberr.Append("failed to get tenant usage")
+	}
+
+	return usage, nil
+}
+
+func (m *manager) ListUsages() ([]model.TenantUsage, apperrors.AppError) {
+	usages, dberr := m.dbSessionFactory.NewReadSession().ListTenantUsages()
+	if dberr != nil {
+		return nil, dberr.Append("failed to list tenant usages")
+	}
+
+	return usages, nil
+}
+
+func (m *manager) getQuotaAndUsage(tenant string) (model.TenantQuota, model.TenantUsage, apperrors.AppError) {
+	quota, err := m.GetQuota(tenant)
+	if err != nil {
+		return model.TenantQuota{}, model.TenantUsage{}, err
+	}
+
+	usage, err := m.GetUsage(tenant)
+	if err != nil {
+		return model.TenantQuota{}, model.TenantUsage{}, err
+	}
+
+	return quota, usage, nil
+}
+
+func withDefault(limit *int, defaultLimit int) *int {
+	if limit != nil {
+		return limit
+	}
+	if defaultLimit > 0 {
+		return &defaultLimit
+	}
+	return nil
+}
+
+func exceeded(limit *int, value int) bool {
+	return limit != nil && value > *limit
+}
+
+func invalid(limit *int) bool {
+	return limit != nil && *limit < 0
+}
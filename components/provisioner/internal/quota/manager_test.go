@@ -0,0 +1,176 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tenant   = "tenant"
+	provider = "gcp"
+)
+
+func TestManager_CheckProvisioning(t *testing.T) {
+	usage := model.TenantUsage{
+		Tenant:               tenant,
+		Runtimes:             2,
+		OperationsInProgress: 1,
+		NodesPerProvider:     map[string]int{provider: 10},
+	}
+
+	for _, testCase := range []struct {
+		description   string
+		config        Config
+		quota         *model.TenantQuota
+		maxNodes      int
+		expectedError bool
+	}{
+		{
+			description: "should allow provisioning when tenant has no quota and there are no defaults",
+			maxNodes:    100,
+		},
+		{
+			description:   "should reject provisioning when default runtimes quota is exceeded",
+			config:        Config{DefaultMaxRuntimes: 2},
+			maxNodes:      1,
+			expectedError: true,
+		},
+		{
+			description: "should allow provisioning when tenant quota overrides default",
+			config:      Config{DefaultMaxRuntimes: 2},
+			quota:       &model.TenantQuota{Tenant: tenant, MaxRuntimes: util.IntPtr(3)},
+			maxNodes:    1,
+		},
+		{
+			description:   "should reject provisioning when operations in progress quota is exceeded",
+			quota:         &model.TenantQuota{Tenant: tenant, MaxOperationsInProgress: util.IntPtr(1)},
+			maxNodes:      1,
+			expectedError: true,
+		},
+		{
+			description:   "should reject provisioning when provider nodes quota is exceeded",
+			quota:         &model.TenantQuota{Tenant: tenant, MaxNodesPerProvider: map[string]int{provider: 12}},
+			maxNodes:      3,
+			expectedError: true,
+		},
+		{
+			description: "should allow provisioning when provider nodes quota is not exceeded",
+			quota:       &model.TenantQuota{Tenant: tenant, MaxNodesPerProvider: map[string]int{provider: 13}},
+			maxNodes:    3,
+		},
+		{
+			description:   "should reject provisioning when default nodes quota is exceeded",
+			config:        Config{DefaultMaxNodesPerProvider: 12},
+			maxNodes:      3,
+			expectedError: true,
+		},
+		{
+			description: "should not apply nodes quota of other provider",
+			quota:       &model.TenantQuota{Tenant: tenant, MaxNodesPerProvider: map[string]int{"azure": 1}},
+			maxNodes:    3,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			// given
+			readSession := &sessionMocks.ReadSession{}
+			sessionFactory := &sessionMocks.Factory{}
+			sessionFactory.On("NewReadSession").Return(readSession)
+			readSession.On("GetTenantUsage", tenant).Return(usage, nil)
+
+			if testCase.quota != nil {
+				readSession.On("GetTenantQuota", tenant).Return(*testCase.quota, nil)
+			} else {
+				readSession.On("GetTenantQuota", tenant).Return(model.TenantQuota{}, dberrors.NotFound("not found"))
+			}
+
+			manager := NewManager(testCase.config, sessionFactory)
+
+			// when
+			err := manager.CheckProvisioning(tenant, provider, testCase.maxNodes)
+
+			// then
+			if testCase.expectedError {
+				require.Error(t, err)
+				assert.Equal(t, apperrors.CodeForbidden, err.Code())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManager_CheckShootUpgrade(t *testing.T) {
+	usage := model.TenantUsage{
+		Tenant:           tenant,
+		Runtimes:         2,
+		NodesPerProvider: map[string]int{provider: 20},
+	}
+	quota := model.TenantQuota{Tenant: tenant, MaxRuntimes: util.IntPtr(2), MaxNodesPerProvider: map[string]int{provider: 15}}
+
+	readSession := &sessionMocks.ReadSession{}
+	sessionFactory := &sessionMocks.Factory{}
+	sessionFactory.On("NewReadSession").Return(readSession)
+	readSession.On("GetTenantUsage", tenant).Return(usage, nil)
+	readSession.On("GetTenantQuota", tenant).Return(quota, nil)
+
+	manager := NewManager(Config{}, sessionFactory)
+
+	t.Run("should allow scaling down when tenant exceeds nodes quota", func(t *testing.T) {
+		// when
+		err := manager.CheckShootUpgrade(tenant, provider, -2)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject scaling up when nodes quota is exceeded", func(t *testing.T) {
+		// when
+		err := manager.CheckShootUpgrade(tenant, provider, 1)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeForbidden, err.Code())
+	})
+}
+
+func TestManager_SetQuota(t *testing.T) {
+	t.Run("should store tenant quota", func(t *testing.T) {
+		// given
+		quota := model.TenantQuota{Tenant: tenant, MaxRuntimes: util.IntPtr(5), MaxNodesPerProvider: map[string]int{provider: 50}}
+
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+		sessionFactory := &sessionMocks.Factory{}
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("UpsertTenantQuota", quota).Return(nil)
+		writeSession.On("Commit").Return(nil)
+		writeSession.On("RollbackUnlessCommitted").Return()
+
+		manager := NewManager(Config{}, sessionFactory)
+
+		// when
+		err := manager.SetQuota(quota)
+
+		// then
+		require.NoError(t, err)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should reject negative limits", func(t *testing.T) {
+		// given
+		manager := NewManager(Config{}, &sessionMocks.Factory{})
+
+		// when
+		err := manager.SetQuota(model.TenantQuota{Tenant: tenant, MaxNodesPerProvider: map[string]int{provider: -1}})
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+	})
+}
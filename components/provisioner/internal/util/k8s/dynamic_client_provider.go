@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+)
+
+//go:generate mockery --name=DynamicClientProvider
+type DynamicClientProvider interface {
+	CreateDynamicClient(kubeconfigRaw []byte) (dynamic.Interface, apperrors.AppError)
+}
+
+type dynamicClientBuilder struct{}
+
+func NewDynamicClientProvider() DynamicClientProvider {
+	return &dynamicClientBuilder{}
+}
+
+func (c *dynamicClientBuilder) CreateDynamicClient(kubeconfigRaw []byte) (dynamic.Interface, apperrors.AppError) {
+	k8sConfig, err := ParseToK8sConfig(kubeconfigRaw)
+	if err != nil {
+		return nil, util.K8SErrorToAppError(errors.Wrap(err, "failed to parse kubeconfig"))
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, util.K8SErrorToAppError(errors.Wrap(err, "failed to create k8s dynamic client"))
+	}
+
+	return dynamicClient, nil
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	apperrors "github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	dynamic "k8s.io/client-go/dynamic"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DynamicClientProvider is an autogenerated mock type for the DynamicClientProvider type
+type DynamicClientProvider struct {
+	mock.Mock
+}
+
+// CreateDynamicClient provides a mock function with given fields: kubeconfigRaw
+func (_m *DynamicClientProvider) CreateDynamicClient(kubeconfigRaw []byte) (dynamic.Interface, apperrors.AppError) {
+	ret := _m.Called(kubeconfigRaw)
+
+	var r0 dynamic.Interface
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func([]byte) (dynamic.Interface, apperrors.AppError)); ok {
+		return rf(kubeconfigRaw)
+	}
+	if rf, ok := ret.Get(0).(func([]byte) dynamic.Interface); ok {
+		r0 = rf(kubeconfigRaw)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dynamic.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte) apperrors.AppError); ok {
+		r1 = rf(kubeconfigRaw)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewDynamicClientProvider creates a new instance of DynamicClientProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDynamicClientProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DynamicClientProvider {
+	mock := &DynamicClientProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
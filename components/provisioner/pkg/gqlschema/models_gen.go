@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 )
 
 type ProviderSpecificConfig interface {
@@ -66,6 +67,36 @@ type AzureZoneInput struct {
 	Cidr string `json:"cidr"`
 }
 
+type BulkOperationChild struct {
+	RuntimeID   string         `json:"runtimeID"`
+	OperationID *string        `json:"operationID"`
+	State       OperationState `json:"state"`
+	Message     *string        `json:"message"`
+}
+
+type BulkOperationStatus struct {
+	ID              string                `json:"id"`
+	Operation       OperationType         `json:"operation"`
+	State           BulkOperationState    `json:"state"`
+	Message         *string               `json:"message"`
+	MaxParallel     int                   `json:"maxParallel"`
+	MaxFailureRatio float64               `json:"maxFailureRatio"`
+	StartTimestamp  time.Time             `json:"startTimestamp"`
+	EndTimestamp    *time.Time            `json:"endTimestamp"`
+	Total           int                   `json:"total"`
+	Pending         int                   `json:"pending"`
+	InProgress      int                   `json:"inProgress"`
+	Succeeded       int                   `json:"succeeded"`
+	Failed          int                   `json:"failed"`
+	Canceled        int                   `json:"canceled"`
+	Children        []*BulkOperationChild `json:"children"`
+}
+
+type BulkOperationStrategyInput struct {
+	MaxParallel     int     `json:"maxParallel"`
+	MaxFailureRatio float64 `json:"maxFailureRatio"`
+}
+
 type ClusterConfigInput struct {
 	GardenerConfig *GardenerConfigInput `json:"gardenerConfig"`
 	Administrators []string             `json:"administrators"`
@@ -98,6 +129,13 @@ type ConfigEntryInput struct {
 	Secret *bool  `json:"secret"`
 }
 
+type CredentialsRotationStatus struct {
+	Kind               CredentialsRotationKind `json:"kind"`
+	Phase              *string                 `json:"phase"`
+	LastInitiationTime *time.Time              `json:"lastInitiationTime"`
+	LastCompletionTime *time.Time              `json:"lastCompletionTime"`
+}
+
 type DNSConfig struct {
 	Domain    string         `json:"domain"`
 	Providers []*DNSProvider `json:"providers"`
@@ -244,6 +282,7 @@ type LastError struct {
 	ErrMessage string `json:"errMessage"`
 	Reason     string `json:"reason"`
 	Component  string `json:"component"`
+	Action     string `json:"action"`
 }
 
 type OIDCConfig struct {
@@ -280,6 +319,18 @@ type OpenStackProviderConfigInput struct {
 	LoadBalancerProvider string   `json:"loadBalancerProvider"`
 }
 
+type OperationDiagnostics struct {
+	OperationID string            `json:"operationID"`
+	RuntimeID   string            `json:"runtimeID"`
+	Stage       string            `json:"stage"`
+	CapturedAt  time.Time         `json:"capturedAt"`
+	Conditions  []*ShootCondition `json:"conditions"`
+	Constraints []*ShootCondition `json:"constraints"`
+	LastErrors  []*ShootLastError `json:"lastErrors"`
+	Events      []*ShootEvent     `json:"events"`
+	Truncated   bool              `json:"truncated"`
+}
+
 type OperationStatus struct {
 	ID        *string        `json:"id"`
 	Operation OperationType  `json:"operation"`
@@ -289,6 +340,30 @@ type OperationStatus struct {
 	LastError *LastError     `json:"lastError"`
 }
 
+type Orphan struct {
+	Category         OrphanCategory `json:"category"`
+	RuntimeID        string         `json:"runtimeID"`
+	Tenant           *string        `json:"tenant"`
+	ShootName        *string        `json:"shootName"`
+	Remediated       bool           `json:"remediated"`
+	RemediationError *string        `json:"remediationError"`
+}
+
+type OrphansReport struct {
+	ScanTime time.Time `json:"scanTime"`
+	Orphans  []*Orphan `json:"orphans"`
+}
+
+type ProviderNodes struct {
+	Provider string `json:"provider"`
+	Nodes    int    `json:"nodes"`
+}
+
+type ProviderNodesInput struct {
+	Provider string `json:"provider"`
+	Nodes    int    `json:"nodes"`
+}
+
 type ProviderSpecificInput struct {
 	GcpConfig       *GCPProviderConfigInput       `json:"gcpConfig"`
 	AzureConfig     *AzureProviderConfigInput     `json:"azureConfig"`
@@ -303,9 +378,10 @@ type ProvisionRuntimeInput struct {
 }
 
 type RuntimeConfig struct {
-	ClusterConfig *GardenerConfig `json:"clusterConfig"`
-	KymaConfig    *KymaConfig     `json:"kymaConfig"`
-	Kubeconfig    *string         `json:"kubeconfig"`
+	ClusterConfig      *GardenerConfig `json:"clusterConfig"`
+	KymaConfig         *KymaConfig     `json:"kymaConfig"`
+	Kubeconfig         *string         `json:"kubeconfig"`
+	DeletionProtection *bool           `json:"deletionProtection"`
 }
 
 type RuntimeConnectionStatus struct {
@@ -313,17 +389,83 @@ type RuntimeConnectionStatus struct {
 	Errors []*Error                     `json:"errors"`
 }
 
+type RuntimeEvent struct {
+	RuntimeID   string         `json:"runtimeID"`
+	OperationID string         `json:"operationID"`
+	Operation   OperationType  `json:"operation"`
+	State       OperationState `json:"state"`
+	Stage       string         `json:"stage"`
+	Message     *string        `json:"message"`
+	LastError   *LastError     `json:"lastError"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
 type RuntimeInput struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description"`
 	Labels      Labels  `json:"labels"`
 }
 
+type RuntimeSelectorInput struct {
+	Tenants            []string `json:"tenants"`
+	Providers          []string `json:"providers"`
+	Regions            []string `json:"regions"`
+	KubernetesVersions []string `json:"kubernetesVersions"`
+}
+
 type RuntimeStatus struct {
-	LastOperationStatus     *OperationStatus         `json:"lastOperationStatus"`
-	RuntimeConnectionStatus *RuntimeConnectionStatus `json:"runtimeConnectionStatus"`
-	RuntimeConfiguration    *RuntimeConfig           `json:"runtimeConfiguration"`
-	HibernationStatus       *HibernationStatus       `json:"hibernationStatus"`
+	LastOperationStatus     *OperationStatus             `json:"lastOperationStatus"`
+	RuntimeConnectionStatus *RuntimeConnectionStatus     `json:"runtimeConnectionStatus"`
+	RuntimeConfiguration    *RuntimeConfig               `json:"runtimeConfiguration"`
+	HibernationStatus       *HibernationStatus           `json:"hibernationStatus"`
+	CredentialsRotation     []*CredentialsRotationStatus `json:"credentialsRotation"`
+}
+
+type ShootCondition struct {
+	Type               string     `json:"type"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason"`
+	Message            string     `json:"message"`
+	Codes              []string   `json:"codes"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime"`
+}
+
+type ShootEvent struct {
+	Type           string     `json:"type"`
+	Reason         string     `json:"reason"`
+	Message        string     `json:"message"`
+	Count          int        `json:"count"`
+	FirstTimestamp *time.Time `json:"firstTimestamp"`
+	LastTimestamp  *time.Time `json:"lastTimestamp"`
+}
+
+type ShootLastError struct {
+	Description    string     `json:"description"`
+	TaskID         *string    `json:"taskID"`
+	Codes          []string   `json:"codes"`
+	LastUpdateTime *time.Time `json:"lastUpdateTime"`
+}
+
+type TenantQuota struct {
+	Tenant                     string           `json:"tenant"`
+	MaxRuntimes                *int             `json:"maxRuntimes"`
+	MaxOperationsInProgress    *int             `json:"maxOperationsInProgress"`
+	MaxNodesPerProvider        []*ProviderNodes `json:"maxNodesPerProvider"`
+	DefaultMaxNodesPerProvider *int             `json:"defaultMaxNodesPerProvider"`
+}
+
+type TenantQuotaInput struct {
+	MaxRuntimes             *int                  `json:"maxRuntimes"`
+	MaxOperationsInProgress *int                  `json:"maxOperationsInProgress"`
+	MaxNodesPerProvider     []*ProviderNodesInput `json:"maxNodesPerProvider"`
+}
+
+type TenantUsage struct {
+	Tenant               string           `json:"tenant"`
+	Runtimes             int              `json:"runtimes"`
+	OperationsInProgress int              `json:"operationsInProgress"`
+	NodesPerProvider     []*ProviderNodes `json:"nodesPerProvider"`
+	Quota                *TenantQuota     `json:"quota"`
 }
 
 type UpgradeRuntimeInput struct {
@@ -335,6 +477,53 @@ type UpgradeShootInput struct {
 	Administrators []string              `json:"administrators"`
 }
 
+type BulkOperationState string
+
+const (
+	BulkOperationStateInProgress BulkOperationState = "InProgress"
+	BulkOperationStatePaused     BulkOperationState = "Paused"
+	BulkOperationStateCanceled   BulkOperationState = "Canceled"
+	BulkOperationStateSucceeded  BulkOperationState = "Succeeded"
+	BulkOperationStateFailed     BulkOperationState = "Failed"
+)
+
+var AllBulkOperationState = []BulkOperationState{
+	BulkOperationStateInProgress,
+	BulkOperationStatePaused,
+	BulkOperationStateCanceled,
+	BulkOperationStateSucceeded,
+	BulkOperationStateFailed,
+}
+
+func (e BulkOperationState) IsValid() bool {
+	switch e {
+	case BulkOperationStateInProgress, BulkOperationStatePaused, BulkOperationStateCanceled, BulkOperationStateSucceeded, BulkOperationStateFailed:
+		return true
+	}
+	return false
+}
+
+func (e BulkOperationState) String() string {
+	return string(e)
+}
+
+func (e *BulkOperationState) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BulkOperationState(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BulkOperationState", str)
+	}
+	return nil
+}
+
+func (e BulkOperationState) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type ConflictStrategy string
 
 const (
@@ -376,6 +565,94 @@ func (e ConflictStrategy) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type CredentialsRotationKind string
+
+const (
+	CredentialsRotationKindCertificateAuthorities CredentialsRotationKind = "CertificateAuthorities"
+	CredentialsRotationKindServiceAccountKey      CredentialsRotationKind = "ServiceAccountKey"
+	CredentialsRotationKindETCDEncryptionKey      CredentialsRotationKind = "ETCDEncryptionKey"
+	CredentialsRotationKindObservability          CredentialsRotationKind = "Observability"
+	CredentialsRotationKindSSHKeypair             CredentialsRotationKind = "SSHKeypair"
+)
+
+var AllCredentialsRotationKind = []CredentialsRotationKind{
+	CredentialsRotationKindCertificateAuthorities,
+	CredentialsRotationKindServiceAccountKey,
+	CredentialsRotationKindETCDEncryptionKey,
+	CredentialsRotationKindObservability,
+	CredentialsRotationKindSSHKeypair,
+}
+
+func (e CredentialsRotationKind) IsValid() bool {
+	switch e {
+	case CredentialsRotationKindCertificateAuthorities, CredentialsRotationKindServiceAccountKey, CredentialsRotationKindETCDEncryptionKey, CredentialsRotationKindObservability, CredentialsRotationKindSSHKeypair:
+		return true
+	}
+	return false
+}
+
+func (e CredentialsRotationKind) String() string {
+	return string(e)
+}
+
+func (e *CredentialsRotationKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CredentialsRotationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CredentialsRotationKind", str)
+	}
+	return nil
+}
+
+func (e CredentialsRotationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CredentialsRotationPhase string
+
+const (
+	CredentialsRotationPhasePrepare  CredentialsRotationPhase = "Prepare"
+	CredentialsRotationPhaseComplete CredentialsRotationPhase = "Complete"
+)
+
+var AllCredentialsRotationPhase = []CredentialsRotationPhase{
+	CredentialsRotationPhasePrepare,
+	CredentialsRotationPhaseComplete,
+}
+
+func (e CredentialsRotationPhase) IsValid() bool {
+	switch e {
+	case CredentialsRotationPhasePrepare, CredentialsRotationPhaseComplete:
+		return true
+	}
+	return false
+}
+
+func (e CredentialsRotationPhase) String() string {
+	return string(e)
+}
+
+func (e *CredentialsRotationPhase) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CredentialsRotationPhase(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CredentialsRotationPhase", str)
+	}
+	return nil
+}
+
+func (e CredentialsRotationPhase) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type KymaProfile string
 
 const (
@@ -424,6 +701,7 @@ const (
 	OperationStateInProgress OperationState = "InProgress"
 	OperationStateSucceeded  OperationState = "Succeeded"
 	OperationStateFailed     OperationState = "Failed"
+	OperationStateCanceled   OperationState = "Canceled"
 )
 
 var AllOperationState = []OperationState{
@@ -431,11 +709,12 @@ var AllOperationState = []OperationState{
 	OperationStateInProgress,
 	OperationStateSucceeded,
 	OperationStateFailed,
+	OperationStateCanceled,
 }
 
 func (e OperationState) IsValid() bool {
 	switch e {
-	case OperationStatePending, OperationStateInProgress, OperationStateSucceeded, OperationStateFailed:
+	case OperationStatePending, OperationStateInProgress, OperationStateSucceeded, OperationStateFailed, OperationStateCanceled:
 		return true
 	}
 	return false
@@ -473,6 +752,7 @@ const (
 	OperationTypeDeprovisionNoInstall OperationType = "DeprovisionNoInstall"
 	OperationTypeReconnectRuntime     OperationType = "ReconnectRuntime"
 	OperationTypeHibernate            OperationType = "Hibernate"
+	OperationTypeRotateCredentials    OperationType = "RotateCredentials"
 )
 
 var AllOperationType = []OperationType{
@@ -484,11 +764,12 @@ var AllOperationType = []OperationType{
 	OperationTypeDeprovisionNoInstall,
 	OperationTypeReconnectRuntime,
 	OperationTypeHibernate,
+	OperationTypeRotateCredentials,
 }
 
 func (e OperationType) IsValid() bool {
 	switch e {
-	case OperationTypeProvision, OperationTypeProvisionNoInstall, OperationTypeUpgrade, OperationTypeUpgradeShoot, OperationTypeDeprovision, OperationTypeDeprovisionNoInstall, OperationTypeReconnectRuntime, OperationTypeHibernate:
+	case OperationTypeProvision, OperationTypeProvisionNoInstall, OperationTypeUpgrade, OperationTypeUpgradeShoot, OperationTypeDeprovision, OperationTypeDeprovisionNoInstall, OperationTypeReconnectRuntime, OperationTypeHibernate, OperationTypeRotateCredentials:
 		return true
 	}
 	return false
@@ -515,6 +796,49 @@ func (e OperationType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type OrphanCategory string
+
+const (
+	OrphanCategoryDirectorRuntimeWithoutCluster OrphanCategory = "DirectorRuntimeWithoutCluster"
+	OrphanCategoryShootWithoutCluster           OrphanCategory = "ShootWithoutCluster"
+	OrphanCategoryClusterWithoutShoot           OrphanCategory = "ClusterWithoutShoot"
+)
+
+var AllOrphanCategory = []OrphanCategory{
+	OrphanCategoryDirectorRuntimeWithoutCluster,
+	OrphanCategoryShootWithoutCluster,
+	OrphanCategoryClusterWithoutShoot,
+}
+
+func (e OrphanCategory) IsValid() bool {
+	switch e {
+	case OrphanCategoryDirectorRuntimeWithoutCluster, OrphanCategoryShootWithoutCluster, OrphanCategoryClusterWithoutShoot:
+		return true
+	}
+	return false
+}
+
+func (e OrphanCategory) String() string {
+	return string(e)
+}
+
+func (e *OrphanCategory) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrphanCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrphanCategory", str)
+	}
+	return nil
+}
+
+func (e OrphanCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type RuntimeAgentConnectionStatus string
 
 const (
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE tenant_provider_quota;
+DROP TABLE tenant_quota;
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE tenant_quota
+(
+    tenant varchar(256) PRIMARY KEY,
+    max_runtimes integer,
+    max_operations_in_progress integer
+);
+
+CREATE TABLE tenant_provider_quota
+(
+    tenant varchar(256) NOT NULL,
+    provider varchar(256) NOT NULL,
+    max_nodes integer NOT NULL,
+    PRIMARY KEY (tenant, provider),
+    foreign key (tenant) REFERENCES tenant_quota (tenant) ON DELETE CASCADE
+);
+
+COMMIT;
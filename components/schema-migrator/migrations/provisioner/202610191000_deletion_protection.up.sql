@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE cluster ADD COLUMN deletion_protection boolean NOT NULL DEFAULT false;
+
+COMMIT;
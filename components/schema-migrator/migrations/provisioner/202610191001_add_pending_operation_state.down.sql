@@ -0,0 +1,17 @@
+BEGIN;
+
+DELETE FROM operation WHERE state = 'PENDING';
+
+ALTER TYPE operation_state RENAME TO operation_state_old;
+
+CREATE TYPE operation_state AS ENUM (
+    'IN_PROGRESS',
+    'SUCCEEDED',
+    'FAILED'
+    );
+
+ALTER TABLE operation ALTER COLUMN state TYPE operation_state USING state::text::operation_state;
+
+DROP TYPE operation_state_old;
+
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE operation_stage;
+ALTER TABLE operation DROP COLUMN pipeline_version;
+COMMIT;
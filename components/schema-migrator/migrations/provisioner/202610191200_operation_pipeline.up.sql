@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Operations started before stage pipelines were introduced finish on the linear stage chain
+ALTER TABLE operation ADD COLUMN pipeline_version varchar(64) NOT NULL DEFAULT 'legacy';
+ALTER TABLE operation ALTER COLUMN pipeline_version SET DEFAULT '';
+
+CREATE TABLE operation_stage
+(
+    operation_id uuid NOT NULL,
+    stage varchar(256) NOT NULL,
+    state varchar(32) NOT NULL,
+    start_timestamp timestamp without time zone NOT NULL,
+    end_timestamp timestamp without time zone,
+    PRIMARY KEY (operation_id, stage),
+    foreign key (operation_id) REFERENCES operation (id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE credentials_rotation_status;
+DROP TABLE credentials_rotation;
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE credentials_rotation
+(
+    operation_id uuid PRIMARY KEY,
+    kinds varchar(256) NOT NULL,
+    phase varchar(32) NOT NULL,
+    foreign key (operation_id) REFERENCES operation (id) ON DELETE CASCADE
+);
+
+CREATE TABLE credentials_rotation_status
+(
+    cluster_id uuid NOT NULL,
+    kind varchar(64) NOT NULL,
+    phase varchar(32) NOT NULL,
+    last_initiation_time timestamp without time zone,
+    last_completion_time timestamp without time zone,
+    PRIMARY KEY (cluster_id, kind),
+    foreign key (cluster_id) REFERENCES cluster (id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE operation DROP COLUMN action;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE operation ADD COLUMN action varchar(32) NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE operation DROP COLUMN diagnostics;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE operation ADD COLUMN diagnostics jsonb;
+
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE bulk_operation_child;
+DROP TABLE bulk_operation;
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE bulk_operation
+(
+    id uuid PRIMARY KEY CHECK (id <> '00000000-0000-0000-0000-000000000000'),
+    type operation_type NOT NULL,
+    state varchar(32) NOT NULL,
+    message text NOT NULL DEFAULT '',
+    selector jsonb NOT NULL,
+    input jsonb NOT NULL,
+    max_parallel integer NOT NULL,
+    max_failure_ratio double precision NOT NULL,
+    start_timestamp timestamp without time zone NOT NULL,
+    end_timestamp timestamp without time zone
+);
+
+CREATE TABLE bulk_operation_child
+(
+    bulk_operation_id uuid NOT NULL,
+    runtime_id uuid NOT NULL,
+    operation_id uuid,
+    state varchar(32) NOT NULL,
+    message text NOT NULL DEFAULT '',
+    PRIMARY KEY (bulk_operation_id, runtime_id),
+    foreign key (bulk_operation_id) REFERENCES bulk_operation (id) ON DELETE CASCADE,
+    foreign key (runtime_id) REFERENCES cluster (id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1 @@
+ALTER TYPE operation_type ADD VALUE 'ROTATE_CREDENTIALS' AFTER 'DEPROVISION_NO_INSTALL';
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE cluster DROP COLUMN deletion_timestamp;
+
+COMMIT;
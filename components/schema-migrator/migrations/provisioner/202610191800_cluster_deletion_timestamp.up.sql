@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE cluster ADD COLUMN deletion_timestamp timestamp without time zone;
+
+UPDATE cluster SET deletion_timestamp = (SELECT MAX(end_timestamp) FROM operation WHERE operation.cluster_id = cluster.id) WHERE deleted;
+
+COMMIT;